@@ -16,7 +16,7 @@ const (
 // Documentation: https://docs.librenms.org/API/Alerts/#ack_alert
 func (a *AlertAPI) Ack(alertID int, payload *types.AlertAckRequest) (*types.BaseResponse, error) {
 	c := a.client
-	req, err := c.newRequest(http.MethodPut, fmt.Sprintf("%s/%d", alertEndpoint, alertID), payload, nil)
+	req, err := c.newRequest(opAlertAck, http.MethodPut, fmt.Sprintf("%s/%d", alertEndpoint, alertID), payload, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +30,7 @@ func (a *AlertAPI) Ack(alertID int, payload *types.AlertAckRequest) (*types.Base
 // Documentation: https://docs.librenms.org/API/Alerts/#get_alert
 func (a *AlertAPI) Get(alertID int) (*types.AlertsResponse, error) {
 	c := a.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%d", alertEndpoint, alertID), nil, nil)
+	req, err := c.newRequest(opAlertGet, http.MethodGet, fmt.Sprintf("%s/%d", alertEndpoint, alertID), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +47,7 @@ func (a *AlertAPI) List(query *types.AlertsQuery) (*types.AlertsResponse, error)
 	if query == nil {
 		query = types.NewAlertsQuery()
 	}
-	req, err := c.newRequest(http.MethodGet, alertEndpoint, nil, query.Values())
+	req, err := c.newRequest(opAlertList, http.MethodGet, alertEndpoint, nil, query.Values())
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +61,7 @@ func (a *AlertAPI) List(query *types.AlertsQuery) (*types.AlertsResponse, error)
 // Documentation: https://docs.librenms.org/API/Alerts/#unmute_alert
 func (a *AlertAPI) UnmuteAlert(alertID int) (*types.BaseResponse, error) {
 	c := a.client
-	req, err := c.newRequest(http.MethodPut, fmt.Sprintf("%s/unmute/%d", alertEndpoint, alertID), nil, nil)
+	req, err := c.newRequest(opAlertUnmuteAlert, http.MethodPut, fmt.Sprintf("%s/unmute/%d", alertEndpoint, alertID), nil, nil)
 	if err != nil {
 		return nil, err
 	}
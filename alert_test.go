@@ -75,13 +75,13 @@ func TestClient_GetAlert(t *testing.T) {
 	r.NotNil(alertResp, "GetAlert response is nil")
 
 	r.Equal("ok", alertResp.Status, "Expected status 'ok'")
-	r.Equal(1, alertResp.Count, "Expected count 1")
+	r.EqualValues(1, alertResp.Count, "Expected count 1")
 	r.Len(alertResp.Alerts, 1, "Expected 1 alerts")
 
 	alert := alertResp.Alerts[0]
-	r.Equal(testAlertID, alert.ID, "Expected AlertID "+strconv.Itoa(testAlertID))
-	r.Equal(6, alert.DeviceID, "Expected DeviceID 6")
-	r.Equal("warning", alert.Severity, "Expected Severity 'warning'")
+	r.EqualValues(testAlertID, alert.ID, "Expected AlertID "+strconv.Itoa(testAlertID))
+	r.EqualValues(6, alert.DeviceID, "Expected DeviceID 6")
+	r.Equal("warning", string(alert.Severity), "Expected Severity 'warning'")
 }
 
 func TestClient_GetAlerts(t *testing.T) {
@@ -97,12 +97,12 @@ func TestClient_GetAlerts(t *testing.T) {
 	r.NotNil(alertResp, "GetAlerts response is nil")
 
 	r.Equal("ok", alertResp.Status, "Expected status 'ok'")
-	r.Equal(6, alertResp.Count, "Expected count 6")
+	r.EqualValues(6, alertResp.Count, "Expected count 6")
 	r.Len(alertResp.Alerts, 6, "Expected 6 alerts")
 
 	alert := alertResp.Alerts[0]
-	r.Equal(15, alert.ID, "Expected AlertID 15")
-	r.Equal(3, alert.RuleID, "Expected Alert RuleID 3")
+	r.EqualValues(15, alert.ID, "Expected AlertID 15")
+	r.EqualValues(3, alert.RuleID, "Expected Alert RuleID 3")
 }
 
 func TestClient_GetAlerts_NilPayload(t *testing.T) {
@@ -116,12 +116,12 @@ func TestClient_GetAlerts_NilPayload(t *testing.T) {
 	r.NotNil(alertResp, "GetAlerts response is nil")
 
 	r.Equal("ok", alertResp.Status, "Expected status 'ok'")
-	r.Equal(6, alertResp.Count, "Expected count 6")
+	r.EqualValues(6, alertResp.Count, "Expected count 6")
 	r.Len(alertResp.Alerts, 6, "Expected 6 alerts")
 
 	alert := alertResp.Alerts[0]
-	r.Equal(15, alert.ID, "Expected AlertID 15")
-	r.Equal(3, alert.RuleID, "Expected Alert RuleID 3")
+	r.EqualValues(15, alert.ID, "Expected AlertID 15")
+	r.EqualValues(3, alert.RuleID, "Expected Alert RuleID 3")
 }
 
 func TestClient_UnmuteAlert(t *testing.T) {
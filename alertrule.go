@@ -21,7 +21,7 @@ func (a *AlertRuleAPI) Create(payload *types.AlertRuleCreateRequest) (*types.Bas
 		payload.Devices = []int{-1}
 	}
 
-	req, err := c.newRequest(http.MethodPost, alertRuleEndpoint, payload, nil)
+	req, err := c.newRequest(opAlertRuleCreate, http.MethodPost, alertRuleEndpoint, payload, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +34,7 @@ func (a *AlertRuleAPI) Create(payload *types.AlertRuleCreateRequest) (*types.Bas
 // Documentation: https://docs.librenms.org/API/Alerts/#delete_rule
 func (a *AlertRuleAPI) Delete(id int) (*types.BaseResponse, error) {
 	c := a.client
-	req, err := c.newRequest(http.MethodDelete, fmt.Sprintf("%s/%d", alertRuleEndpoint, id), nil, nil)
+	req, err := c.newRequest(opAlertRuleDelete, http.MethodDelete, fmt.Sprintf("%s/%d", alertRuleEndpoint, id), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +47,7 @@ func (a *AlertRuleAPI) Delete(id int) (*types.BaseResponse, error) {
 // Documentation: https://docs.librenms.org/API/Alerts/#get_alert_rule
 func (a *AlertRuleAPI) Get(id int) (*types.AlertRuleResponse, error) {
 	c := a.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%d", alertRuleEndpoint, id), nil, nil)
+	req, err := c.newRequest(opAlertRuleGet, http.MethodGet, fmt.Sprintf("%s/%d", alertRuleEndpoint, id), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +60,7 @@ func (a *AlertRuleAPI) Get(id int) (*types.AlertRuleResponse, error) {
 // Documentation: https://docs.librenms.org/API/Alerts/#list_alert_rules
 func (a *AlertRuleAPI) List() (*types.AlertRuleResponse, error) {
 	c := a.client
-	req, err := c.newRequest(http.MethodGet, alertRuleEndpoint, nil, nil)
+	req, err := c.newRequest(opAlertRuleList, http.MethodGet, alertRuleEndpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +82,7 @@ func (a *AlertRuleAPI) Update(payload *types.AlertRuleUpdateRequest) (*types.Bas
 		payload.Devices = []int{-1}
 	}
 
-	req, err := c.newRequest(http.MethodPut, alertRuleEndpoint, payload, nil)
+	req, err := c.newRequest(opAlertRuleUpdate, http.MethodPut, alertRuleEndpoint, payload, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -40,12 +40,12 @@ func TestClient_GetAlertRule(t *testing.T) {
 	r.NotNil(resp, "GetAlertRule response is nil")
 
 	r.Equal("ok", resp.Status, "Expected status 'ok'")
-	r.Equal(1, resp.Count, "Expected count 1")
+	r.EqualValues(1, resp.Count, "Expected count 1")
 	r.Len(resp.Rules, 1, "Expected 1 alert rule")
 
 	rule := resp.Rules[0]
-	r.Equal(1, rule.ID, "Expected AlertRule ID 1")
-	r.Equal("Device Down! Due to no ICMP response.", rule.Name, "Unexpected name")
+	r.EqualValues(1, rule.ID, "Expected AlertRule ID 1")
+	r.Equal("Device Down! Due to no ICMP response.", string(rule.Name), "Unexpected name")
 }
 
 func TestClient_GetAlertRules(t *testing.T) {
@@ -59,12 +59,12 @@ func TestClient_GetAlertRules(t *testing.T) {
 	r.NotNil(resp, "GetAlertRules response is nil")
 
 	r.Equal("ok", resp.Status, "Expected status 'ok'")
-	r.Equal(12, resp.Count, "Expected count 12")
+	r.EqualValues(12, resp.Count, "Expected count 12")
 	r.Len(resp.Rules, 12, "Expected 12 alert rules")
 
 	rule := resp.Rules[0]
-	r.Equal(1, rule.ID, "Expected AlertRule ID 1")
-	r.Equal("Device Down! Due to no ICMP response.", rule.Name, "Unexpected Name")
+	r.EqualValues(1, rule.ID, "Expected AlertRule ID 1")
+	r.Equal("Device Down! Due to no ICMP response.", string(rule.Name), "Unexpected Name")
 }
 
 func TestClient_CreateAlertRule(t *testing.T) {
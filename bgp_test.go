@@ -0,0 +1,33 @@
+package librenms_test
+
+import (
+	"testing"
+
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBGPPeerState(t *testing.T) {
+	r := require.New(t)
+
+	for s, want := range map[string]types.BGPPeerState{
+		"established":  types.BGPStateEstablished,
+		"Established":  types.BGPStateEstablished,
+		"OpenSent":     types.BGPStateOpenSent,
+		"open-confirm": types.BGPStateOpenConfirm,
+		"idle":         types.BGPStateIdle,
+		"3":            types.BGPStateActive,
+		"full":         types.BGPStateUnknown,
+		"7":            types.BGPStateUnknown,
+	} {
+		state, err := types.ParseBGPPeerState(s)
+		if want == types.BGPStateUnknown {
+			r.Error(err, "Expected an error for %q", s)
+			continue
+		}
+		r.NoError(err, "ParseBGPPeerState returned an error for %q", s)
+		r.Equal(want, state, "Unexpected state for %q", s)
+	}
+	r.Equal("openconfirm", types.BGPStateOpenConfirm.String(), "Expected the MIB name")
+	r.Equal("", types.BGPStateUnknown.String(), "Expected no name for an unknown state")
+}
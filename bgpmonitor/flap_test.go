@@ -0,0 +1,76 @@
+package bgpmonitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitor_FlapBetweenPolls(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{DeviceID: 1, Hostname: "edge1"})
+	session := types.BGPSession{
+		BGPPeerID: 1, DeviceID: 1, BGPPeerIdentifier: "192.0.2.1", BGPPeerRemoteAS: 64500,
+		BGPPeerState: "established", BGPPeerAdminStatus: "start", BGPPeerFsmEstablishedTime: 120,
+	}
+	srv.AddBGPSession(session)
+	m := New(srv.Client(), &Options{SkipCounters: true})
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	poll := func(at time.Duration, established int64) []Event {
+		t.Helper()
+		session.BGPPeerFsmEstablishedTime = types.Int64(established)
+		srv.UpdateBGPSession(session)
+		events, err := m.poll(ctx, start.Add(at))
+		r.NoError(err, "poll returned an error")
+		return events
+	}
+
+	r.Empty(poll(0, 120), "Expected no events on the first poll")
+	r.Empty(poll(5*time.Minute, 120), "Expected no flap while LibreNMS didn't poll the session again")
+	r.Empty(poll(10*time.Minute, 390), "Expected no flap within the slack")
+
+	events := poll(15*time.Minute, 470)
+	r.Len(events, 1, "Expected the session reset between the polls to be a flap")
+	r.Equal(EventFlap, events[0].Type, "Unexpected event")
+	r.Equal("edge1 peer 192.0.2.1 (AS64500) flapped, established for 7m50s after 6m30s", events[0].Message, "Unexpected message")
+
+	r.Len(poll(15*time.Minute+time.Second, 400), 1, "Expected a shorter established time to always be a flap")
+}
+
+func TestMonitor_FlapAfterStaleSamples(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{DeviceID: 1, Hostname: "edge1"})
+	session := types.BGPSession{
+		BGPPeerID: 1, DeviceID: 1, BGPPeerIdentifier: "192.0.2.1", BGPPeerRemoteAS: 64500,
+		BGPPeerState: "established", BGPPeerAdminStatus: "start", BGPPeerFsmEstablishedTime: 120,
+	}
+	srv.AddBGPSession(session)
+	m := New(srv.Client(), &Options{SkipCounters: true})
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, at := range []time.Duration{0, 5 * time.Minute, 10 * time.Minute} {
+		events, err := m.poll(ctx, start.Add(at))
+		r.NoError(err, "poll returned an error")
+		r.Empty(events, "Expected no events while LibreNMS didn't poll the session again")
+	}
+
+	// Without a reset, the session would be up for at least 120s plus the 10 minutes the
+	// same established time was seen for.
+	session.BGPPeerFsmEstablishedTime = 360
+	srv.UpdateBGPSession(session)
+	events, err := m.poll(ctx, start.Add(15*time.Minute))
+	r.NoError(err, "poll returned an error")
+	r.Len(events, 1, "Expected the reset hidden by the stale samples to be a flap")
+	r.Equal(EventFlap, events[0].Type, "Unexpected event")
+}
@@ -0,0 +1,481 @@
+// Package bgpmonitor polls the BGP sessions known to LibreNMS and reports how they change.
+//
+// LibreNMS only exposes the state of each session at its last poll. The monitor keeps a
+// short history per session and compares every poll with the previous one. It reports state
+// changes, flaps, admin-down sessions and accepted prefix counts that deviate from their
+// baseline. A flap is a session that stayed established but whose established time went
+// backwards, so it went down and up again between two polls. The baseline of a prefix count
+// is the median of the previous polls.
+package bgpmonitor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+)
+
+const (
+	// DefaultInterval is the default polling interval, the polling interval of LibreNMS.
+	DefaultInterval = 5 * time.Minute
+	// DefaultTolerance is the default relative deviation of prefix counts from their
+	// baseline that is reported.
+	DefaultTolerance = 0.2
+	// DefaultHistory is the default number of samples kept per session, a day at the
+	// default interval.
+	DefaultHistory = 288
+	// DefaultFlapSlack is the default time the established time of a session may lag
+	// behind the time elapsed since the previous poll before a flap is reported.
+	DefaultFlapSlack = time.Minute
+)
+
+// State is the state of the BGP finite state machine of a session.
+type State string
+
+// BGP session states.
+const (
+	StateIdle        State = "idle"
+	StateConnect     State = "connect"
+	StateActive      State = "active"
+	StateOpenSent    State = "opensent"
+	StateOpenConfirm State = "openconfirm"
+	StateEstablished State = "established"
+)
+
+// EventType is the kind of an Event.
+type EventType string
+
+// Event types.
+const (
+	// EventStateChange is reported when the state of a session changed.
+	EventStateChange EventType = "state-change"
+	// EventFlap is reported when a session went down and up again between two polls.
+	EventFlap EventType = "flap"
+	// EventPrefixDeviation is reported when the accepted prefixes of an address family
+	// deviate from their baseline by more than the tolerance. It is reported once until
+	// the count is back within the tolerance.
+	EventPrefixDeviation EventType = "prefix-deviation"
+	// EventAdminDown is reported when a session is administratively shut down, and for the
+	// sessions already down on the first poll.
+	EventAdminDown EventType = "admin-down"
+)
+
+type (
+	// Options configures a Monitor.
+	Options struct {
+		// Interval is the time between polls, DefaultInterval when zero.
+		Interval time.Duration
+		// Query limits the monitored sessions.
+		Query *types.BGPQuery
+		// SkipCounters skips the prefix counters, and so the prefix deviations.
+		SkipCounters bool
+		// Tolerance is the relative deviation of prefix counts that is reported, e.g. 0.2
+		// for 20%. DefaultTolerance when zero.
+		Tolerance float64
+		// History is the number of samples kept per session, DefaultHistory when zero.
+		History int
+		// FlapSlack is the time the established time of a session may lag behind its
+		// previous established time plus the time elapsed since the previous poll before a
+		// flap is reported, DefaultFlapSlack when zero. It covers the delay between the
+		// polls of LibreNMS and those of the monitor; with an Interval longer than the
+		// polling interval of LibreNMS, it should be at least that interval.
+		FlapSlack time.Duration
+		// OnEvent is called with each event, in the order they are found.
+		OnEvent func(Event)
+		// OnError is called with the errors of the polls of Run and Watch, which carry on
+		// with the next poll.
+		OnError func(error)
+	}
+
+	// Monitor polls BGP sessions and reports events. It is safe for concurrent use.
+	Monitor struct {
+		client *librenms.Client
+		opts   Options
+
+		mu        sync.Mutex
+		sessions  map[int]*tracked
+		hostnames map[int]string
+	}
+
+	// Session is the state of a session at the last poll.
+	Session struct {
+		ID       int
+		DeviceID int
+		Hostname string
+		// Peer is the BGP identifier of the peer.
+		Peer        string
+		RemoteAS    int
+		Description string
+		State       State
+		// AdminStatus is "start" or "stop".
+		AdminStatus string
+		// Established is how long the session has been established, as of the last poll
+		// of LibreNMS.
+		Established time.Duration
+		LastError   string
+		// Prefixes are the accepted prefixes by address family, e.g. "ipv4.unicast".
+		Prefixes map[string]int64
+	}
+
+	// Sample is the state of a session at a poll.
+	Sample struct {
+		Time        time.Time
+		State       State
+		AdminStatus string
+		Established time.Duration
+		Prefixes    map[string]int64
+	}
+
+	// Event is a change of a session.
+	Event struct {
+		Type    EventType
+		Time    time.Time
+		Session Session
+		// From and To are the previous and new states of state changes.
+		From State
+		To   State
+		// Family, Prefixes and Baseline are the address family, accepted prefixes and
+		// baseline of prefix deviations.
+		Family   string
+		Prefixes int64
+		Baseline int64
+		// Message describes the event, e.g. "core1 peer 10.0.0.2 (AS65002) went from
+		// established to idle".
+		Message string
+	}
+
+	// tracked is the history of a session.
+	tracked struct {
+		session Session
+		history []Sample
+		// deviating are the address families whose deviation was reported.
+		deviating map[string]bool
+	}
+)
+
+// New returns a Monitor using client.
+func New(client *librenms.Client, opts *Options) *Monitor {
+	m := &Monitor{
+		client:    client,
+		sessions:  make(map[int]*tracked),
+		hostnames: make(map[int]string),
+	}
+	if opts != nil {
+		m.opts = *opts
+	}
+	if m.opts.Interval <= 0 {
+		m.opts.Interval = DefaultInterval
+	}
+	if m.opts.Tolerance <= 0 {
+		m.opts.Tolerance = DefaultTolerance
+	}
+	if m.opts.History <= 0 {
+		m.opts.History = DefaultHistory
+	}
+	if m.opts.FlapSlack <= 0 {
+		m.opts.FlapSlack = DefaultFlapSlack
+	}
+	return m
+}
+
+// Poll polls the sessions once and returns the events, after passing them to OnEvent.
+func (m *Monitor) Poll(ctx context.Context) ([]Event, error) {
+	events, err := m.poll(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if m.opts.OnEvent != nil {
+		for _, event := range events {
+			m.opts.OnEvent(event)
+		}
+	}
+	return events, nil
+}
+
+// Run polls the sessions immediately and then at every interval until ctx is done, and
+// returns the error of ctx. Events are passed to OnEvent and errors to OnError.
+func (m *Monitor) Run(ctx context.Context) error {
+	return m.run(ctx, func(Event) bool { return true })
+}
+
+// Watch runs the monitor in the background and returns a channel receiving the events,
+// which is closed when ctx is done. Polling waits for the events to be received.
+func (m *Monitor) Watch(ctx context.Context) <-chan Event {
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		_ = m.run(ctx, func(event Event) bool {
+			select {
+			case ch <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return ch
+}
+
+// Sessions returns the sessions at the last poll, ordered by ID.
+func (m *Monitor) Sessions() []Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sessions := make([]Session, 0, len(m.sessions))
+	for _, t := range m.sessions {
+		sessions = append(sessions, t.session)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+	return sessions
+}
+
+// History returns the samples of a session, oldest first.
+func (m *Monitor) History(sessionID int) []Sample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.sessions[sessionID]
+	if !ok {
+		return nil
+	}
+	return append([]Sample(nil), t.history...)
+}
+
+// run polls until ctx is done, passing the events to send until it returns false.
+func (m *Monitor) run(ctx context.Context, send func(Event) bool) error {
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+	for {
+		events, err := m.Poll(ctx)
+		if err != nil && m.opts.OnError != nil && ctx.Err() == nil {
+			m.opts.OnError(err)
+		}
+		for _, event := range events {
+			if !send(event) {
+				return ctx.Err()
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll fetches the sessions and counters and compares them with the previous poll.
+func (m *Monitor) poll(ctx context.Context, now time.Time) ([]Event, error) {
+	client := m.client.WithContext(ctx)
+	resp, err := client.Routing.ListBGP(m.opts.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list BGP sessions: %w", err)
+	}
+	prefixes := make(map[string]map[string]int64)
+	if !m.opts.SkipCounters {
+		hostname := ""
+		if m.opts.Query != nil {
+			hostname = m.opts.Query.Hostname
+		}
+		counters, err := client.Routing.ListBGPCounters(hostname)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list BGP counters: %w", err)
+		}
+		for _, c := range counters.BGPCounters {
+			key := sessionKey(int(c.DeviceID), string(c.BGPPeerIdentifier))
+			if prefixes[key] == nil {
+				prefixes[key] = make(map[string]int64)
+			}
+			prefixes[key][string(c.AFI+"."+c.SAFI)] = int64(c.AcceptedPrefixes)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.loadHostnames(client, resp.BGPSessions); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(resp.BGPSessions, func(i, j int) bool { return resp.BGPSessions[i].BGPPeerID < resp.BGPSessions[j].BGPPeerID })
+	var events []Event
+	seen := make(map[int]bool, len(resp.BGPSessions))
+	for _, s := range resp.BGPSessions {
+		session := Session{
+			ID:          int(s.BGPPeerID),
+			DeviceID:    int(s.DeviceID),
+			Hostname:    m.hostnames[int(s.DeviceID)],
+			Peer:        string(s.BGPPeerIdentifier),
+			RemoteAS:    int(s.BGPPeerRemoteAS),
+			Description: string(s.BGPPeerDescr),
+			State:       State(strings.ToLower(string(s.BGPPeerState))),
+			AdminStatus: strings.ToLower(string(s.BGPPeerAdminStatus)),
+			Established: time.Duration(s.BGPPeerFsmEstablishedTime) * time.Second,
+			LastError:   string(s.BGPPeerLastErrorText),
+			Prefixes:    prefixes[sessionKey(int(s.DeviceID), string(s.BGPPeerIdentifier))],
+		}
+		seen[session.ID] = true
+		t, ok := m.sessions[session.ID]
+		if !ok {
+			t = &tracked{deviating: make(map[string]bool)}
+			m.sessions[session.ID] = t
+		}
+		events = append(events, m.compare(t, session, ok, now)...)
+
+		t.session = session
+		t.history = append(t.history, Sample{
+			Time: now, State: session.State, AdminStatus: session.AdminStatus,
+			Established: session.Established, Prefixes: session.Prefixes,
+		})
+		if len(t.history) > m.opts.History {
+			t.history = t.history[len(t.history)-m.opts.History:]
+		}
+	}
+	for id := range m.sessions {
+		if !seen[id] {
+			delete(m.sessions, id)
+		}
+	}
+	return events, nil
+}
+
+// compare returns the events of a session since its previous poll. known is false for
+// sessions seen for the first time.
+func (m *Monitor) compare(t *tracked, session Session, known bool, now time.Time) []Event {
+	var events []Event
+	add := func(event Event) {
+		event.Time, event.Session = now, session
+		events = append(events, event)
+	}
+	name := fmt.Sprintf("%s peer %s (AS%d)", session.Hostname, session.Peer, session.RemoteAS)
+	previous := t.session
+
+	if adminDown(session) && (!known || !adminDown(previous)) {
+		add(Event{Type: EventAdminDown, Message: name + " is administratively down"})
+	}
+	if known {
+		switch {
+		case session.State != previous.State:
+			add(Event{
+				Type: EventStateChange, From: previous.State, To: session.State,
+				Message: fmt.Sprintf("%s went from %s to %s", name, previous.State, session.State),
+			})
+		case session.State == StateEstablished && m.flapped(t, session, now):
+			add(Event{
+				Type: EventFlap, From: previous.State, To: session.State,
+				Message: fmt.Sprintf("%s flapped, established for %s after %s", name, session.Established, previous.Established),
+			})
+		}
+	}
+
+	if session.State != StateEstablished {
+		return events
+	}
+	families := make([]string, 0, len(session.Prefixes))
+	for family := range session.Prefixes {
+		families = append(families, family)
+	}
+	sort.Strings(families)
+	for _, family := range families {
+		baseline, ok := t.baseline(family)
+		if !ok {
+			continue
+		}
+		count := session.Prefixes[family]
+		deviation := float64(count-baseline) / float64(baseline)
+		if math.Abs(deviation) <= m.opts.Tolerance {
+			delete(t.deviating, family)
+			continue
+		}
+		if t.deviating[family] {
+			continue
+		}
+		t.deviating[family] = true
+		add(Event{
+			Type: EventPrefixDeviation, Family: family, Prefixes: count, Baseline: baseline,
+			Message: fmt.Sprintf("%s accepts %d %s prefixes, baseline %d (%+.0f%%)", name, count, family, baseline, 100*deviation),
+		})
+	}
+	return events
+}
+
+// flapped reports whether an established session was reset since the previous poll: its
+// established time went down, or is shorter than the previous one plus the time elapsed
+// since, less the slack. An unchanged established time means LibreNMS didn't poll the
+// session again, so the elapsed time is at least the time the previous established time
+// was seen for.
+func (m *Monitor) flapped(t *tracked, session Session, now time.Time) bool {
+	previous := t.session
+	switch {
+	case session.Established < previous.Established:
+		return true
+	case session.Established == previous.Established || len(t.history) == 0:
+		return false
+	}
+	last := t.history[len(t.history)-1]
+	first := last
+	for i := len(t.history) - 2; i >= 0 && t.history[i].Established == previous.Established; i-- {
+		first = t.history[i]
+	}
+	elapsed := max(now.Sub(last.Time), last.Time.Sub(first.Time))
+	return session.Established < previous.Established+elapsed-m.opts.FlapSlack
+}
+
+// baseline returns the median of the accepted prefixes of a family over the established
+// samples. It reports false without samples or with a zero baseline.
+func (t *tracked) baseline(family string) (int64, bool) {
+	var counts []int64
+	for _, sample := range t.history {
+		if count, ok := sample.Prefixes[family]; ok && sample.State == StateEstablished {
+			counts = append(counts, count)
+		}
+	}
+	if len(counts) == 0 {
+		return 0, false
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i] < counts[j] })
+	median := counts[len(counts)/2]
+	if len(counts)%2 == 0 {
+		median = (counts[len(counts)/2-1] + median) / 2
+	}
+	return median, median > 0
+}
+
+// loadHostnames lists the devices when sessions belong to unknown devices.
+func (m *Monitor) loadHostnames(client *librenms.Client, sessions []types.BGPSession) error {
+	missing := false
+	for _, s := range sessions {
+		if _, ok := m.hostnames[int(s.DeviceID)]; !ok {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return nil
+	}
+	devices, err := client.Device.List(nil)
+	if err != nil {
+		return fmt.Errorf("failed to list devices: %w", err)
+	}
+	for _, device := range devices.Devices {
+		m.hostnames[int(device.DeviceID)] = string(device.Hostname)
+	}
+	for _, s := range sessions {
+		if _, ok := m.hostnames[int(s.DeviceID)]; !ok {
+			// Keep the devices LibreNMS doesn't know from being listed on every poll.
+			m.hostnames[int(s.DeviceID)] = fmt.Sprintf("device %d", s.DeviceID)
+		}
+	}
+	return nil
+}
+
+// adminDown reports whether a session is administratively shut down.
+func adminDown(session Session) bool {
+	return session.AdminStatus == "stop" || session.AdminStatus == "down"
+}
+
+func sessionKey(deviceID int, peer string) string {
+	return fmt.Sprintf("%d/%s", deviceID, peer)
+}
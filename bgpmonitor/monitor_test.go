@@ -0,0 +1,164 @@
+package bgpmonitor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go/bgpmonitor"
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	transit = types.BGPSession{
+		BGPPeerID: 1, DeviceID: 1, BGPPeerIdentifier: "192.0.2.1", BGPPeerRemoteAS: 64500,
+		BGPPeerState: "established", BGPPeerAdminStatus: "start", BGPPeerFsmEstablishedTime: 3600,
+	}
+	peering = types.BGPSession{
+		BGPPeerID: 2, DeviceID: 1, BGPPeerIdentifier: "198.51.100.1", BGPPeerRemoteAS: 64501,
+		BGPPeerState: "established", BGPPeerAdminStatus: "start", BGPPeerFsmEstablishedTime: 7200,
+	}
+	shutdown = types.BGPSession{
+		BGPPeerID: 3, DeviceID: 2, BGPPeerIdentifier: "203.0.113.1", BGPPeerRemoteAS: 64502,
+		BGPPeerState: "idle", BGPPeerAdminStatus: "stop",
+	}
+)
+
+// newServer returns a server with two routers, where transit accepts prefixes.
+func newServer(t *testing.T) *librenmstest.Server {
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{DeviceID: 1, Hostname: "edge1"})
+	srv.AddDevice(types.Device{DeviceID: 2, Hostname: "edge2"})
+	for _, session := range []types.BGPSession{transit, peering, shutdown} {
+		srv.AddBGPSession(session)
+	}
+	srv.SetBGPCounters(prefixes(100))
+	return srv
+}
+
+func prefixes(accepted int64) types.BGPCounters {
+	return types.BGPCounters{DeviceID: 1, BGPPeerIdentifier: "192.0.2.1", AFI: "ipv4", SAFI: "unicast", AcceptedPrefixes: types.Int64(accepted)}
+}
+
+func types_(events []bgpmonitor.Event) []bgpmonitor.EventType {
+	kinds := make([]bgpmonitor.EventType, 0, len(events))
+	for _, event := range events {
+		kinds = append(kinds, event.Type)
+	}
+	return kinds
+}
+
+func TestMonitor_Poll(t *testing.T) {
+	r := require.New(t)
+
+	srv := newServer(t)
+	var received []bgpmonitor.Event
+	m := bgpmonitor.New(srv.Client(), &bgpmonitor.Options{OnEvent: func(event bgpmonitor.Event) {
+		received = append(received, event)
+	}})
+	ctx := context.Background()
+
+	events, err := m.Poll(ctx)
+	r.NoError(err, "Poll returned an error")
+	r.Equal([]bgpmonitor.EventType{bgpmonitor.EventAdminDown}, types_(events), "Expected only the session already down")
+	r.Equal("edge2 peer 203.0.113.1 (AS64502) is administratively down", events[0].Message, "Unexpected message")
+	r.Equal(events, received, "Expected the events to be passed to OnEvent")
+
+	down, flapped := transit, peering
+	down.BGPPeerState, down.BGPPeerFsmEstablishedTime = "idle", 0
+	flapped.BGPPeerFsmEstablishedTime = 60
+	srv.UpdateBGPSession(down)
+	srv.UpdateBGPSession(flapped)
+	events, err = m.Poll(ctx)
+	r.NoError(err, "Poll returned an error")
+	r.Equal([]bgpmonitor.EventType{bgpmonitor.EventStateChange, bgpmonitor.EventFlap}, types_(events), "Expected the state change and the flap")
+	r.Equal(bgpmonitor.StateEstablished, events[0].From, "Unexpected previous state")
+	r.Equal(bgpmonitor.StateIdle, events[0].To, "Unexpected new state")
+	r.Equal("edge1 peer 192.0.2.1 (AS64500) went from established to idle", events[0].Message, "Unexpected message")
+	r.Equal(time.Minute, events[1].Session.Established, "Expected the new established time")
+
+	srv.UpdateBGPSession(transit)
+	srv.SetBGPCounters(prefixes(50))
+	events, err = m.Poll(ctx)
+	r.NoError(err, "Poll returned an error")
+	r.Equal([]bgpmonitor.EventType{bgpmonitor.EventStateChange, bgpmonitor.EventPrefixDeviation}, types_(events), "Expected the session to come back with fewer prefixes")
+	r.Equal("ipv4.unicast", events[1].Family, "Unexpected family")
+	r.Equal(int64(50), events[1].Prefixes, "Unexpected prefixes")
+	r.Equal(int64(100), events[1].Baseline, "Expected the prefixes before the session went down")
+	r.Equal("edge1 peer 192.0.2.1 (AS64500) accepts 50 ipv4.unicast prefixes, baseline 100 (-50%)", events[1].Message, "Unexpected message")
+
+	events, err = m.Poll(ctx)
+	r.NoError(err, "Poll returned an error")
+	r.Empty(events, "Expected the deviation to be reported once")
+
+	events, err = m.Poll(ctx)
+	r.NoError(err, "Poll returned an error")
+	r.Empty(events, "Expected the new prefixes to become the baseline")
+	srv.SetBGPCounters(prefixes(100))
+	events, err = m.Poll(ctx)
+	r.NoError(err, "Poll returned an error")
+	r.Equal([]bgpmonitor.EventType{bgpmonitor.EventPrefixDeviation}, types_(events), "Expected a new deviation once back within the tolerance")
+
+	r.Len(m.History(1), 6, "Expected a sample per poll")
+	r.Equal(bgpmonitor.StateIdle, m.History(1)[1].State, "Expected the state of the second poll")
+	sessions := m.Sessions()
+	r.Len(sessions, 3, "Expected every session")
+	r.Equal(map[string]int64{"ipv4.unicast": 100}, sessions[0].Prefixes, "Expected the last prefixes")
+
+	m = bgpmonitor.New(srv.Client(), &bgpmonitor.Options{History: 2, Query: &types.BGPQuery{Hostname: "edge1"}})
+	for i := 0; i < 3; i++ {
+		_, err = m.Poll(ctx)
+		r.NoError(err, "Poll returned an error")
+	}
+	r.Len(m.History(1), 2, "Expected the history to be limited")
+	r.Len(m.Sessions(), 2, "Expected the sessions of edge1")
+}
+
+func TestMonitor_Watch(t *testing.T) {
+	r := require.New(t)
+
+	srv := newServer(t)
+	m := bgpmonitor.New(srv.Client(), &bgpmonitor.Options{Interval: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	events := m.Watch(ctx)
+
+	event := <-events
+	r.Equal(bgpmonitor.EventAdminDown, event.Type, "Expected the first poll")
+	down := transit
+	down.BGPPeerState = "active"
+	srv.UpdateBGPSession(down)
+	event = <-events
+	r.Equal(bgpmonitor.EventStateChange, event.Type, "Expected a later poll")
+	r.Equal(bgpmonitor.StateActive, event.To, "Unexpected new state")
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestMonitor_Errors(t *testing.T) {
+	r := require.New(t)
+
+	srv := newServer(t)
+	m := bgpmonitor.New(srv.Client(), &bgpmonitor.Options{Interval: 10 * time.Millisecond})
+
+	srv.InjectError("bgp", 500, 1)
+	_, err := m.Poll(context.Background())
+	r.ErrorContains(err, "failed to list BGP sessions", "Expected the API error")
+	srv.InjectError("routing/bgp/cbgp", 500, 1)
+	_, err = m.Poll(context.Background())
+	r.ErrorContains(err, "failed to list BGP counters", "Expected the API error")
+
+	errs := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	m = bgpmonitor.New(srv.Client(), &bgpmonitor.Options{
+		Interval: 10 * time.Millisecond,
+		OnError:  func(err error) { errs <- err },
+		OnEvent:  func(bgpmonitor.Event) { cancel() },
+	})
+	srv.InjectError("bgp", 500, 1)
+	r.ErrorIs(m.Run(ctx), context.Canceled, "Expected Run to stop with the context")
+	r.ErrorContains(<-errs, "failed to list BGP sessions", "Expected the error to be passed to OnError")
+}
@@ -0,0 +1,286 @@
+package librenms
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCacheSize is the number of responses kept by the in-memory cache WithCache
+// creates when no store is given.
+const DefaultCacheSize = 1024
+
+type (
+	// CacheStore stores cached responses, e.g. in memory with LRUCache or in an external
+	// store shared by several clients. Implementations must be safe for concurrent use.
+	// Errors of external stores should be handled by the store, e.g. by logging them and
+	// reporting a miss.
+	CacheStore interface {
+		// Get returns the value stored for key, unless it expired.
+		Get(ctx context.Context, key string) ([]byte, bool)
+		// Set stores value for key for the ttl duration.
+		Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+	}
+
+	// CacheStats counts the lookups of the response cache.
+	CacheStats struct {
+		// Hits is the number of GET requests served from the cache.
+		Hits uint64
+		// Misses is the number of GET requests sent to the server.
+		Misses uint64
+		// Invalidations is the number of successful writes that invalidated cached
+		// responses.
+		Invalidations uint64
+	}
+
+	// LRUCache is an in-memory CacheStore keeping the most recently used values.
+	LRUCache struct {
+		mu       sync.Mutex
+		capacity int
+		entries  map[string]*list.Element
+		order    *list.List
+	}
+
+	lruEntry struct {
+		key     string
+		value   []byte
+		expires time.Time
+	}
+
+	// cache serves GET requests from a CacheStore and invalidates the cached responses of
+	// an API service when a write request of the same service succeeds.
+	cache struct {
+		store     CacheStore
+		ttl       time.Duration
+		namespace string
+
+		hits          atomic.Uint64
+		misses        atomic.Uint64
+		invalidations atomic.Uint64
+	}
+)
+
+// WithCache caches the responses of GET requests in store for ttl. A nil store uses an
+// LRUCache of DefaultCacheSize entries.
+//
+// Cached responses are grouped by API service, e.g. Device or Location: a successful write
+// request (POST, PUT, PATCH or DELETE) of a service, such as Location.Update, invalidates the
+// responses cached for all the methods of that service, such as Location.List and
+// Location.Get. Writes changing the data of other services also invalidate them, e.g.
+// Port.UpdatePortDescription invalidates Device.GetPorts and DeviceGroup.SetMaintenance
+// invalidates Device.GetDeviceMaintenance. The generation of each service is kept in the store, so a write invalidates
+// the responses cached by every client sharing the store and the namespace.
+//
+// The keys are prefixed with a namespace, a hash of the token by default, so that clients
+// using different tokens never see each other's responses. Clients whose token is set by
+// TokenMiddleware should use WithCacheNamespace.
+//
+// The cache answers before the middleware, so cache hits are not seen by them.
+func WithCache(store CacheStore, ttl time.Duration) Option {
+	return func(c *Client) {
+		if store == nil {
+			store = NewLRUCache(DefaultCacheSize)
+		}
+		c.cache = &cache{store: store, ttl: ttl}
+	}
+}
+
+// WithCacheNamespace sets the namespace of the keys of the cache set with WithCache, e.g. the
+// user the token belongs to. Clients sharing a store and a namespace share their cached
+// responses, so they should be granted the same permissions.
+func WithCacheNamespace(namespace string) Option {
+	return func(c *Client) {
+		c.cacheNamespace = namespace
+	}
+}
+
+// CacheStats returns the statistics of the cache set with WithCache, zero without cache.
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return CacheStats{
+		Hits:          c.cache.hits.Load(),
+		Misses:        c.cache.misses.Load(),
+		Invalidations: c.cache.invalidations.Load(),
+	}
+}
+
+// wrap returns a Doer serving the cached responses before calling next.
+func (ch *cache) wrap(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		info, _ := RequestInfoFromContext(req.Context())
+		service := serviceOf(info.Operation)
+		switch req.Method {
+		case http.MethodGet:
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			resp, err := next.Do(req)
+			if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				ch.invalidate(req.Context(), append([]string{service}, dependentServices[info.Operation]...)...)
+			}
+			return resp, err
+		default:
+			return next.Do(req)
+		}
+
+		key := ch.key(req.Context(), service, req)
+		if data, ok := ch.store.Get(req.Context(), key); ok {
+			if resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req); err == nil {
+				ch.hits.Add(1)
+				return resp, nil
+			}
+		}
+		ch.misses.Add(1)
+
+		resp, err := next.Do(req)
+		if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return resp, err
+		}
+		data, err := httputil.DumpResponse(resp, true)
+		if err != nil {
+			closeBody(resp.Body)
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		ch.store.Set(req.Context(), key, data, ch.ttl)
+		return resp, nil
+	})
+}
+
+// generationTTL is the minimum time the generation of a service is stored for. A generation
+// that expired or was evicted is replaced with a new one, which only drops the responses
+// cached with the old one.
+const generationTTL = 24 * time.Hour
+
+// cacheNamespace returns the default namespace of the cache keys of a token.
+func cacheNamespace(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// key returns the cache key of a GET request. It includes the generation of the service,
+// so that invalidated responses are no longer found.
+func (ch *cache) key(ctx context.Context, service string, req *http.Request) string {
+	generation, ok := ch.store.Get(ctx, ch.generationKey(service))
+	if !ok {
+		generation = ch.newGeneration(ctx, service)
+	}
+	return fmt.Sprintf("librenms:%s:%s:%s:%s", ch.namespace, service, generation, req.URL.String())
+}
+
+// invalidate drops the cached responses of the services changed by a write.
+func (ch *cache) invalidate(ctx context.Context, services ...string) {
+	for _, service := range services {
+		ch.newGeneration(ctx, service)
+	}
+	ch.invalidations.Add(1)
+}
+
+// newGeneration stores and returns a new random generation for a service.
+func (ch *cache) newGeneration(ctx context.Context, service string) []byte {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	generation := []byte(hex.EncodeToString(id))
+	ch.store.Set(ctx, ch.generationKey(service), generation, max(ch.ttl, generationTTL))
+	return generation
+}
+
+// generationKey returns the key the generation of a service is stored with.
+func (ch *cache) generationKey(service string) string {
+	return fmt.Sprintf("librenms:%s:%s:generation", ch.namespace, service)
+}
+
+// dependentServices are the other services whose cached responses a write operation
+// invalidates, because they return the data it changes.
+var dependentServices = map[string][]string{
+	// Dynamic device groups match device fields, and deleting a device removes its ports,
+	// services, links and routing sessions.
+	opDeviceCreate.name:       {"DeviceGroup"},
+	opDeviceUpdate.name:       {"DeviceGroup"},
+	opDeviceRenameDevice.name: {"DeviceGroup"},
+	opDeviceDelete.name:       {"DeviceGroup", "Port", "Service", "Switching", "Routing", "Inventory"},
+
+	opDeviceUpdateDevicePortNotes.name: {"Port"},
+	opDeviceGroupSetMaintenance.name:   {"Device"},
+	opPortUpdatePortDescription.name:   {"Device"},
+
+	// Devices return the name of their location, which dynamic device groups may match.
+	opLocationUpdate.name: {"Device", "DeviceGroup"},
+	opLocationDelete.name: {"Device", "DeviceGroup"},
+}
+
+// serviceOf returns the API service of an operation, e.g. "Device" for "Device.List".
+func serviceOf(operation string) string {
+	service, _, _ := strings.Cut(operation, ".")
+	return service
+}
+
+// NewLRUCache returns an in-memory cache keeping up to capacity values.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get implements CacheStore.
+func (l *LRUCache) Get(_ context.Context, key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		l.remove(element)
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set implements CacheStore, evicting the least recently used value when the cache is full.
+func (l *LRUCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	expires := time.Now().Add(ttl)
+	if element, ok := l.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value, entry.expires = value, expires
+		l.order.MoveToFront(element)
+		return
+	}
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for l.order.Len() > l.capacity {
+		l.remove(l.order.Back())
+	}
+}
+
+// Len returns the number of stored values, including expired ones not yet removed.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+func (l *LRUCache) remove(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.entries, element.Value.(*lruEntry).key)
+}
+
+var _ CacheStore = (*LRUCache)(nil)
@@ -0,0 +1,220 @@
+package librenms_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/rules"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_HitsAndInvalidation(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{Hostname: "core1"})
+	srv.AddLocation(types.Location{Name: "DC1"})
+	client := srv.Client(librenms.WithCache(nil, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Device.List(nil)
+		r.NoError(err, "Device.List returned an error")
+		r.Len(resp.Devices, 1, "Unexpected devices")
+	}
+	_, err := client.Location.List()
+	r.NoError(err, "Location.List returned an error")
+	r.Len(srv.Requests(), 2, "Expected the repeated list to be served from the cache")
+	r.Equal(librenms.CacheStats{Hits: 2, Misses: 2}, client.CacheStats(), "Unexpected cache stats")
+
+	_, err = client.Device.Create(&types.DeviceCreateRequest{Hostname: "core2", ForceAdd: true})
+	r.NoError(err, "Device.Create returned an error")
+	resp, err := client.Device.List(nil)
+	r.NoError(err, "Device.List returned an error")
+	r.Len(resp.Devices, 2, "Expected the write to invalidate the cached devices")
+
+	_, err = client.Location.List()
+	r.NoError(err, "Location.List returned an error")
+	r.Len(srv.Requests(), 4, "Expected the locations to stay cached after a device write")
+	r.Equal(librenms.CacheStats{Hits: 3, Misses: 3, Invalidations: 1}, client.CacheStats(), "Unexpected cache stats")
+
+	_, err = client.Device.Delete("core9")
+	r.Error(err, "Expected an error for an unknown device")
+	_, err = client.Device.List(nil)
+	r.NoError(err, "Device.List returned an error")
+	r.Equal(uint64(1), client.CacheStats().Invalidations, "Expected failed writes not to invalidate the cache")
+}
+
+func TestCache_GetUsesCachedList(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDeviceGroup(types.DeviceGroup{Name: "core", Type: "static"})
+	srv.AddDeviceGroup(types.DeviceGroup{Name: "edge", Type: "static"})
+	client := srv.Client(librenms.WithCache(nil, time.Minute))
+
+	_, err := client.DeviceGroup.List()
+	r.NoError(err, "DeviceGroup.List returned an error")
+	for _, name := range []string{"core", "edge"} {
+		resp, err := client.DeviceGroup.Get(name)
+		r.NoError(err, "DeviceGroup.Get returned an error")
+		r.Len(resp.Groups, 1, "Expected a single group")
+		r.Equal(name, string(resp.Groups[0].Name), "Unexpected group")
+	}
+	r.Len(srv.Requests(), 1, "Expected the lookups to use the cached list")
+}
+
+func TestCache_SharedStore(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{Hostname: "core1"})
+	store := librenms.NewLRUCache(librenms.DefaultCacheSize)
+	first := srv.Client(librenms.WithCache(store, time.Minute))
+	second := srv.Client(librenms.WithCache(store, time.Minute))
+	other := srv.Client(librenms.WithCache(store, time.Minute), librenms.WithCacheNamespace("readonly"))
+
+	_, err := first.Device.List(nil)
+	r.NoError(err, "Device.List returned an error")
+	_, err = second.Device.List(nil)
+	r.NoError(err, "Device.List returned an error")
+	r.Equal(uint64(1), second.CacheStats().Hits, "Expected clients with the same token to share the cached responses")
+	_, err = other.Device.List(nil)
+	r.NoError(err, "Device.List returned an error")
+	r.Equal(uint64(0), other.CacheStats().Hits, "Expected other namespaces not to see the cached responses")
+
+	_, err = second.Device.Create(&types.DeviceCreateRequest{Hostname: "core2", ForceAdd: true})
+	r.NoError(err, "Device.Create returned an error")
+	resp, err := first.Device.List(nil)
+	r.NoError(err, "Device.List returned an error")
+	r.Len(resp.Devices, 2, "Expected the write of another client to invalidate the cached devices")
+	r.Equal(librenms.CacheStats{Hits: 0, Misses: 2}, first.CacheStats(), "Unexpected cache stats")
+}
+
+func TestCache_DependentServices(t *testing.T) {
+	tests := []struct {
+		name  string
+		read  func(*librenms.Client) (any, error)
+		write func(*librenms.Client) error
+		check func(r *require.Assertions, read any)
+	}{
+		{
+			name: "port description invalidates device ports",
+			read: func(c *librenms.Client) (any, error) { return c.Device.GetPorts("core1", "ifAlias") },
+			write: func(c *librenms.Client) error {
+				_, err := c.Port.UpdatePortDescription(1, "uplink")
+				return err
+			},
+		},
+		{
+			name: "device port notes invalidate ports",
+			read: func(c *librenms.Client) (any, error) { return c.Port.GetPortInfo(1) },
+			write: func(c *librenms.Client) error {
+				_, err := c.Device.UpdateDevicePortNotes("core1", 1, "patched to rack 4")
+				return err
+			},
+		},
+		{
+			name: "group maintenance invalidates device maintenance",
+			read: func(c *librenms.Client) (any, error) { return c.Device.GetDeviceMaintenance("core1") },
+			write: func(c *librenms.Client) error {
+				_, err := c.DeviceGroup.SetMaintenance("core", &types.DeviceMaintenanceRequest{Duration: "1:00"})
+				return err
+			},
+			check: func(r *require.Assertions, read any) {
+				r.True(bool(read.(*types.DeviceMaintenanceResponse).IsUnderMaintenance), "Expected the device to be under maintenance")
+			},
+		},
+		{
+			name: "device update invalidates group members",
+			read: func(c *librenms.Client) (any, error) { return c.DeviceGroup.GetMembers("noted") },
+			write: func(c *librenms.Client) error {
+				_, err := c.Device.Update("core1", types.NewDeviceUpdate().SetNotes("core"))
+				return err
+			},
+			check: func(r *require.Assertions, read any) {
+				r.Len(read.(*types.DeviceGroupMembersResponse).Devices, 1, "Expected the updated device to become a member")
+			},
+		},
+		{
+			name: "device delete invalidates group members",
+			read: func(c *librenms.Client) (any, error) { return c.DeviceGroup.GetMembers("core") },
+			write: func(c *librenms.Client) error {
+				_, err := c.Device.Delete("core1")
+				return err
+			},
+			check: func(r *require.Assertions, read any) {
+				r.Empty(read.(*types.DeviceGroupMembersResponse).Devices, "Expected the deleted device not to be a member")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			srv := librenmstest.New(t)
+			device := srv.AddDevice(types.Device{Hostname: "core1"})
+			srv.AddPort(types.Port{PortID: 1, DeviceID: device.DeviceID, IfName: "Gi1/0/1"})
+			srv.AddDeviceGroup(types.DeviceGroup{Name: "core", Type: "static"}, int(device.DeviceID))
+			srv.AddDeviceGroup(types.DeviceGroup{Name: "noted", Type: "dynamic", Rules: *rules.MustParse(`devices.notes = "core"`)})
+			client := srv.Client(librenms.WithCache(nil, time.Minute))
+
+			for i := 0; i < 2; i++ {
+				_, err := tt.read(client)
+				r.NoError(err, "Read returned an error")
+			}
+			r.Equal(uint64(1), client.CacheStats().Hits, "Expected the second read to be cached")
+
+			r.NoError(tt.write(client), "Write returned an error")
+			read, err := tt.read(client)
+			r.NoError(err, "Read returned an error")
+			r.Equal(librenms.CacheStats{Hits: 1, Misses: 2, Invalidations: 1}, client.CacheStats(), "Expected the write to invalidate the read")
+			if tt.check != nil {
+				tt.check(r, read)
+			}
+		})
+	}
+}
+
+func TestCache_Errors(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	client := srv.Client(librenms.WithCache(nil, time.Minute))
+
+	srv.InjectError("system", 503, 1)
+	_, err := client.System.Get()
+	r.Error(err, "Expected the injected error")
+	_, err = client.System.Get()
+	r.NoError(err, "Expected the error not to be cached")
+	_, err = client.System.Get()
+	r.NoError(err, "System.Get returned an error")
+	r.Len(srv.Requests(), 2, "Expected the successful response to be cached")
+}
+
+func TestLRUCache(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	lru := librenms.NewLRUCache(2)
+	lru.Set(ctx, "a", []byte("1"), time.Minute)
+	lru.Set(ctx, "b", []byte("2"), time.Minute)
+	_, ok := lru.Get(ctx, "a")
+	r.True(ok, "Expected a to be cached")
+	lru.Set(ctx, "c", []byte("3"), time.Minute)
+	_, ok = lru.Get(ctx, "b")
+	r.False(ok, "Expected the least recently used value to be evicted")
+	value, ok := lru.Get(ctx, "a")
+	r.True(ok, "Expected a to be kept")
+	r.Equal([]byte("1"), value, "Unexpected value")
+	r.Equal(2, lru.Len(), "Unexpected size")
+
+	lru.Set(ctx, "d", []byte("4"), -time.Second)
+	_, ok = lru.Get(ctx, "d")
+	r.False(ok, "Expected expired values to be dropped")
+	r.Equal(1, lru.Len(), "Expected the expired value to be removed")
+}
@@ -0,0 +1,347 @@
+package librenms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces the scrubbed values in recorded cassettes.
+const Redacted = "REDACTED"
+
+// DefaultScrubKeys are the JSON keys scrubbed from recorded request and response bodies, in
+// addition to the X-Auth-Token header. They hold the SNMP credentials of devices.
+var DefaultScrubKeys = []string{"community", "authpass", "cryptopass"}
+
+type (
+	// Cassette is a recorded sequence of HTTP interactions, stored as JSON by WithRecorder and
+	// served back by WithReplayer.
+	Cassette struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+
+	// Interaction is a recorded request and its response.
+	Interaction struct {
+		Request  RecordedRequest  `json:"request"`
+		Response RecordedResponse `json:"response"`
+	}
+
+	// RecordedRequest is a recorded HTTP request. URL is the request URI without the host,
+	// e.g. "/api/v0/devices?type=os&query=ios", so cassettes can be replayed against any base URL.
+	RecordedRequest struct {
+		Method  string       `json:"method"`
+		URL     string       `json:"url"`
+		Headers http.Header  `json:"headers,omitempty"`
+		Body    RecordedBody `json:"body,omitempty"`
+	}
+
+	// RecordedResponse is a recorded HTTP response.
+	RecordedResponse struct {
+		StatusCode int          `json:"status_code"`
+		Headers    http.Header  `json:"headers,omitempty"`
+		Body       RecordedBody `json:"body,omitempty"`
+	}
+
+	// RecordedBody is a recorded body. JSON bodies are stored as is to keep cassettes readable,
+	// other bodies as a JSON string.
+	RecordedBody []byte
+
+	// cassetteConfig holds the cassette options until the HTTP client is known.
+	cassetteConfig struct {
+		path      string
+		record    bool
+		scrubKeys []string
+	}
+
+	// recorder is a transport saving every interaction to a cassette file.
+	recorder struct {
+		next      http.RoundTripper
+		path      string
+		scrubKeys map[string]bool
+
+		mu       sync.Mutex
+		cassette Cassette
+	}
+
+	// replayer is a transport serving the interactions of a cassette.
+	replayer struct {
+		scrubKeys map[string]bool
+
+		mu           sync.Mutex
+		interactions []Interaction
+		used         []bool
+	}
+)
+
+// WithRecorder records every request and response to a cassette file at path, overwriting
+// it. The X-Auth-Token header and the values of the scrub keys in JSON bodies are replaced
+// with Redacted; DefaultScrubKeys are used when no keys are given. Keys are matched case
+// insensitively at any depth.
+//
+// The recorder wraps the transport of the HTTP client, including one set by WithHTTPClient.
+func WithRecorder(path string, scrubKeys ...string) Option {
+	return func(c *Client) {
+		c.cassette = &cassetteConfig{path: path, record: true, scrubKeys: scrubKeys}
+	}
+}
+
+// WithReplayer serves the interactions recorded in a cassette file instead of sending
+// requests. Requests are matched by method, URL and body in the order they were recorded;
+// once all matching interactions were used, the last one is served again. Requests without
+// a recorded interaction fail. NewClient returns an error when the cassette can't be read.
+func WithReplayer(path string) Option {
+	return func(c *Client) {
+		c.cassette = &cassetteConfig{path: path}
+	}
+}
+
+// LoadCassette reads a cassette file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := new(Cassette)
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	return cassette, nil
+}
+
+// MarshalJSON implements the JSON marshaling for the RecordedBody type.
+func (b RecordedBody) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return []byte(`""`), nil
+	}
+	if json.Valid(b) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, b); err == nil {
+			return buf.Bytes(), nil
+		}
+	}
+	return json.Marshal(string(b))
+}
+
+// UnmarshalJSON implements the JSON unmarshalling for the RecordedBody type.
+func (b *RecordedBody) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = RecordedBody(s)
+		return nil
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+// setupCassette wraps the HTTP client transport with the configured recorder or replayer.
+// The client is copied so that a client passed to WithHTTPClient is not modified.
+func (c *Client) setupCassette() error {
+	if c.cassette == nil {
+		return nil
+	}
+	scrubKeys := c.cassette.scrubKeys
+	if len(scrubKeys) == 0 {
+		scrubKeys = DefaultScrubKeys
+	}
+	keys := make(map[string]bool, len(scrubKeys))
+	for _, key := range scrubKeys {
+		keys[strings.ToLower(key)] = true
+	}
+
+	httpClient := *c.client
+	if c.cassette.record {
+		next := httpClient.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		httpClient.Transport = &recorder{next: next, path: c.cassette.path, scrubKeys: keys}
+	} else {
+		cassette, err := LoadCassette(c.cassette.path)
+		if err != nil {
+			return fmt.Errorf("failed to load cassette: %w", err)
+		}
+		httpClient.Transport = &replayer{
+			scrubKeys:    keys,
+			interactions: cassette.Interactions,
+			used:         make([]bool, len(cassette.Interactions)),
+		}
+	}
+	c.client = &httpClient
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := resp.Header.Clone()
+	headers.Del("Content-Length")
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     req.URL.RequestURI(),
+			Headers: scrubHeaders(req.Header),
+			Body:    scrubBody(reqBody, r.scrubKeys),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    headers,
+			Body:       scrubBody(respBody, r.scrubKeys),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	body = scrubBody(body, r.scrubKeys)
+	uri := req.URL.RequestURI()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := -1
+	for i, interaction := range r.interactions {
+		recorded := interaction.Request
+		if recorded.Method != req.Method || recorded.URL != uri || !sameBody(recorded.Body, body) {
+			continue
+		}
+		last = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if last < 0 {
+		return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, uri)
+	}
+	r.used[last] = true
+
+	recorded := r.interactions[last].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// readBody reads a request or response body and replaces it with a copy.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	closeBody(*body)
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// scrubHeaders returns a copy of the headers with the API token redacted.
+func scrubHeaders(headers http.Header) http.Header {
+	scrubbed := headers.Clone()
+	if scrubbed.Get(authHeader) != "" {
+		scrubbed.Set(authHeader, Redacted)
+	}
+	return scrubbed
+}
+
+// scrubBody redacts the values of the scrub keys in a JSON body. Other bodies are returned
+// as is.
+func scrubBody(body []byte, keys map[string]bool) []byte {
+	if len(body) == 0 || len(keys) == 0 {
+		return body
+	}
+	var v any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if decoder.Decode(&v) != nil {
+		return body
+	}
+	if !scrubValue(v, keys) {
+		return body
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return data
+}
+
+// scrubValue redacts the scrub keys in a decoded JSON value and reports whether anything
+// was redacted. Null and empty values are kept.
+func scrubValue(v any, keys map[string]bool) bool {
+	scrubbed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if keys[strings.ToLower(key)] {
+				if value != nil && value != "" {
+					v[key] = Redacted
+					scrubbed = true
+				}
+				continue
+			}
+			scrubbed = scrubValue(value, keys) || scrubbed
+		}
+	case []any:
+		for _, value := range v {
+			scrubbed = scrubValue(value, keys) || scrubbed
+		}
+	}
+	return scrubbed
+}
+
+// sameBody reports whether two bodies are equal, comparing JSON bodies semantically.
+func sameBody(a, b []byte) bool {
+	if bytes.Equal(bytes.TrimSpace(a), bytes.TrimSpace(b)) {
+		return true
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	ja, errA := json.Marshal(va)
+	jb, errB := json.Marshal(vb)
+	return errors.Join(errA, errB) == nil && bytes.Equal(ja, jb)
+}
@@ -0,0 +1,92 @@
+package librenms_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	r := require.New(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	recording, err := librenms.New(testServer.URL+"/", "secret-token",
+		librenms.WithRecorder(path),
+		librenms.WithHTTPClient(&http.Client{Timeout: 5 * time.Second}))
+	r.NoError(err, "Failed to create recording client")
+
+	created, err := recording.Device.Create(&types.DeviceCreateRequest{Hostname: "192.168.10.5", OS: "linux", SNMPCommunity: "private"})
+	r.NoError(err, "Create returned an error")
+	_, err = recording.System.Get()
+	r.NoError(err, "System.Get returned an error")
+
+	data, err := os.ReadFile(path)
+	r.NoError(err, "Cassette should be written")
+	r.NotContains(string(data), "secret-token", "Token should be scrubbed")
+	r.NotContains(string(data), "private", "Request community should be scrubbed")
+	r.NotContains(string(data), `"community": "public"`, "Response community should be scrubbed")
+	r.Contains(string(data), librenms.Redacted, "Scrubbed values should be redacted")
+
+	cassette, err := librenms.LoadCassette(path)
+	r.NoError(err, "LoadCassette returned an error")
+	r.Len(cassette.Interactions, 2, "Expected an interaction per request")
+	r.Equal("/api/v0/devices/", cassette.Interactions[0].Request.URL, "URL should not include the host")
+
+	replaying, err := librenms.New("http://librenms.invalid/", "other-token", librenms.WithReplayer(path))
+	r.NoError(err, "Failed to create replaying client")
+	replayed, err := replaying.Device.Create(&types.DeviceCreateRequest{Hostname: "192.168.10.5", OS: "linux", SNMPCommunity: "anything"})
+	r.NoError(err, "Replayed Create returned an error")
+	r.Equal(created.Devices[0].DeviceID, replayed.Devices[0].DeviceID, "Expected the recorded device")
+	r.Equal(librenms.Redacted, string(replayed.Devices[0].Community), "Expected the scrubbed community")
+
+	for i := 0; i < 2; i++ {
+		system, err := replaying.System.Get()
+		r.NoError(err, "Repeated requests should replay the last interaction")
+		r.NotEmpty(system.System, "Expected the recorded system info")
+	}
+
+	_, err = replaying.Device.Create(&types.DeviceCreateRequest{Hostname: "other", OS: "linux"})
+	r.ErrorContains(err, "no recorded interaction for POST /api/v0/devices/", "Expected error for a different body")
+	_, err = replaying.Alert.List(nil)
+	r.ErrorContains(err, "no recorded interaction for GET /api/v0/alerts", "Expected error for unrecorded requests")
+}
+
+func TestCassette_ReplayErrors(t *testing.T) {
+	r := require.New(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err := w.Write(loadMockResponse("create_device_500.json"))
+		handleWriteErr(err, w)
+	}))
+	t.Cleanup(server.Close)
+
+	recording, err := librenms.New(server.URL+"/", "secret-token", librenms.WithRecorder(path, "message"))
+	r.NoError(err, "Failed to create recording client")
+	_, err = recording.Device.Create(&types.DeviceCreateRequest{Hostname: "compute-vm-2", OS: "linux"})
+	r.Error(err, "Expected the mocked API error")
+
+	data, err := os.ReadFile(path)
+	r.NoError(err, "Cassette should be written")
+	r.False(strings.Contains(string(data), "Could not ping"), "Custom scrub keys should be used")
+
+	replaying, err := librenms.New("http://librenms.invalid/", "token", librenms.WithReplayer(path))
+	r.NoError(err, "Failed to create replaying client")
+	_, err = replaying.Device.Create(&types.DeviceCreateRequest{Hostname: "compute-vm-2", OS: "linux"})
+	var apiErr *librenms.ErrorResponse
+	r.ErrorAs(err, &apiErr, "Expected a replayed API error")
+	r.Equal(http.StatusInternalServerError, apiErr.Response.StatusCode, "Unexpected status code")
+	r.Equal(librenms.Redacted, apiErr.Message, "Expected the scrubbed message")
+
+	_, err = librenms.New("http://librenms.invalid/", "token", librenms.WithReplayer(filepath.Join(t.TempDir(), "missing.json")))
+	r.ErrorContains(err, "failed to load cassette", "Expected error for a missing cassette")
+}
@@ -0,0 +1,731 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// Columns shown by the table and CSV outputs.
+var (
+	deviceColumns   = []string{"device_id", "hostname", "sysName", "ip", "os", "version", "location", "status"}
+	portColumns     = []string{"port_id", "device_id", "ifName", "ifAlias", "ifOperStatus", "ifAdminStatus", "ifSpeed"}
+	alertColumns    = []string{"id", "hostname", "name", "severity", "state", "timestamp"}
+	ruleColumns     = []string{"id", "name", "severity", "disabled", "builder"}
+	groupColumns    = []string{"id", "name", "type", "desc"}
+	locationColumns = []string{"id", "location", "lat", "lng"}
+	serviceColumns  = []string{"service_id", "device_id", "service_type", "service_name", "service_status", "service_message"}
+	bgpColumns      = []string{"bgpPeer_id", "hostname", "bgpPeerIdentifier", "bgpPeerRemoteAs", "bgpPeerState", "bgpPeerAdminStatus", "bgpPeerDescr"}
+	vlanColumns     = []string{"device_id", "vlan_vlan", "vlan_name", "vlan_type", "vlan_state"}
+	fdbColumns      = []string{"hostname", "sysName", "ifName", "ifAlias", "last_seen"}
+	logColumns      = []string{"datetime", "hostname", "type", "message"}
+)
+
+// logKinds maps the logs tail -kind values to the API methods.
+var logKinds = map[string]func(*librenms.LogsAPI, string, *types.LogsQuery) (*types.LogsResponse, error){
+	"eventlog": (*librenms.LogsAPI).ListEventLogs,
+	"syslog":   (*librenms.LogsAPI).ListSysLogs,
+	"alertlog": (*librenms.LogsAPI).ListAlertLogs,
+	"authlog":  (*librenms.LogsAPI).ListAuthLogs,
+}
+
+// root returns the command tree.
+func root() *command {
+	return &command{subcommands: []*command{
+		{name: "devices", summary: "Manage devices", run: devicesList, subcommands: []*command{
+			{name: "list", summary: "List devices", run: devicesList},
+			{name: "get", args: "<device>", summary: "Show a device", run: devicesGet},
+			{name: "add", args: "<hostname>", summary: "Add a device", run: devicesAdd},
+			{name: "rm", args: "<device>", summary: "Remove a device", run: devicesRemove},
+			{name: "rename", args: "<device> <new-hostname>", summary: "Rename a device", run: devicesRename},
+			{name: "discover", args: "<device>", summary: "Trigger a discovery of a device", run: devicesDiscover},
+		}},
+		{name: "ports", summary: "Search ports", subcommands: []*command{
+			{name: "search", args: "<text>", summary: "Search ports by ifAlias, ifDescr and ifName", run: portsSearch},
+		}},
+		{name: "alerts", summary: "Manage alerts", run: alertsList, subcommands: []*command{
+			{name: "list", summary: "List alerts", run: alertsList},
+			{name: "ack", args: "<alert-id>", summary: "Acknowledge an alert", run: alertsAck},
+			{name: "unmute", args: "<alert-id>", summary: "Unmute an alert", run: alertsUnmute},
+		}},
+		{name: "rules", summary: "Manage alert rules", run: rulesList, subcommands: []*command{
+			{name: "list", summary: "List alert rules", run: rulesList},
+			{name: "get", args: "<rule-id>", summary: "Show an alert rule", run: rulesGet},
+			{name: "rm", args: "<rule-id>", summary: "Remove an alert rule", run: rulesRemove},
+		}},
+		{name: "groups", summary: "List device groups", run: groupsList, subcommands: []*command{
+			{name: "list", summary: "List device groups", run: groupsList},
+			{name: "members", args: "<group>", summary: "List the devices of a group", run: groupsMembers},
+		}},
+		{name: "locations", summary: "List locations", run: locationsList, subcommands: []*command{
+			{name: "list", summary: "List locations", run: locationsList},
+		}},
+		{name: "services", summary: "List services", run: servicesList, subcommands: []*command{
+			{name: "list", summary: "List services", run: servicesList},
+		}},
+		{name: "logs", summary: "Read logs", subcommands: []*command{
+			{name: "tail", args: "<device>", summary: "Show the latest log entries of a device", run: logsTail},
+		}},
+		{name: "bgp", summary: "List BGP sessions", run: bgpList, subcommands: []*command{
+			{name: "list", summary: "List BGP sessions", run: bgpList},
+		}},
+		{name: "vlans", summary: "List VLANs", run: vlansList, subcommands: []*command{
+			{name: "list", summary: "List VLANs", run: vlansList},
+		}},
+		{name: "fdb", summary: "Query forwarding databases", subcommands: []*command{
+			{name: "lookup", args: "<mac>", summary: "Find the ports a MAC address was learnt on", run: fdbLookup},
+		}},
+		{name: "context", summary: "Manage configuration contexts", run: contextList, subcommands: []*command{
+			{name: "list", summary: "List contexts", run: contextList},
+			{name: "use", args: "<name>", summary: "Set the current context", run: contextUse},
+			{name: "set", args: "<name>", summary: "Add or update a context", run: contextSet},
+			{name: "rm", args: "<name>", summary: "Remove a context", run: contextRemove},
+		}},
+	}}
+}
+
+func devicesList(a *app, args []string) error {
+	fs := a.flags("devices list")
+	query := new(types.DevicesQuery)
+	fs.StringVar(&query.Type, "type", "", "filter `type`, e.g. up, down, disabled, os, location")
+	fs.StringVar(&query.OS, "os", "", "filter by `os`")
+	fs.StringVar(&query.Location, "location", "", "filter by `location`")
+	fs.StringVar(&query.Hostname, "hostname", "", "filter by `hostname`")
+	fs.StringVar(&query.Order, "order", "", "sort `column`, e.g. hostname or \"hostname DESC\"")
+	if _, err := a.parse(fs, args, 0, "[flags]"); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Device.List(query)
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.Devices, deviceColumns...)
+}
+
+func devicesGet(a *app, args []string) error {
+	args, err := a.parse(a.flags("devices get"), args, 1, "<device>")
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Device.Get(args[0])
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.Devices, deviceColumns...)
+}
+
+func devicesAdd(a *app, args []string) error {
+	fs := a.flags("devices add")
+	req := new(types.DeviceCreateRequest)
+	fs.StringVar(&req.OS, "os", "", "device `os`, detected by LibreNMS when empty")
+	fs.StringVar(&req.SNMPVersion, "snmp-version", "v2c", "SNMP `version`: v1, v2c or v3")
+	fs.StringVar(&req.SNMPCommunity, "community", "", "SNMP `community`")
+	fs.IntVar(&req.Port, "port", 0, "SNMP `port`")
+	fs.StringVar(&req.Transport, "transport", "", "SNMP `transport`, e.g. udp or tcp")
+	fs.StringVar(&req.SNMPAuthLevel, "auth-level", "", "SNMPv3 auth `level`: noAuthNoPriv, authNoPriv or authPriv")
+	fs.StringVar(&req.SNMPAuthName, "auth-name", "", "SNMPv3 auth `user`")
+	fs.StringVar(&req.SNMPAuthPass, "auth-pass", "", "SNMPv3 auth `password`")
+	fs.StringVar(&req.SNMPAuthAlgo, "auth-algo", "", "SNMPv3 auth `algorithm`")
+	fs.StringVar(&req.SNMPCryptoPass, "crypto-pass", "", "SNMPv3 privacy `password`")
+	fs.StringVar(&req.SNMPCrytoAlgo, "crypto-algo", "", "SNMPv3 privacy `algorithm`")
+	fs.StringVar(&req.Display, "display", "", "display `name`")
+	fs.StringVar(&req.Location, "location", "", "`location` name")
+	fs.BoolVar(&req.ForceAdd, "force", false, "add the device without checking it's reachable")
+	fs.BoolVar(&req.PingFallback, "ping-fallback", false, "add the device as ping only when SNMP fails")
+	fs.BoolVar(&req.SNMPDisable, "snmp-disable", false, "add the device as ping only")
+	args, err := a.parse(fs, args, 1, "<hostname> [flags]")
+	if err != nil {
+		return err
+	}
+	req.Hostname = args[0]
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Device.Create(req)
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.Devices, deviceColumns...)
+}
+
+func devicesRemove(a *app, args []string) error {
+	args, err := a.parse(a.flags("devices rm"), args, 1, "<device>")
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Device.Delete(args[0])
+	if err != nil {
+		return err
+	}
+	return a.printer().message(resp, resp.Message)
+}
+
+func devicesRename(a *app, args []string) error {
+	args, err := a.parse(a.flags("devices rename"), args, 2, "<device> <new-hostname>")
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Device.RenameDevice(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	return a.printer().message(resp, resp.Message)
+}
+
+func devicesDiscover(a *app, args []string) error {
+	args, err := a.parse(a.flags("devices discover"), args, 1, "<device>")
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Device.Discover(args[0])
+	if err != nil {
+		return err
+	}
+	return a.printer().message(resp, resp.Message)
+}
+
+func portsSearch(a *app, args []string) error {
+	fs := a.flags("ports search")
+	field := fs.String("field", "", "comma separated `fields` to search, default ifAlias, ifDescr and ifName")
+	columns := fs.String("columns", strings.Join(portColumns, ","), "comma separated `columns` to request")
+	args, err := a.parse(fs, args, 1, "<text> [flags]")
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	selected, err := types.NewColumns[types.Port](*columns)
+	if err != nil {
+		return err
+	}
+	params := &types.PortsQueryParams{Columns: selected.String()}
+	var resp *types.PortsResponse
+	if *field != "" {
+		resp, err = client.Port.SearchPortsInField(*field, args[0], params)
+	} else {
+		resp, err = client.Port.SearchPorts(args[0], params)
+	}
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.Ports, strings.Split(*columns, ",")...)
+}
+
+func alertsList(a *app, args []string) error {
+	fs := a.flags("alerts list")
+	query := types.NewAlertsQuery()
+	fs.IntVar(&query.State, "state", 0, "filter by `state`: 1=alert, 2=acknowledged, 0 for the API default")
+	fs.StringVar(&query.Severity, "severity", "", "filter by `severity`: ok, warning or critical")
+	fs.IntVar(&query.RuleID, "rule", 0, "filter by rule `id`")
+	fs.StringVar(&query.Order, "order", "", "sort order, e.g. \"timestamp DESC\"")
+	if _, err := a.parse(fs, args, 0, "[flags]"); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Alert.List(query)
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.Alerts, alertColumns...)
+}
+
+func alertsAck(a *app, args []string) error {
+	fs := a.flags("alerts ack")
+	req := new(types.AlertAckRequest)
+	fs.StringVar(&req.Note, "note", "", "acknowledgement `note`")
+	fs.BoolVar(&req.UntilClear, "until-clear", false, "keep the alert acknowledged until it clears, even if it changes")
+	args, err := a.parse(fs, args, 1, "<alert-id> [flags]")
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid alert ID %q", args[0])
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Alert.Ack(id, req)
+	if err != nil {
+		return err
+	}
+	return a.printer().message(resp, resp.Message)
+}
+
+func alertsUnmute(a *app, args []string) error {
+	args, err := a.parse(a.flags("alerts unmute"), args, 1, "<alert-id>")
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid alert ID %q", args[0])
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Alert.UnmuteAlert(id)
+	if err != nil {
+		return err
+	}
+	return a.printer().message(resp, resp.Message)
+}
+
+func rulesList(a *app, args []string) error {
+	if _, err := a.parse(a.flags("rules list"), args, 0, "[flags]"); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.AlertRule.List()
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.Rules, ruleColumns...)
+}
+
+func rulesGet(a *app, args []string) error {
+	args, err := a.parse(a.flags("rules get"), args, 1, "<rule-id>")
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid rule ID %q", args[0])
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.AlertRule.Get(id)
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.Rules, append(ruleColumns, "rule")...)
+}
+
+func rulesRemove(a *app, args []string) error {
+	args, err := a.parse(a.flags("rules rm"), args, 1, "<rule-id>")
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid rule ID %q", args[0])
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.AlertRule.Delete(id)
+	if err != nil {
+		return err
+	}
+	return a.printer().message(resp, resp.Message)
+}
+
+func groupsList(a *app, args []string) error {
+	if _, err := a.parse(a.flags("groups list"), args, 0, "[flags]"); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.DeviceGroup.List()
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.Groups, groupColumns...)
+}
+
+func groupsMembers(a *app, args []string) error {
+	args, err := a.parse(a.flags("groups members"), args, 1, "<group>")
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.DeviceGroup.GetMembers(args[0])
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.Devices, "device_id")
+}
+
+func locationsList(a *app, args []string) error {
+	if _, err := a.parse(a.flags("locations list"), args, 0, "[flags]"); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Location.List()
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.Locations, locationColumns...)
+}
+
+func servicesList(a *app, args []string) error {
+	fs := a.flags("services list")
+	host := fs.String("host", "", "list the services of a `device`")
+	if _, err := a.parse(fs, args, 0, "[flags]"); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	var resp *types.ServiceResponse
+	if *host != "" {
+		resp, err = client.Service.GetForHost(*host)
+	} else {
+		resp, err = client.Service.List()
+	}
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.Services, serviceColumns...)
+}
+
+// logsTail prints the latest entries of a device log and, with -f, polls for new ones until
+// interrupted.
+func logsTail(a *app, args []string) error {
+	fs := a.flags("logs tail")
+	kind := fs.String("kind", "eventlog", "log `kind`: eventlog, syslog, alertlog or authlog")
+	lines := fs.Int("n", 20, "number of entries to show")
+	follow := fs.Bool("f", false, "keep polling for new entries")
+	interval := fs.Duration("interval", 10*time.Second, "polling `interval` with -f")
+	args, err := a.parse(fs, args, 1, "<device> [flags]")
+	if err != nil {
+		return err
+	}
+	list, ok := logKinds[*kind]
+	if !ok {
+		return fmt.Errorf("unknown log kind %q, expected eventlog, syslog, alertlog or authlog", *kind)
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	tail := &logTail{printer: a.printer(), seen: make(map[string]bool)}
+	query := &types.LogsQuery{Limit: *lines, SortOrder: "DESC"}
+	resp, err := list(client.Logs, args[0], query)
+	if err != nil {
+		return err
+	}
+	if err := tail.write(resp.Logs, *lines); err != nil {
+		return err
+	}
+	if !*follow {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		resp, err := list(client.Logs, args[0], &types.LogsQuery{From: tail.from(), SortOrder: "ASC"})
+		if err != nil {
+			fmt.Fprintln(a.stderr, "Error:", err)
+			continue
+		}
+		if err := tail.write(resp.Logs, 0); err != nil {
+			return err
+		}
+	}
+}
+
+// logTail writes log entries as lines, skipping the entries already written.
+type logTail struct {
+	printer *printer
+	seen    map[string]bool
+	last    types.Time
+	header  bool
+}
+
+// write writes the new entries in chronological order, the last n ones when n > 0.
+func (t *logTail) write(entries []types.Log, n int) error {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].DateTime.Before(entries[j].DateTime.Time) })
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	for _, entry := range entries {
+		key := fmt.Sprint(entry.EventID, entry.DateTime, entry.Message)
+		if t.seen[key] {
+			continue
+		}
+		t.seen[key] = true
+		if entry.DateTime.After(t.last.Time) {
+			t.last = entry.DateTime
+		}
+		if err := t.writeEntry(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// from returns the From parameter of the next poll: the time of the last written entry.
+func (t *logTail) from() string {
+	if t.last.IsZero() {
+		return ""
+	}
+	return t.last.Format(types.TimeLayout)
+}
+
+// writeEntry writes an entry as a line: JSON for the JSON and YAML outputs, CSV, or the
+// space separated columns.
+func (t *logTail) writeEntry(entry types.Log) error {
+	switch t.printer.format {
+	case formatJSON, formatYAML:
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(t.printer.w, string(data))
+		return err
+	case formatCSV:
+		w := csv.NewWriter(t.printer.w)
+		if !t.header {
+			t.header = true
+			_ = w.Write(logColumns)
+		}
+		_ = w.Write([]string{entry.DateTime.Format(types.TimeLayout), string(entry.Hostname), string(entry.Type), string(entry.Message)})
+		w.Flush()
+		return w.Error()
+	}
+	_, err := fmt.Fprintf(t.printer.w, "%s  %s  %s\n", entry.DateTime.Format(types.TimeLayout), entry.Hostname, strings.TrimSpace(string(entry.Message)))
+	return err
+}
+
+// bgpSession is a BGP session with the hostname of its device.
+type bgpSession struct {
+	Hostname string `json:"hostname"`
+	types.BGPSession
+}
+
+func bgpList(a *app, args []string) error {
+	fs := a.flags("bgp list")
+	host := fs.String("host", "", "list the sessions of a `device`")
+	state := fs.String("state", "", "filter by `state`, e.g. established or idle")
+	if _, err := a.parse(fs, args, 0, "[flags]"); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Routing.ListBGP(&types.BGPQuery{Hostname: *host, BGPState: *state})
+	if err != nil {
+		return err
+	}
+	devices, err := client.Device.List(nil)
+	if err != nil {
+		return err
+	}
+	hostnames := make(map[types.Int]string, len(devices.Devices))
+	for _, device := range devices.Devices {
+		hostnames[device.DeviceID] = string(device.Hostname)
+	}
+
+	sessions := make([]bgpSession, 0, len(resp.BGPSessions))
+	for _, session := range resp.BGPSessions {
+		if *state != "" && !strings.EqualFold(string(session.BGPPeerState), *state) {
+			continue
+		}
+		sessions = append(sessions, bgpSession{Hostname: hostnames[session.DeviceID], BGPSession: session})
+	}
+	return a.printer().print(sessions, bgpColumns...)
+}
+
+func vlansList(a *app, args []string) error {
+	fs := a.flags("vlans list")
+	host := fs.String("host", "", "list the VLANs of a `device`")
+	if _, err := a.parse(fs, args, 0, "[flags]"); err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	var resp *types.VLANsResponse
+	if *host != "" {
+		resp, err = client.Switching.GetDeviceVLANs(*host, nil)
+	} else {
+		resp, err = client.Switching.GetAllVLANs(nil)
+	}
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.VLANs, vlanColumns...)
+}
+
+func fdbLookup(a *app, args []string) error {
+	args, err := a.parse(a.flags("fdb lookup"), args, 1, "<mac>")
+	if err != nil {
+		return err
+	}
+	mac, err := types.ParseMAC(args[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Switching.GetPortFDBDetail(mac.Text(types.MACBare), nil)
+	if err != nil {
+		return err
+	}
+	return a.printer().print(resp.PortsFDB, fdbColumns...)
+}
+
+// contextEntry is a context as listed by context list. Tokens are not shown.
+type contextEntry struct {
+	Current bool   `json:"current"`
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+}
+
+func contextList(a *app, args []string) error {
+	if _, err := a.parse(a.flags("context list"), args, 0, "[flags]"); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return err
+	}
+	entries := make([]contextEntry, 0, len(cfg.Contexts))
+	for _, name := range cfg.names() {
+		entries = append(entries, contextEntry{Current: name == cfg.CurrentContext, Name: name, URL: cfg.Contexts[name].URL})
+	}
+	return a.printer().print(entries, "current", "name", "url")
+}
+
+func contextUse(a *app, args []string) error {
+	args, err := a.parse(a.flags("context use"), args, 1, "<name>")
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Contexts[args[0]]; !ok {
+		return fmt.Errorf("unknown context %q", args[0])
+	}
+	cfg.CurrentContext = args[0]
+	if err := cfg.save(a.configPath); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "Switched to context %q.\n", args[0])
+	return nil
+}
+
+func contextSet(a *app, args []string) error {
+	fs := a.flags("context set")
+	url := fs.String("url", "", "LibreNMS base `URL`")
+	token := fs.String("token", "", "API `token`")
+	args, err := a.parse(fs, args, 1, "<name> -url <url> -token <token>")
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return err
+	}
+	ctx := cfg.Contexts[args[0]]
+	if *url != "" {
+		ctx.URL = *url
+	}
+	if *token != "" {
+		ctx.Token = *token
+	}
+	if ctx.URL == "" || ctx.Token == "" {
+		return fmt.Errorf("context %q needs a URL and a token", args[0])
+	}
+	cfg.Contexts[args[0]] = ctx
+	if cfg.CurrentContext == "" {
+		cfg.CurrentContext = args[0]
+	}
+	if err := cfg.save(a.configPath); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "Context %q saved to %s.\n", args[0], a.configPath)
+	return nil
+}
+
+func contextRemove(a *app, args []string) error {
+	args, err := a.parse(a.flags("context rm"), args, 1, "<name>")
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Contexts[args[0]]; !ok {
+		return fmt.Errorf("unknown context %q", args[0])
+	}
+	delete(cfg.Contexts, args[0])
+	if cfg.CurrentContext == args[0] {
+		cfg.CurrentContext = ""
+	}
+	if err := cfg.save(a.configPath); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "Context %q removed.\n", args[0])
+	return nil
+}
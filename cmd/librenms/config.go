@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// config is the configuration file, holding named contexts:
+	//
+	//	current-context: prod
+	//	contexts:
+	//	  prod:
+	//	    url: https://librenms.example.net/
+	//	    token: 0123456789abcdef
+	config struct {
+		CurrentContext string                   `yaml:"current-context,omitempty"`
+		Contexts       map[string]contextConfig `yaml:"contexts,omitempty"`
+	}
+
+	// contextConfig is a LibreNMS server and the token to access it.
+	contextConfig struct {
+		URL   string `yaml:"url"`
+		Token string `yaml:"token"`
+	}
+)
+
+// defaultConfigPath returns the default path of the configuration file,
+// $XDG_CONFIG_HOME/librenms/config.yaml or its platform equivalent.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "librenms.yaml"
+	}
+	return filepath.Join(dir, "librenms", "config.yaml")
+}
+
+// loadConfig reads the configuration file. A missing file is an empty configuration.
+func loadConfig(path string) (*config, error) {
+	cfg := &config{Contexts: make(map[string]contextConfig)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.Contexts == nil {
+		cfg.Contexts = make(map[string]contextConfig)
+	}
+	return cfg, nil
+}
+
+// save writes the configuration file, readable by the user only as it holds tokens.
+func (c *config) save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// names returns the sorted context names.
+func (c *config) names() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,240 @@
+// Command librenms is a command-line client for the LibreNMS API.
+//
+// The server is selected, in order, with the -url and -token flags, the context named by
+// -context, the LIBRENMS_URL and LIBRENMS_TOKEN environment variables, or the context named
+// by $LIBRENMS_CONTEXT or current-context in the configuration file (-config,
+// $LIBRENMS_CONFIG, default $XDG_CONFIG_HOME/librenms/config.yaml). The URL and the token
+// always come from the same place:
+//
+//	librenms context set prod -url https://librenms.example.net/ -token 0123456789abcdef
+//	librenms devices list -os iosxe
+//	librenms -context lab alerts list -o json
+//
+// Run librenms help for the list of commands.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/javen-yan/librenms-go"
+)
+
+type (
+	// app holds the global options and the state of a command run.
+	app struct {
+		stdout io.Writer
+		stderr io.Writer
+		getenv func(string) string
+
+		configPath  string
+		contextName string
+		envContext  string
+		url         string
+		token       string
+		output      string
+
+		// newClient creates the API client, replaced in tests.
+		newClient func(url, token string) (*librenms.Client, error)
+		client    *librenms.Client
+	}
+
+	// command is a command or a group of subcommands. A group with a run function runs it
+	// when no subcommand is given.
+	command struct {
+		name        string
+		args        string
+		summary     string
+		run         func(a *app, args []string) error
+		subcommands []*command
+	}
+)
+
+// errUsage is returned for invalid command lines, after the usage was printed.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr, os.Getenv); err != nil {
+		if !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run runs the command line args.
+func run(args []string, stdout, stderr io.Writer, getenv func(string) string) error {
+	a := &app{
+		stdout:    stdout,
+		stderr:    stderr,
+		getenv:    getenv,
+		output:    formatTable,
+		newClient: newClient,
+	}
+	a.configPath = getenv("LIBRENMS_CONFIG")
+	if a.configPath == "" {
+		a.configPath = defaultConfigPath()
+	}
+	a.envContext = getenv("LIBRENMS_CONTEXT")
+
+	fs := a.flags("librenms")
+	fs.StringVar(&a.configPath, "config", a.configPath, "configuration `file`")
+	fs.StringVar(&a.url, "url", "", "LibreNMS base URL, overrides -context and $LIBRENMS_URL, requires -token")
+	fs.StringVar(&a.token, "token", "", "API token, overrides -context and $LIBRENMS_TOKEN, requires -url")
+	fs.Usage = func() { a.usage(fs, root(), nil) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return a.dispatch(root(), nil, fs.Args())
+}
+
+// newClient creates a client with the default options.
+func newClient(url, token string) (*librenms.Client, error) {
+	return librenms.NewClient(url, token)
+}
+
+// dispatch runs the subcommand of cmd named by the first argument.
+func (a *app) dispatch(cmd *command, path []string, args []string) error {
+	if len(cmd.subcommands) == 0 {
+		return cmd.run(a, args)
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if cmd.run != nil {
+			return cmd.run(a, args)
+		}
+		a.usage(nil, cmd, path)
+		return errUsage
+	}
+	for _, sub := range cmd.subcommands {
+		if sub.name == args[0] {
+			return a.dispatch(sub, append(path, sub.name), args[1:])
+		}
+	}
+	if args[0] == "help" {
+		a.usage(nil, cmd, path)
+		return nil
+	}
+	fmt.Fprintf(a.stderr, "Unknown command %q.\n\n", strings.Join(append(path, args[0]), " "))
+	a.usage(nil, cmd, path)
+	return errUsage
+}
+
+// usage prints the flags and subcommands of cmd.
+func (a *app) usage(fs *flag.FlagSet, cmd *command, path []string) {
+	name := strings.Join(append([]string{"librenms"}, path...), " ")
+	fmt.Fprintf(a.stderr, "Usage: %s [flags] <command>\n", name)
+	if fs != nil {
+		fmt.Fprintln(a.stderr, "\nFlags:")
+		fs.SetOutput(a.stderr)
+		fs.PrintDefaults()
+	}
+	fmt.Fprintln(a.stderr, "\nCommands:")
+	for _, sub := range cmd.subcommands {
+		fmt.Fprintf(a.stderr, "  %-30s %s\n", strings.TrimSpace(sub.name+" "+sub.args), sub.summary)
+	}
+}
+
+// flags returns a flag set with the flags shared by all commands.
+func (a *app) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.output, "o", a.output, "output `format`: table, json, yaml or csv")
+	fs.StringVar(&a.contextName, "context", a.contextName, "configuration context to use, overrides $LIBRENMS_URL and $LIBRENMS_TOKEN")
+	return fs
+}
+
+// parse parses the flags of a command, which may follow its arguments, and returns the
+// arguments. With n >= 0, exactly n arguments are expected.
+func (a *app) parse(fs *flag.FlagSet, args []string, n int, usage string) ([]string, error) {
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: librenms %s %s\n", fs.Name(), usage)
+		fs.PrintDefaults()
+	}
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if n >= 0 && len(positional) != n {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, checkFormat(a.output)
+}
+
+// api returns the client of the selected server.
+func (a *app) api() (*librenms.Client, error) {
+	if a.client != nil {
+		return a.client, nil
+	}
+	url, token, err := a.server()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := a.newClient(url, token)
+	if err != nil {
+		return nil, err
+	}
+	a.client = client
+	return client, nil
+}
+
+// server returns the URL and token of the selected server: the flags, the context named by
+// -context, the environment variables, then the context named by $LIBRENMS_CONTEXT or the
+// current context. The URL and the token are never taken from different places.
+func (a *app) server() (url, token string, err error) {
+	if a.url != "" || a.token != "" {
+		if a.url == "" || a.token == "" {
+			return "", "", errors.New("-url and -token must be set together")
+		}
+		return a.url, a.token, nil
+	}
+	if a.contextName != "" {
+		return a.contextServer(a.contextName)
+	}
+	url, token = a.getenv("LIBRENMS_URL"), a.getenv("LIBRENMS_TOKEN")
+	if url != "" || token != "" {
+		if url == "" || token == "" {
+			return "", "", errors.New("LIBRENMS_URL and LIBRENMS_TOKEN must be set together")
+		}
+		return url, token, nil
+	}
+	return a.contextServer(a.envContext)
+}
+
+// contextServer returns the URL and token of the named context, or of the current context when
+// name is empty.
+func (a *app) contextServer(name string) (url, token string, err error) {
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return "", "", err
+	}
+	if name == "" {
+		name = cfg.CurrentContext
+	}
+	if name == "" {
+		return "", "", errors.New("no LibreNMS server configured: set -url and -token, LIBRENMS_URL and LIBRENMS_TOKEN, or a context with librenms context set")
+	}
+	ctx, ok := cfg.Contexts[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown context %q in %s", name, a.configPath)
+	}
+	return ctx.URL, ctx.Token, nil
+}
+
+// printer returns a printer for the selected output format.
+func (a *app) printer() *printer {
+	return &printer{w: a.stdout, format: a.output}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// runCLI runs a command line against srv and returns its outputs.
+func runCLI(t *testing.T, srv *librenmstest.Server, env map[string]string, args ...string) (string, string, error) {
+	t.Helper()
+	if srv != nil {
+		args = append([]string{"-url", srv.URL(), "-token", librenmstest.Token}, args...)
+	}
+	var stdout, stderr bytes.Buffer
+	err := run(args, &stdout, &stderr, func(key string) string { return env[key] })
+	return stdout.String(), stderr.String(), err
+}
+
+func seed(srv *librenmstest.Server) {
+	srv.AddDevice(types.Device{Hostname: "core1", SysName: "core1.example.net", OS: "iosxe", Location: "DC1", Status: true})
+	srv.AddDevice(types.Device{Hostname: "core2", SysName: "core2.example.net", OS: "junos", Location: "DC2"})
+}
+
+func TestDevices(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	seed(srv)
+
+	stdout, _, err := runCLI(t, srv, nil, "devices", "list")
+	r.NoError(err, "devices list returned an error")
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	r.Len(lines, 3, "Expected a header and a line per device")
+	r.Regexp(`^DEVICE_ID\s+HOSTNAME\s+SYSNAME`, lines[0], "Unexpected table header")
+	r.Regexp(`^1\s+core1\s+core1\.example\.net`, lines[1], "Unexpected table row")
+
+	stdout, _, err = runCLI(t, srv, nil, "devices", "get", "core2", "-o", "json")
+	r.NoError(err, "devices get returned an error")
+	var devices []types.Device
+	r.NoError(json.Unmarshal([]byte(stdout), &devices), "Expected JSON output")
+	r.Len(devices, 1, "Expected a single device")
+	r.Equal("junos", string(devices[0].OS), "Unexpected device")
+
+	stdout, _, err = runCLI(t, srv, nil, "-o", "yaml", "devices")
+	r.NoError(err, "devices returned an error")
+	var generic []map[string]any
+	r.NoError(yaml.Unmarshal([]byte(stdout), &generic), "Expected YAML output")
+	r.Len(generic, 2, "Expected all devices")
+	r.Equal("core2", generic[1]["hostname"], "Unexpected YAML fields")
+
+	stdout, _, err = runCLI(t, srv, nil, "devices", "add", "edge1", "-community", "public", "-force")
+	r.NoError(err, "devices add returned an error")
+	r.Contains(stdout, "edge1", "Expected the added device")
+	_, ok := srv.Device("edge1")
+	r.True(ok, "Expected the device to be created")
+
+	stdout, _, err = runCLI(t, srv, nil, "devices", "rename", "edge1", "edge2")
+	r.NoError(err, "devices rename returned an error")
+	r.NotEmpty(stdout, "Expected the API message")
+	_, ok = srv.Device("edge2")
+	r.True(ok, "Expected the device to be renamed")
+
+	stdout, _, err = runCLI(t, srv, nil, "devices", "list", "-o", "csv")
+	r.NoError(err, "devices list returned an error")
+	r.True(strings.HasPrefix(stdout, "device_id,hostname,sysName,"), "Expected a CSV header")
+	r.Contains(stdout, "\n3,edge2,", "Expected the renamed device")
+
+	_, _, err = runCLI(t, srv, nil, "devices", "get")
+	r.ErrorIs(err, errUsage, "Expected a usage error for a missing argument")
+	_, _, err = runCLI(t, srv, nil, "devices", "list", "-o", "xml")
+	r.ErrorContains(err, `unknown output format "xml"`, "Expected an error for unknown formats")
+	_, stderr, err := runCLI(t, srv, nil, "devices", "reboot")
+	r.ErrorIs(err, errUsage, "Expected a usage error for an unknown command")
+	r.Contains(stderr, `Unknown command "devices reboot"`, "Expected the unknown command")
+}
+
+func TestAlertsAndBGP(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	seed(srv)
+	alert := srv.AddAlert(types.Alert{DeviceID: 2, RuleID: 1, Name: "Device Down", Severity: "critical", State: 1})
+	srv.AddBGPSession(types.BGPSession{DeviceID: 1, BGPPeerIdentifier: "10.0.0.2", BGPPeerRemoteAS: 65002, BGPPeerState: "established"})
+	srv.AddBGPSession(types.BGPSession{DeviceID: 2, BGPPeerIdentifier: "10.0.0.1", BGPPeerRemoteAS: 65001, BGPPeerState: "idle"})
+
+	_, _, err := runCLI(t, srv, nil, "alerts", "ack", "1", "-note", "on it")
+	r.NoError(err, "alerts ack returned an error")
+	r.EqualValues(2, srv.Alerts()[0].State, "Expected the alert to be acknowledged")
+	r.Equal(alert.ID, srv.Alerts()[0].ID, "Unexpected alert")
+
+	_, _, err = runCLI(t, srv, nil, "alerts", "ack", "one")
+	r.ErrorContains(err, `invalid alert ID "one"`, "Expected an error for invalid IDs")
+
+	stdout, _, err := runCLI(t, srv, nil, "bgp", "-state", "idle")
+	r.NoError(err, "bgp returned an error")
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	r.Len(lines, 2, "Expected the idle session only")
+	r.Regexp(`^2\s+core2\s+10\.0\.0\.1\s+65001\s+idle`, lines[1], "Expected the session with its hostname")
+}
+
+func TestLogsTail(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	seed(srv)
+	at := func(minute int) types.Time {
+		return types.NewTime(time.Date(2024, 5, 1, 10, minute, 0, 0, time.UTC))
+	}
+	for _, entry := range []types.Log{
+		{DeviceID: 1, DateTime: at(0), Message: "first"},
+		{DeviceID: 1, DateTime: at(2), Message: "third"},
+		{DeviceID: 1, DateTime: at(1), Message: "second"},
+		{DeviceID: 2, DateTime: at(3), Message: "other device"},
+	} {
+		srv.AddLog(librenmstest.EventLog, entry)
+	}
+
+	stdout, _, err := runCLI(t, srv, nil, "logs", "tail", "core1", "-n", "2")
+	r.NoError(err, "logs tail returned an error")
+	r.Equal("2024-05-01 10:01:00  core1  second\n2024-05-01 10:02:00  core1  third\n", stdout,
+		"Expected the last entries in chronological order")
+
+	_, _, err = runCLI(t, srv, nil, "logs", "tail", "core1", "-kind", "kernel")
+	r.ErrorContains(err, `unknown log kind "kernel"`, "Expected an error for unknown kinds")
+}
+
+func TestContexts(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	seed(srv)
+	env := map[string]string{"LIBRENMS_CONFIG": filepath.Join(t.TempDir(), "librenms", "config.yaml")}
+
+	_, _, err := runCLI(t, nil, env, "devices")
+	r.ErrorContains(err, "no LibreNMS server configured", "Expected an error without a server")
+
+	_, _, err = runCLI(t, nil, env, "context", "set", "lab", "-url", srv.URL(), "-token", librenmstest.Token)
+	r.NoError(err, "context set returned an error")
+	_, _, err = runCLI(t, nil, env, "context", "set", "prod", "-url", "http://127.0.0.1:1/", "-token", "secret")
+	r.NoError(err, "context set returned an error")
+
+	stdout, _, err := runCLI(t, nil, env, "context", "list", "-o", "csv")
+	r.NoError(err, "context list returned an error")
+	r.Equal("current,name,url\ntrue,lab,"+srv.URL()+"\nfalse,prod,http://127.0.0.1:1/\n", stdout,
+		"Expected the contexts, the first one being current")
+	r.NotContains(stdout, "secret", "Expected tokens to be hidden")
+
+	stdout, _, err = runCLI(t, nil, env, "devices", "get", "core1")
+	r.NoError(err, "devices get returned an error with the current context")
+	r.Contains(stdout, "core1.example.net", "Expected the device from the lab context")
+
+	_, _, err = runCLI(t, nil, env, "context", "use", "prod")
+	r.NoError(err, "context use returned an error")
+	_, _, err = runCLI(t, nil, env, "-context", "lab", "devices", "get", "core1")
+	r.NoError(err, "Expected -context to override the current context")
+	_, _, err = runCLI(t, nil, env, "context", "use", "staging")
+	r.ErrorContains(err, `unknown context "staging"`, "Expected an error for unknown contexts")
+
+	env["LIBRENMS_URL"] = srv.URL()
+	env["LIBRENMS_TOKEN"] = librenmstest.Token
+	env["LIBRENMS_CONTEXT"] = "prod"
+	_, _, err = runCLI(t, nil, env, "devices", "get", "core1")
+	r.NoError(err, "Expected the environment to override the current context and $LIBRENMS_CONTEXT")
+
+	env["LIBRENMS_URL"] = "http://127.0.0.1:1/"
+	_, _, err = runCLI(t, nil, env, "-context", "lab", "devices", "get", "core1")
+	r.NoError(err, "Expected -context to override the environment")
+
+	delete(env, "LIBRENMS_TOKEN")
+	_, _, err = runCLI(t, nil, env, "devices", "get", "core1")
+	r.ErrorContains(err, "LIBRENMS_URL and LIBRENMS_TOKEN must be set together", "Expected the environment not to be mixed with a context")
+	_, _, err = runCLI(t, nil, env, "-context", "lab", "-url", srv.URL(), "devices", "get", "core1")
+	r.ErrorContains(err, "-url and -token must be set together", "Expected the flags not to be mixed with a context")
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+	formatCSV   = "csv"
+)
+
+// printer writes API objects in an output format. Table and CSV outputs show the given
+// columns, which are JSON field names; JSON and YAML outputs show all fields.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// checkFormat returns an error for unknown output formats.
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatYAML, formatCSV:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, expected table, json, yaml or csv", format)
+}
+
+// print writes v, a slice of objects or a single object.
+func (p *printer) print(v any, columns ...string) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	switch p.format {
+	case formatJSON:
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(generic)
+	case formatYAML:
+		enc := yaml.NewEncoder(p.w)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	var rows []map[string]any
+	switch generic := generic.(type) {
+	case []any:
+		for _, item := range generic {
+			row, _ := item.(map[string]any)
+			rows = append(rows, row)
+		}
+	case map[string]any:
+		rows = append(rows, generic)
+	}
+	if p.format == formatCSV {
+		return p.csv(rows, columns)
+	}
+	return p.table(rows, columns)
+}
+
+// message writes the message of an API response in table output, and the whole response
+// otherwise.
+func (p *printer) message(resp any, message string) error {
+	if p.format == formatTable {
+		_, err := fmt.Fprintln(p.w, strings.TrimSpace(message))
+		return err
+	}
+	return p.print(resp)
+}
+
+func (p *printer) table(rows []map[string]any, columns []string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(cells(row, columns), "\t"))
+	}
+	return tw.Flush()
+}
+
+func (p *printer) csv(rows []map[string]any, columns []string) error {
+	w := csv.NewWriter(p.w)
+	if err := w.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := w.Write(cells(row, columns)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// cells formats the columns of a row.
+func cells(row map[string]any, columns []string) []string {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		values[i] = formatValue(row[column])
+	}
+	return values
+}
+
+// formatValue formats a decoded JSON value for a table cell.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// toGeneric converts v to its decoded JSON form, so that all outputs use the JSON field
+// names of the API.
+func toGeneric(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return numbersToValues(generic), nil
+}
+
+// numbersToValues replaces json.Number with int64 or float64, which YAML can encode.
+func numbersToValues(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			v[k] = numbersToValues(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = numbersToValues(value)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
@@ -0,0 +1,82 @@
+package librenms
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+type (
+	// coalescer shares the response of a GET request with the identical requests made while
+	// it is in flight.
+	coalescer struct {
+		mu    sync.Mutex
+		calls map[string]*coalescedCall
+	}
+
+	// coalescedCall is an in-flight request and its waiters.
+	coalescedCall struct {
+		done    chan struct{}
+		body    []byte
+		err     error
+		waiters int
+		cancel  context.CancelFunc
+	}
+)
+
+// WithRequestCoalescing makes identical concurrent GET requests, with the same URL and
+// query, share a single HTTP request. Each caller decodes its own copy of the response and
+// stops waiting when its own context is done; the shared request is canceled once all its
+// callers stopped waiting.
+//
+// It helps when many goroutines resolve the same objects at once, e.g. with Device.Get or
+// DeviceGroup.Get, which lists all the groups.
+func WithRequestCoalescing() Option {
+	return func(c *Client) {
+		c.coalescer = &coalescer{calls: make(map[string]*coalescedCall)}
+	}
+}
+
+// do returns the body fetched for req, joining an identical request in flight if any.
+func (g *coalescer) do(req *http.Request, fetch func(*http.Request) ([]byte, error)) ([]byte, error) {
+	key := req.Method + " " + req.URL.String()
+	ctx := req.Context()
+
+	g.mu.Lock()
+	call, ok := g.calls[key]
+	if !ok {
+		// The shared request keeps the values of the first caller's context, e.g. its
+		// trace, but is only canceled when every caller gave up.
+		shared, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &coalescedCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = call
+		go func() {
+			call.body, call.err = fetch(req.WithContext(shared))
+			g.mu.Lock()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+			cancel()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.body, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
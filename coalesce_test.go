@@ -0,0 +1,91 @@
+package librenms_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestCoalescing(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{Hostname: "core1", OS: "iosxe"})
+	srv.InjectLatency("devices/core1", 100*time.Millisecond)
+	client := srv.Client(librenms.WithRequestCoalescing())
+
+	responses := make([]*types.DeviceResponse, 50)
+	errs := make([]error, len(responses))
+	var wg sync.WaitGroup
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], errs[i] = client.Device.Get("core1")
+		}(i)
+	}
+	wg.Wait()
+
+	r.Len(srv.Requests(), 1, "Expected the identical requests to be coalesced")
+	for i, resp := range responses {
+		r.NoError(errs[i], "Device.Get returned an error")
+		r.Len(resp.Devices, 1, "Expected the device")
+	}
+	responses[0].Devices[0].OS = "changed"
+	r.Equal("iosxe", string(responses[1].Devices[0].OS), "Expected each caller to decode its own copy")
+
+	_, err := client.Device.Get("core1")
+	r.NoError(err, "Device.Get returned an error")
+	r.Len(srv.Requests(), 2, "Expected a new request once the previous one completed")
+}
+
+func TestRequestCoalescing_Cancel(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{Hostname: "core1"})
+	srv.InjectLatency("devices/core1", 200*time.Millisecond)
+	client := srv.Client(librenms.WithRequestCoalescing())
+
+	var wg sync.WaitGroup
+	var resp *types.DeviceResponse
+	var err error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		resp, err = client.Device.Get("core1")
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, canceledErr := client.WithContext(ctx).Device.Get("core1")
+	r.ErrorIs(canceledErr, context.DeadlineExceeded, "Expected the caller's deadline to be honoured")
+	r.Less(time.Since(start), 150*time.Millisecond, "Expected the caller to stop waiting at its deadline")
+
+	wg.Wait()
+	r.NoError(err, "Expected the other caller to get the shared response")
+	r.Len(resp.Devices, 1, "Expected the device")
+	r.Len(srv.Requests(), 1, "Expected a single request")
+}
+
+func TestClient_WithContext(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.InjectLatency("system", time.Second)
+	client := srv.Client()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.WithContext(ctx).System.Get()
+	r.ErrorIs(err, context.Canceled, "Expected the request to use the context")
+	r.NotSame(client.Device, client.WithContext(ctx).Device, "Expected a copy of the client")
+}
@@ -0,0 +1,78 @@
+package librenms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewColumns(t *testing.T) {
+	r := require.New(t)
+
+	columns, err := types.NewColumns[types.Port]("ifName", "ifAlias, ifOperStatus", "port_id", "ifName")
+	r.NoError(err, "NewColumns returned an error")
+	r.Equal([]string{"port_id", "device_id", "ifName", "ifAlias", "ifOperStatus"}, columns.Names(), "Expected the key columns first, without duplicates")
+	r.Equal("port_id,device_id,ifName,ifAlias,ifOperStatus", columns.String(), "Unexpected columns parameter")
+	r.True(columns.Has("ifAlias"), "Expected ifAlias to be selected")
+	r.False(columns.Has("ifSpeed"), "Expected ifSpeed not to be selected")
+
+	links := types.MustColumns[types.Link]("remote_hostname")
+	r.Equal("id,local_device_id,remote_hostname", links.String(), "Expected the key columns of links")
+
+	_, err = types.NewColumns[types.Port]("ifName", "ifname")
+	r.ErrorContains(err, `unknown Port column "ifname"`, "Expected an error for an unknown column")
+	r.Empty(types.Columns[types.Port]{}.String(), "Expected the zero Columns to select nothing")
+	r.Panics(func() { types.MustColumns[types.VLAN]("vlan") }, "Expected MustColumns to panic")
+}
+
+func TestColumns_Fields(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddPort(types.Port{PortID: 1, DeviceID: 1, IfName: "Gi1/0/1", IfAlias: "uplink", IfSpeed: 1_000_000_000})
+	srv.AddLink(types.Link{ID: 1, LocalDeviceID: 1, LocalPortID: 1, RemoteHostname: "core1", Protocol: "lldp"})
+	client := srv.Client()
+
+	columns := types.MustColumns[types.Port]("ifAlias")
+	ports, err := client.Port.GetAllPortsWithFields(&types.PortsQueryParams{Columns: columns.String()})
+	r.NoError(err, "GetAllPortsWithFields returned an error")
+	r.Equal(types.FieldSet{"device_id", "ifAlias", "port_id"}, ports.Fields(), "Expected the requested fields only")
+	r.True(ports.Fields().Has("ifAlias"), "Expected ifAlias to be returned")
+	r.False(ports.Fields().Has("ifSpeed"), "Expected ifSpeed not to be returned")
+	r.Equal(types.Port{PortID: 1, DeviceID: 1, IfAlias: "uplink"}, ports.Ports[0].Value, "Expected only the requested fields to be decoded")
+
+	ports, err = client.Port.GetAllPortsWithFields(nil)
+	r.NoError(err, "GetAllPortsWithFields returned an error")
+	r.Equal(types.FieldSet{"ifName", "port_id"}, ports.Fields(), "Expected the default fields")
+
+	plain, err := client.Port.GetAllPorts(&types.PortsQueryParams{Columns: columns.String()})
+	r.NoError(err, "GetAllPorts returned an error")
+	r.Equal([]types.Port{{PortID: 1, DeviceID: 1, IfAlias: "uplink"}}, plain.Ports, "Expected the same ports without fields")
+
+	links, err := client.Switching.GetAllLinksWithFields(&types.SwitchingQueryParams{Columns: types.MustColumns[types.Link]("protocol").String()})
+	r.NoError(err, "GetAllLinksWithFields returned an error")
+	r.Equal(types.FieldSet{"id", "local_device_id", "protocol"}, links.Fields(), "Expected the requested link fields")
+	r.Equal("lldp", string(links.Links[0].Value.Protocol), "Expected the protocol")
+}
+
+func TestPortsWithFieldsResponse_UnmarshalJSON(t *testing.T) {
+	r := require.New(t)
+
+	var ports types.PortsWithFieldsResponse
+	r.NoError(json.Unmarshal([]byte(`{"status":"ok","meta":{"ports":[{"skipped":1}]},"ports":[
+		{"port_id":1,"ifName":"Gi1","ifAlias":"uplink","extra":{"nested":["port_id",{"ifSpeed":1}]}},
+		null,
+		{"port_id":2,"ifDescr":"Gi2","ifDescr":"GigabitEthernet2"}
+	],"count":2}`), &ports), "Unmarshal returned an error")
+	r.Equal(types.FieldSet{"extra", "ifAlias", "ifDescr", "ifName", "port_id"}, ports.Fields(), "Expected the top-level fields of the rows only")
+	r.Equal([]string{"ifDescr", "port_id"}, ports.Ports[2].Fields(), "Expected the fields of the row")
+	r.Equal("GigabitEthernet2", string(ports.Ports[2].Value.IfDescr), "Expected the last duplicate value")
+
+	ports = types.PortsWithFieldsResponse{}
+	r.NoError(json.Unmarshal([]byte(`{"status":"ok","ports":null}`), &ports), "Unmarshal returned an error")
+	r.Equal(types.FieldSet{}, ports.Fields(), "Expected no fields")
+	r.Error(json.Unmarshal([]byte(`{"ports":[1]}`), &ports), "Expected an error for invalid rows")
+}
@@ -18,7 +18,7 @@ const (
 // Documentation: https://docs.librenms.org/API/Devices/#add_device
 func (d *DeviceAPI) Create(payload *types.DeviceCreateRequest) (*types.DeviceResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodPost, fmt.Sprintf("%s/", deviceEndpoint), payload, nil)
+	req, err := c.newRequest(opDeviceCreate, http.MethodPost, fmt.Sprintf("%s/", deviceEndpoint), payload, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +31,7 @@ func (d *DeviceAPI) Create(payload *types.DeviceCreateRequest) (*types.DeviceRes
 // Documentation: https://docs.librenms.org/API/Devices/#del_device
 func (d *DeviceAPI) Delete(identifier string) (*types.DeviceResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodDelete, fmt.Sprintf("%s/%s", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceDelete, http.MethodDelete, fmt.Sprintf("%s/%s", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +44,7 @@ func (d *DeviceAPI) Delete(identifier string) (*types.DeviceResponse, error) {
 // Documentation: https://docs.librenms.org/API/Devices/#get_device
 func (d *DeviceAPI) Get(identifier string) (*types.DeviceResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceGet, http.MethodGet, fmt.Sprintf("%s/%s", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +52,18 @@ func (d *DeviceAPI) Get(identifier string) (*types.DeviceResponse, error) {
 	return deviceResp, c.do(req, deviceResp)
 }
 
+// GetWithFields is like Get, but records the fields returned for the device, e.g. to build
+// an update with DiffDevices from the fields that were really returned.
+func (d *DeviceAPI) GetWithFields(identifier string) (*types.DeviceWithFieldsResponse, error) {
+	c := d.client
+	req, err := c.newRequest(opDeviceGetWithFields, http.MethodGet, fmt.Sprintf("%s/%s", deviceEndpoint, identifier), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	deviceResp := new(types.DeviceWithFieldsResponse)
+	return deviceResp, c.do(req, deviceResp)
+}
+
 // List retrieves a list of devices from the LibreNMS API.
 //
 // Documentation: https://docs.librenms.org/API/Devices/#list_devices
@@ -62,7 +74,7 @@ func (d *DeviceAPI) List(query *types.DevicesQuery) (*types.DeviceResponse, erro
 		return nil, err
 	}
 
-	req, err := c.newRequest(http.MethodGet, deviceEndpoint, nil, params)
+	req, err := c.newRequest(opDeviceList, http.MethodGet, deviceEndpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -71,12 +83,36 @@ func (d *DeviceAPI) List(query *types.DevicesQuery) (*types.DeviceResponse, erro
 	return deviceResp, c.do(req, deviceResp)
 }
 
-// Update updates a device by its ID or hostname.
+// ListWithFields is like List, but records the fields returned for each device.
+func (d *DeviceAPI) ListWithFields(query *types.DevicesQuery) (*types.DeviceWithFieldsResponse, error) {
+	c := d.client
+	params, err := parseParams(query)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(opDeviceListWithFields, http.MethodGet, deviceEndpoint, nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceResp := new(types.DeviceWithFieldsResponse)
+	return deviceResp, c.do(req, deviceResp)
+}
+
+// Update updates a device by its ID or hostname. Payloads created by NewDeviceUpdate or
+// DiffDevices are validated first, while payloads whose Field and Data are set directly are
+// sent as is.
 //
 // Documentation: https://docs.librenms.org/API/Devices/#update_device_field
 func (d *DeviceAPI) Update(identifier string, payload *types.DeviceUpdateRequest) (*types.BaseResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodPatch, fmt.Sprintf("%s/%s", deviceEndpoint, identifier), payload, nil)
+	if payload.Built() {
+		if err := payload.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	req, err := c.newRequest(opDeviceUpdate, http.MethodPatch, fmt.Sprintf("%s/%s", deviceEndpoint, identifier), payload, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +125,7 @@ func (d *DeviceAPI) Update(identifier string, payload *types.DeviceUpdateRequest
 // Documentation: https://docs.librenms.org/API/Devices/#discover_device
 func (d *DeviceAPI) Discover(identifier string) (*types.BaseResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s/discover", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceDiscover, http.MethodGet, fmt.Sprintf("%s/%s/discover", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +138,7 @@ func (d *DeviceAPI) Discover(identifier string) (*types.BaseResponse, error) {
 // Documentation: https://docs.librenms.org/API/Devices/#availability
 func (d *DeviceAPI) GetAvailability(identifier string) (*types.DeviceAvailabilityResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s/availability", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceGetAvailability, http.MethodGet, fmt.Sprintf("%s/%s/availability", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +151,7 @@ func (d *DeviceAPI) GetAvailability(identifier string) (*types.DeviceAvailabilit
 // Documentation: https://docs.librenms.org/API/Devices/#outages
 func (d *DeviceAPI) GetOutages(identifier string) (*types.DeviceOutagesResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s/outages", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceGetOutages, http.MethodGet, fmt.Sprintf("%s/%s/outages", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -128,7 +164,7 @@ func (d *DeviceAPI) GetOutages(identifier string) (*types.DeviceOutagesResponse,
 // Documentation: https://docs.librenms.org/API/Devices/#get_graphs
 func (d *DeviceAPI) GetGraphs(identifier string) (*types.DeviceGraphsResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s/graphs", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceGetGraphs, http.MethodGet, fmt.Sprintf("%s/%s/graphs", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -150,7 +186,7 @@ func (d *DeviceAPI) GetHealthGraphs(identifier string, graphType string, sensorI
 		endpoint = fmt.Sprintf("%s/%s/health", deviceEndpoint, identifier)
 	}
 
-	req, err := c.newRequest(http.MethodGet, endpoint, nil, nil)
+	req, err := c.newRequest(opDeviceGetHealthGraphs, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +208,7 @@ func (d *DeviceAPI) GetWirelessGraphs(identifier string, graphType string, senso
 		endpoint = fmt.Sprintf("%s/%s/wireless", deviceEndpoint, identifier)
 	}
 
-	req, err := c.newRequest(http.MethodGet, endpoint, nil, nil)
+	req, err := c.newRequest(opDeviceGetWirelessGraphs, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -193,7 +229,7 @@ func (d *DeviceAPI) GetPorts(identifier string, columns string) (*types.DevicePo
 		params.Set("columns", columns)
 	}
 
-	req, err := c.newRequest(http.MethodGet, endpoint, nil, params)
+	req, err := c.newRequest(opDeviceGetPorts, http.MethodGet, endpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -206,7 +242,7 @@ func (d *DeviceAPI) GetPorts(identifier string, columns string) (*types.DevicePo
 // Documentation: https://docs.librenms.org/API/Devices/#get_device_fdb
 func (d *DeviceAPI) GetDeviceFDB(identifier string) (*types.DeviceFDBResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s/fdb", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceGetDeviceFDB, http.MethodGet, fmt.Sprintf("%s/%s/fdb", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -219,7 +255,7 @@ func (d *DeviceAPI) GetDeviceFDB(identifier string) (*types.DeviceFDBResponse, e
 // Documentation: https://docs.librenms.org/API/Devices/#get_device_nac
 func (d *DeviceAPI) GetDeviceNAC(identifier string) (*types.DeviceNACResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s/nac", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceGetDeviceNAC, http.MethodGet, fmt.Sprintf("%s/%s/nac", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -232,7 +268,7 @@ func (d *DeviceAPI) GetDeviceNAC(identifier string) (*types.DeviceNACResponse, e
 // Documentation: https://docs.librenms.org/API/Devices/#get_device_ip_addresses
 func (d *DeviceAPI) GetDeviceIPAddresses(identifier string) (*types.DeviceIPAddressesResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s/ip", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceGetDeviceIPAddresses, http.MethodGet, fmt.Sprintf("%s/%s/ip", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -253,7 +289,7 @@ func (d *DeviceAPI) GetPortStack(identifier string, validMappings bool) (*types.
 		params.Set("valid_mappings", "")
 	}
 
-	req, err := c.newRequest(http.MethodGet, endpoint, nil, params)
+	req, err := c.newRequest(opDeviceGetPortStack, http.MethodGet, endpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -266,7 +302,7 @@ func (d *DeviceAPI) GetPortStack(identifier string, validMappings bool) (*types.
 // Documentation: https://docs.librenms.org/API/Devices/#get_device_transceivers
 func (d *DeviceAPI) GetDeviceTransceivers(identifier string) (*types.DeviceTransceiversResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s/transceivers", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceGetDeviceTransceivers, http.MethodGet, fmt.Sprintf("%s/%s/transceivers", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -285,7 +321,7 @@ func (d *DeviceAPI) GetComponents(identifier string, query *types.ComponentsQuer
 	if err != nil {
 		return nil, err
 	}
-	req, err := c.newRequest(http.MethodGet, endpoint, nil, params)
+	req, err := c.newRequest(opDeviceGetComponents, http.MethodGet, endpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -298,7 +334,7 @@ func (d *DeviceAPI) GetComponents(identifier string, query *types.ComponentsQuer
 // Documentation: https://docs.librenms.org/API/Devices/#add_components
 func (d *DeviceAPI) AddComponent(identifier string, componentType string) (*types.DeviceComponentsResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodPost, fmt.Sprintf("%s/%s/components/%s", deviceEndpoint, identifier, componentType), nil, nil)
+	req, err := c.newRequest(opDeviceAddComponent, http.MethodPost, fmt.Sprintf("%s/%s/components/%s", deviceEndpoint, identifier, componentType), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -311,7 +347,7 @@ func (d *DeviceAPI) AddComponent(identifier string, componentType string) (*type
 // Documentation: https://docs.librenms.org/API/Devices/#edit_components
 func (d *DeviceAPI) EditComponents(identifier string, payload map[string]interface{}) (*types.BaseResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodPut, fmt.Sprintf("%s/%s/components", deviceEndpoint, identifier), payload, nil)
+	req, err := c.newRequest(opDeviceEditComponents, http.MethodPut, fmt.Sprintf("%s/%s/components", deviceEndpoint, identifier), payload, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -324,7 +360,7 @@ func (d *DeviceAPI) EditComponents(identifier string, payload map[string]interfa
 // Documentation: https://docs.librenms.org/API/Devices/#delete_components
 func (d *DeviceAPI) DeleteComponent(identifier string, componentID string) (*types.BaseResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodDelete, fmt.Sprintf("%s/%s/components/%s", deviceEndpoint, identifier, componentID), nil, nil)
+	req, err := c.newRequest(opDeviceDeleteComponent, http.MethodDelete, fmt.Sprintf("%s/%s/components/%s", deviceEndpoint, identifier, componentID), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -345,7 +381,7 @@ func (d *DeviceAPI) GetPortStats(identifier string, ifName string, columns strin
 		params.Set("columns", columns)
 	}
 
-	req, err := c.newRequest(http.MethodGet, endpoint, nil, params)
+	req, err := c.newRequest(opDeviceGetPortStats, http.MethodGet, endpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -358,7 +394,7 @@ func (d *DeviceAPI) GetPortStats(identifier string, ifName string, columns strin
 // Documentation: https://docs.librenms.org/API/Devices/#device_under_maintenance
 func (d *DeviceAPI) GetDeviceMaintenance(identifier string) (*types.DeviceMaintenanceResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s/maintenance", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceGetDeviceMaintenance, http.MethodGet, fmt.Sprintf("%s/%s/maintenance", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -371,7 +407,7 @@ func (d *DeviceAPI) GetDeviceMaintenance(identifier string) (*types.DeviceMainte
 // Documentation: https://docs.librenms.org/API/Devices/#maintenance_device
 func (d *DeviceAPI) SetDeviceMaintenance(identifier string, payload *types.DeviceMaintenanceRequest) (*types.BaseResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodPost, fmt.Sprintf("%s/%s/maintenance", deviceEndpoint, identifier), payload, nil)
+	req, err := c.newRequest(opDeviceSetDeviceMaintenance, http.MethodPost, fmt.Sprintf("%s/%s/maintenance", deviceEndpoint, identifier), payload, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -384,7 +420,7 @@ func (d *DeviceAPI) SetDeviceMaintenance(identifier string, payload *types.Devic
 // Documentation: https://docs.librenms.org/API/Devices/#rename_device
 func (d *DeviceAPI) RenameDevice(identifier string, newHostname string) (*types.BaseResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodPatch, fmt.Sprintf("%s/%s/rename/%s", deviceEndpoint, identifier, newHostname), nil, nil)
+	req, err := c.newRequest(opDeviceRenameDevice, http.MethodPatch, fmt.Sprintf("%s/%s/rename/%s", deviceEndpoint, identifier, newHostname), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -397,7 +433,7 @@ func (d *DeviceAPI) RenameDevice(identifier string, newHostname string) (*types.
 // Documentation: https://docs.librenms.org/API/Devices/#get_device_groups
 func (d *DeviceAPI) GetDeviceGroups(identifier string) (*types.DeviceGroupsResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s/groups", deviceEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceGetDeviceGroups, http.MethodGet, fmt.Sprintf("%s/%s/groups", deviceEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -411,7 +447,7 @@ func (d *DeviceAPI) GetDeviceGroups(identifier string) (*types.DeviceGroupsRespo
 func (d *DeviceAPI) UpdateDevicePortNotes(identifier string, portID int, notes string) (*types.BaseResponse, error) {
 	c := d.client
 	payload := map[string]string{"notes": notes}
-	req, err := c.newRequest(http.MethodPatch, fmt.Sprintf("%s/%s/port/%d", deviceEndpoint, identifier, portID), payload, nil)
+	req, err := c.newRequest(opDeviceUpdateDevicePortNotes, http.MethodPatch, fmt.Sprintf("%s/%s/port/%d", deviceEndpoint, identifier, portID), payload, nil)
 	if err != nil {
 		return nil, err
 	}
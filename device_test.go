@@ -42,12 +42,12 @@ func TestClient_GetDevice(t *testing.T) {
 	r.NotNil(deviceResp, "GetDevice response is nil")
 
 	r.Equal("ok", deviceResp.Status, "Expected status 'ok'")
-	r.Equal(1, deviceResp.Count, "Expected count 1")
+	r.EqualValues(1, deviceResp.Count, "Expected count 1")
 	r.Len(deviceResp.Devices, 1, "Expected 1 device")
 
 	device := deviceResp.Devices[0]
-	r.Equal(1, device.DeviceID, "Expected DeviceID 1")
-	r.Equal("1.1.1.1", device.Hostname, "Expected Hostname '1.1.1.1'")
+	r.EqualValues(1, device.DeviceID, "Expected DeviceID 1")
+	r.Equal("1.1.1.1", string(device.Hostname), "Expected Hostname '1.1.1.1'")
 
 	// verify a Bool field unmarshals correctly
 	r.Equal(types.Bool(true), device.SNMPDisable, "Expected SNMPDisable true (1)")
@@ -64,21 +64,21 @@ func TestClient_GetDevices(t *testing.T) {
 	r.NotNil(deviceResp, "GetDevices response is nil")
 
 	r.Equal("ok", deviceResp.Status, "Expected status 'ok'")
-	r.Equal(3, deviceResp.Count, "Expected count 3")
+	r.EqualValues(3, deviceResp.Count, "Expected count 3")
 	r.Len(deviceResp.Devices, 3, "Expected 3 devices")
 
 	device := deviceResp.Devices[0]
-	r.Equal(1, device.DeviceID, "Expected DeviceID 1")
-	r.Equal("1.1.1.1", device.Hostname, "Expected Hostname '1.1.1.1'")
+	r.EqualValues(1, device.DeviceID, "Expected DeviceID 1")
+	r.Equal("1.1.1.1", string(device.Hostname), "Expected Hostname '1.1.1.1'")
 
 	// verify a Bool field unmarshals correctly
 	r.Equal(types.Bool(true), device.SNMPDisable, "Expected SNMPDisable true (1)")
 
 	// verify a Float64 field unmarshals correctly
 	device = deviceResp.Devices[2]
-	r.Equal(2, device.DeviceID, "Expected DeviceID 2")
-	r.NotNil(device.Latitude, "Expected Latitude to be non-nil")
-	r.Equal(-45.08624620, float64(*device.Latitude), "Expected Latitude -45.0862462")
+	r.EqualValues(2, device.DeviceID, "Expected DeviceID 2")
+	r.True(device.Latitude.Valid, "Expected Latitude to be set")
+	r.Equal(-45.08624620, float64(device.Latitude.V), "Expected Latitude -45.0862462")
 }
 
 func TestClient_CreateDevice(t *testing.T) {
@@ -99,18 +99,18 @@ func TestClient_CreateDevice(t *testing.T) {
 	r.NotNil(createResp, "CreateDevice response is nil")
 
 	r.Equal("ok", createResp.Status, "Expected status 'ok'")
-	r.Equal(1, createResp.Count, "Expected count 1")
+	r.EqualValues(1, createResp.Count, "Expected count 1")
 	r.Len(createResp.Devices, 1, "Expected 1 device in response")
 
 	// Verify the details of the device in the response
 	// Note: The response device structure is librenms.Device
 	deviceInResponse := createResp.Devices[0]
-	r.Equal("192.168.10.5", deviceInResponse.Hostname, "Expected Hostname '192.168.10.5'")
-	r.Equal("v2c", deviceInResponse.SNMPVersion, "Expected snmpver 'v2c'")
+	r.Equal("192.168.10.5", string(deviceInResponse.Hostname), "Expected Hostname '192.168.10.5'")
+	r.Equal("v2c", string(deviceInResponse.SNMPVersion), "Expected snmpver 'v2c'")
 
 	// Verify that SNMPCommunity is not empty
 	r.NotEmpty(deviceInResponse.Community, "Expected SNMPCommunity to be set in the response")
-	r.Equal("public", deviceInResponse.Community, "Expected SNMPCommunity 'public' in response")
+	r.Equal("public", string(deviceInResponse.Community), "Expected SNMPCommunity 'public' in response")
 }
 
 func TestClient_DeleteDevice(t *testing.T) {
@@ -124,12 +124,12 @@ func TestClient_DeleteDevice(t *testing.T) {
 	r.NotNil(deviceResp, "DeleteDevice response is nil")
 
 	r.Equal("ok", deviceResp.Status, "Expected status 'ok'")
-	r.Equal(1, deviceResp.Count, "Expected count 1")
+	r.EqualValues(1, deviceResp.Count, "Expected count 1")
 	r.Len(deviceResp.Devices, 1, "Expected 1 device")
 
 	device := deviceResp.Devices[0]
-	r.Equal(1, device.DeviceID, "Expected DeviceID 1")
-	r.Equal("1.1.1.1", device.Hostname, "Expected Hostname '1.1.1.1'")
+	r.EqualValues(1, device.DeviceID, "Expected DeviceID 1")
+	r.Equal("1.1.1.1", string(device.Hostname), "Expected Hostname '1.1.1.1'")
 }
 
 func TestClient_UpdateDevice(t *testing.T) {
@@ -149,3 +149,49 @@ func TestClient_UpdateDevice(t *testing.T) {
 	r.Equal("ok", deviceResp.Status, "Expected status 'ok'")
 	r.Equal("Device fields have been updated", deviceResp.Message, "Update message mismatch")
 }
+
+func TestNewDeviceUpdate(t *testing.T) {
+	r := require.New(t)
+
+	payload := types.NewDeviceUpdate().SetNotes("rack 4").SetDisabled(true).SetLocationID(3).SetNotes("rack 5")
+	r.Equal([]string{"notes", "disabled", "location_id"}, payload.Field, "Expected each field once")
+	r.Equal([]any{"rack 5", true, 3}, payload.Data, "Expected the values aligned with the fields")
+	r.NoError(payload.Validate(), "Expected a valid payload")
+
+	r.NoError(types.NewDeviceUpdate().Set("ignore", 1).Set("disabled", "0").Validate(), "Expected 0 and 1 to be valid booleans")
+	r.NoError(types.NewDeviceUpdate().Set("notes", nil).Set("location_id", nil).Validate(), "Expected nil to clear fields")
+	r.NoError(types.NewDeviceUpdate().Set("sysName", "core1").Set("ip", "10.0.0.1").Validate(), "Expected poller fields to be accepted")
+	r.ErrorContains(types.NewDeviceUpdate().Validate(), "no device fields", "Expected an error for an empty payload")
+	r.ErrorContains(types.NewDeviceUpdate().Set("hostname", "core2").Validate(), `device field "hostname" cannot be updated`,
+		"Expected an error for a field that cannot be patched")
+	r.ErrorContains(types.NewDeviceUpdate().Set("location_id", "3").Validate(), `invalid value 3 for device field "location_id"`,
+		"Expected an error for a value of the wrong type")
+	r.ErrorContains(types.NewDeviceUpdate().Set("ignore", 2).Validate(), "invalid value", "Expected an error for a boolean out of range")
+	r.ErrorContains((&types.DeviceUpdateRequest{Field: []string{"notes", "purpose"}, Data: []any{"rack 4"}}).Validate(),
+		"2 device fields for 1 values", "Expected an error for misaligned fields")
+
+	_, err := testAPIClient.Device.Update("1.1.1.1", types.NewDeviceUpdate().Set("notes", nil).Set("unknown", "x"))
+	r.ErrorContains(err, `device field "unknown" cannot be updated`, "Expected Update to validate built payloads")
+	_, err = testAPIClient.Device.Update("1.1.1.1", &types.DeviceUpdateRequest{Field: []string{"notes", "unknown"}, Data: []any{nil, "x"}})
+	r.NoError(err, "Expected Update to send the other payloads as is")
+}
+
+func TestDiffDevices(t *testing.T) {
+	r := require.New(t)
+
+	old := types.Device{DeviceID: 1, Hostname: "core1", Notes: "rack 4", Disabled: true, LocationID: 2, Status: true}
+	updated := old
+	updated.Hostname = "core2"
+	updated.Status = false
+	updated.Notes = "rack 5"
+	updated.Disabled = false
+	updated.LocationID = 3
+	updated.Ignore = true
+
+	payload := types.DiffDevices(old, updated)
+	r.Equal([]string{"disabled", "ignore", "location_id", "notes"}, payload.Field, "Expected the changed fields that can be patched")
+	r.Equal([]any{false, true, 3, "rack 5"}, payload.Data, "Expected plain values")
+	r.NoError(payload.Validate(), "Expected a valid payload")
+
+	r.Empty(types.DiffDevices(old, old).Field, "Expected no changes")
+}
@@ -19,7 +19,7 @@ const (
 // Documentation: https://docs.librenms.org/API/DeviceGroups/#add_devicegroup
 func (d *DeviceGroupAPI) Create(group *types.DeviceGroupCreateRequest) (*types.DeviceGroupCreateResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodPost, deviceGroupEndpoint, group, nil)
+	req, err := c.newRequest(opDeviceGroupCreate, http.MethodPost, deviceGroupEndpoint, group, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +38,7 @@ func (d *DeviceGroupAPI) Delete(identifier string) (*types.BaseResponse, error)
 		return nil, fmt.Errorf("failed to parse URI: %w", err)
 	}
 
-	req, err := c.newRequest(http.MethodDelete, uri.String(), nil, nil)
+	req, err := c.newRequest(opDeviceGroupDelete, http.MethodDelete, uri.String(), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +51,7 @@ func (d *DeviceGroupAPI) Delete(identifier string) (*types.BaseResponse, error)
 // This is primarily a convenience function for the Terraform provider.
 func (d *DeviceGroupAPI) Get(identifier string) (*types.DeviceGroupResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, deviceGroupEndpoint, nil, nil)
+	req, err := c.newRequest(opDeviceGroupGet, http.MethodGet, deviceGroupEndpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +72,7 @@ func (d *DeviceGroupAPI) Get(identifier string) (*types.DeviceGroupResponse, err
 	singleGroupResp.Status = resp.Status
 
 	for _, group := range resp.Groups {
-		if group.Name == identifier || strconv.Itoa(group.ID) == identifier {
+		if string(group.Name) == identifier || strconv.Itoa(int(group.ID)) == identifier {
 			singleGroupResp.Groups = append(singleGroupResp.Groups, group)
 			singleGroupResp.Count = 1
 			break
@@ -87,7 +87,7 @@ func (d *DeviceGroupAPI) Get(identifier string) (*types.DeviceGroupResponse, err
 // Documentation: https://docs.librenms.org/API/DeviceGroups/#get_devicegroups
 func (d *DeviceGroupAPI) List() (*types.DeviceGroupResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, deviceGroupEndpoint, nil, nil)
+	req, err := c.newRequest(opDeviceGroupList, http.MethodGet, deviceGroupEndpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +102,7 @@ func (d *DeviceGroupAPI) List() (*types.DeviceGroupResponse, error) {
 // Documentation: https://docs.librenms.org/API/DeviceGroups/#get_devices_by_group
 func (d *DeviceGroupAPI) GetMembers(identifier string) (*types.DeviceGroupMembersResponse, error) {
 	c := d.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s", deviceGroupEndpoint, identifier), nil, nil)
+	req, err := c.newRequest(opDeviceGroupGetMembers, http.MethodGet, fmt.Sprintf("%s/%s", deviceGroupEndpoint, identifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -111,6 +111,21 @@ func (d *DeviceGroupAPI) GetMembers(identifier string) (*types.DeviceGroupMember
 	return resp, c.do(req, resp)
 }
 
+// SetMaintenance sets all devices of a device group into maintenance mode.
+// The identifier can be either the group ID or the group name.
+//
+// Documentation: https://docs.librenms.org/API/DeviceGroups/#maintenance_devicegroup
+func (d *DeviceGroupAPI) SetMaintenance(identifier string, payload *types.DeviceMaintenanceRequest) (*types.BaseResponse, error) {
+	c := d.client
+	req, err := c.newRequest(opDeviceGroupSetMaintenance, http.MethodPost, fmt.Sprintf("%s/%s/maintenance", deviceGroupEndpoint, url.PathEscape(identifier)), payload, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(types.BaseResponse)
+	return resp, c.do(req, resp)
+}
+
 // Update updates an existing device group in the LibreNMS API.
 //
 // The documentation states it uses name rather than ID to reference the group, but both seem to work (as of v25.5).
@@ -122,7 +137,7 @@ func (d *DeviceGroupAPI) Update(identifier string, payload *types.DeviceGroupUpd
 		return nil, fmt.Errorf("failed to parse URI: %w", err)
 	}
 
-	req, err := c.newRequest(http.MethodPatch, uri.String(), payload, nil)
+	req, err := c.newRequest(opDeviceGroupUpdate, http.MethodPatch, uri.String(), payload, nil)
 	if err != nil {
 		return nil, err
 	}
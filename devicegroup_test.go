@@ -3,6 +3,7 @@ package librenms_test
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/javen-yan/librenms-go/types"
 
@@ -10,8 +11,9 @@ import (
 )
 
 const (
-	testEndpointDeviceGroups = "/api/v0/devicegroups"
-	testEndpointDeviceGroup  = "/api/v0/devicegroups/4"
+	testEndpointDeviceGroups           = "/api/v0/devicegroups"
+	testEndpointDeviceGroup            = "/api/v0/devicegroups/4"
+	testEndpointDeviceGroupMaintenance = "/api/v0/devicegroups/4/maintenance"
 )
 
 // This init function will register handlers for devicegroup-related API endpoints.
@@ -22,6 +24,10 @@ func init() {
 		http.MethodPatch:  loadMockResponse("update_devicegroup_200.json"),
 	})
 
+	handleEndpoint(testEndpointDeviceGroupMaintenance, mockResponses{
+		http.MethodPost: []byte(`{"status":"ok","message":"Device group NestedRules (4) will begin maintenance mode at 5 minutes"}`),
+	})
+
 	// Registering this endpoint outside of handleEndpoint() to mock the HTTP 201 POST response.
 	mux.HandleFunc(testEndpointDeviceGroups, func(w http.ResponseWriter, r *http.Request) {
 		var err error
@@ -51,12 +57,12 @@ func TestClient_GetDeviceGroup(t *testing.T) {
 	r.NotNil(groupResp, "GetDeviceGroup response is nil")
 
 	r.Equal("ok", groupResp.Status, "Expected status 'ok'")
-	r.Equal(1, groupResp.Count, "Expected count 1")
+	r.EqualValues(1, groupResp.Count, "Expected count 1")
 	r.Len(groupResp.Groups, 1, "Expected 1 device groups")
 
 	group := groupResp.Groups[0]
-	r.Equal(4, group.ID, "Expected GroupID 4")
-	r.Equal("NestedRules", group.Name, "Expected Group 'NestedRules'")
+	r.EqualValues(4, group.ID, "Expected GroupID 4")
+	r.Equal("NestedRules", string(group.Name), "Expected Group 'NestedRules'")
 }
 
 func TestClient_GetDeviceGroups(t *testing.T) {
@@ -70,12 +76,12 @@ func TestClient_GetDeviceGroups(t *testing.T) {
 	r.NotNil(groupResp, "GetDeviceGroups response is nil")
 
 	r.Equal("ok", groupResp.Status, "Expected status 'ok'")
-	r.Equal(3, groupResp.Count, "Expected count 3")
+	r.EqualValues(3, groupResp.Count, "Expected count 3")
 	r.Len(groupResp.Groups, 3, "Expected 3 device groups")
 
 	group := groupResp.Groups[0]
-	r.Equal(1, group.ID, "Expected GroupID 1")
-	r.Equal("GCP", group.Name, "Expected Group 'GCP'")
+	r.EqualValues(1, group.ID, "Expected GroupID 1")
+	r.Equal("GCP", string(group.Name), "Expected Group 'GCP'")
 }
 
 func TestClient_GetDeviceGroupMembers(t *testing.T) {
@@ -89,11 +95,11 @@ func TestClient_GetDeviceGroupMembers(t *testing.T) {
 	r.NotNil(groupResp, "GetDeviceGroupMembers response is nil")
 
 	r.Equal("ok", groupResp.Status, "Expected status 'ok'")
-	r.Equal(1, groupResp.Count, "Expected count 1")
+	r.EqualValues(1, groupResp.Count, "Expected count 1")
 	r.Len(groupResp.Devices, 1, "Expected 1 device groups")
 
 	member := groupResp.Devices[0]
-	r.Equal(6, member.ID, "Expected Device ID 6")
+	r.EqualValues(6, member.ID, "Expected Device ID 6")
 }
 
 func TestClient_CreateDeviceGroup(t *testing.T) {
@@ -130,7 +136,7 @@ func TestClient_CreateDeviceGroup(t *testing.T) {
 	r.NotNil(createResp, "CreateDeviceGroup response is nil")
 
 	r.Equal("ok", createResp.Status, "Expected status 'ok'")
-	r.Equal(4, createResp.ID, "Expected ID 4")
+	r.EqualValues(4, createResp.ID, "Expected ID 4")
 }
 
 func TestClient_CreateDeviceGroupNested(t *testing.T) {
@@ -217,7 +223,7 @@ func TestClient_CreateDeviceGroupNested(t *testing.T) {
 	r.NotNil(createResp, "CreateDeviceGroup response is nil")
 
 	r.Equal("ok", createResp.Status, "Expected status 'ok'")
-	r.Equal(4, createResp.ID, "Expected ID 4")
+	r.EqualValues(4, createResp.ID, "Expected ID 4")
 }
 
 func TestClient_CreateDeviceGroupStatic(t *testing.T) {
@@ -237,7 +243,7 @@ func TestClient_CreateDeviceGroupStatic(t *testing.T) {
 	r.NotNil(createResp, "CreateDeviceGroup response is nil")
 
 	r.Equal("ok", createResp.Status, "Expected status 'ok'")
-	r.Equal(4, createResp.ID, "Expected ID 4")
+	r.EqualValues(4, createResp.ID, "Expected ID 4")
 }
 
 func TestClient_DeleteDeviceGroup(t *testing.T) {
@@ -288,3 +294,18 @@ func TestClient_UpdateDeviceGroup(t *testing.T) {
 
 	r.Equal("ok", createResp.Status, "Expected status 'ok'")
 }
+
+func TestClient_SetDeviceGroupMaintenance(t *testing.T) {
+	r := require.New(t)
+
+	r.NotNil(testAPIClient, "Global testAPIClient should be initialized")
+
+	payload := types.NewDeviceMaintenanceRequest("Upgrade", time.Time{}, 2*time.Hour)
+	resp, err := testAPIClient.DeviceGroup.SetMaintenance("4", payload)
+
+	r.NoError(err, "SetMaintenance returned an error")
+	r.NotNil(resp, "SetMaintenance response is nil")
+	r.Equal("ok", resp.Status, "Expected status 'ok'")
+	r.Equal("2:00", payload.Duration, "Expected duration formatted as H:i")
+	r.Empty(payload.Start, "Expected no start for immediate maintenance")
+}
@@ -49,7 +49,7 @@ func main() {
 		for _, device := range devices.Devices {
 			display := ""
 			if device.Display != "" {
-				display = device.Display
+				display = string(device.Display)
 			}
 			fmt.Printf("- %s (%s) - %s\n", device.Hostname, display, device.OS)
 		}
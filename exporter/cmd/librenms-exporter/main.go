@@ -0,0 +1,69 @@
+// Command librenms-exporter exposes the state of the network as seen by LibreNMS as
+// Prometheus metrics.
+//
+// Usage:
+//
+//	LIBRENMS_TOKEN=... librenms-exporter -url http://librenms:8000/ -listen :9860
+package main
+
+import (
+	"flag"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/exporter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	var (
+		baseURL     = flag.String("url", os.Getenv("LIBRENMS_URL"), "LibreNMS base URL, defaults to $LIBRENMS_URL")
+		token       = flag.String("token", os.Getenv("LIBRENMS_TOKEN"), "LibreNMS API token, defaults to $LIBRENMS_TOKEN")
+		listen      = flag.String("listen", ":9860", "address to serve the metrics on")
+		metricsPath = flag.String("path", "/metrics", "path to serve the metrics on")
+		cacheTTL    = flag.Duration("cache-ttl", exporter.DefaultCacheTTL, "time scrape results are reused for")
+		concurrency = flag.Int("concurrency", exporter.DefaultConcurrency, "maximum number of concurrent API requests")
+		timeout     = flag.Duration("timeout", 30*time.Second, "timeout of the API requests")
+		enabled     = flag.String("collectors", strings.Join(exporter.DefaultCollectors, ","), "comma separated list of enabled collectors")
+		retries     = flag.Int("retries", 2, "number of retries of failed API requests")
+	)
+	flag.Parse()
+	if *baseURL == "" || *token == "" {
+		log.Fatal("the LibreNMS URL and token are required")
+	}
+
+	httpClient := cleanhttp.DefaultPooledClient()
+	httpClient.Timeout = *timeout
+	client, err := librenms.NewClient(*baseURL, *token,
+		librenms.WithHTTPClient(httpClient),
+		librenms.WithLogLevel(slog.LevelWarn),
+		librenms.WithMiddleware(librenms.RetryMiddleware(*retries, time.Second)))
+	if err != nil {
+		log.Fatalf("Failed to create LibreNMS client: %v", err)
+	}
+
+	collector, err := exporter.NewCollector(client,
+		exporter.WithCacheTTL(*cacheTTL),
+		exporter.WithConcurrency(*concurrency),
+		exporter.WithCollectors(strings.Split(*enabled, ",")...))
+	if err != nil {
+		log.Fatalf("Failed to create collector: %v", err)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector, collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	mux := http.NewServeMux()
+	mux.Handle(*metricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	log.Printf("Serving LibreNMS metrics on %s%s", *listen, *metricsPath)
+	log.Fatal(server.ListenAndServe())
+}
@@ -0,0 +1,430 @@
+// Package exporter exposes the state of the network as seen by LibreNMS as Prometheus
+// metrics.
+//
+// The Collector queries the API at scrape time: device status and availability, open
+// alerts, BGP sessions, OSPF neighbours, services and ports. Results are cached for a
+// configurable time and API requests are limited in concurrency, so frequent scrapes or
+// several Prometheus servers don't hammer LibreNMS:
+//
+//	collector, err := exporter.NewCollector(client, exporter.WithCacheTTL(time.Minute))
+//	if err != nil {
+//		return err
+//	}
+//	prometheus.MustRegister(collector)
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace is the prefix of the metric names.
+const Namespace = "librenms"
+
+// Names of the collectors, see WithCollectors.
+const (
+	CollectorDevices      = "devices"
+	CollectorAvailability = "availability"
+	CollectorAlerts       = "alerts"
+	CollectorBGP          = "bgp"
+	CollectorOSPF         = "ospf"
+	CollectorServices     = "services"
+	CollectorPorts        = "ports"
+)
+
+const (
+	// DefaultCacheTTL is the time scrape results are reused for.
+	DefaultCacheTTL = 30 * time.Second
+	// DefaultConcurrency is the maximum number of concurrent API requests of a scrape.
+	DefaultConcurrency = 4
+)
+
+// DefaultCollectors are the collectors enabled by default: all of them.
+var DefaultCollectors = []string{
+	CollectorDevices, CollectorAvailability, CollectorAlerts, CollectorBGP,
+	CollectorOSPF, CollectorServices, CollectorPorts,
+}
+
+// portColumns limits the port listing to the fields of the port metrics.
+var portColumns = types.MustColumns[types.Port]("ifName", "ifOperStatus", "ifAdminStatus")
+
+var (
+	deviceUpDesc = prometheus.NewDesc(Namespace+"_device_up",
+		"Whether LibreNMS sees the device as up (1) or down (0). Disabled devices are skipped.",
+		[]string{"hostname", "sysname", "os", "location"}, nil)
+	availabilityDesc = prometheus.NewDesc(Namespace+"_device_availability_percent",
+		"Availability of the device over the duration, in seconds, as computed by LibreNMS.",
+		[]string{"hostname", "duration"}, nil)
+	alertsDesc = prometheus.NewDesc(Namespace+"_alerts",
+		"Number of open alerts by severity, rule and state.",
+		[]string{"severity", "rule_id", "rule", "state"}, nil)
+	bgpStateDesc = prometheus.NewDesc(Namespace+"_bgp_session_state",
+		"BGP finite state machine state: 1=idle, 2=connect, 3=active, 4=opensent, 5=openconfirm, 6=established, 0=unknown.",
+		[]string{"hostname", "peer", "remote_as"}, nil)
+	ospfStateDesc = prometheus.NewDesc(Namespace+"_ospf_neighbor_state",
+		"OSPF neighbour state: 1=down, 2=attempt, 3=init, 4=twoWay, 5=exchangeStart, 6=exchange, 7=loading, 8=full, 0=unknown.",
+		[]string{"hostname", "neighbor", "address"}, nil)
+	serviceStatusDesc = prometheus.NewDesc(Namespace+"_service_status",
+		"Service status: 0=ok, 1=warning, 2=critical, 3=unknown.",
+		[]string{"hostname", "service", "type"}, nil)
+	portOperUpDesc = prometheus.NewDesc(Namespace+"_port_oper_up",
+		"Whether the operational status of the port is up (1) or not (0).",
+		[]string{"hostname", "ifname"}, nil)
+	portAdminUpDesc = prometheus.NewDesc(Namespace+"_port_admin_up",
+		"Whether the administrative status of the port is up (1) or not (0).",
+		[]string{"hostname", "ifname"}, nil)
+	collectorSuccessDesc = prometheus.NewDesc(Namespace+"_collector_success",
+		"Whether the collector succeeded during the last refresh.",
+		[]string{"collector"}, nil)
+	collectorDurationDesc = prometheus.NewDesc(Namespace+"_collector_duration_seconds",
+		"Duration of the collector during the last refresh.",
+		[]string{"collector"}, nil)
+)
+
+// alertStates names the open alert states.
+var alertStates = map[types.Int]string{1: "alert", 2: "acknowledged", 3: "worse", 4: "better", 5: "changed"}
+
+type (
+	// Collector is a prometheus.Collector reporting the state of the network from LibreNMS.
+	Collector struct {
+		client      *librenms.Client
+		cacheTTL    time.Duration
+		concurrency int
+		collectors  []string
+
+		mu      sync.Mutex
+		metrics []prometheus.Metric
+		expires time.Time
+	}
+
+	// Option configures a Collector.
+	Option func(*Collector)
+
+	// scrape holds the state shared by the collectors during a refresh.
+	scrape struct {
+		client *librenms.Client
+		sem    chan struct{}
+		// hostnames maps device IDs to hostnames, for the endpoints returning only IDs.
+		hostnames map[types.Int]string
+		devices   []types.Device
+
+		mu      sync.Mutex
+		metrics []prometheus.Metric
+		seen    map[string]bool
+	}
+
+	// collectFunc collects the metrics of a collector.
+	collectFunc func(s *scrape) error
+)
+
+// WithCacheTTL sets the time the results of a scrape are reused for. Zero refreshes them at
+// every scrape. Defaults to DefaultCacheTTL.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *Collector) {
+		c.cacheTTL = ttl
+	}
+}
+
+// WithConcurrency sets the maximum number of concurrent API requests of a scrape. Defaults to
+// DefaultConcurrency.
+func WithConcurrency(n int) Option {
+	return func(c *Collector) {
+		c.concurrency = n
+	}
+}
+
+// WithCollectors sets the enabled collectors. Defaults to DefaultCollectors.
+func WithCollectors(names ...string) Option {
+	return func(c *Collector) {
+		c.collectors = names
+	}
+}
+
+// NewCollector creates a collector using the client. It returns an error for unknown
+// collector names.
+func NewCollector(client *librenms.Client, opts ...Option) (*Collector, error) {
+	c := &Collector{
+		client:      client,
+		cacheTTL:    DefaultCacheTTL,
+		concurrency: DefaultConcurrency,
+		collectors:  DefaultCollectors,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.concurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be at least 1, got %d", c.concurrency)
+	}
+	for _, name := range c.collectors {
+		if collectFuncs[name] == nil {
+			return nil, fmt.Errorf("unknown collector %q", name)
+		}
+	}
+	return c, nil
+}
+
+// collectFuncs are the collectors by name.
+var collectFuncs = map[string]collectFunc{
+	CollectorDevices:      collectDevices,
+	CollectorAvailability: collectAvailability,
+	CollectorAlerts:       collectAlerts,
+	CollectorBGP:          collectBGP,
+	CollectorOSPF:         collectOSPF,
+	CollectorServices:     collectServices,
+	CollectorPorts:        collectPorts,
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		deviceUpDesc, availabilityDesc, alertsDesc, bgpStateDesc, ospfStateDesc,
+		serviceStatusDesc, portOperUpDesc, portAdminUpDesc, collectorSuccessDesc, collectorDurationDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector. Concurrent scrapes wait for a single refresh.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	if c.metrics == nil || !time.Now().Before(c.expires) {
+		c.metrics = c.refresh()
+		c.expires = time.Now().Add(c.cacheTTL)
+	}
+	metrics := c.metrics
+	c.mu.Unlock()
+
+	for _, m := range metrics {
+		ch <- m
+	}
+}
+
+// refresh runs the enabled collectors concurrently and returns their metrics.
+func (c *Collector) refresh() []prometheus.Metric {
+	s := &scrape{
+		client:    c.client,
+		sem:       make(chan struct{}, c.concurrency),
+		hostnames: make(map[types.Int]string),
+		seen:      make(map[string]bool),
+	}
+	devicesErr := s.loadDevices()
+
+	var wg sync.WaitGroup
+	for _, name := range c.collectors {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			start := time.Now()
+			var err error
+			if devicesErr != nil && (name == CollectorDevices || name == CollectorAvailability) {
+				err = devicesErr
+			} else {
+				err = collectFuncs[name](s)
+			}
+			success := 1.0
+			if err != nil {
+				success = 0
+			}
+			s.add(collectorSuccessDesc, success, name)
+			s.add(collectorDurationDesc, time.Since(start).Seconds(), name)
+		}(name)
+	}
+	wg.Wait()
+	return s.metrics
+}
+
+// loadDevices lists the devices, used by the collectors to name devices.
+func (s *scrape) loadDevices() error {
+	var resp *types.DeviceResponse
+	err := s.call(func() (err error) {
+		resp, err = s.client.Device.List(nil)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.devices = resp.Devices
+	for _, device := range resp.Devices {
+		s.hostnames[device.DeviceID] = string(device.Hostname)
+	}
+	return nil
+}
+
+// call runs an API request once a concurrency slot is free.
+func (s *scrape) call(fn func() error) error {
+	s.sem <- struct{}{}
+	defer func() { <-s.sem }()
+	return fn()
+}
+
+// add adds a gauge metric. Metrics with the labels of an earlier one are dropped, as the
+// registry rejects duplicates, e.g. ports sharing a name on a device.
+func (s *scrape) add(desc *prometheus.Desc, value float64, labels ...string) {
+	key := desc.String() + "\xff" + strings.Join(labels, "\xff")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[key] {
+		return
+	}
+	s.seen[key] = true
+	s.metrics = append(s.metrics, prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...))
+}
+
+// hostname returns the hostname of a device, or its ID when it is unknown.
+func (s *scrape) hostname(deviceID types.Int) string {
+	if hostname, ok := s.hostnames[deviceID]; ok {
+		return hostname
+	}
+	return strconv.Itoa(int(deviceID))
+}
+
+func collectDevices(s *scrape) error {
+	for _, device := range s.devices {
+		if device.Disabled {
+			continue
+		}
+		s.add(deviceUpDesc, boolValue(bool(device.Status)), string(device.Hostname), string(device.SysName), string(device.OS), string(device.Location))
+	}
+	return nil
+}
+
+// collectAvailability requests the availability of every enabled device.
+func collectAvailability(s *scrape) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, device := range s.devices {
+		if device.Disabled {
+			continue
+		}
+		wg.Add(1)
+		go func(device types.Device) {
+			defer wg.Done()
+			var resp *types.DeviceAvailabilityResponse
+			err := s.call(func() (err error) {
+				resp, err = s.client.Device.GetAvailability(strconv.Itoa(int(device.DeviceID)))
+				return err
+			})
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("device %s: %w", device.Hostname, err))
+				mu.Unlock()
+				return
+			}
+			for _, availability := range resp.Availability {
+				s.add(availabilityDesc, float64(availability.AvailabilityPerc), string(device.Hostname), strconv.Itoa(int(availability.Duration)))
+			}
+		}(device)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+func collectAlerts(s *scrape) error {
+	var resp *types.AlertsResponse
+	err := s.call(func() (err error) {
+		resp, err = s.client.Alert.List(nil)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	type key struct{ severity, ruleID, rule, state string }
+	counts := make(map[key]float64)
+	for _, alert := range resp.Alerts {
+		state, ok := alertStates[alert.State]
+		if !ok {
+			continue
+		}
+		counts[key{string(alert.Severity), strconv.Itoa(int(alert.RuleID)), string(alert.Name), state}]++
+	}
+	for k, count := range counts {
+		s.add(alertsDesc, count, k.severity, k.ruleID, k.rule, k.state)
+	}
+	return nil
+}
+
+func collectBGP(s *scrape) error {
+	var resp *types.BGPResponse
+	err := s.call(func() (err error) {
+		resp, err = s.client.Routing.ListBGP(nil)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, session := range resp.BGPSessions {
+		state, _ := types.ParseBGPPeerState(string(session.BGPPeerState))
+		s.add(bgpStateDesc, float64(state),
+			s.hostname(session.DeviceID), string(session.BGPPeerIdentifier), strconv.Itoa(int(session.BGPPeerRemoteAS)))
+	}
+	return nil
+}
+
+func collectOSPF(s *scrape) error {
+	var resp *types.OSPFResponse
+	err := s.call(func() (err error) {
+		resp, err = s.client.Routing.ListOSPF("")
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, neighbor := range resp.OSPFNeighbors {
+		s.add(ospfStateDesc, float64(neighbor.OSPFNbrState),
+			s.hostname(neighbor.DeviceID), string(neighbor.OSPFNbrRtrID), string(neighbor.OSPFNbrIPAddr))
+	}
+	return nil
+}
+
+func collectServices(s *scrape) error {
+	var resp *types.ServiceResponse
+	err := s.call(func() (err error) {
+		resp, err = s.client.Service.List()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, service := range resp.Services {
+		s.add(serviceStatusDesc, float64(service.Status), s.hostname(service.DeviceID), string(service.Name), string(service.Type))
+	}
+	return nil
+}
+
+func collectPorts(s *scrape) error {
+	var resp *types.PortsResponse
+	err := s.call(func() (err error) {
+		resp, err = s.client.Port.GetAllPorts(&types.PortsQueryParams{Columns: portColumns.String()})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, port := range resp.Ports {
+		hostname := s.hostname(port.DeviceID)
+		s.add(portOperUpDesc, boolValue(port.IfOperStatus == "up"), hostname, string(port.IfName))
+		s.add(portAdminUpDesc, boolValue(port.IfAdminStatus == "up"), hostname, string(port.IfName))
+	}
+	return nil
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,145 @@
+package exporter_test
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go/exporter"
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// seed adds a small network to the server.
+func seed(srv *librenmstest.Server) {
+	srv.AddDevice(types.Device{Hostname: "core1", SysName: "core1.example.net", OS: "iosxe", Location: "DC1", Status: true})
+	srv.AddDevice(types.Device{Hostname: "core2", SysName: "core2.example.net", OS: "junos", Location: "DC2"})
+	srv.AddDevice(types.Device{Hostname: "lab1", Disabled: true})
+	srv.SetAvailability(1, types.DeviceAvailability{Duration: 86400, AvailabilityPerc: 99.5})
+	srv.AddAlert(types.Alert{DeviceID: 2, RuleID: 3, Name: "Device Down", Severity: "critical", State: 1})
+	srv.AddAlert(types.Alert{DeviceID: 1, RuleID: 4, Name: "Port Errors", Severity: "warning", State: 2})
+	srv.AddAlert(types.Alert{DeviceID: 1, RuleID: 4, Name: "Port Errors", Severity: "warning", State: 2})
+	srv.AddAlert(types.Alert{DeviceID: 1, RuleID: 5, Name: "Recovered", Severity: "ok", State: 0})
+	srv.AddBGPSession(types.BGPSession{DeviceID: 1, BGPPeerIdentifier: "10.0.0.2", BGPPeerRemoteAS: 65002, BGPPeerState: "established"})
+	srv.AddBGPSession(types.BGPSession{DeviceID: 2, BGPPeerIdentifier: "10.0.0.1", BGPPeerRemoteAS: 65001, BGPPeerState: "active"})
+	srv.AddOSPFNeighbor(types.OSPFNeighbor{DeviceID: 1, OSPFNbrRtrID: "10.255.0.2", OSPFNbrIPAddr: "10.1.0.2", OSPFNbrState: types.OSPFStateFull})
+	srv.AddService(types.Service{DeviceID: 1, Name: "web", Type: "http", Status: 2})
+	srv.AddPort(types.Port{DeviceID: 1, IfName: "Gi0/1", IfOperStatus: "up", IfAdminStatus: "up"})
+	srv.AddPort(types.Port{DeviceID: 1, IfName: "Gi0/2", IfOperStatus: "down", IfAdminStatus: "up"})
+}
+
+func TestCollector(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	seed(srv)
+	collector, err := exporter.NewCollector(srv.Client())
+	r.NoError(err, "NewCollector returned an error")
+
+	expected := `
+# HELP librenms_alerts Number of open alerts by severity, rule and state.
+# TYPE librenms_alerts gauge
+librenms_alerts{rule="Device Down",rule_id="3",severity="critical",state="alert"} 1
+librenms_alerts{rule="Port Errors",rule_id="4",severity="warning",state="acknowledged"} 2
+# HELP librenms_bgp_session_state BGP finite state machine state: 1=idle, 2=connect, 3=active, 4=opensent, 5=openconfirm, 6=established, 0=unknown.
+# TYPE librenms_bgp_session_state gauge
+librenms_bgp_session_state{hostname="core1",peer="10.0.0.2",remote_as="65002"} 6
+librenms_bgp_session_state{hostname="core2",peer="10.0.0.1",remote_as="65001"} 3
+# HELP librenms_device_availability_percent Availability of the device over the duration, in seconds, as computed by LibreNMS.
+# TYPE librenms_device_availability_percent gauge
+librenms_device_availability_percent{duration="86400",hostname="core1"} 99.5
+# HELP librenms_device_up Whether LibreNMS sees the device as up (1) or down (0). Disabled devices are skipped.
+# TYPE librenms_device_up gauge
+librenms_device_up{hostname="core1",location="DC1",os="iosxe",sysname="core1.example.net"} 1
+librenms_device_up{hostname="core2",location="DC2",os="junos",sysname="core2.example.net"} 0
+# HELP librenms_ospf_neighbor_state OSPF neighbour state: 1=down, 2=attempt, 3=init, 4=twoWay, 5=exchangeStart, 6=exchange, 7=loading, 8=full, 0=unknown.
+# TYPE librenms_ospf_neighbor_state gauge
+librenms_ospf_neighbor_state{address="10.1.0.2",hostname="core1",neighbor="10.255.0.2"} 8
+# HELP librenms_port_admin_up Whether the administrative status of the port is up (1) or not (0).
+# TYPE librenms_port_admin_up gauge
+librenms_port_admin_up{hostname="core1",ifname="Gi0/1"} 1
+librenms_port_admin_up{hostname="core1",ifname="Gi0/2"} 1
+# HELP librenms_port_oper_up Whether the operational status of the port is up (1) or not (0).
+# TYPE librenms_port_oper_up gauge
+librenms_port_oper_up{hostname="core1",ifname="Gi0/1"} 1
+librenms_port_oper_up{hostname="core1",ifname="Gi0/2"} 0
+# HELP librenms_service_status Service status: 0=ok, 1=warning, 2=critical, 3=unknown.
+# TYPE librenms_service_status gauge
+librenms_service_status{hostname="core1",service="web",type="http"} 2
+`
+	r.NoError(testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"librenms_alerts", "librenms_bgp_session_state", "librenms_device_availability_percent", "librenms_device_up",
+		"librenms_ospf_neighbor_state", "librenms_port_admin_up", "librenms_port_oper_up", "librenms_service_status"),
+		"Unexpected metrics")
+
+	registry := prometheus.NewPedanticRegistry()
+	r.NoError(registry.Register(collector), "Failed to register the collector")
+	problems, err := testutil.GatherAndLint(registry)
+	r.NoError(err, "Failed to lint the metrics")
+	r.Empty(problems, "Unexpected lint problems")
+}
+
+func TestCollector_CacheAndErrors(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	seed(srv)
+	collector, err := exporter.NewCollector(srv.Client(),
+		exporter.WithCollectors(exporter.CollectorDevices, exporter.CollectorBGP),
+		exporter.WithCacheTTL(time.Hour))
+	r.NoError(err, "NewCollector returned an error")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			testutil.CollectAndCount(collector)
+		}()
+	}
+	wg.Wait()
+	r.Len(srv.Requests(), 2, "Expected a single refresh for concurrent scrapes")
+
+	srv.InjectError("bgp", http.StatusInternalServerError, 0)
+	collector, err = exporter.NewCollector(srv.Client(),
+		exporter.WithCollectors(exporter.CollectorDevices, exporter.CollectorBGP),
+		exporter.WithCacheTTL(0))
+	r.NoError(err, "NewCollector returned an error")
+	r.NoError(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP librenms_collector_success Whether the collector succeeded during the last refresh.
+# TYPE librenms_collector_success gauge
+librenms_collector_success{collector="bgp"} 0
+librenms_collector_success{collector="devices"} 1
+`), "librenms_collector_success"), "Expected the BGP collector to fail")
+	r.Equal(0, testutil.CollectAndCount(collector, "librenms_bgp_session_state"), "Expected no BGP metrics")
+
+	_, err = exporter.NewCollector(srv.Client(), exporter.WithCollectors("devices", "isis"))
+	r.ErrorContains(err, `unknown collector "isis"`, "Expected an error for unknown collectors")
+	_, err = exporter.NewCollector(srv.Client(), exporter.WithConcurrency(0))
+	r.Error(err, "Expected an error for a zero concurrency")
+}
+
+func TestCollector_Concurrency(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	for i := 0; i < 12; i++ {
+		srv.AddDevice(types.Device{Hostname: types.String("sw" + string(rune('a'+i)))})
+	}
+	srv.InjectLatency("devices/*/availability", 20*time.Millisecond)
+	collector, err := exporter.NewCollector(srv.Client(),
+		exporter.WithCollectors(exporter.CollectorAvailability),
+		exporter.WithConcurrency(3))
+	r.NoError(err, "NewCollector returned an error")
+
+	start := time.Now()
+	testutil.CollectAndCount(collector)
+	elapsed := time.Since(start)
+	r.GreaterOrEqual(elapsed, 80*time.Millisecond, "Expected at most 3 concurrent requests")
+	r.Len(srv.Requests(), 13, "Expected the device list and an availability request per device")
+}
@@ -0,0 +1,24 @@
+module github.com/javen-yan/librenms-go/exporter
+
+go 1.21
+
+require (
+	github.com/hashicorp/go-cleanhttp v0.5.2
+	github.com/javen-yan/librenms-go v0.0.0-20261019044634-dda222b2ec53
+	github.com/prometheus/client_golang v1.19.1
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/google/go-querystring v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/prometheus/client_model v0.5.0 // indirect
+	github.com/prometheus/common v0.48.0 // indirect
+	github.com/prometheus/procfs v0.12.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
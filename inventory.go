@@ -35,7 +35,7 @@ func (i *InventoryAPI) GetInventory(hostname string, params *types.InventoryPara
 	}
 
 	var resp types.InventoryResponse
-	httpReq, err := i.client.newRequest(http.MethodGet, path, nil, queryParams)
+	httpReq, err := i.client.newRequest(opInventoryGetInventory, http.MethodGet, path, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +51,7 @@ func (i *InventoryAPI) GetInventory(hostname string, params *types.InventoryPara
 func (i *InventoryAPI) GetInventoryForDevice(hostname string) (*types.InventoryResponse, error) {
 	path := fmt.Sprintf("%s/%s/all", inventoryEndpoint, hostname)
 	var resp types.InventoryResponse
-	httpReq, err := i.client.newRequest(http.MethodGet, path, nil, nil)
+	httpReq, err := i.client.newRequest(opInventoryGetInventoryForDevice, http.MethodGet, path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +30,18 @@ type Client struct {
 	log     *slog.Logger
 	token   string
 
+	cassette   *cassetteConfig
+	middleware []Middleware
+	cache      *cache
+	coalescer  *coalescer
+	doer       Doer
+
+	// cacheNamespace is the namespace of the cache keys, set with WithCacheNamespace.
+	cacheNamespace string
+
+	// ctx is the context of the requests, set with WithContext.
+	ctx context.Context
+
 	// API interfaces
 	Device      *DeviceAPI
 	Alert       *AlertAPI
@@ -165,8 +177,42 @@ func NewClient(baseURL, token string, opts ...Option) (*Client, error) {
 	for _, opt := range opts {
 		opt(c)
 	}
+	if err := c.setupCassette(); err != nil {
+		return nil, err
+	}
+	c.doer = chain(c.client, c.middleware)
+	if c.cache != nil {
+		c.cache.namespace = c.cacheNamespace
+		if c.cache.namespace == "" {
+			c.cache.namespace = cacheNamespace(c.token)
+		}
+		c.doer = c.cache.wrap(c.doer)
+	}
 
-	// Initialize API interfaces
+	c.initAPIs()
+	return c, nil
+}
+
+// WithContext returns a shallow copy of the client sending its requests with ctx, e.g. to
+// cancel them or to set a deadline. The copy shares the HTTP client, middleware, cache and
+// coalescing of c:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+//	defer cancel()
+//	devices, err := client.WithContext(ctx).Device.List(nil)
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic("nil context")
+	}
+	c2 := new(Client)
+	*c2 = *c
+	c2.ctx = ctx
+	c2.initAPIs()
+	return c2
+}
+
+// initAPIs initializes the API interfaces.
+func (c *Client) initAPIs() {
 	c.Device = &DeviceAPI{client: c}
 	c.Alert = &AlertAPI{client: c}
 	c.AlertRule = &AlertRuleAPI{client: c}
@@ -179,8 +225,6 @@ func NewClient(baseURL, token string, opts ...Option) (*Client, error) {
 	c.Routing = &RoutingAPI{client: c}
 	c.Switching = &SwitchingAPI{client: c}
 	c.Logs = &LogsAPI{client: c}
-
-	return c, nil
 }
 
 // New is an alias for NewClient for backward compatibility
@@ -198,9 +242,9 @@ func (c *Client) GetToken() string {
 	return c.token
 }
 
-// newRequest creates a new HTTP request with the given method and path.
+// newRequest creates a new HTTP request for the operation with the given method and path.
 // A relative URI should be provided and should not have a leading slash.
-func (c *Client) newRequest(method, uri string, body any, query *url.Values) (*http.Request, error) {
+func (c *Client) newRequest(op operation, method, uri string, body any, query *url.Values) (*http.Request, error) {
 	var buf io.ReadWriter
 	if body != nil {
 		buf = &bytes.Buffer{}
@@ -210,7 +254,11 @@ func (c *Client) newRequest(method, uri string, body any, query *url.Values) (*h
 			return nil, err
 		}
 	}
-	ctx := context.Background()
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = withRequestInfo(ctx, RequestInfo{Operation: op.name, Route: op.route, Attempt: 1})
 
 	// Parse the URI and construct the full URL
 	fullURL, err := c.baseURL.Parse(uri)
@@ -245,32 +293,57 @@ func (c *Client) newRequest(method, uri string, body any, query *url.Values) (*h
 // use do() which JSON-decodes and closes the response body, but if there is a non-JSON
 // endpoint or other reason to not decode, this can be used.
 func (c *Client) rawDo(req *http.Request) (*http.Response, error) {
-	resp, err := c.client.Do(req)
+	resp, err := c.doer.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
-	c.log.LogAttrs(context.Background(), slog.LevelDebug, "http response", logResponseAttr(resp))
+	c.log.LogAttrs(req.Context(), slog.LevelDebug, "http response", logResponseAttr(resp))
 	return resp, checkResponse(resp)
 }
 
 // do sends an HTTP request and decodes the JSON response into the provided response object.
+// With WithRequestCoalescing, identical concurrent GET requests share a single response.
 func (c *Client) do(req *http.Request, respObj any) error {
 	if respObj == nil {
 		return errors.New("response object cannot be nil")
 	}
 
+	if c.coalescer != nil && req.Method == http.MethodGet {
+		body, err := c.coalescer.do(req, c.readBody)
+		if err != nil {
+			return err
+		}
+		return decodeResponse(bytes.NewReader(body), respObj)
+	}
+
 	resp, err := c.rawDo(req)
 	if err != nil {
 		return err
 	}
 	defer closeBody(resp.Body)
+	return decodeResponse(resp.Body, respObj)
+}
+
+// readBody sends an HTTP request and returns the response body.
+func (c *Client) readBody(req *http.Request) ([]byte, error) {
+	resp, err := c.rawDo(req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+	return io.ReadAll(resp.Body)
+}
 
+// decodeResponse decodes a JSON response body into respObj, or copies it when respObj is
+// an io.Writer.
+func decodeResponse(body io.Reader, respObj any) error {
+	var err error
 	switch v := respObj.(type) {
 	case io.Writer:
-		_, err = io.Copy(v, resp.Body)
+		_, err = io.Copy(v, body)
 	default:
-		decErr := json.NewDecoder(resp.Body).Decode(v)
+		decErr := json.NewDecoder(body).Decode(v)
 		if errors.Is(decErr, io.EOF) {
 			decErr = nil // No content to decode, treat as success
 		}
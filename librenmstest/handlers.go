@@ -0,0 +1,1336 @@
+package librenmstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// defaultPortColumns are the port columns returned when no columns are requested.
+const defaultPortColumns = "port_id,ifName"
+
+// handleDevices serves the devices endpoints.
+func (s *Server) handleDevices(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		s.listDevices(w, r)
+		return
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		s.createDevice(w, r)
+		return
+	case len(segments) == 1:
+		notImplemented(w, r)
+		return
+	}
+
+	i := s.deviceIndex(segments[1])
+	if i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Device %s does not exist", segments[1]))
+		return
+	}
+	device := &s.devices[i]
+
+	switch {
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		writeOK(w, "", map[string]any{"count": 1, "devices": []types.Device{*device}})
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		deleted := *device
+		s.deleteDevice(i)
+		writeOK(w, fmt.Sprintf("Removed device %s\n", deleted.Hostname), map[string]any{"count": 1, "devices": []types.Device{deleted}})
+	case len(segments) == 2 && r.Method == http.MethodPatch:
+		s.updateDevice(w, r, device)
+	case len(segments) == 3 && segments[2] == "maintenance" && r.Method == http.MethodGet:
+		writeOK(w, "", map[string]any{"is_under_maintenance": s.inMaintenance(device.DeviceID, time.Now())})
+	case len(segments) == 3 && segments[2] == "maintenance" && r.Method == http.MethodPost:
+		window, ok := decodeMaintenance(w, r)
+		if !ok {
+			return
+		}
+		s.maintenance[device.DeviceID] = window
+		writeOK(w, fmt.Sprintf("Device %s (%d) will begin maintenance mode at %s for %s",
+			device.Hostname, device.DeviceID, window.start.Format(types.MaintenanceStartLayout), window.end.Sub(window.start)), nil)
+	case len(segments) == 4 && segments[2] == "rename" && r.Method == http.MethodPatch:
+		if s.deviceIndex(segments[3]) >= 0 {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Device %s already exists", segments[3]))
+			return
+		}
+		device.Hostname = types.String(segments[3])
+		writeOK(w, "Device has been renamed", nil)
+	case len(segments) == 3 && segments[2] == "groups" && r.Method == http.MethodGet:
+		groups := make([]types.DeviceGroup, 0)
+		for j := range s.groups {
+			members, err := s.groupMembers(&s.groups[j])
+			if err == nil && containsInt(members, int(device.DeviceID)) {
+				groups = append(groups, s.groups[j].DeviceGroup)
+			}
+		}
+		writeOK(w, "Found "+strconv.Itoa(len(groups))+" device groups", map[string]any{"count": len(groups), "groups": groups})
+	case len(segments) == 3 && segments[2] == "availability" && r.Method == http.MethodGet:
+		availability := append(make([]types.DeviceAvailability, 0), s.available[device.DeviceID]...)
+		writeOK(w, "", map[string]any{"count": len(availability), "availability": availability})
+	case len(segments) == 3 && segments[2] == "ports" && r.Method == http.MethodGet:
+		ports := make([]map[string]any, 0)
+		for _, port := range s.ports {
+			if port.DeviceID == device.DeviceID {
+				ports = append(ports, project(port, r.URL.Query().Get("columns"), "ifName"))
+			}
+		}
+		writeOK(w, "", map[string]any{"count": len(ports), "ports": ports})
+	case len(segments) == 4 && segments[2] == "port" && r.Method == http.MethodPatch:
+		// LibreNMS keeps port notes in the device attributes, which the server doesn't serve,
+		// so the notes are only checked.
+		var req struct {
+			Notes *string `json:"notes"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Notes == nil {
+			writeError(w, http.StatusBadRequest, "Missing notes")
+			return
+		}
+		portID, _ := strconv.Atoi(segments[3])
+		if !slices.ContainsFunc(s.ports, func(p types.Port) bool { return int(p.PortID) == portID && p.DeviceID == device.DeviceID }) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Port %s does not exist on device %s", segments[3], device.Hostname))
+			return
+		}
+		writeOK(w, "Port notes updated", nil)
+	case len(segments) == 3 && segments[2] == "vlans" && r.Method == http.MethodGet:
+		vlans := make([]map[string]any, 0)
+		for _, vlan := range s.vlans {
+			if vlan.DeviceID == device.DeviceID {
+				vlans = append(vlans, project(vlan, r.URL.Query().Get("columns"), ""))
+			}
+		}
+		writeOK(w, "", map[string]any{"count": len(vlans), "vlans": vlans})
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// listDevices lists devices filtered like list_devices, by the type and query parameters.
+func (s *Server) listDevices(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter, search := query.Get("type"), query.Get("query")
+
+	devices := make([]types.Device, 0)
+	for _, device := range s.devices {
+		if matchDevice(device, filter, search) {
+			devices = append(devices, device)
+		}
+	}
+	if order := query.Get("order"); order != "" {
+		column, desc := strings.CutSuffix(order, " DESC")
+		column = strings.TrimSuffix(column, " ASC")
+		sort.SliceStable(devices, func(a, b int) bool {
+			if desc {
+				return compareField(devices[b], devices[a], column) < 0
+			}
+			return compareField(devices[a], devices[b], column) < 0
+		})
+	}
+	writeOK(w, "", map[string]any{"count": len(devices), "devices": devices})
+}
+
+// matchDevice reports whether a device matches a list_devices filter type and query.
+func matchDevice(device types.Device, filter, search string) bool {
+	switch filter {
+	case "", "all":
+		return true
+	case "active":
+		return !bool(device.Ignore) && !bool(device.Disabled)
+	case "ignored":
+		return bool(device.Ignore) && !bool(device.Disabled)
+	case "up":
+		return bool(device.Status) && !bool(device.Ignore) && !bool(device.Disabled)
+	case "down":
+		return !bool(device.Status) && !bool(device.Ignore) && !bool(device.Disabled)
+	case "disabled":
+		return bool(device.Disabled)
+	case "ipv4":
+		return string(device.IP) == search
+	case "hostname", "sysName", "display", "location", "hardware", "features", "serial", "version":
+		return strings.Contains(strings.ToLower(fieldOf(device, filter)), strings.ToLower(search))
+	default:
+		return matchFields(device, map[string]string{filter: search})
+	}
+}
+
+func (s *Server) createDevice(w http.ResponseWriter, r *http.Request) {
+	var req types.DeviceCreateRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Hostname == "" {
+		writeError(w, http.StatusBadRequest, "Missing the device hostname")
+		return
+	}
+	if s.deviceIndex(req.Hostname) >= 0 {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Already have device %s", req.Hostname))
+		return
+	}
+
+	device := types.Device{
+		Hostname:            types.String(req.Hostname),
+		Display:             types.String(req.Display),
+		Hardware:            types.String(req.Hardware),
+		Location:            types.String(req.Location),
+		LocationID:          types.Int(req.LocationID),
+		OS:                  types.String(valueOr(req.OS, "generic")),
+		OverrideSysLocation: types.Bool(req.OverrideSysLocation),
+		PollerGroup:         types.Int(req.PollerGroup),
+		Port:                types.Int(req.Port),
+		PortAssociationMode: types.Int(req.PortAssocMode),
+		AuthAlgorithm:       types.String(req.SNMPAuthAlgo),
+		AuthLevel:           types.String(req.SNMPAuthLevel),
+		AuthName:            types.String(req.SNMPAuthName),
+		AuthPass:            types.String(req.SNMPAuthPass),
+		CryptoAlgorithm:     types.String(req.SNMPCrytoAlgo),
+		CryptoPass:          types.String(req.SNMPCryptoPass),
+		Community:           types.String(req.SNMPCommunity),
+		SNMPDisable:         types.Bool(req.SNMPDisable),
+		SNMPVersion:         types.String(valueOr(req.SNMPVersion, "v2c")),
+		SysName:             types.String(valueOr(req.SysName, req.Hostname)),
+		Transport:           types.String(valueOr(req.Transport, "udp")),
+		Status:              true,
+	}
+	if device.Port == 0 {
+		device.Port = 161
+	}
+	if device.PortAssociationMode == 0 {
+		device.PortAssociationMode = 1
+	}
+	if device.LocationID == 0 && device.Location != "" {
+		for _, location := range s.locations {
+			if strings.EqualFold(string(location.Name), string(device.Location)) {
+				device.LocationID = location.ID
+			}
+		}
+	}
+
+	device = s.addDevice(device)
+	writeOK(w, fmt.Sprintf("Device %s (%d) has been added successfully", device.Hostname, device.DeviceID),
+		map[string]any{"count": 1, "devices": []types.Device{device}})
+}
+
+func (s *Server) updateDevice(w http.ResponseWriter, r *http.Request, device *types.Device) {
+	var req struct {
+		Field any `json:"field"`
+		Data  any `json:"data"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	fields, data := toSlice(req.Field), toSlice(req.Data)
+	if len(fields) == 0 || len(fields) != len(data) {
+		writeError(w, http.StatusBadRequest, "Field and data must have the same number of values")
+		return
+	}
+
+	columns := jsonFields(reflect.TypeOf(types.Device{}))
+	update := make(map[string]any, len(fields))
+	for i, field := range fields {
+		name := fmt.Sprint(field)
+		if !columns[name] || name == "device_id" {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Device field %s can't be updated", name))
+			return
+		}
+		update[name] = data[i]
+	}
+
+	updated := *device
+	if err := patch(&updated, update); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Device fields could not be updated: %v", err))
+		return
+	}
+	if _, ok := update["location_id"]; ok {
+		if i := s.locationIndex(updated.LocationID); i >= 0 {
+			updated.Location = s.locations[i].Name
+		}
+	}
+	*device = updated
+	writeOK(w, "Device fields have been updated", nil)
+}
+
+// deleteDevice removes a device with its ports, services and alerts.
+func (s *Server) deleteDevice(i int) {
+	id := s.devices[i].DeviceID
+	s.devices = append(s.devices[:i], s.devices[i+1:]...)
+	s.ports = filterSlice(s.ports, func(p types.Port) bool { return p.DeviceID != id })
+	s.services = filterSlice(s.services, func(svc types.Service) bool { return svc.DeviceID != id })
+	s.alerts = filterSlice(s.alerts, func(a types.Alert) bool { return a.DeviceID != id })
+	delete(s.maintenance, id)
+}
+
+// handleDeviceGroups serves the devicegroups endpoints.
+func (s *Server) handleDeviceGroups(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		groups := make([]types.DeviceGroup, 0, len(s.groups))
+		for _, group := range s.groups {
+			groups = append(groups, group.DeviceGroup)
+		}
+		writeOK(w, fmt.Sprintf("Found %d device groups", len(groups)), map[string]any{"count": len(groups), "groups": groups})
+		return
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		s.createDeviceGroup(w, r)
+		return
+	case len(segments) == 1:
+		notImplemented(w, r)
+		return
+	}
+
+	i := s.groupIndex(segments[1])
+	if i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Device group %s not found", segments[1]))
+		return
+	}
+	group := &s.groups[i]
+
+	switch {
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		members, err := s.groupMembers(group)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		devices := make([]types.DeviceGroupMember, 0, len(members))
+		for _, id := range members {
+			devices = append(devices, types.DeviceGroupMember{ID: types.Int(id)})
+		}
+		writeOK(w, "", map[string]any{"count": len(devices), "devices": devices})
+	case len(segments) == 2 && r.Method == http.MethodPatch:
+		var req types.DeviceGroupUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		updated := *group
+		updated.Name = valueOr(types.String(req.Name), updated.Name)
+		updated.Description = valueOr(types.String(req.Description), updated.Description)
+		updated.Type = valueOr(types.String(req.Type), updated.Type)
+		if req.Rules != "" {
+			updated.Rules = types.DeviceGroupRuleContainer{}
+			if err := json.Unmarshal([]byte(req.Rules), &updated.Rules); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid rules: %v", err))
+				return
+			}
+		}
+		if req.Devices != nil {
+			updated.members = append([]int(nil), req.Devices...)
+		}
+		*group = updated
+		writeOK(w, fmt.Sprintf("Device group %s updated", segments[1]), nil)
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		s.groups = append(s.groups[:i], s.groups[i+1:]...)
+		writeOK(w, fmt.Sprintf("Device group %s deleted", segments[1]), nil)
+	case len(segments) == 3 && segments[2] == "maintenance" && r.Method == http.MethodPost:
+		members, err := s.groupMembers(group)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		window, ok := decodeMaintenance(w, r)
+		if !ok {
+			return
+		}
+		for _, id := range members {
+			s.maintenance[types.Int(id)] = window
+		}
+		writeOK(w, fmt.Sprintf("Device group %s (%d) will begin maintenance mode at %s for %s",
+			group.Name, group.ID, window.start.Format(types.MaintenanceStartLayout), window.end.Sub(window.start)), nil)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+func (s *Server) createDeviceGroup(w http.ResponseWriter, r *http.Request) {
+	var req types.DeviceGroupCreateRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	switch {
+	case req.Name == "":
+		writeError(w, http.StatusBadRequest, "Missing the device group name")
+		return
+	case s.groupIndex(req.Name) >= 0:
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Device group %s already exists", req.Name))
+		return
+	case !strings.EqualFold(req.Type, "static") && !strings.EqualFold(req.Type, "dynamic"):
+		writeError(w, http.StatusBadRequest, "Type must be static or dynamic")
+		return
+	case strings.EqualFold(req.Type, "dynamic") && req.Rules == "":
+		writeError(w, http.StatusBadRequest, "Dynamic groups require rules")
+		return
+	}
+
+	group := deviceGroup{
+		DeviceGroup: types.DeviceGroup{Name: types.String(req.Name), Description: types.String(req.Description), Type: types.String(strings.ToLower(req.Type))},
+		members:     append([]int(nil), req.Devices...),
+	}
+	if req.Rules != "" {
+		if err := json.Unmarshal([]byte(req.Rules), &group.Rules); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid rules: %v", err))
+			return
+		}
+	}
+	group.ID = s.assignID("devicegroups", 0)
+	s.groups = append(s.groups, group)
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"status":  "ok",
+		"id":      group.ID,
+		"message": fmt.Sprintf("Device group %s created", group.Name),
+	})
+}
+
+// handleResources serves the resources endpoints. Only locations, links, FDB, NAC and ARP
+// entries are supported.
+func (s *Server) handleResources(w http.ResponseWriter, r *http.Request, segments []string) {
+	if r.Method == http.MethodGet && len(segments) >= 3 {
+		switch segments[1] {
+		case "fdb":
+			s.handleFDB(w, r, segments)
+			return
+		case "nac":
+			s.handleNAC(w, r, segments)
+			return
+		}
+	}
+	switch {
+	case len(segments) == 2 && segments[1] == "locations" && r.Method == http.MethodGet:
+		writeOK(w, "", map[string]any{"count": len(s.locations), "locations": s.locations})
+	case len(segments) == 2 && segments[1] == "links" && r.Method == http.MethodGet:
+		links := make([]map[string]any, 0, len(s.links))
+		for _, link := range s.links {
+			links = append(links, project(link, r.URL.Query().Get("columns"), ""))
+		}
+		writeOK(w, "", map[string]any{"count": len(links), "links": links})
+	case len(segments) == 2 && segments[1] == "vlans" && r.Method == http.MethodGet:
+		vlans := make([]map[string]any, 0, len(s.vlans))
+		for _, vlan := range s.vlans {
+			vlans = append(vlans, project(vlan, r.URL.Query().Get("columns"), ""))
+		}
+		writeOK(w, "", map[string]any{"count": len(vlans), "vlans": vlans})
+	case len(segments) == 3 && segments[1] == "links" && r.Method == http.MethodGet:
+		id, _ := strconv.Atoi(segments[2])
+		for _, link := range s.links {
+			if int(link.ID) == id {
+				writeOK(w, "", map[string]any{"count": 1, "links": []types.Link{link}})
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Link %s does not exist", segments[2]))
+	case len(segments) >= 4 && segments[1] == "ip" && segments[2] == "arp" && r.Method == http.MethodGet:
+		s.handleARP(w, r, segments)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// handleFDB serves resources/fdb/:mac and resources/fdb/:mac/detail. Like LibreNMS, it
+// answers 404 when no entry matches.
+func (s *Server) handleFDB(w http.ResponseWriter, r *http.Request, segments []string) {
+	mac := segments[2]
+	var entries []types.PortFDB
+	for _, entry := range s.fdb {
+		if sameMAC(entry.MACAddress, mac) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		writeError(w, http.StatusNotFound, "Fdb entry does not exist")
+		return
+	}
+
+	switch {
+	case len(segments) == 3:
+		writeOK(w, "", map[string]any{"count": len(entries), "ports_fdb": entries})
+	case len(segments) == 4 && segments[3] == "detail":
+		details := make([]types.PortFDBDetail, 0, len(entries))
+		for _, entry := range entries {
+			detail := types.PortFDBDetail{LastSeen: entry.UpdatedAt, UpdatedAt: entry.UpdatedAt}
+			for _, port := range s.ports {
+				if port.PortID == entry.PortID {
+					detail.IfName, detail.IfAlias, detail.IfDescr = port.IfName, port.IfAlias, port.IfDescr
+				}
+			}
+			if i := s.deviceIndex(strconv.Itoa(int(entry.DeviceID))); i >= 0 {
+				detail.Hostname, detail.SysName = s.devices[i].Hostname, s.devices[i].SysName
+			}
+			details = append(details, detail)
+		}
+		writeOK(w, "", map[string]any{"count": len(details), "mac": mac, "ports_fdb": details})
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// handleNAC serves resources/nac/:mac. Like LibreNMS, it answers 404 when no entry matches.
+func (s *Server) handleNAC(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 3 {
+		notImplemented(w, r)
+		return
+	}
+	var entries []types.PortNAC
+	for _, entry := range s.nac {
+		if sameMAC(entry.MACAddress, segments[2]) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		writeError(w, http.StatusNotFound, "Nac entry does not exist")
+		return
+	}
+	writeOK(w, "", map[string]any{"count": len(entries), "ports_nac": entries})
+}
+
+// handleARP serves resources/ip/arp/:query, where the query is an IP address, a MAC address,
+// a CIDR network, or "all" with the device parameter.
+func (s *Server) handleARP(w http.ResponseWriter, r *http.Request, segments []string) {
+	query := strings.Join(segments[3:], "/")
+	var match func(types.ARPEntry) bool
+	if query == "all" {
+		device := r.URL.Query().Get("device")
+		i := s.deviceIndex(device)
+		if i < 0 {
+			writeError(w, http.StatusBadRequest, "Device "+device+" does not exist")
+			return
+		}
+		ports := make(map[types.Int]bool)
+		for _, port := range s.ports {
+			if port.DeviceID == s.devices[i].DeviceID {
+				ports[port.PortID] = true
+			}
+		}
+		match = func(entry types.ARPEntry) bool { return ports[entry.PortID] }
+	} else if prefix, err := netip.ParsePrefix(query); err == nil {
+		match = func(entry types.ARPEntry) bool {
+			addr, err := netip.ParseAddr(string(entry.IPv4Address))
+			return err == nil && prefix.Contains(addr)
+		}
+	} else {
+		match = func(entry types.ARPEntry) bool {
+			return string(entry.IPv4Address) == query || sameMAC(entry.MACAddress, query)
+		}
+	}
+
+	entries := make([]types.ARPEntry, 0)
+	for _, entry := range s.arp {
+		if match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	writeOK(w, "", map[string]any{"count": len(entries), "arp": entries})
+}
+
+// handleLocations serves the location and locations endpoints.
+func (s *Server) handleLocations(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 1 && segments[0] == "locations" && r.Method == http.MethodPost {
+		var req types.LocationCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" {
+			writeError(w, http.StatusBadRequest, "Missing the location name")
+			return
+		}
+		location := types.Location{
+			ID:               s.assignID("locations", 0),
+			Name:             types.String(req.Name),
+			FixedCoordinates: req.FixedCoordinates,
+			Latitude:         types.Float64(req.Latitude),
+			Longitude:        types.Float64(req.Longitude),
+			Timestamp:        types.NewTime(time.Now().UTC().Truncate(time.Second)),
+		}
+		s.locations = append(s.locations, location)
+		writeOK(w, fmt.Sprintf("Location added with id #%d", location.ID), nil)
+		return
+	}
+	if len(segments) != 2 {
+		notImplemented(w, r)
+		return
+	}
+
+	i := -1
+	id, err := strconv.Atoi(segments[1])
+	for j, location := range s.locations {
+		if (err == nil && int(location.ID) == id) || string(location.Name) == segments[1] {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Location %s does not exist", segments[1]))
+		return
+	}
+	location := &s.locations[i]
+
+	switch {
+	case segments[0] == "location" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, types.LocationResponse{Status: "ok", Location: *location})
+	case segments[0] == "locations" && r.Method == http.MethodPatch:
+		var fields map[string]any
+		if !decodeBody(w, r, &fields) {
+			return
+		}
+		if len(fields) == 0 {
+			writeError(w, http.StatusBadRequest, "No fields to update")
+			return
+		}
+		if err := patch(location, fields); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Location could not be updated: %v", err))
+			return
+		}
+		for j := range s.devices {
+			if s.devices[j].LocationID == location.ID {
+				s.devices[j].Location = location.Name
+			}
+		}
+		writeOK(w, "Location updated successfully", nil)
+	case segments[0] == "locations" && r.Method == http.MethodDelete:
+		s.locations = append(s.locations[:i], s.locations[i+1:]...)
+		writeOK(w, fmt.Sprintf("Location %s has been deleted successfully", segments[1]), nil)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// handleServices serves the services endpoints.
+func (s *Server) handleServices(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		writeServices(w, s.services, r.URL.Query().Get("type"))
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		i := s.deviceIndex(segments[1])
+		if i < 0 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Device %s does not exist", segments[1]))
+			return
+		}
+		id := s.devices[i].DeviceID
+		writeServices(w, filterSlice(s.services, func(svc types.Service) bool { return svc.DeviceID == id }), r.URL.Query().Get("type"))
+	case len(segments) == 2 && r.Method == http.MethodPost:
+		i := s.deviceIndex(segments[1])
+		if i < 0 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Device %s does not exist", segments[1]))
+			return
+		}
+		var req types.ServiceCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Type == "" {
+			writeError(w, http.StatusBadRequest, "Missing the service type")
+			return
+		}
+		service := types.Service{
+			ID:          s.assignID("services", 0),
+			DeviceID:    s.devices[i].DeviceID,
+			Name:        types.String(req.Name),
+			Description: types.String(req.Description),
+			IP:          valueOr(types.String(req.IP), s.devices[i].Hostname),
+			Ignore:      req.Ignore,
+			Param:       types.String(req.Param),
+			Type:        types.String(req.Type),
+			Status:      3,
+			Changed:     types.Int64(time.Now().Unix()),
+		}
+		s.services = append(s.services, service)
+		writeOK(w, fmt.Sprintf("Service %s has been added to device %d (#%d)", service.Type, service.DeviceID, service.ID), nil)
+	case len(segments) == 2 && (r.Method == http.MethodPatch || r.Method == http.MethodDelete):
+		id, _ := strconv.Atoi(segments[1])
+		i := -1
+		for j, service := range s.services {
+			if int(service.ID) == id {
+				i = j
+			}
+		}
+		if i < 0 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Service %s does not exist", segments[1]))
+			return
+		}
+		if r.Method == http.MethodDelete {
+			s.services = append(s.services[:i], s.services[i+1:]...)
+			writeOK(w, "Service has been deleted successfully", nil)
+			return
+		}
+		var fields map[string]any
+		if !decodeBody(w, r, &fields) {
+			return
+		}
+		if len(fields) == 0 {
+			writeError(w, http.StatusBadRequest, "No fields to update")
+			return
+		}
+		if err := patch(&s.services[i], fields); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Service could not be updated: %v", err))
+			return
+		}
+		writeOK(w, "Service updated successfully", nil)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// writeServices writes services the way LibreNMS does, as a single nested list.
+func writeServices(w http.ResponseWriter, services []types.Service, serviceType string) {
+	if serviceType != "" {
+		services = filterSlice(services, func(svc types.Service) bool { return strings.EqualFold(string(svc.Type), serviceType) })
+	}
+	writeOK(w, "", map[string]any{"count": 1, "services": [][]types.Service{append([]types.Service{}, services...)}})
+}
+
+// handleAlerts serves the alerts endpoints.
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 1 && r.Method == http.MethodGet {
+		query := r.URL.Query()
+		filters := map[string]string{}
+		for key, field := range map[string]string{"state": "state", "severity": "severity", "alert_rule": "rule_id"} {
+			if value := query.Get(key); value != "" {
+				filters[field] = value
+			}
+		}
+		alerts := filterSlice(s.alerts, func(a types.Alert) bool { return matchFields(a, filters) })
+		if strings.HasSuffix(strings.ToUpper(query.Get("order")), "DESC") {
+			sort.SliceStable(alerts, func(a, b int) bool { return alerts[a].Timestamp.After(alerts[b].Timestamp.Time) })
+		}
+		writeOK(w, "", map[string]any{"count": len(alerts), "alerts": alerts})
+		return
+	}
+
+	unmute := len(segments) == 3 && segments[1] == "unmute" && r.Method == http.MethodPut
+	if len(segments) != 2 && !unmute {
+		notImplemented(w, r)
+		return
+	}
+	id, _ := strconv.Atoi(segments[len(segments)-1])
+	i := -1
+	for j, alert := range s.alerts {
+		if int(alert.ID) == id {
+			i = j
+		}
+	}
+	if i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Alert %s does not exist", segments[len(segments)-1]))
+		return
+	}
+	alert := &s.alerts[i]
+
+	switch {
+	case unmute:
+		alert.State = 1
+		writeOK(w, "Alert has been unmuted", nil)
+	case r.Method == http.MethodGet:
+		writeOK(w, "", map[string]any{"count": 1, "alerts": []types.Alert{*alert}})
+	case r.Method == http.MethodPut:
+		var req types.AlertAckRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		alert.State = 2
+		alert.Note = types.String(req.Note)
+		writeOK(w, "Alert has been acknowledged", nil)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// handleAlertRules serves the rules endpoints.
+func (s *Server) handleAlertRules(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		writeOK(w, "", map[string]any{"count": len(s.rules), "rules": s.rules})
+	case len(segments) == 1 && (r.Method == http.MethodPost || r.Method == http.MethodPut):
+		var req types.AlertRuleUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" || req.Builder == "" || req.Severity == "" {
+			writeError(w, http.StatusBadRequest, "Missing the rule name, builder or severity")
+			return
+		}
+		for _, rule := range s.rules {
+			if string(rule.Name) == req.Name && (r.Method == http.MethodPost || int(rule.ID) != req.ID) {
+				writeError(w, http.StatusInternalServerError, "Addition failed : Name has already been used")
+				return
+			}
+		}
+		rule := alertRuleFromRequest(req.AlertRuleCreateRequest)
+		if r.Method == http.MethodPost {
+			rule.ID = s.assignID("rules", 0)
+			s.rules = append(s.rules, rule)
+			writeOK(w, "", nil)
+			return
+		}
+		for i := range s.rules {
+			if int(s.rules[i].ID) == req.ID {
+				rule.ID = s.rules[i].ID
+				s.rules[i] = rule
+				writeOK(w, "", nil)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Alert rule %d does not exist", req.ID))
+	case len(segments) == 2 && (r.Method == http.MethodGet || r.Method == http.MethodDelete):
+		id, _ := strconv.Atoi(segments[1])
+		for i, rule := range s.rules {
+			if int(rule.ID) != id {
+				continue
+			}
+			if r.Method == http.MethodDelete {
+				s.rules = append(s.rules[:i], s.rules[i+1:]...)
+				writeOK(w, "Alert rule has been removed", nil)
+			} else {
+				writeOK(w, "", map[string]any{"count": 1, "rules": []types.AlertRule{rule}})
+			}
+			return
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Alert rule %s does not exist", segments[1]))
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// alertRuleFromRequest converts a rule request into the stored rule. The scheduling
+// settings are kept in the extra field like LibreNMS does.
+func alertRuleFromRequest(req types.AlertRuleCreateRequest) types.AlertRule {
+	rule := types.AlertRule{
+		Name:         types.String(req.Name),
+		Builder:      types.String(req.Builder),
+		Disabled:     req.Disabled,
+		Notes:        types.String(req.Notes),
+		ProcedureURL: types.String(req.ProcedureURL),
+		Query:        types.String(req.Query),
+		Rule:         types.String(req.Rule),
+		Severity:     types.String(req.Severity),
+	}
+	for _, id := range req.Groups {
+		rule.Groups = append(rule.Groups, types.Int(id))
+	}
+	for _, id := range req.Locations {
+		rule.Locations = append(rule.Locations, types.Int(id))
+	}
+	for _, id := range req.Devices {
+		// -1 selects all devices.
+		if id > 0 {
+			rule.Devices = append(rule.Devices, types.Int(id))
+		}
+	}
+	count := req.Count
+	if count == 0 {
+		count = -1
+	}
+	extra, _ := json.Marshal(map[string]any{
+		"mute":     req.Mute,
+		"count":    strconv.Itoa(count),
+		"delay":    durationSeconds(req.Delay),
+		"interval": durationSeconds(req.Interval),
+	})
+	rule.Extra = types.String(extra)
+	return rule
+}
+
+// handlePorts serves the ports endpoints.
+func (s *Server) handlePorts(w http.ResponseWriter, r *http.Request, segments []string) {
+	columns := r.URL.Query().Get("columns")
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		ports := make([]map[string]any, 0, len(s.ports))
+		for _, port := range s.ports {
+			ports = append(ports, project(port, columns, defaultPortColumns))
+		}
+		writeOK(w, "", map[string]any{"count": len(ports), "ports": ports})
+		return
+	case len(segments) >= 3 && segments[1] == "search" && r.Method == http.MethodGet:
+		fields := []string{"ifAlias", "ifDescr", "ifName"}
+		search := segments[2]
+		if len(segments) == 4 {
+			fields, search = strings.Split(segments[2], ","), segments[3]
+		}
+		ports := make([]map[string]any, 0)
+		for _, port := range s.ports {
+			values := project(port, strings.Join(fields, ","), "")
+			for _, value := range values {
+				if strings.Contains(strings.ToLower(fmt.Sprint(value)), strings.ToLower(search)) {
+					ports = append(ports, project(port, columns, defaultPortColumns))
+					break
+				}
+			}
+		}
+		writeOK(w, "", map[string]any{"count": len(ports), "ports": ports})
+		return
+	case len(segments) < 2 || len(segments) > 3:
+		notImplemented(w, r)
+		return
+	}
+
+	id, _ := strconv.Atoi(segments[1])
+	i := -1
+	for j, port := range s.ports {
+		if int(port.PortID) == id {
+			i = j
+		}
+	}
+	if i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Port %s does not exist", segments[1]))
+		return
+	}
+	port := &s.ports[i]
+
+	switch {
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		writeOK(w, "", map[string]any{"count": 1, "port": []types.Port{*port}})
+	case segments[len(segments)-1] == "ip" && r.Method == http.MethodGet:
+		addresses := make([]types.IPAddress, 0)
+		for _, address := range s.addresses {
+			if address.PortID == port.PortID {
+				addresses = append(addresses, address)
+			}
+		}
+		writeOK(w, "", map[string]any{"count": len(addresses), "addresses": addresses})
+	case segments[len(segments)-1] == "description" && r.Method == http.MethodGet:
+		writeOK(w, "", map[string]any{"port_description": port.IfAlias})
+	case segments[len(segments)-1] == "description" && r.Method == http.MethodPatch:
+		var req types.PortDescriptionUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		port.IfAlias = types.String(req.Description)
+		writeOK(w, "Port description updated.", nil)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// handleBGP serves the bgp endpoints. Sessions are filtered by the list_bgp parameters.
+func (s *Server) handleBGP(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 1 && r.Method == http.MethodGet {
+		query := r.URL.Query()
+		filters := map[string]string{}
+		for key, field := range map[string]string{
+			"remote_asn":     "bgpPeerRemoteAs",
+			"remote_address": "bgpPeerRemoteAddr",
+			"local_address":  "bgpLocalAddr",
+			"bgp_descr":      "bgpPeerDescr",
+			"bgp_state":      "bgpPeerState",
+			"bgp_adminstate": "bgpPeerAdminStatus",
+		} {
+			if value := query.Get(key); value != "" {
+				filters[field] = value
+			}
+		}
+		deviceID := types.Int(-1)
+		if hostname := query.Get("hostname"); hostname != "" {
+			if i := s.deviceIndex(hostname); i >= 0 {
+				deviceID = s.devices[i].DeviceID
+			} else {
+				deviceID = 0
+			}
+		}
+		sessions := filterSlice(s.bgp, func(b types.BGPSession) bool {
+			return (deviceID < 0 || b.DeviceID == deviceID) && matchFields(b, filters)
+		})
+		writeOK(w, "", map[string]any{"count": len(sessions), "bgp_sessions": sessions})
+		return
+	}
+	if len(segments) != 2 {
+		notImplemented(w, r)
+		return
+	}
+
+	id, _ := strconv.Atoi(segments[1])
+	i := -1
+	for j, session := range s.bgp {
+		if int(session.BGPPeerID) == id {
+			i = j
+		}
+	}
+	if i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("BGP peer %s does not exist", segments[1]))
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeOK(w, "", map[string]any{"count": 1, "bgp_session": []types.BGPSession{s.bgp[i]}})
+	case http.MethodPost:
+		var req types.BGPDescriptionUpdate
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		s.bgp[i].BGPPeerDescr = types.String(req.BGPDescr)
+		writeOK(w, "BGP description for peer "+string(s.bgp[i].BGPPeerIdentifier)+" on device "+strconv.Itoa(int(s.bgp[i].DeviceID))+" updated to "+req.BGPDescr+".", nil)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// handleRouting serves the routing endpoints. Only the BGP counters, filtered by the
+// hostname parameter, are implemented.
+func (s *Server) handleRouting(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 3 || segments[1] != "bgp" || segments[2] != "cbgp" || r.Method != http.MethodGet {
+		notImplemented(w, r)
+		return
+	}
+	deviceID := types.Int(-1)
+	if hostname := r.URL.Query().Get("hostname"); hostname != "" {
+		deviceID = 0
+		if i := s.deviceIndex(hostname); i >= 0 {
+			deviceID = s.devices[i].DeviceID
+		}
+	}
+	counters := filterSlice(s.bgpCounters, func(c types.BGPCounters) bool {
+		return deviceID < 0 || c.DeviceID == deviceID
+	})
+	writeOK(w, "", map[string]any{"count": len(counters), "bgp_counters": counters})
+}
+
+// handleOSPF serves the ospf endpoint, filtered by the hostname parameter.
+func (s *Server) handleOSPF(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 1 || r.Method != http.MethodGet {
+		notImplemented(w, r)
+		return
+	}
+	deviceID := s.hostnameDeviceID(r)
+	neighbors := filterSlice(s.ospf, func(n types.OSPFNeighbor) bool {
+		return deviceID < 0 || n.DeviceID == deviceID
+	})
+	writeOK(w, "", map[string]any{"count": len(neighbors), "ospf_neighbours": neighbors})
+}
+
+// handleOSPFPorts serves the ospf_ports endpoint.
+func (s *Server) handleOSPFPorts(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 1 || r.Method != http.MethodGet {
+		notImplemented(w, r)
+		return
+	}
+	ports := append([]types.OSPFPort{}, s.ospfPorts...)
+	writeOK(w, "", map[string]any{"count": len(ports), "ospf_ports": ports})
+}
+
+// handleOSPFv3 serves the ospfv3 endpoint, filtered by the hostname parameter.
+func (s *Server) handleOSPFv3(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 1 || r.Method != http.MethodGet {
+		notImplemented(w, r)
+		return
+	}
+	deviceID := s.hostnameDeviceID(r)
+	neighbors := filterSlice(s.ospfv3, func(n types.OSPFv3Neighbor) bool {
+		return deviceID < 0 || n.DeviceID == deviceID
+	})
+	writeOK(w, "", map[string]any{"count": len(neighbors), "ospfv3_neighbours": neighbors})
+}
+
+// handleOSPFv3Ports serves the ospfv3_ports endpoint.
+func (s *Server) handleOSPFv3Ports(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 1 || r.Method != http.MethodGet {
+		notImplemented(w, r)
+		return
+	}
+	ports := append([]types.OSPFv3Port{}, s.ospfv3Ports...)
+	writeOK(w, "", map[string]any{"count": len(ports), "ospfv3_ports": ports})
+}
+
+// hostnameDeviceID returns the ID of the device named by the hostname parameter, 0 for an
+// unknown device and -1 without the parameter.
+func (s *Server) hostnameDeviceID(r *http.Request) types.Int {
+	hostname := r.URL.Query().Get("hostname")
+	if hostname == "" {
+		return -1
+	}
+	if i := s.deviceIndex(hostname); i >= 0 {
+		return s.devices[i].DeviceID
+	}
+	return 0
+}
+
+// handleLogs serves the logs endpoints.
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 2 && segments[1] == "syslogsink" && r.Method == http.MethodPost {
+		s.receiveSyslog(w, r)
+		return
+	}
+	if len(segments) < 2 || len(segments) > 3 || r.Method != http.MethodGet {
+		notImplemented(w, r)
+		return
+	}
+	kind := LogKind(segments[1])
+	switch kind {
+	case EventLog, SysLog, AlertLog, AuthLog:
+	default:
+		notImplemented(w, r)
+		return
+	}
+
+	logs := append([]types.Log{}, s.logs[kind]...)
+	if len(segments) == 3 {
+		i := s.deviceIndex(segments[2])
+		if i < 0 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Device %s does not exist", segments[2]))
+			return
+		}
+		id := s.devices[i].DeviceID
+		logs = filterSlice(logs, func(l types.Log) bool { return l.DeviceID == id })
+	}
+
+	query := r.URL.Query()
+	from, to := query.Get("from"), query.Get("to")
+	logs = filterSlice(logs, func(l types.Log) bool {
+		datetime := l.DateTime.Format(types.TimeLayout)
+		return (from == "" || datetime >= from) && (to == "" || datetime <= to)
+	})
+	sort.SliceStable(logs, func(a, b int) bool { return logs[a].DateTime.Before(logs[b].DateTime.Time) })
+	if strings.EqualFold(query.Get("sortorder"), "DESC") {
+		sort.SliceStable(logs, func(a, b int) bool { return logs[a].DateTime.After(logs[b].DateTime.Time) })
+	}
+
+	total := len(logs)
+	start, _ := strconv.Atoi(query.Get("start"))
+	start = min(max(start, 0), total)
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	logs = logs[start:min(start+limit, total)]
+	writeOK(w, "", map[string]any{"count": len(logs), "total": total, "logs": logs})
+}
+
+// receiveSyslog stores syslogsink messages and adds them to the syslog of matching devices.
+func (s *Server) receiveSyslog(w http.ResponseWriter, r *http.Request) {
+	var raw json.RawMessage
+	if !decodeBody(w, r, &raw) {
+		return
+	}
+	var messages []types.SyslogMessage
+	if err := json.Unmarshal(raw, &messages); err != nil {
+		var message types.SyslogMessage
+		if err := json.Unmarshal(raw, &message); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid syslog message")
+			return
+		}
+		messages = []types.SyslogMessage{message}
+	}
+
+	for _, message := range messages {
+		s.syslog = append(s.syslog, message)
+		for _, device := range s.devices {
+			if strings.EqualFold(string(device.Hostname), message.Host) || (device.IP != "" && string(device.IP) == message.Host) {
+				s.addLog(SysLog, types.Log{DeviceID: device.DeviceID, Message: types.String(message.Msg), Type: types.String(message.Program), Severity: types.Int(message.Severity)})
+				break
+			}
+		}
+	}
+	writeOK(w, fmt.Sprintf("%d syslog messages received", len(messages)), nil)
+}
+
+// handleSystem serves the system endpoint.
+func (s *Server) handleSystem(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 1 || r.Method != http.MethodGet {
+		notImplemented(w, r)
+		return
+	}
+	writeOK(w, "", map[string]any{"count": 1, "system": []types.SystemInfo{s.system}})
+}
+
+// decodeBody decodes the JSON request body into v, writing an error response on failure.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %v", err))
+		return false
+	}
+	return true
+}
+
+// decodeMaintenance decodes a maintenance request into a window. Start is parsed in the
+// local timezone and defaults to now.
+func decodeMaintenance(w http.ResponseWriter, r *http.Request) (maintenanceWindow, bool) {
+	var req types.DeviceMaintenanceRequest
+	if !decodeBody(w, r, &req) {
+		return maintenanceWindow{}, false
+	}
+	hours, minutes, ok := strings.Cut(req.Duration, ":")
+	h, herr := strconv.Atoi(hours)
+	m, merr := strconv.Atoi(minutes)
+	if !ok || herr != nil || merr != nil {
+		writeError(w, http.StatusBadRequest, "Duration must be formatted as H:i")
+		return maintenanceWindow{}, false
+	}
+
+	start := time.Now()
+	if req.Start != "" {
+		var err error
+		if start, err = time.ParseInLocation(types.MaintenanceStartLayout, req.Start, time.Local); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid start: %v", err))
+			return maintenanceWindow{}, false
+		}
+	}
+	duration := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+	return maintenanceWindow{start: start, end: start.Add(duration)}, true
+}
+
+// project returns the given comma separated columns of a row, or the default columns when
+// none are given.
+func project(row any, columns, defaults string) map[string]any {
+	if columns == "" {
+		columns = defaults
+	}
+	data, _ := json.Marshal(row)
+	all := make(map[string]any)
+	_ = json.Unmarshal(data, &all)
+	if columns == "" {
+		return all
+	}
+	projected := make(map[string]any)
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.TrimSpace(column)
+		if value, ok := all[column]; ok {
+			projected[column] = value
+		}
+	}
+	return projected
+}
+
+// sameMAC reports whether mac is the MAC address in query, in any notation.
+func sameMAC(mac types.MAC, query string) bool {
+	parsed, err := types.ParseMAC(query)
+	return err == nil && !mac.IsZero() && mac == parsed
+}
+
+// matchFields reports whether the JSON fields of v equal the filters, ignoring case.
+func matchFields(v any, filters map[string]string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	data, _ := json.Marshal(v)
+	fields := make(map[string]any)
+	_ = json.Unmarshal(data, &fields)
+	for key, want := range filters {
+		got := formatField(fields[key])
+		if got == "" && want == "0" {
+			// Zero values are omitted from the JSON encoding.
+			continue
+		}
+		if !strings.EqualFold(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareField compares a JSON field of two values, numerically when possible.
+func compareField(a, b any, field string) int {
+	fa, fb := fieldOf(a, field), fieldOf(b, field)
+	na, errA := strconv.ParseFloat(fa, 64)
+	nb, errB := strconv.ParseFloat(fb, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(fa), strings.ToLower(fb))
+}
+
+func fieldOf(v any, field string) string {
+	data, _ := json.Marshal(v)
+	fields := make(map[string]any)
+	_ = json.Unmarshal(data, &fields)
+	return formatField(fields[field])
+}
+
+// formatField formats a decoded JSON value for comparisons. Missing values and false are
+// empty or 0 like in the database.
+func formatField(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// jsonFields returns the JSON field names of a struct type.
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for _, f := range reflect.VisibleFields(t) {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name != "" && name != "-" && f.IsExported() {
+			fields[name] = true
+		}
+	}
+	return fields
+}
+
+// durationSeconds converts a LibreNMS duration such as "5m" or "1d" to seconds. Values
+// that can't be parsed are returned as is.
+func durationSeconds(value string) any {
+	if value == "" {
+		return 0
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return n * 86400
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return int(d.Seconds())
+	}
+	return value
+}
+
+// toSlice converts a decoded JSON value into a slice, wrapping single values.
+func toSlice(v any) []any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	default:
+		return []any{v}
+	}
+}
+
+func filterSlice[T any](items []T, keep func(T) bool) []T {
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func valueOr[T ~string](value, fallback T) T {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
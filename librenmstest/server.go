@@ -0,0 +1,347 @@
+// Package librenmstest provides an in-process fake LibreNMS API server for tests.
+//
+// The server keeps devices, device groups, locations, services, alerts, alert rules, ports,
+// links, VLANs, FDB, NAC and ARP entries, IP addresses, BGP sessions and counters, OSPF and
+// OSPFv3 neighbours and interfaces and logs in memory and implements the endpoints used by
+// the librenms client on top of them, so writes are visible to later reads. Data can be
+// seeded with the Add* methods and faults (latency, HTTP errors, malformed JSON) injected
+// per endpoint:
+//
+//	srv := librenmstest.New(t)
+//	srv.AddDevice(types.Device{Hostname: "sw1", OS: "ios"})
+//	srv.InjectError("devices/*", http.StatusBadGateway, 1)
+//	client := srv.Client()
+package librenmstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// Token is the API token accepted by the server. Clients returned by Server.Client use it.
+const Token = "librenmstest-token"
+
+const apiPrefix = "/api/v0/"
+
+type (
+	// Server is a stateful fake LibreNMS API server. It is safe for concurrent use.
+	Server struct {
+		server *httptest.Server
+		// tb is the test the server was started for by New, nil for NewServer.
+		tb       testing.TB
+		handlers map[string]handlerFunc
+
+		mu          sync.Mutex
+		nextIDs     map[string]types.Int
+		devices     []types.Device
+		locations   []types.Location
+		groups      []deviceGroup
+		services    []types.Service
+		alerts      []types.Alert
+		rules       []types.AlertRule
+		ports       []types.Port
+		bgp         []types.BGPSession
+		bgpCounters []types.BGPCounters
+		ospf        []types.OSPFNeighbor
+		ospfPorts   []types.OSPFPort
+		ospfv3      []types.OSPFv3Neighbor
+		ospfv3Ports []types.OSPFv3Port
+		links       []types.Link
+		vlans       []types.VLAN
+		fdb         []types.PortFDB
+		nac         []types.PortNAC
+		arp         []types.ARPEntry
+		addresses   []types.IPAddress
+		available   map[types.Int][]types.DeviceAvailability
+		logs        map[LogKind][]types.Log
+		syslog      []types.SyslogMessage
+		maintenance map[types.Int]maintenanceWindow
+		system      types.SystemInfo
+		faults      []*Fault
+		requests    []Request
+	}
+
+	// Fault changes the response of the requests it matches.
+	Fault struct {
+		// Method restricts the fault to an HTTP method. Empty matches all methods.
+		Method string
+		// Path is a path.Match pattern relative to /api/v0/, e.g. "devices" or "devices/*".
+		// Empty matches all paths.
+		Path string
+		// Latency delays the response.
+		Latency time.Duration
+		// Status replaces the response status. With a zero Status and a Body, the body is
+		// written with status 200.
+		Status int
+		// Body replaces the response body. When empty and Status is set, a LibreNMS error
+		// body is written.
+		Body string
+		// Count is the number of requests the fault applies to. Zero applies it to all.
+		Count int
+	}
+
+	// Request is a request received by the server.
+	Request struct {
+		Method string
+		// Path is relative to /api/v0/.
+		Path  string
+		Query string
+		Body  []byte
+	}
+
+	// deviceGroup is a device group with its static members.
+	deviceGroup struct {
+		types.DeviceGroup
+		members []int
+	}
+
+	// maintenanceWindow is the maintenance scheduled for a device.
+	maintenanceWindow struct {
+		start time.Time
+		end   time.Time
+	}
+
+	// handlerFunc handles a request with the path split into segments. The server lock is held.
+	handlerFunc func(w http.ResponseWriter, r *http.Request, segments []string)
+)
+
+// New starts a fake server and closes it when the test ends.
+func New(tb testing.TB) *Server {
+	tb.Helper()
+	s := NewServer()
+	s.tb = tb
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// NewServer starts a fake server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		nextIDs:     make(map[string]types.Int),
+		logs:        make(map[LogKind][]types.Log),
+		maintenance: make(map[types.Int]maintenanceWindow),
+		available:   make(map[types.Int][]types.DeviceAvailability),
+		system: types.SystemInfo{
+			LocalVer:    "25.5.0",
+			LocalBranch: "master",
+			DBSchema:    "2025_05_01_000000",
+			PHPVer:      "8.3.0",
+			PythonVer:   "3.12.0",
+			DatabaseVer: "MariaDB 11.4.0",
+			RRDToolVer:  "1.8.0",
+			NetSNMPVer:  "NET-SNMP 5.9.4",
+		},
+	}
+	s.handlers = s.routes()
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL returns the base URL of the server, to be passed to librenms.New.
+func (s *Server) URL() string {
+	return s.server.URL + "/"
+}
+
+// Client returns a client for the server using Token. Options are applied after the
+// defaults. If the client can't be created, the test of a server started by New fails and a
+// server started by NewServer panics.
+func (s *Server) Client(opts ...librenms.Option) *librenms.Client {
+	client, err := librenms.New(s.URL(), Token, opts...)
+	if err != nil {
+		if s.tb != nil {
+			s.tb.Helper()
+			s.tb.Fatalf("librenmstest: failed to create client: %v", err)
+		}
+		panic(fmt.Sprintf("librenmstest: failed to create client: %v", err))
+	}
+	return client
+}
+
+// Inject adds a fault. Faults are checked in the order they were added and the first
+// matching one is used.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// InjectLatency delays all responses for the path pattern.
+func (s *Server) InjectLatency(pattern string, latency time.Duration) {
+	s.Inject(Fault{Path: pattern, Latency: latency})
+}
+
+// InjectError makes the next count requests for the path pattern fail with the status,
+// all of them when count is zero.
+func (s *Server) InjectError(pattern string, status, count int) {
+	s.Inject(Fault{Path: pattern, Status: status, Count: count})
+}
+
+// InjectMalformedJSON makes the next count requests for the path pattern return a truncated
+// JSON body with status 200, all of them when count is zero.
+func (s *Server) InjectMalformedJSON(pattern string, count int) {
+	s.Inject(Fault{Path: pattern, Body: `{"status": "ok", "devices": [{"device_id": 1,`, Count: count})
+}
+
+// ClearFaults removes all faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the requests received so far, including failed ones.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	rel := strings.TrimPrefix(r.URL.Path, apiPrefix)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: rel, Query: r.URL.RawQuery, Body: body})
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Header.Get("X-Auth-Token") != Token {
+		writeError(w, http.StatusUnauthorized, "Unauthenticated.")
+		return
+	}
+
+	s.mu.Lock()
+	fault := s.matchFault(r.Method, rel)
+	s.mu.Unlock()
+	if fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.Status != 0 || fault.Body != "" {
+			status := fault.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if fault.Body == "" {
+				writeError(w, status, fmt.Sprintf("injected fault: %s", http.StatusText(status)))
+				return
+			}
+			w.WriteHeader(status)
+			_, _ = io.WriteString(w, fault.Body)
+			return
+		}
+	}
+
+	if !strings.HasPrefix(r.URL.Path, apiPrefix) {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	segments := strings.Split(strings.TrimSuffix(rel, "/"), "/")
+	handler, ok := s.handlers[segments[0]]
+	if !ok {
+		notImplemented(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	handler(w, r, segments)
+}
+
+// matchFault returns the first fault matching the request and consumes one of its uses.
+func (s *Server) matchFault(method, rel string) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, method) {
+			continue
+		}
+		if f.Path != "" {
+			if ok, _ := path.Match(f.Path, strings.TrimSuffix(rel, "/")); !ok {
+				continue
+			}
+		}
+		matched := *f
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+// routes maps the first path segment to its handler. NewServer builds the map once.
+func (s *Server) routes() map[string]handlerFunc {
+	return map[string]handlerFunc{
+		"alerts":       s.handleAlerts,
+		"bgp":          s.handleBGP,
+		"devicegroups": s.handleDeviceGroups,
+		"devices":      s.handleDevices,
+		"location":     s.handleLocations,
+		"locations":    s.handleLocations,
+		"logs":         s.handleLogs,
+		"ospf":         s.handleOSPF,
+		"ospf_ports":   s.handleOSPFPorts,
+		"ospfv3":       s.handleOSPFv3,
+		"ospfv3_ports": s.handleOSPFv3Ports,
+		"ports":        s.handlePorts,
+		"resources":    s.handleResources,
+		"routing":      s.handleRouting,
+		"rules":        s.handleAlertRules,
+		"services":     s.handleServices,
+		"system":       s.handleSystem,
+	}
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+}
+
+// writeOK writes a successful response with the given extra fields.
+func writeOK(w http.ResponseWriter, message string, fields map[string]any) {
+	resp := map[string]any{"status": "ok"}
+	if message != "" {
+		resp["message"] = message
+	}
+	for k, v := range fields {
+		resp[k] = v
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// writeError writes a LibreNMS error response.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"status": "error", "message": message})
+}
+
+func notImplemented(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotImplemented, fmt.Sprintf("%s %s is not implemented by librenmstest", r.Method, r.URL.Path))
+}
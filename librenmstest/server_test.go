@@ -0,0 +1,362 @@
+package librenmstest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/rules"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+// get decodes the response of a GET request for a path relative to /api/v0/.
+func get(t *testing.T, srv *librenmstest.Server, path string, v any) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srv.URL()+"api/v0/"+path, nil)
+	require.NoError(t, err, "Failed to create request")
+	req.Header.Set("X-Auth-Token", librenmstest.Token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err, "Request failed")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Unexpected status code")
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v), "Failed to decode response")
+}
+
+func TestServer_Devices(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	dc1 := srv.AddLocation(types.Location{Name: "DC1"})
+	srv.AddDevice(types.Device{Hostname: "core1", OS: "iosxe", LocationID: dc1.ID})
+	client := srv.Client()
+
+	created, err := client.Device.Create(&types.DeviceCreateRequest{Hostname: "edge1", OS: "junos", Location: "DC1", SNMPCommunity: "public"})
+	r.NoError(err, "Create returned an error")
+	r.EqualValues(2, created.Devices[0].DeviceID, "Expected the next device ID")
+	r.Equal(dc1.ID, created.Devices[0].LocationID, "Location should be resolved by name")
+
+	_, err = client.Device.Create(&types.DeviceCreateRequest{Hostname: "edge1", OS: "junos"})
+	r.ErrorContains(err, "Already have device edge1", "Expected error for duplicate devices")
+
+	var list types.DeviceResponse
+	get(t, srv, "devices?type=os&query=junos", &list)
+	r.Len(list.Devices, 1, "Expected devices to be filtered by OS")
+	r.Equal("edge1", string(list.Devices[0].Hostname), "Unexpected device")
+
+	_, err = client.Device.Update("edge1", &types.DeviceUpdateRequest{Field: []string{"notes", "disabled"}, Data: []any{"uplink", true}})
+	r.NoError(err, "Update returned an error")
+	_, err = client.Device.RenameDevice("edge1", "edge2")
+	r.NoError(err, "RenameDevice returned an error")
+
+	device, err := client.Device.Get("2")
+	r.NoError(err, "Get returned an error")
+	r.Equal("edge2", string(device.Devices[0].Hostname), "Device should be renamed")
+	r.EqualValues("uplink", device.Devices[0].Notes, "Notes should be updated")
+	r.True(bool(device.Devices[0].Disabled), "Device should be disabled")
+
+	_, err = client.Device.Update("2", &types.DeviceUpdateRequest{Field: []string{"bogus"}, Data: []any{1}})
+	r.ErrorContains(err, "bogus", "Expected error for unknown fields")
+
+	_, err = client.Device.Delete("core1")
+	r.NoError(err, "Delete returned an error")
+	_, err = client.Device.Get("core1")
+	r.ErrorContains(err, "does not exist", "Device should be deleted")
+	r.Len(srv.Devices(), 1, "Expected a single device left")
+}
+
+func TestServer_DeviceGroups(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	dc1 := srv.AddLocation(types.Location{Name: "DC1 Amsterdam"})
+	srv.AddDevice(types.Device{Hostname: "core1", OS: "iosxe", LocationID: dc1.ID})
+	srv.AddDevice(types.Device{Hostname: "core2", OS: "ios", LocationID: dc1.ID})
+	srv.AddDevice(types.Device{Hostname: "lab1", OS: "ios"})
+	srv.AddPort(types.Port{DeviceID: 3, IfName: "Te1/1/1", IfOperStatus: "up"})
+	srv.AddDeviceGroup(types.DeviceGroup{Name: "lab"}, 3)
+	client := srv.Client()
+
+	created, err := client.DeviceGroup.Create(&types.DeviceGroupCreateRequest{
+		Name:  "dc1-ios",
+		Type:  "dynamic",
+		Rules: rules.MustParse(`devices.os LIKE "ios%" AND locations.location LIKE "DC1%"`).MustJSON(),
+	})
+	r.NoError(err, "Create returned an error")
+	r.EqualValues(2, created.ID, "Expected the next group ID")
+
+	members, err := client.DeviceGroup.GetMembers("dc1-ios")
+	r.NoError(err, "GetMembers returned an error")
+	r.Equal([]types.DeviceGroupMember{{ID: 1}, {ID: 2}}, members.Devices, "Dynamic members should be evaluated")
+
+	_, err = client.DeviceGroup.Update("dc1-ios", &types.DeviceGroupUpdateRequest{
+		Rules: rules.MustParse(`ports.ifName LIKE "Te%"`).MustJSON(),
+	})
+	r.NoError(err, "Update returned an error")
+	members, err = client.DeviceGroup.GetMembers("2")
+	r.NoError(err, "GetMembers returned an error")
+	r.Equal([]types.DeviceGroupMember{{ID: 3}}, members.Devices, "Port rules should use the stored ports")
+
+	groups, err := client.Device.GetDeviceGroups("lab1")
+	r.NoError(err, "GetDeviceGroups returned an error")
+	r.Len(groups.Groups, 2, "lab1 should be in both groups")
+
+	_, err = client.DeviceGroup.SetMaintenance("lab", types.NewDeviceMaintenanceRequest("Upgrade", time.Time{}, time.Hour))
+	r.NoError(err, "SetMaintenance returned an error")
+	r.True(srv.InMaintenance(3, time.Now()), "Group members should be under maintenance")
+	r.False(srv.InMaintenance(3, time.Now().Add(2*time.Hour)), "Maintenance should end after the duration")
+
+	_, err = client.DeviceGroup.Delete("lab")
+	r.NoError(err, "Delete returned an error")
+	_, err = client.DeviceGroup.GetMembers("lab")
+	r.ErrorContains(err, "not found", "Group should be deleted")
+}
+
+func TestServer_MaintenancePlanner(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{Hostname: "core1"})
+	srv.AddDevice(types.Device{Hostname: "core2"})
+
+	report, err := librenms.NewMaintenancePlanner(srv.Client()).
+		AddHostnames("core1", "core2").
+		Apply(librenms.MaintenanceWindow{Title: "Upgrade", Duration: 30 * time.Minute})
+	r.NoError(err, "Apply returned an error")
+	r.Empty(report.Failed(), "Maintenance should be verified on the fake server")
+}
+
+func TestServer_Resources(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{Hostname: "core1"})
+	srv.AddAlert(types.Alert{DeviceID: 1, RuleID: 1, State: 1, Severity: "critical"})
+	srv.AddAlert(types.Alert{DeviceID: 1, RuleID: 2, State: 1, Severity: "warning"})
+	client := srv.Client()
+
+	created, err := client.Location.Create(&types.LocationCreateRequest{Name: "DC1", Latitude: 52.1})
+	r.NoError(err, "Location.Create returned an error")
+	r.Equal("Location added with id #1", created.Message, "Unexpected create message")
+	_, err = client.Location.Update(1, types.NewLocationUpdateRequest().SetName("DC1 Amsterdam"))
+	r.NoError(err, "Location.Update returned an error")
+	location, err := client.Location.Get(1)
+	r.NoError(err, "Location.Get returned an error")
+	r.Equal("DC1 Amsterdam", string(location.Location.Name), "Location should be renamed")
+
+	_, err = client.Service.Create("core1", &types.ServiceCreateRequest{Type: "ping", Description: "ICMP"})
+	r.NoError(err, "Service.Create returned an error")
+	_, err = client.Service.Update(1, types.NewServiceUpdateRequest().SetDescription("Ping"))
+	r.NoError(err, "Service.Update returned an error")
+	services, err := client.Service.GetForHost("core1")
+	r.NoError(err, "Service.GetForHost returned an error")
+	r.Len(services.Services, 1, "Expected the created service")
+	r.Equal("Ping", string(services.Services[0].Description), "Service should be updated")
+
+	alerts, err := client.Alert.List(types.NewAlertsQuery().SetSeverity("critical"))
+	r.NoError(err, "Alert.List returned an error")
+	r.Len(alerts.Alerts, 1, "Expected alerts to be filtered by severity")
+	_, err = client.Alert.Ack(1, &types.AlertAckRequest{Note: "on it"})
+	r.NoError(err, "Alert.Ack returned an error")
+	r.EqualValues(2, srv.Alerts()[0].State, "Alert should be acknowledged")
+
+	_, err = client.AlertRule.Create(&types.AlertRuleCreateRequest{
+		Name: "Device down", Builder: rules.MustParse(`macros.device_down = 1`).MustJSON(), Severity: "critical", Devices: []int{-1}, Delay: "5m",
+	})
+	r.NoError(err, "AlertRule.Create returned an error")
+	rule, err := client.AlertRule.Get(1)
+	r.NoError(err, "AlertRule.Get returned an error")
+	r.JSONEq(`{"mute":false,"count":"-1","delay":300,"interval":0}`, string(rule.Rules[0].Extra), "Unexpected rule extra")
+
+	srv.AddLink(types.Link{LocalDeviceID: 1, LocalPortID: 1, RemoteHostname: "edge1", Protocol: "lldp"})
+	links, err := client.Switching.GetAllLinks(nil)
+	r.NoError(err, "GetAllLinks returned an error")
+	r.Len(links.Links, 1, "Expected the added link")
+	link, err := client.Switching.GetLink(1, nil)
+	r.NoError(err, "GetLink returned an error")
+	r.Equal("edge1", string(link.Links[0].RemoteHostname), "Unexpected link")
+	_, err = client.Switching.GetLink(2, nil)
+	r.Error(err, "Expected an error for an unknown link")
+
+	srv.AddVLAN(types.VLAN{DeviceID: 1, VLANVLAN: 10, VLANName: "users"})
+	vlans, err := client.Switching.GetAllVLANs(&types.SwitchingQueryParams{Columns: "vlan_vlan"})
+	r.NoError(err, "GetAllVLANs returned an error")
+	r.Equal([]types.VLAN{{VLANVLAN: 10}}, vlans.VLANs, "Expected the selected columns of the VLAN")
+	vlans, err = client.Switching.GetDeviceVLANs("core1", nil)
+	r.NoError(err, "GetDeviceVLANs returned an error")
+	r.Equal([]types.VLAN{{VLANID: 1, DeviceID: 1, VLANVLAN: 10, VLANName: "users"}}, vlans.VLANs, "Expected the VLANs of core1")
+
+	srv.AddPort(types.Port{DeviceID: 1, IfName: "Vlan10"})
+	srv.AddARP(types.ARPEntry{PortID: 1, MACAddress: types.MustParseMAC("001a2b3c4d5e"), IPv4Address: "10.0.10.50"})
+	srv.AddARP(types.ARPEntry{PortID: 2, MACAddress: types.MustParseMAC("001a2b3c4d5f"), IPv4Address: "10.0.20.50"})
+	arp, err := client.Routing.ListARP("10.0.10.0/24", "")
+	r.NoError(err, "ListARP returned an error")
+	r.Len(arp.ARP, 1, "Expected the entries of the network")
+	arp, err = client.Routing.ListARP("all", "core1")
+	r.NoError(err, "ListARP returned an error")
+	r.Len(arp.ARP, 1, "Expected the entries of the device")
+	srv.AddFDB(types.PortFDB{PortID: 1, DeviceID: 1, MACAddress: types.MustParseMAC("001a2b3c4d5e")})
+	fdb, err := client.Switching.GetPortFDBDetail("00:1a:2b:3c:4d:5e", nil)
+	r.NoError(err, "GetPortFDBDetail returned an error")
+	r.Equal([]types.PortFDBDetail{{Hostname: "core1", IfName: "Vlan10"}}, fdb.PortsFDB, "Expected the named entry")
+	_, err = client.Switching.GetPortNAC("001a2b3c4d5e", nil)
+	r.ErrorContains(err, "Nac entry does not exist", "Expected a 404 without NAC entries")
+}
+
+func TestServer_PortsAndLogs(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{Hostname: "core1", IP: "10.0.0.1"})
+	srv.AddPort(types.Port{DeviceID: 1, IfName: "Gi1/0/1", IfAlias: "uplink to edge1"})
+	srv.AddPort(types.Port{DeviceID: 1, IfName: "Gi1/0/2"})
+	for i := 0; i < 5; i++ {
+		srv.AddLog(librenmstest.EventLog, types.Log{DeviceID: 1, Message: "event", DateTime: types.NewTime(time.Date(2025, 1, i+1, 0, 0, 0, 0, time.UTC))})
+	}
+	client := srv.Client()
+
+	ports, err := client.Port.GetAllPorts(&types.PortsQueryParams{Columns: "port_id,ifAlias"})
+	r.NoError(err, "GetAllPorts returned an error")
+	r.Equal([]types.Port{{PortID: 1, IfAlias: "uplink to edge1"}, {PortID: 2}}, ports.Ports, "Expected only the requested columns")
+
+	found, err := client.Port.SearchPorts("edge1", nil)
+	r.NoError(err, "SearchPorts returned an error")
+	r.Len(found.Ports, 1, "Expected ports matching the alias")
+
+	_, err = client.Port.UpdatePortDescription(2, "uplink to edge2")
+	r.NoError(err, "UpdatePortDescription returned an error")
+	description, err := client.Port.GetPortDescription(2)
+	r.NoError(err, "GetPortDescription returned an error")
+	r.Equal("uplink to edge2", string(description.PortDescription), "Description should be updated")
+
+	logs, err := client.Logs.ListEventLogs("core1", nil)
+	r.NoError(err, "ListEventLogs returned an error")
+	r.Len(logs.Logs, 5, "Expected all logs")
+	get(t, srv, "logs/eventlog/core1?start=1&limit=2&sortorder=DESC", logs)
+	r.EqualValues(5, logs.Total, "Expected the total before paging")
+	r.Len(logs.Logs, 2, "Expected a page of logs")
+	r.Equal("2025-01-04 00:00:00", logs.Logs[0].DateTime.Format(types.TimeLayout), "Logs should be sorted in descending order")
+
+	_, err = client.Logs.Syslogsink(types.SyslogsinkRequest{{Msg: "link down", Host: "10.0.0.1", Program: "kernel"}})
+	r.NoError(err, "Syslogsink returned an error")
+	r.Len(srv.SyslogMessages(), 1, "Expected the received message")
+	r.Equal("link down", string(srv.Logs(librenmstest.SysLog)[0].Message), "Message should be added to the device syslog")
+}
+
+func TestServer_Routing(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{Hostname: "core1"})
+	srv.AddDevice(types.Device{Hostname: "core2"})
+	srv.AddBGPSession(types.BGPSession{DeviceID: 1, BGPPeerIdentifier: "10.0.0.2", BGPPeerRemoteAS: 65002, BGPPeerState: "established"})
+	srv.AddBGPSession(types.BGPSession{DeviceID: 2, BGPPeerIdentifier: "10.0.0.1", BGPPeerRemoteAS: 65001, BGPPeerState: "idle"})
+	srv.AddOSPFNeighbor(types.OSPFNeighbor{DeviceID: 1, OSPFNbrRtrID: "10.255.0.2", OSPFNbrState: types.OSPFStateFull})
+	srv.SetAvailability(1, types.DeviceAvailability{Duration: 86400, AvailabilityPerc: 99.5})
+	client := srv.Client()
+
+	sessions, err := client.Routing.ListBGP(nil)
+	r.NoError(err, "ListBGP returned an error")
+	r.Len(sessions.BGPSessions, 2, "Expected all sessions")
+	get(t, srv, "bgp?hostname=core2&bgp_state=idle", sessions)
+	r.Len(sessions.BGPSessions, 1, "Expected the sessions of core2")
+	r.EqualValues(65001, sessions.BGPSessions[0].BGPPeerRemoteAS, "Unexpected session")
+
+	_, err = client.Routing.UpdateBGPDescription("1", &types.BGPDescriptionUpdate{BGPDescr: "to core2"})
+	r.NoError(err, "UpdateBGPDescription returned an error")
+	session, err := client.Routing.GetBGP("1")
+	r.NoError(err, "GetBGP returned an error")
+	r.Equal("to core2", string(session.BGPSession[0].BGPPeerDescr), "Description should be updated")
+
+	r.True(srv.UpdateBGPSession(types.BGPSession{BGPPeerID: 2, DeviceID: 2, BGPPeerIdentifier: "10.0.0.1", BGPPeerState: "established"}),
+		"Expected the session to be updated")
+	r.False(srv.UpdateBGPSession(types.BGPSession{BGPPeerID: 9}), "Expected no session to update")
+	session, err = client.Routing.GetBGP("2")
+	r.NoError(err, "GetBGP returned an error")
+	r.Equal("established", string(session.BGPSession[0].BGPPeerState), "Expected the updated session")
+
+	srv.SetBGPCounters(
+		types.BGPCounters{DeviceID: 1, BGPPeerIdentifier: "10.0.0.2", AFI: "ipv4", SAFI: "unicast", AcceptedPrefixes: 120},
+		types.BGPCounters{DeviceID: 2, BGPPeerIdentifier: "10.0.0.1", AFI: "ipv4", SAFI: "unicast", AcceptedPrefixes: 80},
+	)
+	counters, err := client.Routing.ListBGPCounters("core2")
+	r.NoError(err, "ListBGPCounters returned an error")
+	r.Len(counters.BGPCounters, 1, "Expected the counters of core2")
+	r.EqualValues(80, counters.BGPCounters[0].AcceptedPrefixes, "Unexpected counters")
+
+	neighbors, err := client.Routing.ListOSPF("core1")
+	r.NoError(err, "ListOSPF returned an error")
+	r.Len(neighbors.OSPFNeighbors, 1, "Expected the neighbours of core1")
+	neighbors, err = client.Routing.ListOSPF("core2")
+	r.NoError(err, "ListOSPF returned an error")
+	r.Empty(neighbors.OSPFNeighbors, "Expected no neighbours for core2")
+
+	srv.AddOSPFPort(types.OSPFPort{DeviceID: 1, PortID: 1, OSPFIfIPAddress: "10.1.0.1", OSPFIfAreaID: 10})
+	srv.AddOSPFv3Neighbor(types.OSPFv3Neighbor{DeviceID: 2, RouterID: "10.255.0.1", OSPFv3NbrState: types.OSPFStateFull})
+	srv.AddOSPFv3Port(types.OSPFv3Port{DeviceID: 2, PortID: 3, OSPFv3IfAreaID: 10})
+	ospfPorts, err := client.Routing.ListOSPFPorts()
+	r.NoError(err, "ListOSPFPorts returned an error")
+	r.Equal(types.OSPFAreaID(10), ospfPorts.OSPFPorts[0].OSPFIfAreaID, "Unexpected OSPF port")
+	neighborsV3, err := client.Routing.ListOSPFv3("core1")
+	r.NoError(err, "ListOSPFv3 returned an error")
+	r.Empty(neighborsV3.OSPFv3Neighbors, "Expected no OSPFv3 neighbours for core1")
+	neighborsV3, err = client.Routing.ListOSPFv3("core2")
+	r.NoError(err, "ListOSPFv3 returned an error")
+	r.Equal(types.OSPFStateFull, neighborsV3.OSPFv3Neighbors[0].OSPFv3NbrState, "Expected the OSPFv3 neighbours of core2")
+	portsV3, err := client.Routing.ListOSPFv3Ports()
+	r.NoError(err, "ListOSPFv3Ports returned an error")
+	r.Len(portsV3.OSPFv3Ports, 1, "Expected the OSPFv3 ports")
+
+	availability, err := client.Device.GetAvailability("core1")
+	r.NoError(err, "GetAvailability returned an error")
+	r.Equal([]types.DeviceAvailability{{Duration: 86400, AvailabilityPerc: 99.5}}, availability.Availability, "Unexpected availability")
+	availability, err = client.Device.GetAvailability("core2")
+	r.NoError(err, "GetAvailability returned an error")
+	r.Empty(availability.Availability, "Expected no availability for core2")
+}
+
+func TestServer_Faults(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{Hostname: "core1"})
+	client := srv.Client()
+
+	srv.InjectError("devices", http.StatusServiceUnavailable, 1)
+	_, err := client.Device.List(nil)
+	var apiErr *librenms.ErrorResponse
+	r.ErrorAs(err, &apiErr, "Expected an API error")
+	r.Equal(http.StatusServiceUnavailable, apiErr.Response.StatusCode, "Unexpected status code")
+	_, err = client.Device.List(nil)
+	r.NoError(err, "Fault should apply once")
+
+	srv.InjectMalformedJSON("devices/*", 0)
+	_, err = client.Device.Get("1")
+	r.Error(err, "Expected a decoding error")
+	_, err = client.Device.List(nil)
+	r.NoError(err, "Fault should only match its path")
+	srv.ClearFaults()
+
+	srv.Inject(librenmstest.Fault{Method: http.MethodGet, Path: "system", Latency: 200 * time.Millisecond})
+	slow := srv.Client(librenms.WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}))
+	_, err = slow.System.Get()
+	r.Error(err, "Expected a timeout")
+
+	unauthorized, err := librenms.New(srv.URL(), "wrong-token")
+	r.NoError(err, "Failed to create client")
+	_, err = unauthorized.Device.List(nil)
+	r.ErrorContains(err, "Unauthenticated", "Expected an authentication error")
+
+	srv.ClearFaults()
+	srv.InjectError("devices", http.StatusServiceUnavailable, 1)
+	_, err = unauthorized.Device.List(nil)
+	r.ErrorContains(err, "Unauthenticated", "Authentication should be checked before faults")
+	_, err = client.Device.List(nil)
+	r.ErrorAs(err, &apiErr, "Unauthenticated requests should not consume faults")
+	r.Equal(http.StatusServiceUnavailable, apiErr.Response.StatusCode, "Unexpected status code")
+
+	r.NotEmpty(srv.Requests(), "Requests should be recorded")
+}
@@ -0,0 +1,479 @@
+package librenmstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/javen-yan/librenms-go/rules"
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// LogKind is the kind of a log served by the logs endpoints.
+type LogKind string
+
+// Log kinds, matching the logs/<kind>/:device routes.
+const (
+	EventLog LogKind = "eventlog"
+	SysLog   LogKind = "syslog"
+	AlertLog LogKind = "alertlog"
+	AuthLog  LogKind = "authlog"
+)
+
+// AddDevice stores a device and returns it. A zero DeviceID is assigned the next free ID.
+// When LocationID refers to a stored location, Location is set to its name.
+func (s *Server) AddDevice(device types.Device) types.Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addDevice(device)
+}
+
+// AddLocation stores a location and returns it. A zero ID is assigned the next free ID.
+func (s *Server) AddLocation(location types.Location) types.Location {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	location.ID = s.assignID("locations", location.ID)
+	s.locations = append(s.locations, location)
+	return location
+}
+
+// AddDeviceGroup stores a device group and returns it. A zero ID is assigned the next free
+// ID. Members are the device IDs of a static group; dynamic groups are resolved from their
+// rules with rules.Evaluate.
+func (s *Server) AddDeviceGroup(group types.DeviceGroup, members ...int) types.DeviceGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group.ID = s.assignID("devicegroups", group.ID)
+	if group.Type == "" {
+		group.Type = "static"
+	}
+	s.groups = append(s.groups, deviceGroup{DeviceGroup: group, members: append([]int(nil), members...)})
+	return group
+}
+
+// AddService stores a service and returns it. A zero ID is assigned the next free ID.
+func (s *Server) AddService(service types.Service) types.Service {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	service.ID = s.assignID("services", service.ID)
+	s.services = append(s.services, service)
+	return service
+}
+
+// AddAlert stores an alert and returns it. A zero ID is assigned the next free ID and the
+// hostname is filled in from the device.
+func (s *Server) AddAlert(alert types.Alert) types.Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	alert.ID = s.assignID("alerts", alert.ID)
+	if i := s.deviceIndex(strconv.Itoa(int(alert.DeviceID))); i >= 0 && alert.Hostname == "" {
+		alert.Hostname = s.devices[i].Hostname
+	}
+	s.alerts = append(s.alerts, alert)
+	return alert
+}
+
+// AddAlertRule stores an alert rule and returns it. A zero ID is assigned the next free ID.
+func (s *Server) AddAlertRule(rule types.AlertRule) types.AlertRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule.ID = s.assignID("rules", rule.ID)
+	s.rules = append(s.rules, rule)
+	return rule
+}
+
+// AddPort stores a port and returns it. A zero PortID is assigned the next free ID.
+func (s *Server) AddPort(port types.Port) types.Port {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	port.PortID = s.assignID("ports", port.PortID)
+	s.ports = append(s.ports, port)
+	return port
+}
+
+// AddBGPSession stores a BGP session and returns it. A zero BGPPeerID is assigned the next
+// free ID.
+func (s *Server) AddBGPSession(session types.BGPSession) types.BGPSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session.BGPPeerID = s.assignID("bgp", session.BGPPeerID)
+	s.bgp = append(s.bgp, session)
+	return session
+}
+
+// UpdateBGPSession replaces the stored BGP session with the same BGPPeerID, as a poll of
+// LibreNMS would. It reports whether the session was found.
+func (s *Server) UpdateBGPSession(session types.BGPSession) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.bgp {
+		if s.bgp[i].BGPPeerID == session.BGPPeerID {
+			s.bgp[i] = session
+			return true
+		}
+	}
+	return false
+}
+
+// SetBGPCounters replaces the BGP prefix counters.
+func (s *Server) SetBGPCounters(counters ...types.BGPCounters) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bgpCounters = append([]types.BGPCounters(nil), counters...)
+}
+
+// AddOSPFNeighbor stores an OSPF neighbour and returns it.
+func (s *Server) AddOSPFNeighbor(neighbor types.OSPFNeighbor) types.OSPFNeighbor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ospf = append(s.ospf, neighbor)
+	return neighbor
+}
+
+// AddOSPFPort stores an OSPF interface and returns it.
+func (s *Server) AddOSPFPort(port types.OSPFPort) types.OSPFPort {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ospfPorts = append(s.ospfPorts, port)
+	return port
+}
+
+// AddOSPFv3Neighbor stores an OSPFv3 neighbour and returns it.
+func (s *Server) AddOSPFv3Neighbor(neighbor types.OSPFv3Neighbor) types.OSPFv3Neighbor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ospfv3 = append(s.ospfv3, neighbor)
+	return neighbor
+}
+
+// AddOSPFv3Port stores an OSPFv3 interface and returns it.
+func (s *Server) AddOSPFv3Port(port types.OSPFv3Port) types.OSPFv3Port {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ospfv3Ports = append(s.ospfv3Ports, port)
+	return port
+}
+
+// AddLink stores a discovered link and returns it. A zero ID is assigned the next free ID.
+func (s *Server) AddLink(link types.Link) types.Link {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link.ID = s.assignID("links", link.ID)
+	s.links = append(s.links, link)
+	return link
+}
+
+// AddVLAN stores a VLAN of a device and returns it. A zero VLANID is assigned the next free
+// ID.
+func (s *Server) AddVLAN(vlan types.VLAN) types.VLAN {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vlan.VLANID = s.assignID("vlans", vlan.VLANID)
+	s.vlans = append(s.vlans, vlan)
+	return vlan
+}
+
+// AddFDB stores a forwarding database entry and returns it. A zero PortsFDBID is assigned
+// the next free ID.
+func (s *Server) AddFDB(entry types.PortFDB) types.PortFDB {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry.PortsFDBID = s.assignID("fdb", entry.PortsFDBID)
+	s.fdb = append(s.fdb, entry)
+	return entry
+}
+
+// AddNAC stores a NAC session and returns it. A zero PortsNACID is assigned the next free ID.
+func (s *Server) AddNAC(entry types.PortNAC) types.PortNAC {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry.PortsNACID = s.assignID("nac", entry.PortsNACID)
+	s.nac = append(s.nac, entry)
+	return entry
+}
+
+// AddARP stores an ARP entry and returns it.
+func (s *Server) AddARP(entry types.ARPEntry) types.ARPEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.arp = append(s.arp, entry)
+	return entry
+}
+
+// AddIPAddress stores an IPv4 address of a port and returns it. A zero IPv4AddressID is
+// assigned the next free ID.
+func (s *Server) AddIPAddress(address types.IPAddress) types.IPAddress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	address.IPv4AddressID = s.assignID("ipv4", address.IPv4AddressID)
+	s.addresses = append(s.addresses, address)
+	return address
+}
+
+// SetAvailability replaces the availability returned for a device.
+func (s *Server) SetAvailability(deviceID int, availability ...types.DeviceAvailability) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.available[types.Int(deviceID)] = append([]types.DeviceAvailability(nil), availability...)
+}
+
+// AddLog stores a log entry of the given kind. The hostname and sysName are filled in from
+// the device and a zero DateTime is set to the current time.
+func (s *Server) AddLog(kind LogKind, entry types.Log) types.Log {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addLog(kind, entry)
+}
+
+// SetSystem replaces the information returned by the system endpoint.
+func (s *Server) SetSystem(info types.SystemInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.system = info
+}
+
+// Devices returns the stored devices.
+func (s *Server) Devices() []types.Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.Device(nil), s.devices...)
+}
+
+// Device returns a stored device by ID or hostname.
+func (s *Server) Device(identifier string) (types.Device, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := s.deviceIndex(identifier); i >= 0 {
+		return s.devices[i], true
+	}
+	return types.Device{}, false
+}
+
+// DeviceGroups returns the stored device groups.
+func (s *Server) DeviceGroups() []types.DeviceGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	groups := make([]types.DeviceGroup, 0, len(s.groups))
+	for _, group := range s.groups {
+		groups = append(groups, group.DeviceGroup)
+	}
+	return groups
+}
+
+// GroupMembers returns the device IDs of a device group, by name or ID.
+func (s *Server) GroupMembers(identifier string) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.groupIndex(identifier)
+	if i < 0 {
+		return nil, fmt.Errorf("device group %q not found", identifier)
+	}
+	return s.groupMembers(&s.groups[i])
+}
+
+// Locations returns the stored locations.
+func (s *Server) Locations() []types.Location {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.Location(nil), s.locations...)
+}
+
+// Services returns the stored services.
+func (s *Server) Services() []types.Service {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.Service(nil), s.services...)
+}
+
+// Alerts returns the stored alerts.
+func (s *Server) Alerts() []types.Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.Alert(nil), s.alerts...)
+}
+
+// AlertRules returns the stored alert rules.
+func (s *Server) AlertRules() []types.AlertRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.AlertRule(nil), s.rules...)
+}
+
+// Ports returns the stored ports.
+func (s *Server) Ports() []types.Port {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.Port(nil), s.ports...)
+}
+
+// BGPSessions returns the stored BGP sessions.
+func (s *Server) BGPSessions() []types.BGPSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.BGPSession(nil), s.bgp...)
+}
+
+// OSPFNeighbors returns the stored OSPF neighbours.
+func (s *Server) OSPFNeighbors() []types.OSPFNeighbor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.OSPFNeighbor(nil), s.ospf...)
+}
+
+// Links returns the stored links.
+func (s *Server) Links() []types.Link {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.Link(nil), s.links...)
+}
+
+// Logs returns the stored logs of the given kind.
+func (s *Server) Logs(kind LogKind) []types.Log {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.Log(nil), s.logs[kind]...)
+}
+
+// SyslogMessages returns the messages received by the syslogsink endpoint.
+func (s *Server) SyslogMessages() []types.SyslogMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.SyslogMessage(nil), s.syslog...)
+}
+
+// InMaintenance reports whether a device is under maintenance at the given time.
+func (s *Server) InMaintenance(deviceID int, at time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inMaintenance(types.Int(deviceID), at)
+}
+
+// assignID returns id when it's set, and the next free ID of the resource otherwise. IDs
+// set by callers move the counter past them.
+func (s *Server) assignID(resource string, id types.Int) types.Int {
+	if id == 0 {
+		s.nextIDs[resource]++
+		return s.nextIDs[resource]
+	}
+	if id > s.nextIDs[resource] {
+		s.nextIDs[resource] = id
+	}
+	return id
+}
+
+func (s *Server) addDevice(device types.Device) types.Device {
+	device.DeviceID = s.assignID("devices", device.DeviceID)
+	if device.Inserted.IsZero() {
+		device.Inserted = types.NewTime(time.Now().UTC().Truncate(time.Second))
+	}
+	if i := s.locationIndex(device.LocationID); i >= 0 {
+		device.Location = s.locations[i].Name
+	}
+	s.devices = append(s.devices, device)
+	return device
+}
+
+func (s *Server) addLog(kind LogKind, entry types.Log) types.Log {
+	if i := s.deviceIndex(strconv.Itoa(int(entry.DeviceID))); i >= 0 {
+		if entry.Hostname == "" {
+			entry.Hostname = s.devices[i].Hostname
+		}
+		if entry.SysName == "" {
+			entry.SysName = s.devices[i].SysName
+		}
+	}
+	if entry.DateTime.IsZero() {
+		entry.DateTime = types.NewTime(time.Now().UTC().Truncate(time.Second))
+	}
+	s.logs[kind] = append(s.logs[kind], entry)
+	return entry
+}
+
+// deviceIndex returns the index of a device by ID or hostname, or -1.
+func (s *Server) deviceIndex(identifier string) int {
+	id, err := strconv.Atoi(identifier)
+	for i, device := range s.devices {
+		if (err == nil && int(device.DeviceID) == id) || strings.EqualFold(string(device.Hostname), identifier) {
+			return i
+		}
+	}
+	return -1
+}
+
+// groupIndex returns the index of a device group by name or ID, or -1.
+func (s *Server) groupIndex(identifier string) int {
+	id, err := strconv.Atoi(identifier)
+	for i, group := range s.groups {
+		if (err == nil && int(group.ID) == id) || string(group.Name) == identifier {
+			return i
+		}
+	}
+	return -1
+}
+
+// locationIndex returns the index of a location by ID, or -1.
+func (s *Server) locationIndex(id types.Int) int {
+	for i, location := range s.locations {
+		if location.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// groupMembers returns the sorted device IDs of a group. Dynamic groups are evaluated
+// against the stored devices, locations and ports.
+func (s *Server) groupMembers(group *deviceGroup) ([]int, error) {
+	if !strings.EqualFold(string(group.Type), "dynamic") {
+		members := make([]int, 0, len(group.members))
+		for _, id := range group.members {
+			if s.deviceIndex(strconv.Itoa(id)) >= 0 {
+				members = append(members, id)
+			}
+		}
+		sort.Ints(members)
+		return members, nil
+	}
+
+	matched, err := rules.Evaluate(&group.Rules, s.devices, &rules.EvaluateOptions{
+		Locations: s.locations,
+		Ports:     append([]types.Port{}, s.ports...),
+	})
+	if err != nil {
+		return nil, err
+	}
+	members := make([]int, 0, len(matched))
+	for _, device := range matched {
+		members = append(members, int(device.DeviceID))
+	}
+	sort.Ints(members)
+	return members, nil
+}
+
+func (s *Server) inMaintenance(deviceID types.Int, at time.Time) bool {
+	window, ok := s.maintenance[deviceID]
+	return ok && !at.Before(window.start) && at.Before(window.end)
+}
+
+// patch applies the fields to v by their JSON names, the way LibreNMS updates model
+// attributes from a request.
+func patch(v any, fields map[string]any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]any)
+	if err := json.Unmarshal(data, &current); err != nil {
+		return err
+	}
+	for k, value := range fields {
+		current[k] = value
+	}
+	if data, err = json.Marshal(current); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -12,7 +12,7 @@ import (
 // Documentation: https://docs.librenms.org/API/Locations/#add_location
 func (l *LocationAPI) Create(location *types.LocationCreateRequest) (*types.BaseResponse, error) {
 	c := l.client
-	req, err := c.newRequest(http.MethodPost, "locations", location, nil)
+	req, err := c.newRequest(opLocationCreate, http.MethodPost, "locations", location, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +26,7 @@ func (l *LocationAPI) Create(location *types.LocationCreateRequest) (*types.Base
 // Documentation: https://docs.librenms.org/API/Locations/#delete_location
 func (l *LocationAPI) Delete(locationID int) (*types.BaseResponse, error) {
 	c := l.client
-	req, err := c.newRequest(http.MethodDelete, fmt.Sprintf("locations/%d", locationID), nil, nil)
+	req, err := c.newRequest(opLocationDelete, http.MethodDelete, fmt.Sprintf("locations/%d", locationID), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +40,7 @@ func (l *LocationAPI) Delete(locationID int) (*types.BaseResponse, error) {
 // Documentation: https://docs.librenms.org/API/Locations/#get_location
 func (l *LocationAPI) Get(locationID int) (*types.LocationResponse, error) {
 	c := l.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("location/%d", locationID), nil, nil)
+	req, err := c.newRequest(opLocationGet, http.MethodGet, fmt.Sprintf("location/%d", locationID), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func (l *LocationAPI) Get(locationID int) (*types.LocationResponse, error) {
 // Documentation: https://docs.librenms.org/API/Locations/#list_locations
 func (l *LocationAPI) List() (*types.LocationsResponse, error) {
 	c := l.client
-	req, err := c.newRequest(http.MethodGet, "resources/locations", nil, nil)
+	req, err := c.newRequest(opLocationList, http.MethodGet, "resources/locations", nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +68,7 @@ func (l *LocationAPI) List() (*types.LocationsResponse, error) {
 // Documentation: https://docs.librenms.org/API/Locations/#edit_location
 func (l *LocationAPI) Update(locationID int, location *types.LocationUpdateRequest) (*types.BaseResponse, error) {
 	c := l.client
-	req, err := c.newRequest(http.MethodPatch, fmt.Sprintf("locations/%d", locationID), location.Payload(), nil)
+	req, err := c.newRequest(opLocationUpdate, http.MethodPatch, fmt.Sprintf("locations/%d", locationID), location.Payload(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -47,10 +47,10 @@ func TestClient_GetLocation(t *testing.T) {
 	r.NoError(err, "GetLocation returned an error")
 	r.NotNil(locationResp, "GetLocation response is nil")
 
-	r.Equal("ok", locationResp.Status, "Expected status 'ok'")
+	r.Equal("ok", string(locationResp.Status), "Expected status 'ok'")
 
-	r.Equal(1, locationResp.Location.ID, "Expected location ID 1")
-	r.Equal("test location", locationResp.Location.Name, "Expected Location name 'test location'")
+	r.EqualValues(1, locationResp.Location.ID, "Expected location ID 1")
+	r.Equal("test location", string(locationResp.Location.Name), "Expected Location name 'test location'")
 	r.Equal(types.Bool(true), locationResp.Location.FixedCoordinates, "Expected FixedCoordinates to be true")
 	r.Equal(types.Float64(37.4220648), locationResp.Location.Longitude, "Expected Longitude to be 37.4220648")
 }
@@ -66,17 +66,17 @@ func TestClient_GetLocations(t *testing.T) {
 	r.NotNil(locationResp, "GetLocations response is nil")
 
 	r.Equal("ok", locationResp.Status, "Expected status 'ok'")
-	r.Equal(5, locationResp.Count, "Expected count 5")
+	r.EqualValues(5, locationResp.Count, "Expected count 5")
 	r.Len(locationResp.Locations, 5, "Expected 5 locations")
 
 	location := locationResp.Locations[0]
-	r.Equal(1, location.ID, "Expected Location ID 1")
-	r.Equal("Sitting on the Dock of the Bay", location.Name, "Expected Location name 'Sitting on the Dock of the Bay'")
+	r.EqualValues(1, location.ID, "Expected Location ID 1")
+	r.Equal("Sitting on the Dock of the Bay", string(location.Name), "Expected Location name 'Sitting on the Dock of the Bay'")
 	r.Equal(types.Bool(false), location.FixedCoordinates, "Expected FixedCoordinates to be false")
 
 	location = locationResp.Locations[4]
-	r.Equal(5, location.ID, "Expected Location ID 5")
-	r.Equal("test location", location.Name, "Expected Location name 'test location'")
+	r.EqualValues(5, location.ID, "Expected Location ID 5")
+	r.Equal("test location", string(location.Name), "Expected Location name 'test location'")
 	r.Equal(types.Bool(true), location.FixedCoordinates, "Expected FixedCoordinates to be true")
 	r.Equal(types.Float64(37.42206480), location.Longitude, "Expected Longitude to be 37.4220648")
 }
@@ -0,0 +1,489 @@
+// Package locator finds the switch port an endpoint is plugged into from its MAC or IP
+// address.
+//
+// LibreNMS spreads the answer over several tables: the forwarding databases (FDB) of the
+// switches, NAC sessions, ARP caches of the routers and the IP addresses of their ports. The
+// FDB of every switch between the endpoint and the core knows the MAC address, so the
+// locator discards the ports that carry links to other devices or are trunks, and ranks the
+// remaining access ports by the time the endpoint was last seen on them.
+package locator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/oui"
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// linkColumns limits the link listing to the ports on either side.
+var linkColumns = types.MustColumns[types.Link]("local_port_id", "remote_port_id", "remote_hostname", "protocol")
+
+var (
+	// ErrInvalidQuery is returned when a query is neither a MAC nor an IP address.
+	ErrInvalidQuery = errors.New("not a MAC or IP address")
+	// ErrNotFound is returned when LibreNMS has no data about an endpoint.
+	ErrNotFound = errors.New("endpoint not found")
+)
+
+// Source is the LibreNMS data a candidate or a piece of evidence comes from.
+type Source string
+
+// Sources of candidates and evidence.
+const (
+	SourceFDB   Source = "fdb"
+	SourceNAC   Source = "nac"
+	SourceARP   Source = "arp"
+	SourceLinks Source = "links"
+	SourcePort  Source = "port"
+)
+
+type (
+	// Locator locates endpoints. The links used to recognise uplinks are fetched on first
+	// use and kept for the lifetime of the Locator. It is safe for concurrent use.
+	Locator struct {
+		client *librenms.Client
+
+		mu sync.Mutex
+		// uplinks maps the ports carrying a link to the neighbour on the other side.
+		uplinks map[int]string
+	}
+
+	// Result is where an endpoint is connected, with the evidence it was derived from.
+	Result struct {
+		// Query is the MAC or IP address passed to Locate.
+		Query string
+		// MAC is the MAC address of the endpoint.
+		MAC types.MAC
+		// Vendor is the vendor of the MAC address, from LibreNMS or the built-in OUI
+		// database. It is empty when unknown.
+		Vendor string
+		// IPs are the IP addresses of the endpoint found in ARP and NAC entries.
+		IPs []string
+		// Port is the first candidate, nil when the endpoint was only seen on uplinks.
+		Port *Candidate
+		// Candidates are the access ports the endpoint was seen on, most recent first.
+		Candidates []Candidate
+		// Uplinks are the ports the endpoint was seen on that lead to other devices.
+		Uplinks []Candidate
+		// Evidence lists the data the result was derived from, in the order it was found.
+		Evidence []Evidence
+	}
+
+	// Candidate is a port an endpoint was seen on.
+	Candidate struct {
+		DeviceID int
+		Hostname string
+		PortID   int
+		// Port is the interface name.
+		Port string
+		// Description is the interface alias.
+		Description string
+		VLAN        int
+		// LastSeen is the last time the endpoint was seen on the port, zero when unknown.
+		LastSeen time.Time
+		// Username is the user authenticated by NAC on the port.
+		Username string
+		// Sources are the tables the endpoint was found in for this port.
+		Sources []Source
+	}
+
+	// Evidence is a piece of data supporting a result.
+	Evidence struct {
+		Source   Source
+		DeviceID int
+		PortID   int
+		// Message describes the evidence, e.g. "FDB of sw1 Gi1/0/3 in VLAN 10".
+		Message string
+		// Seen is when the data was last updated, zero when unknown.
+		Seen time.Time
+	}
+
+	// portInfo is the port and device data used to describe a port.
+	portInfo struct {
+		deviceID    int
+		hostname    string
+		name        string
+		description string
+		trunk       string
+	}
+
+	// search holds the state of a single Locate call.
+	search struct {
+		client  *librenms.Client
+		result  *Result
+		ports   map[int]*portInfo
+		devices map[int]types.Device
+	}
+)
+
+// New returns a Locator using client.
+func New(client *librenms.Client) *Locator {
+	return &Locator{client: client}
+}
+
+// Locate finds where the endpoint with the given MAC or IP address is connected. MAC
+// addresses may use any notation accepted by types.ParseMAC. IP addresses are
+// resolved to a MAC address through the ARP caches; when several MAC addresses are found,
+// the first one is used and the others are recorded as evidence.
+//
+// It returns ErrNotFound when neither FDB, NAC nor ARP entries mention the endpoint.
+func (l *Locator) Locate(ctx context.Context, macOrIP string) (*Result, error) {
+	s := &search{
+		client:  l.client.WithContext(ctx),
+		result:  &Result{Query: macOrIP},
+		ports:   make(map[int]*portInfo),
+		devices: make(map[int]types.Device),
+	}
+
+	query := strings.TrimSpace(macOrIP)
+	if addr, err := netip.ParseAddr(query); err == nil {
+		if err := s.resolveIP(addr); err != nil {
+			return nil, err
+		}
+		if s.result.MAC.IsZero() {
+			return nil, fmt.Errorf("%w: no ARP entry for %s", ErrNotFound, addr)
+		}
+	} else {
+		mac, err := types.ParseMAC(query)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidQuery, macOrIP)
+		}
+		s.result.MAC = mac
+		if err := s.arp(mac); err != nil {
+			return nil, err
+		}
+	}
+
+	uplinks, err := l.loadUplinks(s.client)
+	if err != nil {
+		return nil, err
+	}
+	found, err := s.candidates(uplinks)
+	if err != nil {
+		return nil, err
+	}
+	if !found && len(s.result.IPs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, s.result.MAC)
+	}
+	if len(s.result.Candidates) > 0 {
+		s.result.Port = &s.result.Candidates[0]
+	}
+	if s.result.Vendor == "" {
+		s.result.Vendor = oui.Default().Vendor(s.result.MAC)
+	}
+	return s.result, nil
+}
+
+// loadUplinks returns the ports carrying links, fetching them on first use.
+func (l *Locator) loadUplinks(client *librenms.Client) (map[int]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.uplinks != nil {
+		return l.uplinks, nil
+	}
+
+	links, err := client.Switching.GetAllLinks(&types.SwitchingQueryParams{Columns: linkColumns.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links: %w", err)
+	}
+	uplinks := make(map[int]string)
+	for _, link := range links.Links {
+		if link.LocalPortID != 0 {
+			uplinks[int(link.LocalPortID)] = fmt.Sprintf("%s neighbour %s", link.Protocol, link.RemoteHostname)
+		}
+		if _, ok := uplinks[int(link.RemotePortID)]; !ok && link.RemotePortID != 0 {
+			uplinks[int(link.RemotePortID)] = fmt.Sprintf("%s neighbour of port %d", link.Protocol, link.LocalPortID)
+		}
+	}
+	l.uplinks = uplinks
+	return uplinks, nil
+}
+
+// resolveIP sets the MAC address from the ARP entries of addr.
+func (s *search) resolveIP(addr netip.Addr) error {
+	entries, err := s.client.Routing.ListARP(addr.String(), "")
+	if err != nil {
+		return fmt.Errorf("failed to list ARP entries of %s: %w", addr, err)
+	}
+	for _, entry := range entries.ARP {
+		mac := entry.MACAddress
+		if mac.IsZero() {
+			continue
+		}
+		message, err := s.arpMessage(entry, mac)
+		if err != nil {
+			return err
+		}
+		switch s.result.MAC {
+		case types.MAC{}:
+			s.result.MAC = mac
+		case mac:
+		default:
+			message += ", ignored in favour of " + s.result.MAC.String()
+		}
+		s.addEvidence(Evidence{Source: SourceARP, PortID: int(entry.PortID), DeviceID: s.deviceOf(int(entry.PortID)), Message: message})
+		s.addIP(string(entry.IPv4Address))
+	}
+	return nil
+}
+
+// arp adds the IP addresses of mac found in the ARP entries.
+func (s *search) arp(mac types.MAC) error {
+	entries, err := s.client.Routing.ListARP(mac.Text(types.MACBare), "")
+	if err != nil {
+		return fmt.Errorf("failed to list ARP entries of %s: %w", mac, err)
+	}
+	for _, entry := range entries.ARP {
+		message, err := s.arpMessage(entry, mac)
+		if err != nil {
+			return err
+		}
+		s.addEvidence(Evidence{Source: SourceARP, PortID: int(entry.PortID), DeviceID: s.deviceOf(int(entry.PortID)), Message: message})
+		s.addIP(string(entry.IPv4Address))
+	}
+	return nil
+}
+
+// arpMessage describes an ARP entry, with the addresses of the port it was learned on.
+func (s *search) arpMessage(entry types.ARPEntry, mac types.MAC) (string, error) {
+	message := fmt.Sprintf("ARP maps %s to %s", entry.IPv4Address, mac)
+	if entry.PortID == 0 {
+		return message, nil
+	}
+	info, err := s.port(int(entry.PortID))
+	if err != nil {
+		return "", err
+	}
+	message += fmt.Sprintf(" on %s %s", info.hostname, info.name)
+
+	addresses, err := s.client.Port.GetPortIPInfo(int(entry.PortID))
+	if err != nil {
+		return "", fmt.Errorf("failed to get IP addresses of port %d: %w", entry.PortID, err)
+	}
+	var networks []string
+	for _, address := range addresses.Addresses {
+		networks = append(networks, fmt.Sprintf("%s/%d", address.IPv4Address, address.IPv4Prefixlen))
+	}
+	if len(networks) > 0 {
+		message += " (" + strings.Join(networks, ", ") + ")"
+	}
+	return message, nil
+}
+
+// candidates adds the ports the endpoint was seen on from the FDB and NAC entries. It
+// reports whether any entry was found.
+func (s *search) candidates(uplinks map[int]string) (bool, error) {
+	fdb, err := s.client.Switching.GetPortFDB(s.result.MAC.Text(types.MACBare), nil)
+	if err != nil && !notFound(err) {
+		return false, fmt.Errorf("failed to list FDB entries: %w", err)
+	}
+	nac, err := s.client.Switching.GetPortNAC(s.result.MAC.Text(types.MACBare), nil)
+	if err != nil && !notFound(err) {
+		return false, fmt.Errorf("failed to list NAC entries: %w", err)
+	}
+	if len(fdb.PortsFDB) == 0 && len(nac.PortsNAC) == 0 {
+		return false, nil
+	}
+	lastSeen, err := s.fdbDetail()
+	if err != nil {
+		return false, err
+	}
+
+	byPort := make(map[int]*Candidate)
+	var order []int
+	candidate := func(portID, deviceID int) (*Candidate, error) {
+		if c, ok := byPort[portID]; ok {
+			return c, nil
+		}
+		info, err := s.port(portID)
+		if err != nil {
+			return nil, err
+		}
+		if deviceID == 0 {
+			deviceID = info.deviceID
+		}
+		c := &Candidate{DeviceID: deviceID, Hostname: info.hostname, PortID: portID, Port: info.name, Description: info.description}
+		byPort[portID] = c
+		order = append(order, portID)
+		return c, nil
+	}
+
+	for _, entry := range fdb.PortsFDB {
+		c, err := candidate(int(entry.PortID), int(entry.DeviceID))
+		if err != nil {
+			return false, err
+		}
+		seen := entry.UpdatedAt.Time
+		if detail := lastSeen[c.Hostname+"/"+c.Port]; detail.After(seen) {
+			seen = detail
+		}
+		c.VLAN = int(entry.VLANID)
+		c.see(SourceFDB, seen)
+		s.addEvidence(Evidence{
+			Source: SourceFDB, DeviceID: c.DeviceID, PortID: c.PortID, Seen: seen,
+			Message: fmt.Sprintf("FDB of %s %s in VLAN %d", c.Hostname, c.Port, entry.VLANID),
+		})
+	}
+	for _, entry := range nac.PortsNAC {
+		c, err := candidate(int(entry.PortID), int(entry.DeviceID))
+		if err != nil {
+			return false, err
+		}
+		if entry.VLAN != 0 {
+			c.VLAN = int(entry.VLAN)
+		}
+		if entry.Username != "" {
+			c.Username = string(entry.Username)
+		}
+		c.see(SourceNAC, entry.UpdatedAt.Time)
+		s.addIP(string(entry.IPAddress))
+		message := fmt.Sprintf("NAC session on %s %s", c.Hostname, c.Port)
+		if entry.Username != "" {
+			message += " for " + string(entry.Username)
+		}
+		if entry.AuthzStatus != "" {
+			message += ", " + string(entry.AuthzStatus)
+		}
+		s.addEvidence(Evidence{Source: SourceNAC, DeviceID: c.DeviceID, PortID: c.PortID, Seen: entry.UpdatedAt.Time, Message: message})
+	}
+
+	for _, portID := range order {
+		c := byPort[portID]
+		info := s.ports[portID]
+		switch {
+		case uplinks[portID] != "":
+			s.result.Uplinks = append(s.result.Uplinks, *c)
+			s.addEvidence(Evidence{
+				Source: SourceLinks, DeviceID: c.DeviceID, PortID: c.PortID,
+				Message: fmt.Sprintf("%s %s is an uplink (%s)", c.Hostname, c.Port, uplinks[portID]),
+			})
+		case info.trunk != "":
+			s.result.Uplinks = append(s.result.Uplinks, *c)
+			s.addEvidence(Evidence{
+				Source: SourcePort, DeviceID: c.DeviceID, PortID: c.PortID,
+				Message: fmt.Sprintf("%s %s is a trunk (%s)", c.Hostname, c.Port, info.trunk),
+			})
+		default:
+			s.result.Candidates = append(s.result.Candidates, *c)
+		}
+	}
+	sort.SliceStable(s.result.Candidates, func(i, j int) bool {
+		a, b := s.result.Candidates[i], s.result.Candidates[j]
+		if !a.LastSeen.Equal(b.LastSeen) {
+			return a.LastSeen.After(b.LastSeen)
+		}
+		return len(a.Sources) > len(b.Sources)
+	})
+	return true, nil
+}
+
+// fdbDetail returns the last time the endpoint was seen per "hostname/port", and sets the
+// vendor of the MAC address.
+func (s *search) fdbDetail() (map[string]time.Time, error) {
+	detail, err := s.client.Switching.GetPortFDBDetail(s.result.MAC.Text(types.MACBare), nil)
+	if err != nil && !notFound(err) {
+		return nil, fmt.Errorf("failed to get FDB details: %w", err)
+	}
+	s.result.Vendor = string(detail.MACOUI)
+	lastSeen := make(map[string]time.Time, len(detail.PortsFDB))
+	for _, entry := range detail.PortsFDB {
+		lastSeen[string(entry.Hostname+"/"+entry.IfName)] = entry.LastSeen.Time
+	}
+	return lastSeen, nil
+}
+
+// port returns the names of a port and its device, fetching them once per search.
+func (s *search) port(portID int) (*portInfo, error) {
+	if info, ok := s.ports[portID]; ok {
+		return info, nil
+	}
+	resp, err := s.client.Port.GetPortInfo(portID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get port %d: %w", portID, err)
+	}
+	info := &portInfo{name: strconv.Itoa(portID)}
+	if len(resp.Port) > 0 {
+		port := resp.Port[0]
+		info.deviceID = int(port.DeviceID)
+		info.name = string(port.IfName)
+		if info.name == "" {
+			info.name = string(port.IfDescr)
+		}
+		info.description = string(port.IfAlias)
+		if port.IfTrunk.Valid {
+			info.trunk = string(port.IfTrunk.V)
+		}
+	}
+	if info.deviceID != 0 {
+		device, err := s.device(info.deviceID)
+		if err != nil {
+			return nil, err
+		}
+		info.hostname = string(device.Hostname)
+	}
+	s.ports[portID] = info
+	return info, nil
+}
+
+// device returns a device, fetching it once per search.
+func (s *search) device(deviceID int) (types.Device, error) {
+	if device, ok := s.devices[deviceID]; ok {
+		return device, nil
+	}
+	resp, err := s.client.Device.Get(strconv.Itoa(deviceID))
+	if err != nil {
+		return types.Device{}, fmt.Errorf("failed to get device %d: %w", deviceID, err)
+	}
+	var device types.Device
+	if len(resp.Devices) > 0 {
+		device = resp.Devices[0]
+	}
+	s.devices[deviceID] = device
+	return device, nil
+}
+
+// deviceOf returns the device ID of a port already fetched, 0 otherwise.
+func (s *search) deviceOf(portID int) int {
+	if info, ok := s.ports[portID]; ok {
+		return info.deviceID
+	}
+	return 0
+}
+
+func (s *search) addEvidence(evidence Evidence) {
+	s.result.Evidence = append(s.result.Evidence, evidence)
+}
+
+func (s *search) addIP(ip string) {
+	if ip != "" && !slices.Contains(s.result.IPs, ip) {
+		s.result.IPs = append(s.result.IPs, ip)
+	}
+}
+
+// see records that the endpoint was seen on the port by source at the given time.
+func (c *Candidate) see(source Source, at time.Time) {
+	if !slices.Contains(c.Sources, source) {
+		c.Sources = append(c.Sources, source)
+	}
+	if at.After(c.LastSeen) {
+		c.LastSeen = at
+	}
+}
+
+// notFound reports whether err is a 404 response, which LibreNMS returns for empty FDB and
+// NAC lookups.
+func notFound(err error) bool {
+	var resp *librenms.ErrorResponse
+	return errors.As(err, &resp) && resp.Response != nil && resp.Response.StatusCode == http.StatusNotFound
+}
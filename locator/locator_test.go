@@ -0,0 +1,142 @@
+package locator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/locator"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	seen     = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	endpoint = types.MustParseMAC("00:1a:2b:3c:4d:5e")
+)
+
+// newServer returns a server with a core switch routing VLAN 10 and two access switches. The
+// endpoint 00:1a:2b:3c:4d:5e is plugged into sw1 Gi1/0/5 and was seen on Gi1/0/6 the day
+// before.
+func newServer(t *testing.T) *librenmstest.Server {
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{DeviceID: 1, Hostname: "core1"})
+	srv.AddDevice(types.Device{DeviceID: 2, Hostname: "sw1"})
+	srv.AddDevice(types.Device{DeviceID: 3, Hostname: "sw2"})
+	srv.AddPort(types.Port{PortID: 10, DeviceID: 1, IfName: "Vlan10"})
+	srv.AddPort(types.Port{PortID: 11, DeviceID: 1, IfName: "Te1/0/1"})
+	srv.AddPort(types.Port{PortID: 21, DeviceID: 2, IfName: "Te1/1/1"})
+	srv.AddPort(types.Port{PortID: 22, DeviceID: 2, IfName: "Gi1/0/5", IfAlias: "desk 4.12"})
+	srv.AddPort(types.Port{PortID: 23, DeviceID: 2, IfName: "Gi1/0/6"})
+	srv.AddPort(types.Port{PortID: 31, DeviceID: 3, IfName: "Gi1/0/1", IfTrunk: types.NewNull(types.String("dot1Q"))})
+	srv.AddLink(types.Link{LocalDeviceID: 2, LocalPortID: 21, RemoteDeviceID: 1, RemotePortID: 11, RemoteHostname: "core1", Protocol: "lldp"})
+	srv.AddIPAddress(types.IPAddress{PortID: 10, IPv4Address: "10.0.10.1", IPv4Prefixlen: 24})
+	srv.AddARP(types.ARPEntry{PortID: 10, MACAddress: endpoint, IPv4Address: "10.0.10.50"})
+
+	for _, entry := range []types.PortFDB{
+		{PortID: 11, DeviceID: 1, UpdatedAt: types.NewTime(seen.Add(time.Minute))},
+		{PortID: 21, DeviceID: 2, UpdatedAt: types.NewTime(seen.Add(time.Minute))},
+		{PortID: 23, DeviceID: 2, UpdatedAt: types.NewTime(seen.Add(-24 * time.Hour))},
+		{PortID: 22, DeviceID: 2, UpdatedAt: types.NewTime(seen)},
+		{PortID: 31, DeviceID: 3, UpdatedAt: types.NewTime(seen)},
+	} {
+		entry.MACAddress = endpoint
+		entry.VLANID = 10
+		srv.AddFDB(entry)
+	}
+	srv.AddNAC(types.PortNAC{
+		PortID: 22, DeviceID: 2, MACAddress: endpoint, IPAddress: "10.0.10.50", Username: "alice",
+		AuthzStatus: "authorizationSuccess", UpdatedAt: types.NewTime(seen.Add(-time.Hour)),
+	})
+	return srv
+}
+
+func TestLocate(t *testing.T) {
+	r := require.New(t)
+
+	srv := newServer(t)
+	l := locator.New(srv.Client())
+
+	result, err := l.Locate(context.Background(), "00:1A:2B:3C:4D:5E")
+	r.NoError(err, "Locate returned an error")
+	r.Equal(endpoint, result.MAC, "Expected the MAC address")
+	r.Equal([]string{"10.0.10.50"}, result.IPs, "Expected the IP address from ARP and NAC")
+
+	r.NotNil(result.Port, "Expected a port")
+	r.Equal(locator.Candidate{
+		DeviceID: 2, Hostname: "sw1", PortID: 22, Port: "Gi1/0/5", Description: "desk 4.12", VLAN: 10,
+		LastSeen: seen, Username: "alice", Sources: []locator.Source{locator.SourceFDB, locator.SourceNAC},
+	}, *result.Port, "Unexpected port")
+	r.Len(result.Candidates, 2, "Expected the access ports")
+	r.Equal(23, result.Candidates[1].PortID, "Expected the older port last")
+
+	uplinks := make([]int, 0, len(result.Uplinks))
+	for _, uplink := range result.Uplinks {
+		uplinks = append(uplinks, uplink.PortID)
+	}
+	r.Equal([]int{11, 21, 31}, uplinks, "Expected the link and trunk ports to be excluded")
+
+	messages := make(map[locator.Source][]string)
+	for _, evidence := range result.Evidence {
+		messages[evidence.Source] = append(messages[evidence.Source], evidence.Message)
+	}
+	r.Equal([]string{"ARP maps 10.0.10.50 to 00:1a:2b:3c:4d:5e on core1 Vlan10 (10.0.10.1/24)"}, messages[locator.SourceARP],
+		"Expected the ARP entry with the addresses of its port")
+	r.Contains(messages[locator.SourceNAC], "NAC session on sw1 Gi1/0/5 for alice, authorizationSuccess", "Expected the NAC session")
+	r.Contains(messages[locator.SourceLinks], "sw1 Te1/1/1 is an uplink (lldp neighbour core1)", "Expected the uplink")
+	r.Contains(messages[locator.SourcePort], "sw2 Gi1/0/1 is a trunk (dot1Q)", "Expected the trunk")
+	r.Len(messages[locator.SourceFDB], 5, "Expected every FDB entry")
+}
+
+func TestLocate_Formats(t *testing.T) {
+	r := require.New(t)
+
+	srv := newServer(t)
+	l := locator.New(srv.Client())
+
+	for _, query := range []string{"001a2b3c4d5e", "00-1A-2B-3C-4D-5E", "001a.2b3c.4d5e", " 10.0.10.50 "} {
+		result, err := l.Locate(context.Background(), query)
+		r.NoError(err, "Locate returned an error for %q", query)
+		r.NotNil(result.Port, "Expected a port for %q", query)
+		r.Equal(22, result.Port.PortID, "Unexpected port for %q", query)
+	}
+
+	links := 0
+	for _, req := range srv.Requests() {
+		if req.Path == "resources/links" {
+			links++
+		}
+	}
+	r.Equal(1, links, "Expected the links to be fetched once")
+}
+
+func TestLocate_Errors(t *testing.T) {
+	r := require.New(t)
+
+	srv := newServer(t)
+	srv.AddARP(types.ARPEntry{MACAddress: types.MustParseMAC("0050.56aa.bbcc"), IPv4Address: "10.0.10.60"})
+	l := locator.New(srv.Client())
+
+	_, err := l.Locate(context.Background(), "aa:bb:cc:dd:ee:ff")
+	r.ErrorIs(err, locator.ErrNotFound, "Expected an unknown MAC address")
+	_, err = l.Locate(context.Background(), "10.0.10.99")
+	r.ErrorIs(err, locator.ErrNotFound, "Expected an IP address without ARP entry")
+	_, err = l.Locate(context.Background(), "printer")
+	r.ErrorIs(err, locator.ErrInvalidQuery, "Expected an invalid query")
+
+	result, err := l.Locate(context.Background(), "10.0.10.60")
+	r.NoError(err, "Expected an endpoint known from ARP only")
+	r.Equal("0050.56aa.bbcc", result.MAC.Text(types.MACDot), "Expected the MAC address from ARP")
+	r.Equal("VMware, Inc.", result.Vendor, "Expected the vendor from the OUI database")
+	r.Nil(result.Port, "Expected no port")
+
+	srv.InjectError("resources/fdb/*", 500, 1)
+	_, err = l.Locate(context.Background(), "001a2b3c4d5e")
+	r.ErrorContains(err, "failed to list FDB entries", "Expected the API error")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = l.Locate(ctx, "001a2b3c4d5e")
+	r.ErrorIs(err, context.Canceled, "Expected the context error")
+}
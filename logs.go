@@ -21,28 +21,28 @@ type LogsAPI struct {
 // The identifier can be either a device ID or hostname.
 func (l *LogsAPI) ListEventLogs(identifier string, query *types.LogsQuery) (*types.LogsResponse, error) {
 	uri := fmt.Sprintf("%s/eventlog/%s", logsEndpoint, identifier)
-	return l.listLogs(uri, query)
+	return l.listLogs(opLogsListEventLogs, uri, query)
 }
 
 // ListSysLogs retrieves system logs for a specific device.
 // The identifier can be either a device ID or hostname.
 func (l *LogsAPI) ListSysLogs(identifier string, query *types.LogsQuery) (*types.LogsResponse, error) {
 	uri := fmt.Sprintf("%s/syslog/%s", logsEndpoint, identifier)
-	return l.listLogs(uri, query)
+	return l.listLogs(opLogsListSysLogs, uri, query)
 }
 
 // ListAlertLogs retrieves alert logs for a specific device.
 // The identifier can be either a device ID or hostname.
 func (l *LogsAPI) ListAlertLogs(identifier string, query *types.LogsQuery) (*types.LogsResponse, error) {
 	uri := fmt.Sprintf("%s/alertlog/%s", logsEndpoint, identifier)
-	return l.listLogs(uri, query)
+	return l.listLogs(opLogsListAlertLogs, uri, query)
 }
 
 // ListAuthLogs retrieves authentication logs for a specific device.
 // The identifier can be either a device ID or hostname.
 func (l *LogsAPI) ListAuthLogs(identifier string, query *types.LogsQuery) (*types.LogsResponse, error) {
 	uri := fmt.Sprintf("%s/authlog/%s", logsEndpoint, identifier)
-	return l.listLogs(uri, query)
+	return l.listLogs(opLogsListAuthLogs, uri, query)
 }
 
 // ListLogs is an alias for ListEventLogs for backward compatibility.
@@ -57,7 +57,7 @@ func (l *LogsAPI) ListLogs(identifier string, query *types.LogsQuery) (*types.Lo
 func (l *LogsAPI) Syslogsink(messages types.SyslogsinkRequest) (*types.BaseResponse, error) {
 	uri := fmt.Sprintf("%s/syslogsink", logsEndpoint)
 
-	req, err := l.client.newRequest(http.MethodPost, uri, messages, nil)
+	req, err := l.client.newRequest(opLogsSyslogsink, http.MethodPost, uri, messages, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create syslogsink request: %w", err)
 	}
@@ -71,13 +71,13 @@ func (l *LogsAPI) Syslogsink(messages types.SyslogsinkRequest) (*types.BaseRespo
 }
 
 // listLogs is a helper method that handles the common logic for listing logs.
-func (l *LogsAPI) listLogs(uri string, query *types.LogsQuery) (*types.LogsResponse, error) {
+func (l *LogsAPI) listLogs(op operation, uri string, query *types.LogsQuery) (*types.LogsResponse, error) {
 	params, err := parseParams(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse query parameters: %w", err)
 	}
 
-	req, err := l.client.newRequest(http.MethodGet, uri, nil, params)
+	req, err := l.client.newRequest(op, http.MethodGet, uri, nil, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create logs request: %w", err)
 	}
@@ -0,0 +1,98 @@
+package librenms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMAC(t *testing.T) {
+	r := require.New(t)
+
+	want := types.MAC{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}
+	for _, s := range []string{
+		"00:1a:2b:3c:4d:5e",
+		"00-1A-2B-3C-4D-5E",
+		"001a.2b3c.4d5e",
+		"001A2B3C4D5E",
+		"001a2b-3c4d5e",
+		"0:1a:2b:3c:4d:5e",
+		"00 1a 2b 3c 4d 5e",
+		"0x001a2b3c4d5e",
+		" 00:1a:2b:3c:4d:5e ",
+	} {
+		mac, err := types.ParseMAC(s)
+		r.NoError(err, "ParseMAC returned an error for %q", s)
+		r.Equal(want, mac, "Unexpected MAC address for %q", s)
+	}
+
+	for _, s := range []string{
+		"",
+		"00:1a:2b:3c:4d",
+		"00:1a:2b:3c:4d:5e:6f",
+		"00:1a-2b:3c:4d:5e",
+		"001a.2b3c4d5e",
+		"001:a2:b3:c4:d5:e",
+		"001a2b3c4d5g",
+		"printer",
+	} {
+		_, err := types.ParseMAC(s)
+		r.Error(err, "Expected an error for %q", s)
+	}
+}
+
+func TestMAC_Text(t *testing.T) {
+	r := require.New(t)
+
+	mac := types.MustParseMAC("00:1a:2b:3c:4d:5e")
+	r.Equal("00:1a:2b:3c:4d:5e", mac.Text(types.MACColon), "Unexpected colon notation")
+	r.Equal("00-1a-2b-3c-4d-5e", mac.Text(types.MACDash), "Unexpected dash notation")
+	r.Equal("001a.2b3c.4d5e", mac.Text(types.MACDot), "Unexpected dotted notation")
+	r.Equal("001a2b3c4d5e", mac.Text(types.MACBare), "Unexpected bare notation")
+	r.Equal("00:1a:2b:3c:4d:5e", mac.String(), "Expected the colon notation by default")
+	r.Equal("", types.MAC{}.String(), "Expected an empty string for the zero MAC")
+
+	r.Equal([3]byte{0x00, 0x1a, 0x2b}, mac.OUI(), "Unexpected OUI")
+	r.False(mac.IsLocal(), "Expected a universal address")
+	r.True(types.MustParseMAC("52:54:00:12:34:56").IsLocal(), "Expected a locally administered address")
+	r.True(types.MustParseMAC("01:00:5e:00:00:01").IsMulticast(), "Expected a multicast address")
+}
+
+func TestMAC_JSON(t *testing.T) {
+	r := require.New(t)
+
+	var value struct {
+		FDB        types.MAC `json:"fdb"`
+		Port       types.MAC `json:"port"`
+		Tunnel     types.MAC `json:"tunnel"`
+		Null       types.MAC `json:"null"`
+		Empty      types.MAC `json:"empty"`
+		Omitted    types.MAC `json:"omitted"`
+		InfiniBand types.MAC `json:"infiniband"`
+	}
+	r.NoError(json.Unmarshal([]byte(`{"fdb":"001a2b3c4d5e","port":"00:1A:2B:3C:4D:5E","tunnel":"00000000",`+
+		`"null":null,"empty":"","infiniband":"80000220fe800000000000000002c903000fa0b1"}`), &value), "Failed to decode MAC addresses")
+	want := types.MustParseMAC("001a2b3c4d5e")
+	r.Equal(want, value.FDB, "Expected the bare notation to decode")
+	r.Equal(want, value.Port, "Expected the colon notation to decode")
+	r.True(value.Tunnel.IsZero(), "Expected addresses of other lengths to be zero")
+	r.True(value.InfiniBand.IsZero(), "Expected addresses of other lengths to be zero")
+	r.True(value.Null.IsZero(), "Expected null to be zero")
+	r.True(value.Empty.IsZero(), "Expected an empty string to be zero")
+
+	var invalid types.MAC
+	r.ErrorContains(json.Unmarshal([]byte(`"not a mac"`), &invalid), "failed to unmarshal MAC", "Expected an error for text")
+
+	data, err := json.Marshal(value)
+	r.NoError(err, "Failed to encode MAC addresses")
+	r.JSONEq(`{"fdb":"00:1a:2b:3c:4d:5e","port":"00:1a:2b:3c:4d:5e","tunnel":"","null":"","empty":"","omitted":"","infiniband":""}`,
+		string(data), "Expected the default notation")
+
+	defer func(format types.MACFormat) { types.DefaultMACFormat = format }(types.DefaultMACFormat)
+	types.DefaultMACFormat = types.MACDot
+	data, err = json.Marshal(map[types.MAC]int{want: 1})
+	r.NoError(err, "Failed to encode MAC keys")
+	r.JSONEq(`{"001a.2b3c.4d5e":1}`, string(data), "Expected the configured notation")
+}
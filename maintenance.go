@@ -0,0 +1,200 @@
+package librenms
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// ErrMaintenanceNotActive is reported for devices that were scheduled but did not enter
+// maintenance according to GetDeviceMaintenance.
+var ErrMaintenanceNotActive = errors.New("device is not under maintenance")
+
+type (
+	// MaintenanceWindow describes a maintenance window applied by a MaintenancePlanner.
+	MaintenanceWindow struct {
+		Title string
+		Notes string
+		// Start is the start of the window. A zero start begins the maintenance immediately.
+		// It is formatted in its own location, which should match the LibreNMS server timezone.
+		Start time.Time
+		// Duration of the window, rounded up to whole minutes.
+		Duration time.Duration
+	}
+
+	// MaintenancePlanner applies a maintenance window to devices resolved from device groups,
+	// locations and hostnames. Create it with NewMaintenancePlanner.
+	MaintenancePlanner struct {
+		client    *Client
+		groups    []string
+		locations []string
+		hostnames []string
+	}
+
+	// MaintenanceResult is the outcome of a maintenance window for a single device.
+	MaintenanceResult struct {
+		DeviceID int
+		Hostname string
+		// Sources lists how the device was selected, e.g. "group:core" or "location:DC1".
+		Sources []string
+		// Applied is set when the maintenance request was accepted.
+		Applied bool
+		// Verified is set when GetDeviceMaintenance confirmed the device is under maintenance.
+		// Windows starting in the future are not verified.
+		Verified bool
+		Err      error
+	}
+
+	// MaintenanceReport lists the per-device results of a maintenance window.
+	MaintenanceReport struct {
+		Window  MaintenanceWindow
+		Results []MaintenanceResult
+	}
+)
+
+// Request converts the window into a maintenance request payload.
+func (w MaintenanceWindow) Request() *types.DeviceMaintenanceRequest {
+	return types.NewDeviceMaintenanceRequest(w.Title, w.Start, w.Duration).SetNotes(w.Notes)
+}
+
+// Failed returns the results with an error.
+func (r *MaintenanceReport) Failed() []MaintenanceResult {
+	var failed []MaintenanceResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// NewMaintenancePlanner creates a new, empty MaintenancePlanner for the client.
+func NewMaintenancePlanner(client *Client) *MaintenancePlanner {
+	return &MaintenancePlanner{client: client}
+}
+
+// AddGroups selects the members of the given device groups, by name or ID.
+func (p *MaintenancePlanner) AddGroups(groups ...string) *MaintenancePlanner {
+	p.groups = append(p.groups, groups...)
+	return p
+}
+
+// AddLocations selects the devices of the given locations, by name or ID.
+func (p *MaintenancePlanner) AddLocations(locations ...string) *MaintenancePlanner {
+	p.locations = append(p.locations, locations...)
+	return p
+}
+
+// AddHostnames selects devices by hostname. Unknown hostnames make Resolve fail.
+func (p *MaintenancePlanner) AddHostnames(hostnames ...string) *MaintenancePlanner {
+	p.hostnames = append(p.hostnames, hostnames...)
+	return p
+}
+
+// Resolve returns the selected devices, without duplicates, with the sources that selected
+// them. Nothing is changed in LibreNMS.
+func (p *MaintenancePlanner) Resolve() ([]MaintenanceResult, error) {
+	devices, err := p.client.Device.List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	byID := make(map[int]types.Device, len(devices.Devices))
+	for _, device := range devices.Devices {
+		byID[int(device.DeviceID)] = device
+	}
+
+	var results []MaintenanceResult
+	index := make(map[int]int)
+	add := func(device types.Device, source string) {
+		i, ok := index[int(device.DeviceID)]
+		if !ok {
+			i = len(results)
+			index[int(device.DeviceID)] = i
+			results = append(results, MaintenanceResult{DeviceID: int(device.DeviceID), Hostname: string(device.Hostname)})
+		}
+		results[i].Sources = append(results[i].Sources, source)
+	}
+
+	for _, group := range p.groups {
+		members, err := p.client.DeviceGroup.GetMembers(group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get members of device group %q: %w", group, err)
+		}
+		for _, member := range members.Devices {
+			device, ok := byID[int(member.ID)]
+			if !ok {
+				device = types.Device{DeviceID: member.ID}
+			}
+			add(device, "group:"+group)
+		}
+	}
+
+	for _, location := range p.locations {
+		id, err := strconv.Atoi(location)
+		for _, device := range devices.Devices {
+			if (err == nil && int(device.LocationID) == id) || strings.EqualFold(string(device.Location), location) {
+				add(device, "location:"+location)
+			}
+		}
+	}
+
+	for _, hostname := range p.hostnames {
+		found := false
+		for _, device := range devices.Devices {
+			if strings.EqualFold(string(device.Hostname), hostname) {
+				add(device, "hostname:"+hostname)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("device %q not found", hostname)
+		}
+	}
+	return results, nil
+}
+
+// Apply resolves the selected devices and sets each of them into maintenance. Devices are
+// checked with GetDeviceMaintenance when the window has already started. An error is only
+// returned when the devices can't be resolved; per-device failures are in the report.
+func (p *MaintenancePlanner) Apply(window MaintenanceWindow) (*MaintenanceReport, error) {
+	if window.Duration <= 0 {
+		return nil, errors.New("maintenance duration must be positive")
+	}
+
+	results, err := p.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	req := window.Request()
+	verify := !window.Start.After(time.Now())
+	for i := range results {
+		result := &results[i]
+		identifier := strconv.Itoa(result.DeviceID)
+
+		if _, err := p.client.Device.SetDeviceMaintenance(identifier, req); err != nil {
+			result.Err = fmt.Errorf("failed to set maintenance: %w", err)
+			continue
+		}
+		result.Applied = true
+		if !verify {
+			continue
+		}
+
+		status, err := p.client.Device.GetDeviceMaintenance(identifier)
+		switch {
+		case err != nil:
+			result.Err = fmt.Errorf("failed to verify maintenance: %w", err)
+		case !bool(status.IsUnderMaintenance):
+			result.Err = ErrMaintenanceNotActive
+		default:
+			result.Verified = true
+		}
+	}
+	return &MaintenanceReport{Window: window, Results: results}, nil
+}
@@ -0,0 +1,134 @@
+package librenms_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+// maintenanceServer is a fake LibreNMS serving the endpoints used by the MaintenancePlanner.
+type maintenanceServer struct {
+	mu       sync.Mutex
+	requests map[string]types.DeviceMaintenanceRequest
+	// inactive lists device IDs that don't report maintenance after being scheduled.
+	inactive map[string]bool
+}
+
+func (s *maintenanceServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v0/devices":
+		_, _ = io.WriteString(w, `{"status":"ok","devices":[
+			{"device_id":1,"hostname":"core1","location":"DC1","location_id":10},
+			{"device_id":2,"hostname":"core2","location":"DC1","location_id":10},
+			{"device_id":3,"hostname":"edge1","location":"DC2","location_id":20},
+			{"device_id":4,"hostname":"lab1","location":"Lab","location_id":30}
+		]}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v0/devicegroups/core":
+		_, _ = io.WriteString(w, `{"status":"ok","devices":[{"device_id":1},{"device_id":2}]}`)
+	case r.Method == http.MethodPost && len(r.URL.Path) > len("/api/v0/devices/"):
+		id := r.URL.Path[len("/api/v0/devices/") : len(r.URL.Path)-len("/maintenance")]
+		if id == "4" {
+			http.Error(w, `{"status":"error","message":"Device not found"}`, http.StatusNotFound)
+			return
+		}
+		var req types.DeviceMaintenanceRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.mu.Lock()
+		s.requests[id] = req
+		s.mu.Unlock()
+		_, _ = io.WriteString(w, `{"status":"ok","message":"Device has been put into maintenance"}`)
+	case r.Method == http.MethodGet:
+		id := r.URL.Path[len("/api/v0/devices/") : len(r.URL.Path)-len("/maintenance")]
+		s.mu.Lock()
+		_, scheduled := s.requests[id]
+		active := scheduled && !s.inactive[id]
+		s.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "ok", "is_under_maintenance": active})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newMaintenanceClient(t *testing.T, inactive ...string) (*librenms.Client, *maintenanceServer) {
+	fake := &maintenanceServer{requests: make(map[string]types.DeviceMaintenanceRequest), inactive: make(map[string]bool)}
+	for _, id := range inactive {
+		fake.inactive[id] = true
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := librenms.New(server.URL+"/", "test-token")
+	require.NoError(t, err, "Failed to create client")
+	return client, fake
+}
+
+func TestMaintenancePlanner_Resolve(t *testing.T) {
+	r := require.New(t)
+
+	client, _ := newMaintenanceClient(t)
+	results, err := librenms.NewMaintenancePlanner(client).
+		AddGroups("core").
+		AddLocations("dc1", "20").
+		AddHostnames("lab1").
+		Resolve()
+	r.NoError(err, "Resolve returned an error")
+	r.Len(results, 4, "Devices should be deduplicated")
+
+	r.Equal("core1", results[0].Hostname, "Unexpected first device")
+	r.Equal([]string{"group:core", "location:dc1"}, results[0].Sources, "Expected all sources of the device")
+	r.Equal([]string{"location:20"}, results[2].Sources, "Locations should match by ID")
+
+	_, err = librenms.NewMaintenancePlanner(client).AddHostnames("missing").Resolve()
+	r.ErrorContains(err, `device "missing" not found`, "Expected error for unknown hostnames")
+}
+
+func TestMaintenancePlanner_Apply(t *testing.T) {
+	r := require.New(t)
+
+	client, fake := newMaintenanceClient(t, "2")
+	report, err := librenms.NewMaintenancePlanner(client).
+		AddGroups("core").
+		AddHostnames("lab1").
+		Apply(librenms.MaintenanceWindow{Title: "Upgrade", Notes: "IOS-XE 17.9", Duration: 90 * time.Minute})
+	r.NoError(err, "Apply returned an error")
+	r.Len(report.Results, 3, "Expected a result per device")
+
+	r.True(report.Results[0].Applied, "Maintenance should be applied")
+	r.True(report.Results[0].Verified, "Maintenance should be verified")
+	r.True(report.Results[1].Applied, "Maintenance should be applied")
+	r.ErrorIs(report.Results[1].Err, librenms.ErrMaintenanceNotActive, "Expected unverified maintenance")
+	r.False(report.Results[2].Applied, "Maintenance should fail for lab1")
+	r.ErrorContains(report.Results[2].Err, "Device not found", "Expected API error")
+	r.Len(report.Failed(), 2, "Expected 2 failed devices")
+
+	r.Equal(types.DeviceMaintenanceRequest{Title: "Upgrade", Notes: "IOS-XE 17.9", Duration: "1:30"}, fake.requests["1"],
+		"Unexpected maintenance request")
+}
+
+func TestMaintenancePlanner_ApplyScheduled(t *testing.T) {
+	r := require.New(t)
+
+	client, fake := newMaintenanceClient(t, "1")
+	start := time.Date(2999, 1, 2, 3, 4, 55, 0, time.UTC)
+	report, err := librenms.NewMaintenancePlanner(client).
+		AddHostnames("core1").
+		Apply(librenms.MaintenanceWindow{Title: "Later", Start: start, Duration: 25*time.Hour + 30*time.Second})
+	r.NoError(err, "Apply returned an error")
+	r.Empty(report.Failed(), "Future windows should not be verified")
+	r.False(report.Results[0].Verified, "Future windows should not be verified")
+
+	r.Equal("2999-01-02 03:04:00", fake.requests["1"].Start, "Unexpected start")
+	r.Equal("25:01", fake.requests["1"].Duration, "Duration should be rounded up to minutes")
+
+	_, err = librenms.NewMaintenancePlanner(client).Apply(librenms.MaintenanceWindow{})
+	r.ErrorContains(err, "duration must be positive", "Expected error without duration")
+}
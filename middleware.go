@@ -0,0 +1,209 @@
+package librenms
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type (
+	// Doer sends an HTTP request and returns its response. *http.Client implements it.
+	Doer interface {
+		Do(req *http.Request) (*http.Response, error)
+	}
+
+	// DoerFunc is a function implementing Doer.
+	DoerFunc func(req *http.Request) (*http.Response, error)
+
+	// Middleware wraps the Doer sending the requests of the client, e.g. to add tracing,
+	// metrics, request signing or caching. A middleware may modify the request, retry it or
+	// answer it without calling next.
+	Middleware func(next Doer) Doer
+
+	// RequestInfo describes the API operation a request was created for. Middleware read it
+	// with RequestInfoFromContext.
+	RequestInfo struct {
+		// Operation is the API method, e.g. "Device.List" or "Port.GetPortInfo".
+		Operation string
+		// Route is the endpoint template relative to /api/v0/, e.g. "devices/:identifier/maintenance".
+		Route string
+		// Attempt is the 1-based attempt number, incremented by RetryMiddleware.
+		Attempt int
+	}
+
+	// TokenSource returns the API token to send with a request.
+	TokenSource func(ctx context.Context) (string, error)
+
+	// operation identifies an API method, see operations.go.
+	operation struct {
+		name  string
+		route string
+	}
+
+	requestInfoKey struct{}
+)
+
+// Do implements Doer.
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware adds middleware around the requests sent by the client. Middleware run in
+// the order they are given, across all WithMiddleware options: the first one sees the
+// request first and the response last. They run between the request creation and the HTTP
+// client, so API errors are not yet checked and the transport set by WithHTTPClient,
+// WithRecorder or WithReplayer is called last.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// RequestInfoFromContext returns the RequestInfo of a request created by the client.
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// withRequestInfo returns a copy of ctx carrying info.
+func withRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// chain wraps doer with the middleware, the first middleware being the outermost.
+func chain(doer Doer, middleware []Middleware) Doer {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		doer = middleware[i](doer)
+	}
+	return doer
+}
+
+// HeaderMiddleware sets the given headers on every request, replacing existing values.
+func HeaderMiddleware(headers http.Header) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			for key, values := range headers {
+				req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// TokenMiddleware replaces the API token of every request with the one returned by source,
+// e.g. to rotate tokens without recreating the client.
+func TokenMiddleware(source TokenSource) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			token, err := source(req.Context())
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set(authHeader, token)
+			return next.Do(req)
+		})
+	}
+}
+
+// LoggingMiddleware logs every request with its operation, status and duration at info
+// level, or at error level when it fails.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			info, _ := RequestInfoFromContext(req.Context())
+			start := time.Now()
+			resp, err := next.Do(req)
+
+			attrs := []slog.Attr{
+				slog.String("operation", info.Operation),
+				slog.String("route", info.Route),
+				slog.String("method", req.Method),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				logger.LogAttrs(req.Context(), slog.LevelError, "librenms request failed", append(attrs, slog.Any("error", err))...)
+				return nil, err
+			}
+			logger.LogAttrs(req.Context(), slog.LevelInfo, "librenms request", append(attrs, slog.Int("status", resp.StatusCode))...)
+			return resp, nil
+		})
+	}
+}
+
+// RetryMiddleware retries idempotent requests (GET, HEAD, PUT and DELETE) up to maxRetries
+// times when they fail with a transport error or a 429, 502, 503 or 504 status. The wait
+// between attempts starts at backoff and doubles after each attempt. Retries stop when the
+// request context is done.
+func RetryMiddleware(maxRetries int, backoff time.Duration) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if !isIdempotent(req.Method) {
+				return next.Do(req)
+			}
+			ctx := req.Context()
+			info, _ := RequestInfoFromContext(ctx)
+			if info.Attempt < 1 {
+				info.Attempt = 1
+			}
+			wait := backoff
+			for retry := 0; ; retry++ {
+				attempt := req.WithContext(withRequestInfo(ctx, info))
+				if retry > 0 && req.Body != nil && req.Body != http.NoBody {
+					if req.GetBody == nil {
+						return nil, errors.New("request body can't be replayed for a retry")
+					}
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					attempt.Body = body
+				}
+
+				resp, err := next.Do(attempt)
+				if retry >= maxRetries || !shouldRetry(resp, err) {
+					return resp, err
+				}
+				if resp != nil {
+					_, _ = io.Copy(io.Discard, resp.Body)
+					closeBody(resp.Body)
+				}
+
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, ctx.Err()
+				case <-timer.C:
+				}
+				wait *= 2
+				info.Attempt++
+			}
+		})
+	}
+}
+
+// isIdempotent reports whether a request with the given method can be safely retried.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry reports whether a response or error is worth retrying.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
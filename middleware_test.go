@@ -0,0 +1,204 @@
+package librenms_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+// recordMiddleware appends name and the request info of every request to calls.
+func recordMiddleware(name string, calls *[]string, infos *[]librenms.RequestInfo) librenms.Middleware {
+	return func(next librenms.Doer) librenms.Doer {
+		return librenms.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			*calls = append(*calls, name+" before")
+			if infos != nil {
+				info, ok := librenms.RequestInfoFromContext(req.Context())
+				if ok {
+					*infos = append(*infos, info)
+				}
+			}
+			resp, err := next.Do(req)
+			*calls = append(*calls, name+" after")
+			return resp, err
+		})
+	}
+}
+
+func TestMiddleware_OrderAndRequestInfo(t *testing.T) {
+	r := require.New(t)
+
+	var calls []string
+	var infos []librenms.RequestInfo
+	client, err := librenms.New(testServer.URL+"/", "test-token",
+		librenms.WithMiddleware(recordMiddleware("first", &calls, &infos), recordMiddleware("second", &calls, nil)),
+		librenms.WithMiddleware(recordMiddleware("third", &calls, nil)))
+	r.NoError(err, "Failed to create client")
+
+	_, err = client.System.Get()
+	r.NoError(err, "System.Get returned an error")
+	r.Equal([]string{"first before", "second before", "third before", "third after", "second after", "first after"}, calls, "Unexpected middleware order")
+
+	_, err = client.Device.Get("1.1.1.1")
+	r.NoError(err, "Device.Get returned an error")
+	r.Equal([]librenms.RequestInfo{
+		{Operation: "System.Get", Route: "system", Attempt: 1},
+		{Operation: "Device.Get", Route: "devices/:identifier", Attempt: 1},
+	}, infos, "Unexpected request info")
+
+	_, ok := librenms.RequestInfoFromContext(context.Background())
+	r.False(ok, "Expected no request info in a plain context")
+}
+
+func TestMiddleware_ShortCircuit(t *testing.T) {
+	r := require.New(t)
+
+	cached := librenms.Middleware(func(librenms.Doer) librenms.Doer {
+		return librenms.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewReader(loadMockResponse("get_system_200.json"))),
+				Request:    req,
+			}, nil
+		})
+	})
+	client, err := librenms.New("http://librenms.invalid/", "test-token", librenms.WithMiddleware(cached))
+	r.NoError(err, "Failed to create client")
+
+	resp, err := client.System.Get()
+	r.NoError(err, "Expected the response of the middleware")
+	r.Equal("23.11.0", string(resp.System[0].LocalVer), "Unexpected system version")
+
+	failing := librenms.Middleware(func(librenms.Doer) librenms.Doer {
+		return librenms.DoerFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("blocked")
+		})
+	})
+	client, err = librenms.New("http://librenms.invalid/", "test-token", librenms.WithMiddleware(failing))
+	r.NoError(err, "Failed to create client")
+	_, err = client.System.Get()
+	r.ErrorContains(err, "blocked", "Expected the middleware error")
+}
+
+func TestMiddleware_HeadersAndToken(t *testing.T) {
+	r := require.New(t)
+
+	var mu sync.Mutex
+	var tokens, signatures []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		tokens = append(tokens, req.Header.Get("X-Auth-Token"))
+		signatures = append(signatures, req.Header.Get("X-Signature"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write(loadMockResponse("get_system_200.json"))
+		handleWriteErr(err, w)
+	}))
+	t.Cleanup(server.Close)
+
+	rotation := 0
+	source := func(context.Context) (string, error) {
+		rotation++
+		if rotation > 2 {
+			return "", errors.New("token expired")
+		}
+		return "rotated-" + string(rune('0'+rotation)), nil
+	}
+	client, err := librenms.New(server.URL+"/", "static-token",
+		librenms.WithMiddleware(
+			librenms.HeaderMiddleware(http.Header{"x-signature": []string{"signed"}}),
+			librenms.TokenMiddleware(source)))
+	r.NoError(err, "Failed to create client")
+
+	for i := 0; i < 2; i++ {
+		_, err = client.System.Get()
+		r.NoError(err, "System.Get returned an error")
+	}
+	r.Equal([]string{"rotated-1", "rotated-2"}, tokens, "Expected the rotated tokens")
+	r.Equal([]string{"signed", "signed"}, signatures, "Expected the static header")
+
+	_, err = client.System.Get()
+	r.ErrorContains(err, "token expired", "Expected the token source error")
+	r.Len(tokens, 2, "Expected no request without a token")
+}
+
+func TestMiddleware_Retry(t *testing.T) {
+	r := require.New(t)
+
+	var mu sync.Mutex
+	var requests []string
+	failures := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		mu.Lock()
+		requests = append(requests, req.Method+" "+string(bytes.TrimSpace(body)))
+		fail := failures > 0
+		failures--
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":"error","message":"busy"}`))
+			return
+		}
+		_, err := w.Write([]byte(`{"status":"ok","message":"done"}`))
+		handleWriteErr(err, w)
+	}))
+	t.Cleanup(server.Close)
+
+	var attempts []int
+	attemptRecorder := librenms.Middleware(func(next librenms.Doer) librenms.Doer {
+		return librenms.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			info, _ := librenms.RequestInfoFromContext(req.Context())
+			attempts = append(attempts, info.Attempt)
+			return next.Do(req)
+		})
+	})
+	client, err := librenms.New(server.URL+"/", "test-token",
+		librenms.WithMiddleware(librenms.RetryMiddleware(3, time.Millisecond), attemptRecorder))
+	r.NoError(err, "Failed to create client")
+
+	_, err = client.AlertRule.Update(&types.AlertRuleUpdateRequest{AlertRuleCreateRequest: types.AlertRuleCreateRequest{Name: "rule"}, ID: 5})
+	r.NoError(err, "Expected the PUT request to succeed after retries")
+	r.Equal([]int{1, 2, 3}, attempts, "Unexpected attempts")
+	r.Len(requests, 3, "Expected three requests")
+	r.Equal(requests[0], requests[2], "Expected the body to be resent")
+
+	failures, requests, attempts = 5, nil, nil
+	_, err = client.System.Get()
+	var apiErr *librenms.ErrorResponse
+	r.ErrorAs(err, &apiErr, "Expected the last error once retries are exhausted")
+	r.Equal(http.StatusServiceUnavailable, apiErr.Response.StatusCode, "Unexpected status code")
+	r.Equal([]int{1, 2, 3, 4}, attempts, "Expected the request and three retries")
+
+	failures, requests, attempts = 1, nil, nil
+	_, err = client.Device.Create(&types.DeviceCreateRequest{Hostname: "host", OS: "linux"})
+	r.Error(err, "Expected POST requests not to be retried")
+	r.Len(requests, 1, "Expected a single POST request")
+}
+
+func TestMiddleware_Logging(t *testing.T) {
+	r := require.New(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	client, err := librenms.New(testServer.URL+"/", "test-token", librenms.WithMiddleware(librenms.LoggingMiddleware(logger)))
+	r.NoError(err, "Failed to create client")
+
+	_, err = client.System.Get()
+	r.NoError(err, "System.Get returned an error")
+	r.Contains(buf.String(), "operation=System.Get", "Expected the operation to be logged")
+	r.Contains(buf.String(), "route=system", "Expected the route to be logged")
+	r.Contains(buf.String(), "status=200", "Expected the status to be logged")
+}
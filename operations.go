@@ -0,0 +1,121 @@
+package librenms
+
+// Operations of the API methods, passed to newRequest and exposed to middleware through
+// RequestInfo.
+var (
+	opAlertAck         = operation{name: "Alert.Ack", route: "alerts/:alertID"}
+	opAlertGet         = operation{name: "Alert.Get", route: "alerts/:alertID"}
+	opAlertList        = operation{name: "Alert.List", route: "alerts"}
+	opAlertUnmuteAlert = operation{name: "Alert.UnmuteAlert", route: "alerts/unmute/:alertID"}
+
+	opAlertRuleCreate = operation{name: "AlertRule.Create", route: "rules"}
+	opAlertRuleDelete = operation{name: "AlertRule.Delete", route: "rules/:id"}
+	opAlertRuleGet    = operation{name: "AlertRule.Get", route: "rules/:id"}
+	opAlertRuleList   = operation{name: "AlertRule.List", route: "rules"}
+	opAlertRuleUpdate = operation{name: "AlertRule.Update", route: "rules"}
+
+	opDeviceCreate                = operation{name: "Device.Create", route: "devices"}
+	opDeviceDelete                = operation{name: "Device.Delete", route: "devices/:identifier"}
+	opDeviceGet                   = operation{name: "Device.Get", route: "devices/:identifier"}
+	opDeviceGetWithFields         = operation{name: "Device.GetWithFields", route: "devices/:identifier"}
+	opDeviceList                  = operation{name: "Device.List", route: "devices"}
+	opDeviceListWithFields        = operation{name: "Device.ListWithFields", route: "devices"}
+	opDeviceUpdate                = operation{name: "Device.Update", route: "devices/:identifier"}
+	opDeviceDiscover              = operation{name: "Device.Discover", route: "devices/:identifier/discover"}
+	opDeviceGetAvailability       = operation{name: "Device.GetAvailability", route: "devices/:identifier/availability"}
+	opDeviceGetOutages            = operation{name: "Device.GetOutages", route: "devices/:identifier/outages"}
+	opDeviceGetGraphs             = operation{name: "Device.GetGraphs", route: "devices/:identifier/graphs"}
+	opDeviceGetHealthGraphs       = operation{name: "Device.GetHealthGraphs", route: "devices/:identifier/health"}
+	opDeviceGetWirelessGraphs     = operation{name: "Device.GetWirelessGraphs", route: "devices/:identifier/wireless"}
+	opDeviceGetPorts              = operation{name: "Device.GetPorts", route: "devices/:identifier/ports"}
+	opDeviceGetDeviceFDB          = operation{name: "Device.GetDeviceFDB", route: "devices/:identifier/fdb"}
+	opDeviceGetDeviceNAC          = operation{name: "Device.GetDeviceNAC", route: "devices/:identifier/nac"}
+	opDeviceGetDeviceIPAddresses  = operation{name: "Device.GetDeviceIPAddresses", route: "devices/:identifier/ip"}
+	opDeviceGetPortStack          = operation{name: "Device.GetPortStack", route: "devices/:identifier/port_stack"}
+	opDeviceGetDeviceTransceivers = operation{name: "Device.GetDeviceTransceivers", route: "devices/:identifier/transceivers"}
+	opDeviceGetComponents         = operation{name: "Device.GetComponents", route: "devices/:identifier/components"}
+	opDeviceAddComponent          = operation{name: "Device.AddComponent", route: "devices/:identifier/components/:componentType"}
+	opDeviceEditComponents        = operation{name: "Device.EditComponents", route: "devices/:identifier/components"}
+	opDeviceDeleteComponent       = operation{name: "Device.DeleteComponent", route: "devices/:identifier/components/:componentID"}
+	opDeviceGetPortStats          = operation{name: "Device.GetPortStats", route: "devices/:identifier/ports/:ifName"}
+	opDeviceGetDeviceMaintenance  = operation{name: "Device.GetDeviceMaintenance", route: "devices/:identifier/maintenance"}
+	opDeviceSetDeviceMaintenance  = operation{name: "Device.SetDeviceMaintenance", route: "devices/:identifier/maintenance"}
+	opDeviceRenameDevice          = operation{name: "Device.RenameDevice", route: "devices/:identifier/rename/:newHostname"}
+	opDeviceGetDeviceGroups       = operation{name: "Device.GetDeviceGroups", route: "devices/:identifier/groups"}
+	opDeviceUpdateDevicePortNotes = operation{name: "Device.UpdateDevicePortNotes", route: "devices/:identifier/port/:portID"}
+
+	opDeviceGroupCreate         = operation{name: "DeviceGroup.Create", route: "devicegroups"}
+	opDeviceGroupDelete         = operation{name: "DeviceGroup.Delete", route: "devicegroups/:identifier"}
+	opDeviceGroupGet            = operation{name: "DeviceGroup.Get", route: "devicegroups"}
+	opDeviceGroupList           = operation{name: "DeviceGroup.List", route: "devicegroups"}
+	opDeviceGroupGetMembers     = operation{name: "DeviceGroup.GetMembers", route: "devicegroups/:identifier"}
+	opDeviceGroupSetMaintenance = operation{name: "DeviceGroup.SetMaintenance", route: "devicegroups/:identifier/maintenance"}
+	opDeviceGroupUpdate         = operation{name: "DeviceGroup.Update", route: "devicegroups/:identifier"}
+
+	opInventoryGetInventory          = operation{name: "Inventory.GetInventory", route: "inventory/:hostname"}
+	opInventoryGetInventoryForDevice = operation{name: "Inventory.GetInventoryForDevice", route: "inventory/:hostname/all"}
+
+	opLocationCreate = operation{name: "Location.Create", route: "locations"}
+	opLocationDelete = operation{name: "Location.Delete", route: "locations/:locationID"}
+	opLocationGet    = operation{name: "Location.Get", route: "location/:locationID"}
+	opLocationList   = operation{name: "Location.List", route: "resources/locations"}
+	opLocationUpdate = operation{name: "Location.Update", route: "locations/:locationID"}
+
+	opLogsSyslogsink    = operation{name: "Logs.Syslogsink", route: "logs/syslogsink"}
+	opLogsListEventLogs = operation{name: "Logs.ListEventLogs", route: "logs/eventlog/:identifier"}
+	opLogsListSysLogs   = operation{name: "Logs.ListSysLogs", route: "logs/syslog/:identifier"}
+	opLogsListAlertLogs = operation{name: "Logs.ListAlertLogs", route: "logs/alertlog/:identifier"}
+	opLogsListAuthLogs  = operation{name: "Logs.ListAuthLogs", route: "logs/authlog/:identifier"}
+
+	opPortGetAllPorts           = operation{name: "Port.GetAllPorts", route: "ports"}
+	opPortGetAllPortsWithFields = operation{name: "Port.GetAllPortsWithFields", route: "ports"}
+	opPortStreamAllPorts        = operation{name: "Port.StreamAllPorts", route: "ports"}
+	opPortSearchPorts           = operation{name: "Port.SearchPorts", route: "ports/search/:search"}
+	opPortSearchPortsInField    = operation{name: "Port.SearchPortsInField", route: "ports/search/:field/:search"}
+	opPortGetPortsWithMAC       = operation{name: "Port.GetPortsWithMAC", route: "ports/mac/:mac"}
+	opPortGetPortInfo           = operation{name: "Port.GetPortInfo", route: "ports/:portID"}
+	opPortGetPortIPInfo         = operation{name: "Port.GetPortIPInfo", route: "ports/:portID/ip"}
+	opPortGetPortTransceiver    = operation{name: "Port.GetPortTransceiver", route: "ports/:portID/transceiver"}
+	opPortGetPortDescription    = operation{name: "Port.GetPortDescription", route: "ports/:portID/description"}
+	opPortUpdatePortDescription = operation{name: "Port.UpdatePortDescription", route: "ports/:portID/description"}
+
+	opRoutingListBGP               = operation{name: "Routing.ListBGP", route: "bgp"}
+	opRoutingGetBGP                = operation{name: "Routing.GetBGP", route: "bgp/:id"}
+	opRoutingUpdateBGPDescription  = operation{name: "Routing.UpdateBGPDescription", route: "bgp/:id"}
+	opRoutingListBGPCounters       = operation{name: "Routing.ListBGPCounters", route: "routing/bgp/cbgp"}
+	opRoutingListIPAddresses       = operation{name: "Routing.ListIPAddresses", route: "resources/ip/addresses/:addressFamily"}
+	opRoutingStreamIPAddresses     = operation{name: "Routing.StreamIPAddresses", route: "resources/ip/addresses/:addressFamily"}
+	opRoutingListARP               = operation{name: "Routing.ListARP", route: "resources/ip/arp/:query"}
+	opRoutingGetNetworkIPAddresses = operation{name: "Routing.GetNetworkIPAddresses", route: "resources/ip/networks/:networkID/ip"}
+	opRoutingListIPNetworks        = operation{name: "Routing.ListIPNetworks", route: "resources/ip/networks/:addressFamily"}
+	opRoutingListIPSec             = operation{name: "Routing.ListIPSec", route: "routing/ipsec/data/:hostname"}
+	opRoutingListOSPF              = operation{name: "Routing.ListOSPF", route: "ospf"}
+	opRoutingListOSPFPorts         = operation{name: "Routing.ListOSPFPorts", route: "ospf_ports"}
+	opRoutingListOSPFv3            = operation{name: "Routing.ListOSPFv3", route: "ospfv3"}
+	opRoutingListOSPFv3Ports       = operation{name: "Routing.ListOSPFv3Ports", route: "ospfv3_ports"}
+	opRoutingListVRF               = operation{name: "Routing.ListVRF", route: "routing/vrf"}
+	opRoutingGetVRF                = operation{name: "Routing.GetVRF", route: "routing/vrf/:id"}
+	opRoutingListMPLSServices      = operation{name: "Routing.ListMPLSServices", route: "routing/mpls/services"}
+	opRoutingListMPLSSAPs          = operation{name: "Routing.ListMPLSSAPs", route: "routing/mpls/saps"}
+
+	opServiceCreate     = operation{name: "Service.Create", route: "services/:deviceIdentifier"}
+	opServiceDelete     = operation{name: "Service.Delete", route: "services/:serviceID"}
+	opServiceGet        = operation{name: "Service.Get", route: "services"}
+	opServiceList       = operation{name: "Service.List", route: "services"}
+	opServiceGetForHost = operation{name: "Service.GetForHost", route: "services/:deviceIdentifier"}
+	opServiceUpdate     = operation{name: "Service.Update", route: "services/:serviceID"}
+
+	opSwitchingGetAllVLANs           = operation{name: "Switching.GetAllVLANs", route: "resources/vlans"}
+	opSwitchingStreamAllVLANs        = operation{name: "Switching.StreamAllVLANs", route: "resources/vlans"}
+	opSwitchingGetDeviceVLANs        = operation{name: "Switching.GetDeviceVLANs", route: "devices/:hostname/vlans"}
+	opSwitchingGetAllLinks           = operation{name: "Switching.GetAllLinks", route: "resources/links"}
+	opSwitchingGetAllLinksWithFields = operation{name: "Switching.GetAllLinksWithFields", route: "resources/links"}
+	opSwitchingStreamAllLinks        = operation{name: "Switching.StreamAllLinks", route: "resources/links"}
+	opSwitchingGetDeviceLinks        = operation{name: "Switching.GetDeviceLinks", route: "devices/:hostname/links"}
+	opSwitchingGetLink               = operation{name: "Switching.GetLink", route: "resources/links/:linkID"}
+	opSwitchingGetPortFDB            = operation{name: "Switching.GetPortFDB", route: "resources/fdb/:mac"}
+	opSwitchingGetPortFDBDetail      = operation{name: "Switching.GetPortFDBDetail", route: "resources/fdb/:mac/detail"}
+	opSwitchingGetPortNAC            = operation{name: "Switching.GetPortNAC", route: "resources/nac/:mac"}
+
+	opSystemGet = operation{name: "System.Get", route: "system"}
+)
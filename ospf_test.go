@@ -0,0 +1,85 @@
+package librenms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOSPFNeighborState(t *testing.T) {
+	r := require.New(t)
+
+	for s, want := range map[string]types.OSPFNeighborState{
+		"full":          types.OSPFStateFull,
+		"FULL/DR":       types.OSPFStateFull,
+		"twoWay":        types.OSPFStateTwoWay,
+		"2-Way/DROTHER": types.OSPFStateTwoWay,
+		"exchangeStart": types.OSPFStateExchangeStart,
+		"EXSTART":       types.OSPFStateExchangeStart,
+		"established":   types.OSPFStateUnknown,
+		"down":          types.OSPFStateDown,
+		"5":             types.OSPFStateExchangeStart,
+	} {
+		state, err := types.ParseOSPFNeighborState(s)
+		if want == types.OSPFStateUnknown {
+			r.Error(err, "Expected an error for %q", s)
+			continue
+		}
+		r.NoError(err, "ParseOSPFNeighborState returned an error for %q", s)
+		r.Equal(want, state, "Unexpected state for %q", s)
+	}
+	_, err := types.ParseOSPFNeighborState("9")
+	r.Error(err, "Expected an error for values out of the MIB")
+
+	r.Equal("exchangeStart", types.OSPFStateExchangeStart.String(), "Expected the MIB name")
+	r.True(types.OSPFStateTwoWay.Established(), "Expected two-way to be established")
+	r.False(types.OSPFStateLoading.Established(), "Expected loading not to be established")
+
+	var neighbor types.OSPFNeighbor
+	r.NoError(json.Unmarshal([]byte(`{"ospfNbrState":"full"}`), &neighbor), "Failed to decode the neighbour")
+	r.Equal(types.OSPFStateFull, neighbor.OSPFNbrState, "Expected the state name to decode")
+	r.NoError(json.Unmarshal([]byte(`{"ospfNbrState":8}`), &neighbor), "Failed to decode the neighbour")
+	r.Equal(types.OSPFStateFull, neighbor.OSPFNbrState, "Expected the state value to decode")
+	r.NoError(json.Unmarshal([]byte(`{"ospfNbrState":"graceful"}`), &neighbor), "Failed to decode the neighbour")
+	r.Equal(types.OSPFStateUnknown, neighbor.OSPFNbrState, "Expected unknown states not to fail")
+
+	data, err := json.Marshal(types.OSPFNeighbor{OSPFNbrState: types.OSPFStateTwoWay})
+	r.NoError(err, "Failed to encode the neighbour")
+	r.JSONEq(`{"ospfNbrState":"twoWay"}`, string(data), "Expected the state name")
+}
+
+func TestParseOSPFAreaID(t *testing.T) {
+	r := require.New(t)
+
+	for s, want := range map[string]types.OSPFAreaID{
+		"0.0.0.0":    types.OSPFBackbone,
+		"0":          types.OSPFBackbone,
+		"0.0.0.10":   10,
+		"10":         10,
+		"1.2.3.4":    0x01020304,
+		"4294967295": 0xffffffff,
+	} {
+		area, err := types.ParseOSPFAreaID(s)
+		r.NoError(err, "ParseOSPFAreaID returned an error for %q", s)
+		r.Equal(want, area, "Unexpected area for %q", s)
+	}
+	for _, s := range []string{"", "0.0.0", "0.0.0.256", "4294967296", "backbone"} {
+		_, err := types.ParseOSPFAreaID(s)
+		r.Error(err, "Expected an error for %q", s)
+	}
+	r.Equal("0.0.1.0", types.OSPFAreaID(256).String(), "Expected dotted decimal")
+
+	var port types.OSPFPort
+	r.NoError(json.Unmarshal([]byte(`{"ospfIfAreaId":"0.0.0.10"}`), &port), "Failed to decode the port")
+	r.Equal(types.OSPFAreaID(10), port.OSPFIfAreaID, "Expected the dotted area to decode")
+	var v3 types.OSPFv3Port
+	r.NoError(json.Unmarshal([]byte(`{"ospfv3IfAreaId":10}`), &v3), "Failed to decode the OSPFv3 port")
+	r.Equal(types.OSPFAreaID(10), v3.OSPFv3IfAreaID, "Expected the integer area to decode")
+	r.Error(json.Unmarshal([]byte(`{"ospfIfAreaId":"area 10"}`), &port), "Expected an error for an invalid area")
+
+	data, err := json.Marshal(types.OSPFv3Port{OSPFv3IfAreaID: 10})
+	r.NoError(err, "Failed to encode the OSPFv3 port")
+	r.JSONEq(`{"ospfv3IfAreaId":"0.0.0.10"}`, string(data), "Expected dotted decimal")
+}
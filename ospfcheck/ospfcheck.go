@@ -0,0 +1,497 @@
+// Package ospfcheck checks the consistency of the OSPF adjacencies known to LibreNMS, for
+// instance in the CI of network changes.
+//
+// Every neighbour of a device is cross-referenced with the device at the other end, which
+// must list the first one as a neighbour in turn. OSPFv2 neighbours are resolved by their
+// address, the address of an OSPF interface of the other device. OSPFv3 neighbours only have
+// link-local addresses and are resolved by router ID, from the OSPFv2 adjacencies, the
+// device IPs and Options.RouterIDs. Both interfaces of an adjacency must be in the same area
+// and use the same hello and dead intervals.
+package ospfcheck
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// portColumns are the port columns used to name the interfaces.
+var portColumns = types.MustColumns[types.Port]("ifName").String()
+
+// Version is an OSPF version.
+type Version string
+
+// OSPF versions.
+const (
+	OSPFv2 Version = "ospfv2"
+	OSPFv3 Version = "ospfv3"
+)
+
+// IssueKind is a kind of inconsistency.
+type IssueKind string
+
+// Kinds of issues.
+const (
+	// IssueOneSided is a neighbour whose device doesn't list the first device as a
+	// neighbour.
+	IssueOneSided IssueKind = "one-sided"
+	// IssueAreaMismatch is an adjacency whose interfaces are in different areas.
+	IssueAreaMismatch IssueKind = "area-mismatch"
+	// IssueTimerMismatch is an adjacency whose interfaces use different hello or dead
+	// intervals.
+	IssueTimerMismatch IssueKind = "timer-mismatch"
+	// IssueNoNeighbors is an interface with OSPF enabled but no neighbours. Passive
+	// interfaces are reported as well, since LibreNMS doesn't tell them apart.
+	IssueNoNeighbors IssueKind = "no-neighbors"
+)
+
+type (
+	// Options configures Check.
+	Options struct {
+		// SkipV3 skips OSPFv3, for installations that don't run it.
+		SkipV3 bool
+		// RouterIDs maps router IDs to the hostnames of their devices, for routers whose
+		// ID isn't their device IP nor found through OSPFv2.
+		RouterIDs map[string]string
+	}
+
+	// Input is the data checked by Analyze.
+	Input struct {
+		Devices []types.Device
+		// Ports name the interfaces. Only the IDs and ifName are used.
+		Ports        []types.Port
+		Neighbors    []types.OSPFNeighbor
+		Interfaces   []types.OSPFPort
+		NeighborsV3  []types.OSPFv3Neighbor
+		InterfacesV3 []types.OSPFv3Port
+		// RouterIDs maps router IDs to hostnames, see Options.RouterIDs.
+		RouterIDs map[string]string
+	}
+
+	// Report is the result of a check.
+	Report struct {
+		// Adjacencies are the neighbours listed by both devices.
+		Adjacencies []Adjacency `json:"adjacencies"`
+		Issues      []Issue     `json:"issues"`
+		// External are the neighbours that aren't LibreNMS devices, whose other end can't
+		// be checked.
+		External []Neighbor `json:"external"`
+	}
+
+	// Adjacency is a pair of routers listing each other as neighbours.
+	Adjacency struct {
+		Version  Version `json:"version"`
+		Hostname string  `json:"hostname"`
+		Port     string  `json:"port,omitempty"`
+		// State is the state of the remote router as seen by Hostname.
+		State          types.OSPFNeighborState `json:"state"`
+		RemoteHostname string                  `json:"remote_hostname"`
+		RemotePort     string                  `json:"remote_port,omitempty"`
+		// RemoteState is the state of Hostname as seen by the remote router.
+		RemoteState types.OSPFNeighborState `json:"remote_state"`
+		// Area is the area of the interface of Hostname, when known.
+		Area *types.OSPFAreaID `json:"area,omitempty"`
+	}
+
+	// Neighbor is a neighbour of a device.
+	Neighbor struct {
+		Version  Version                 `json:"version"`
+		Hostname string                  `json:"hostname"`
+		Port     string                  `json:"port,omitempty"`
+		RouterID string                  `json:"router_id"`
+		Address  string                  `json:"address,omitempty"`
+		State    types.OSPFNeighborState `json:"state"`
+	}
+
+	// Issue is an inconsistency found by a check.
+	Issue struct {
+		Kind     IssueKind `json:"kind"`
+		Version  Version   `json:"version"`
+		Hostname string    `json:"hostname"`
+		Port     string    `json:"port,omitempty"`
+		// Neighbor is the router ID of the neighbour, empty for IssueNoNeighbors.
+		Neighbor       string `json:"neighbor,omitempty"`
+		RemoteHostname string `json:"remote_hostname,omitempty"`
+		RemotePort     string `json:"remote_port,omitempty"`
+		Message        string `json:"message"`
+	}
+)
+
+type (
+	// neighbor is a neighbour of either version.
+	neighbor struct {
+		deviceID int
+		portID   int
+		routerID string
+		address  string
+		state    types.OSPFNeighborState
+	}
+
+	// iface is an OSPF interface of either version.
+	iface struct {
+		deviceID int
+		portID   int
+		address  string
+		area     types.OSPFAreaID
+		hello    int
+		dead     int
+		admin    string
+		state    string
+	}
+
+	// portKey identifies an interface.
+	portKey struct{ deviceID, portID int }
+
+	// checker holds the data shared by the checks of both versions.
+	checker struct {
+		report    *Report
+		hostnames map[int]string
+		ports     map[int]string
+		// routers maps router IDs to device IDs.
+		routers map[string]int
+	}
+)
+
+// Check loads the OSPF neighbours and interfaces of the LibreNMS instance behind client and
+// returns the report of Analyze.
+func Check(client *librenms.Client, opts *Options) (*Report, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	in := &Input{RouterIDs: opts.RouterIDs}
+
+	devices, err := client.Device.List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	in.Devices = devices.Devices
+	err = client.Port.StreamAllPorts(&types.PortsQueryParams{Columns: portColumns}, func(port types.Port) error {
+		in.Ports = append(in.Ports, port)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ports: %w", err)
+	}
+
+	neighbors, err := client.Routing.ListOSPF("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list OSPF neighbours: %w", err)
+	}
+	in.Neighbors = neighbors.OSPFNeighbors
+	interfaces, err := client.Routing.ListOSPFPorts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list OSPF ports: %w", err)
+	}
+	in.Interfaces = interfaces.OSPFPorts
+
+	if !opts.SkipV3 {
+		neighbors, err := client.Routing.ListOSPFv3("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list OSPFv3 neighbours: %w", err)
+		}
+		in.NeighborsV3 = neighbors.OSPFv3Neighbors
+		interfaces, err := client.Routing.ListOSPFv3Ports()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list OSPFv3 ports: %w", err)
+		}
+		in.InterfacesV3 = interfaces.OSPFv3Ports
+	}
+	return Analyze(in), nil
+}
+
+// Analyze cross-references the neighbours and interfaces of in.
+func Analyze(in *Input) *Report {
+	c := &checker{
+		report:    &Report{Adjacencies: []Adjacency{}, Issues: []Issue{}, External: []Neighbor{}},
+		hostnames: make(map[int]string),
+		ports:     make(map[int]string),
+		routers:   make(map[string]int),
+	}
+	for _, port := range in.Ports {
+		c.ports[int(port.PortID)] = string(port.IfName)
+	}
+
+	neighbors := make([]neighbor, 0, len(in.Neighbors))
+	for _, n := range in.Neighbors {
+		neighbors = append(neighbors, neighbor{
+			deviceID: int(n.DeviceID), portID: int(n.PortID), routerID: string(n.OSPFNbrRtrID),
+			address: string(n.OSPFNbrIPAddr), state: n.OSPFNbrState,
+		})
+	}
+	interfaces := make([]iface, 0, len(in.Interfaces))
+	for _, i := range in.Interfaces {
+		interfaces = append(interfaces, iface{
+			deviceID: int(i.DeviceID), portID: int(i.PortID), address: string(i.OSPFIfIPAddress), area: i.OSPFIfAreaID,
+			hello: int(i.OSPFIfHelloInterval), dead: int(i.OSPFIfRtrDeadInterval), admin: string(i.OSPFIfAdminStat), state: string(i.OSPFIfState),
+		})
+	}
+	neighborsV3 := make([]neighbor, 0, len(in.NeighborsV3))
+	for _, n := range in.NeighborsV3 {
+		routerID := string(n.RouterID)
+		if routerID == "" && n.OSPFv3NbrRtrID != 0 {
+			id := uint32(n.OSPFv3NbrRtrID)
+			routerID = net.IPv4(byte(id>>24), byte(id>>16), byte(id>>8), byte(id)).String()
+		}
+		neighborsV3 = append(neighborsV3, neighbor{
+			deviceID: int(n.DeviceID), portID: int(n.PortID), routerID: routerID,
+			address: string(n.OSPFv3NbrAddress), state: n.OSPFv3NbrState,
+		})
+	}
+	interfacesV3 := make([]iface, 0, len(in.InterfacesV3))
+	for _, i := range in.InterfacesV3 {
+		interfacesV3 = append(interfacesV3, iface{
+			deviceID: int(i.DeviceID), portID: int(i.PortID), area: i.OSPFv3IfAreaID,
+			hello: int(i.OSPFv3IfHelloInterval), dead: int(i.OSPFv3IfRtrDeadInterval), admin: string(i.OSPFv3IfAdminStatus), state: string(i.OSPFv3IfState),
+		})
+	}
+
+	// Router IDs are resolved from the OSPFv2 addresses first, then the device IPs and
+	// finally the explicit mapping, which wins.
+	byAddress := make(map[string]iface)
+	for _, i := range interfaces {
+		if i.address != "" && i.address != "0.0.0.0" {
+			byAddress[i.address] = i
+		}
+	}
+	for _, n := range neighbors {
+		if i, ok := byAddress[n.address]; ok && n.routerID != "" {
+			c.routers[n.routerID] = i.deviceID
+		}
+	}
+	deviceIDs := make(map[string]int)
+	for _, device := range in.Devices {
+		c.hostnames[int(device.DeviceID)] = string(device.Hostname)
+		deviceIDs[string(device.Hostname)] = int(device.DeviceID)
+		if _, ok := c.routers[string(device.IP)]; device.IP != "" && !ok {
+			c.routers[string(device.IP)] = int(device.DeviceID)
+		}
+	}
+	for routerID, hostname := range in.RouterIDs {
+		if id, ok := deviceIDs[hostname]; ok {
+			c.routers[routerID] = id
+		}
+	}
+
+	c.check(OSPFv2, neighbors, interfaces, byAddress)
+	c.check(OSPFv3, neighborsV3, interfacesV3, nil)
+	return c.report
+}
+
+// OK reports whether no issues were found.
+func (r *Report) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Err returns an error summarizing the issues, or nil when there are none.
+func (r *Report) Err() error {
+	if r.OK() {
+		return nil
+	}
+	counts := make(map[IssueKind]int)
+	for _, issue := range r.Issues {
+		counts[issue.Kind]++
+	}
+	var kinds []string
+	for _, kind := range []IssueKind{IssueOneSided, IssueAreaMismatch, IssueTimerMismatch, IssueNoNeighbors} {
+		if counts[kind] > 0 {
+			kinds = append(kinds, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	return fmt.Errorf("%d OSPF issues: %s", len(r.Issues), strings.Join(kinds, ", "))
+}
+
+// check cross-references the neighbours and interfaces of a version. byAddress maps the
+// interface addresses to the interfaces, for OSPFv2.
+func (c *checker) check(version Version, neighbors []neighbor, interfaces []iface, byAddress map[string]iface) {
+	sort.SliceStable(neighbors, func(i, j int) bool {
+		a, b := neighbors[i], neighbors[j]
+		if a.deviceID != b.deviceID {
+			return a.deviceID < b.deviceID
+		}
+		if a.portID != b.portID {
+			return a.portID < b.portID
+		}
+		return a.routerID < b.routerID
+	})
+	sort.SliceStable(interfaces, func(i, j int) bool {
+		a, b := interfaces[i], interfaces[j]
+		if a.deviceID != b.deviceID {
+			return a.deviceID < b.deviceID
+		}
+		return a.portID < b.portID
+	})
+	byPort := make(map[portKey]iface)
+	for _, i := range interfaces {
+		byPort[portKey{i.deviceID, i.portID}] = i
+	}
+
+	// resolve returns the device of a neighbour and, when known, its interface.
+	resolve := func(n neighbor) (int, *iface) {
+		if i, ok := byAddress[n.address]; ok {
+			return i.deviceID, &i
+		}
+		return c.routers[n.routerID], nil
+	}
+	// local returns the interface of a device a neighbour is seen on, when known.
+	local := func(n neighbor) *iface {
+		if i, ok := byPort[portKey{n.deviceID, n.portID}]; ok && n.portID > 0 {
+			return &i
+		}
+		return nil
+	}
+
+	used := make([]bool, len(neighbors))
+	active := make(map[portKey]bool)
+	for i, n := range neighbors {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+		remoteID, remote := resolve(n)
+		near := local(n)
+		if near != nil {
+			active[portKey{near.deviceID, near.portID}] = true
+		}
+		if remoteID == 0 || remoteID == n.deviceID {
+			c.report.External = append(c.report.External, Neighbor{
+				Version: version, Hostname: c.hostname(n.deviceID), Port: c.port(near),
+				RouterID: n.routerID, Address: n.address, State: n.state,
+			})
+			continue
+		}
+
+		// The reverse neighbour is the first unused neighbour of the remote device that
+		// resolves to this device, preferring the one on the same interfaces for parallel
+		// adjacencies.
+		back := -1
+		for j, m := range neighbors {
+			if used[j] || m.deviceID != remoteID {
+				continue
+			}
+			deviceID, far := resolve(m)
+			if deviceID != n.deviceID {
+				continue
+			}
+			sameNear := near == nil || far == nil || far.portID == near.portID
+			sameRemote := remote == nil || m.portID == 0 || m.portID == remote.portID
+			if back < 0 || sameNear && sameRemote {
+				back = j
+			}
+			if sameNear && sameRemote {
+				break
+			}
+		}
+		if back < 0 {
+			if remote != nil {
+				active[portKey{remote.deviceID, remote.portID}] = true
+			}
+			c.issue(Issue{
+				Kind: IssueOneSided, Version: version, Hostname: c.hostname(n.deviceID), Port: c.port(near),
+				Neighbor: n.routerID, RemoteHostname: c.hostname(remoteID), RemotePort: c.port(remote),
+				Message: fmt.Sprintf("%s lists %s (%s) as a neighbour but %s doesn't list %s",
+					c.label(n.deviceID, near), c.label(remoteID, remote), n.routerID, c.hostname(remoteID), c.hostname(n.deviceID)),
+			})
+			continue
+		}
+
+		used[back] = true
+		m := neighbors[back]
+		if _, far := resolve(m); near == nil {
+			near = far
+		}
+		if remote == nil {
+			remote = local(m)
+		}
+		adjacency := Adjacency{
+			Version: version, Hostname: c.hostname(n.deviceID), Port: c.port(near), State: n.state,
+			RemoteHostname: c.hostname(remoteID), RemotePort: c.port(remote), RemoteState: m.state,
+		}
+		if near != nil {
+			area := near.area
+			adjacency.Area = &area
+			active[portKey{near.deviceID, near.portID}] = true
+		}
+		if remote != nil {
+			active[portKey{remote.deviceID, remote.portID}] = true
+		}
+		c.report.Adjacencies = append(c.report.Adjacencies, adjacency)
+		if near == nil || remote == nil {
+			continue
+		}
+
+		issue := Issue{
+			Version: version, Hostname: adjacency.Hostname, Port: adjacency.Port, Neighbor: n.routerID,
+			RemoteHostname: adjacency.RemoteHostname, RemotePort: adjacency.RemotePort,
+		}
+		if near.area != remote.area {
+			issue.Kind = IssueAreaMismatch
+			issue.Message = fmt.Sprintf("%s is in area %s but %s is in area %s",
+				c.label(near.deviceID, near), near.area, c.label(remote.deviceID, remote), remote.area)
+			c.issue(issue)
+		}
+		if near.hello > 0 && remote.hello > 0 && (near.hello != remote.hello || near.dead != remote.dead) {
+			issue.Kind = IssueTimerMismatch
+			issue.Message = fmt.Sprintf("%s uses hello %ds and dead %ds but %s uses hello %ds and dead %ds",
+				c.label(near.deviceID, near), near.hello, near.dead, c.label(remote.deviceID, remote), remote.hello, remote.dead)
+			c.issue(issue)
+		}
+	}
+
+	for _, i := range interfaces {
+		if active[portKey{i.deviceID, i.portID}] || !enabled(i) {
+			continue
+		}
+		c.issue(Issue{
+			Kind: IssueNoNeighbors, Version: version, Hostname: c.hostname(i.deviceID), Port: c.port(&i),
+			Message: fmt.Sprintf("%s has %s enabled in area %s but no neighbours", c.label(i.deviceID, &i), version, i.area),
+		})
+	}
+}
+
+func (c *checker) issue(issue Issue) {
+	c.report.Issues = append(c.report.Issues, issue)
+}
+
+// hostname returns the hostname of a device, or a placeholder for unknown devices.
+func (c *checker) hostname(deviceID int) string {
+	if hostname := c.hostnames[deviceID]; hostname != "" {
+		return hostname
+	}
+	return fmt.Sprintf("device %d", deviceID)
+}
+
+// port returns the name of an interface, or an empty string when unknown.
+func (c *checker) port(i *iface) string {
+	if i == nil {
+		return ""
+	}
+	if name := c.ports[i.portID]; name != "" {
+		return name
+	}
+	if i.portID > 0 {
+		return fmt.Sprintf("port %d", i.portID)
+	}
+	return i.address
+}
+
+// label returns the hostname of a device followed by the name of the interface.
+func (c *checker) label(deviceID int, i *iface) string {
+	if port := c.port(i); port != "" {
+		return c.hostname(deviceID) + " " + port
+	}
+	return c.hostname(deviceID)
+}
+
+// enabled reports whether OSPF runs on an interface that is up. Loopbacks never have
+// neighbours.
+func enabled(i iface) bool {
+	switch strings.ToLower(i.state) {
+	case "down", "loopback":
+		return false
+	}
+	return i.admin == "" || strings.EqualFold(i.admin, "enabled")
+}
@@ -0,0 +1,161 @@
+package ospfcheck_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/ospfcheck"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer returns a server with four routers: r1 and r2 are consistent, r1 and r3 disagree
+// on the area and timers, r4 doesn't list r3 and r1 has an interface without neighbours.
+func newServer(t *testing.T) *librenmstest.Server {
+	srv := librenmstest.New(t)
+	for _, device := range []types.Device{
+		{DeviceID: 1, Hostname: "r1", IP: "10.255.0.1"},
+		{DeviceID: 2, Hostname: "r2", IP: "10.255.0.2"},
+		{DeviceID: 3, Hostname: "r3", IP: "10.255.0.3"},
+		{DeviceID: 4, Hostname: "r4"},
+	} {
+		srv.AddDevice(device)
+	}
+	for _, port := range []types.Port{
+		{PortID: 11, DeviceID: 1, IfName: "Gi0/1"},
+		{PortID: 12, DeviceID: 1, IfName: "Gi0/2"},
+		{PortID: 13, DeviceID: 1, IfName: "Lo0"},
+		{PortID: 14, DeviceID: 1, IfName: "Gi0/3"},
+		{PortID: 21, DeviceID: 2, IfName: "Gi0/1"},
+		{PortID: 31, DeviceID: 3, IfName: "Gi0/1"},
+		{PortID: 32, DeviceID: 3, IfName: "Gi0/2"},
+		{PortID: 41, DeviceID: 4, IfName: "Gi0/1"},
+	} {
+		srv.AddPort(port)
+	}
+
+	for _, port := range []types.OSPFPort{
+		{DeviceID: 1, PortID: 11, OSPFIfIPAddress: "10.0.12.1", OSPFIfHelloInterval: 10, OSPFIfRtrDeadInterval: 40, OSPFIfAdminStat: "enabled", OSPFIfState: "pointToPoint"},
+		{DeviceID: 1, PortID: 12, OSPFIfIPAddress: "10.0.13.1", OSPFIfHelloInterval: 10, OSPFIfRtrDeadInterval: 40, OSPFIfAdminStat: "enabled", OSPFIfState: "pointToPoint"},
+		{DeviceID: 1, PortID: 13, OSPFIfIPAddress: "10.255.0.1", OSPFIfAdminStat: "enabled", OSPFIfState: "loopback"},
+		{DeviceID: 1, PortID: 14, OSPFIfIPAddress: "10.0.14.1", OSPFIfHelloInterval: 10, OSPFIfRtrDeadInterval: 40, OSPFIfAdminStat: "enabled", OSPFIfState: "designatedRouter"},
+		{DeviceID: 2, PortID: 21, OSPFIfIPAddress: "10.0.12.2", OSPFIfHelloInterval: 10, OSPFIfRtrDeadInterval: 40, OSPFIfAdminStat: "enabled", OSPFIfState: "pointToPoint"},
+		{DeviceID: 3, PortID: 31, OSPFIfIPAddress: "10.0.13.3", OSPFIfAreaID: 10, OSPFIfHelloInterval: 5, OSPFIfRtrDeadInterval: 20, OSPFIfAdminStat: "enabled", OSPFIfState: "pointToPoint"},
+		{DeviceID: 3, PortID: 32, OSPFIfIPAddress: "10.0.34.3", OSPFIfHelloInterval: 10, OSPFIfRtrDeadInterval: 40, OSPFIfAdminStat: "enabled", OSPFIfState: "pointToPoint"},
+		{DeviceID: 4, PortID: 41, OSPFIfIPAddress: "10.0.34.4", OSPFIfHelloInterval: 10, OSPFIfRtrDeadInterval: 40, OSPFIfAdminStat: "enabled", OSPFIfState: "pointToPoint"},
+	} {
+		srv.AddOSPFPort(port)
+	}
+	for _, neighbor := range []types.OSPFNeighbor{
+		{DeviceID: 1, PortID: 11, OSPFNbrRtrID: "10.255.0.2", OSPFNbrIPAddr: "10.0.12.2", OSPFNbrState: types.OSPFStateFull},
+		{DeviceID: 1, OSPFNbrRtrID: "10.255.0.3", OSPFNbrIPAddr: "10.0.13.3", OSPFNbrState: types.OSPFStateFull},
+		{DeviceID: 1, OSPFNbrRtrID: "192.0.2.1", OSPFNbrIPAddr: "192.0.2.1", OSPFNbrState: types.OSPFStateFull},
+		{DeviceID: 2, OSPFNbrRtrID: "10.255.0.1", OSPFNbrIPAddr: "10.0.12.1", OSPFNbrState: types.OSPFStateFull},
+		{DeviceID: 3, OSPFNbrRtrID: "10.255.0.1", OSPFNbrIPAddr: "10.0.13.1", OSPFNbrState: types.OSPFStateExchangeStart},
+		{DeviceID: 3, PortID: 32, OSPFNbrRtrID: "10.255.0.4", OSPFNbrIPAddr: "10.0.34.4", OSPFNbrState: types.OSPFStateFull},
+	} {
+		srv.AddOSPFNeighbor(neighbor)
+	}
+
+	// r2 reports the router ID of r1 as an integer, and r3 has an OSPFv3 neighbour
+	// that isn't a known router.
+	srv.AddOSPFv3Port(types.OSPFv3Port{DeviceID: 1, PortID: 11, OSPFv3IfHelloInterval: 10, OSPFv3IfRtrDeadInterval: 40, OSPFv3IfAdminStatus: "enabled"})
+	srv.AddOSPFv3Port(types.OSPFv3Port{DeviceID: 2, PortID: 21, OSPFv3IfAreaID: 1, OSPFv3IfHelloInterval: 10, OSPFv3IfRtrDeadInterval: 40, OSPFv3IfAdminStatus: "enabled"})
+	srv.AddOSPFv3Neighbor(types.OSPFv3Neighbor{DeviceID: 1, PortID: 11, RouterID: "10.255.0.2", OSPFv3NbrState: types.OSPFStateFull})
+	srv.AddOSPFv3Neighbor(types.OSPFv3Neighbor{DeviceID: 2, PortID: 21, OSPFv3NbrRtrID: 0x0aff0001, OSPFv3NbrState: types.OSPFStateFull})
+	srv.AddOSPFv3Neighbor(types.OSPFv3Neighbor{DeviceID: 3, RouterID: "10.255.0.9", OSPFv3NbrState: types.OSPFStateInit})
+	return srv
+}
+
+func TestCheck(t *testing.T) {
+	r := require.New(t)
+
+	srv := newServer(t)
+	report, err := ospfcheck.Check(srv.Client(), nil)
+	r.NoError(err, "Check returned an error")
+
+	backbone := types.OSPFBackbone
+	r.Equal([]ospfcheck.Adjacency{
+		{Version: ospfcheck.OSPFv2, Hostname: "r1", Port: "Gi0/2", State: types.OSPFStateFull,
+			RemoteHostname: "r3", RemotePort: "Gi0/1", RemoteState: types.OSPFStateExchangeStart, Area: &backbone},
+		{Version: ospfcheck.OSPFv2, Hostname: "r1", Port: "Gi0/1", State: types.OSPFStateFull,
+			RemoteHostname: "r2", RemotePort: "Gi0/1", RemoteState: types.OSPFStateFull, Area: &backbone},
+		{Version: ospfcheck.OSPFv3, Hostname: "r1", Port: "Gi0/1", State: types.OSPFStateFull,
+			RemoteHostname: "r2", RemotePort: "Gi0/1", RemoteState: types.OSPFStateFull, Area: &backbone},
+	}, report.Adjacencies, "Unexpected adjacencies")
+
+	r.Equal([]ospfcheck.Issue{
+		{Kind: ospfcheck.IssueAreaMismatch, Version: ospfcheck.OSPFv2, Hostname: "r1", Port: "Gi0/2", Neighbor: "10.255.0.3",
+			RemoteHostname: "r3", RemotePort: "Gi0/1", Message: "r1 Gi0/2 is in area 0.0.0.0 but r3 Gi0/1 is in area 0.0.0.10"},
+		{Kind: ospfcheck.IssueTimerMismatch, Version: ospfcheck.OSPFv2, Hostname: "r1", Port: "Gi0/2", Neighbor: "10.255.0.3",
+			RemoteHostname: "r3", RemotePort: "Gi0/1", Message: "r1 Gi0/2 uses hello 10s and dead 40s but r3 Gi0/1 uses hello 5s and dead 20s"},
+		{Kind: ospfcheck.IssueOneSided, Version: ospfcheck.OSPFv2, Hostname: "r3", Port: "Gi0/2", Neighbor: "10.255.0.4",
+			RemoteHostname: "r4", RemotePort: "Gi0/1", Message: "r3 Gi0/2 lists r4 Gi0/1 (10.255.0.4) as a neighbour but r4 doesn't list r3"},
+		{Kind: ospfcheck.IssueNoNeighbors, Version: ospfcheck.OSPFv2, Hostname: "r1", Port: "Gi0/3",
+			Message: "r1 Gi0/3 has ospfv2 enabled in area 0.0.0.0 but no neighbours"},
+		{Kind: ospfcheck.IssueAreaMismatch, Version: ospfcheck.OSPFv3, Hostname: "r1", Port: "Gi0/1", Neighbor: "10.255.0.2",
+			RemoteHostname: "r2", RemotePort: "Gi0/1", Message: "r1 Gi0/1 is in area 0.0.0.0 but r2 Gi0/1 is in area 0.0.0.1"},
+	}, report.Issues, "Unexpected issues")
+
+	r.Equal([]ospfcheck.Neighbor{
+		{Version: ospfcheck.OSPFv2, Hostname: "r1", RouterID: "192.0.2.1", Address: "192.0.2.1", State: types.OSPFStateFull},
+		{Version: ospfcheck.OSPFv3, Hostname: "r3", RouterID: "10.255.0.9", State: types.OSPFStateInit},
+	}, report.External, "Expected the neighbours that aren't devices")
+
+	r.False(report.OK(), "Expected issues")
+	r.EqualError(report.Err(), "5 OSPF issues: 1 one-sided, 2 area-mismatch, 1 timer-mismatch, 1 no-neighbors", "Unexpected summary")
+	data, err := json.Marshal(report.Adjacencies[0])
+	r.NoError(err, "Failed to encode the adjacency")
+	r.JSONEq(`{"version":"ospfv2","hostname":"r1","port":"Gi0/2","state":"full","remote_hostname":"r3",`+
+		`"remote_port":"Gi0/1","remote_state":"exchangeStart","area":"0.0.0.0"}`, string(data), "Unexpected JSON")
+
+	report, err = ospfcheck.Check(srv.Client(), &ospfcheck.Options{RouterIDs: map[string]string{"10.255.0.9": "r4"}})
+	r.NoError(err, "Check returned an error")
+	r.Len(report.External, 1, "Expected the router ID to be mapped")
+	r.Equal("r3 lists r4 (10.255.0.9) as a neighbour but r4 doesn't list r3", report.Issues[len(report.Issues)-1].Message,
+		"Expected the OSPFv3 neighbour to be checked")
+
+	report, err = ospfcheck.Check(srv.Client(), &ospfcheck.Options{SkipV3: true})
+	r.NoError(err, "Check returned an error")
+	r.Len(report.Adjacencies, 2, "Expected the OSPFv2 adjacencies only")
+}
+
+func TestAnalyze(t *testing.T) {
+	r := require.New(t)
+
+	report := ospfcheck.Analyze(&ospfcheck.Input{})
+	r.True(report.OK(), "Expected no issues without OSPF")
+	r.NoError(report.Err(), "Expected no error without issues")
+	data, err := json.Marshal(report)
+	r.NoError(err, "Failed to encode the report")
+	r.JSONEq(`{"adjacencies":[],"issues":[],"external":[]}`, string(data), "Expected empty lists rather than null")
+
+	report = ospfcheck.Analyze(&ospfcheck.Input{
+		Devices: []types.Device{{DeviceID: 1, Hostname: "r1"}, {DeviceID: 2, Hostname: "r2"}},
+		Interfaces: []types.OSPFPort{
+			{DeviceID: 1, PortID: 1, OSPFIfIPAddress: "10.0.0.1", OSPFIfAdminStat: "disabled"},
+			{DeviceID: 2, PortID: 2, OSPFIfIPAddress: "10.0.0.2", OSPFIfState: "down"},
+		},
+	})
+	r.True(report.OK(), "Expected disabled and down interfaces to be skipped")
+}
+
+func TestCheck_Errors(t *testing.T) {
+	r := require.New(t)
+
+	srv := newServer(t)
+	for endpoint, message := range map[string]string{
+		"devices":      "failed to list devices",
+		"ports":        "failed to list ports",
+		"ospf":         "failed to list OSPF neighbours",
+		"ospf_ports":   "failed to list OSPF ports",
+		"ospfv3":       "failed to list OSPFv3 neighbours",
+		"ospfv3_ports": "failed to list OSPFv3 ports",
+	} {
+		srv.InjectError(endpoint, http.StatusInternalServerError, 1)
+		_, err := ospfcheck.Check(srv.Client(), nil)
+		r.ErrorContains(err, message, "Expected the error of %s", endpoint)
+	}
+}
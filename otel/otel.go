@@ -0,0 +1,224 @@
+// Package otel instruments the LibreNMS client with OpenTelemetry traces and metrics.
+//
+// Middleware returns a librenms.Middleware creating a client span per request, named after
+// the API operation (e.g. "librenms.Device.GetPorts"), and recording the request duration
+// and errors:
+//
+//	client, err := librenms.New(url, token, librenms.WithMiddleware(
+//		librenms.RetryMiddleware(3, time.Second),
+//		otel.Middleware(),
+//	))
+//
+// Placed after RetryMiddleware, every attempt gets its own span and the retries are counted
+// by the http.request.resend_count attribute.
+package otel
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	gootel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ScopeName is the instrumentation scope of the tracer and meter.
+	ScopeName = "github.com/javen-yan/librenms-go/otel"
+
+	// OperationKey is the attribute holding the API operation, e.g. "Device.GetPorts".
+	OperationKey = attribute.Key("librenms.operation")
+
+	// DurationMetric is the histogram of the request durations, in seconds.
+	DurationMetric = "librenms.client.request.duration"
+	// ErrorsMetric counts the requests failing with a transport error or a 4xx/5xx status.
+	ErrorsMetric = "librenms.client.request.errors"
+
+	apiPrefix = "/api/v0/"
+)
+
+type (
+	// Option configures the instrumentation.
+	Option func(*config)
+
+	config struct {
+		tracerProvider trace.TracerProvider
+		meterProvider  metric.MeterProvider
+		propagators    propagation.TextMapPropagator
+	}
+
+	// instruments holds the tracer and the metric instruments of a middleware.
+	instruments struct {
+		tracer      trace.Tracer
+		propagators propagation.TextMapPropagator
+		duration    metric.Float64Histogram
+		errors      metric.Int64Counter
+	}
+
+	// trackedBody ends the span of a request once its response body is read or closed.
+	trackedBody struct {
+		io.ReadCloser
+		size int64
+		once sync.Once
+		end  func(size int64)
+	}
+)
+
+// WithTracerProvider sets the tracer provider. The global provider is used by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider. The global provider is used by default.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithPropagators sets the propagators injecting the span context into the request
+// headers. The global propagators are used by default.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = propagators
+	}
+}
+
+// Middleware returns a middleware tracing every request and recording its duration and
+// errors. Spans carry the HTTP method, the route template, the status code, the retry
+// count and the response size. They end once the response body is read or closed.
+func Middleware(opts ...Option) librenms.Middleware {
+	cfg := config{
+		tracerProvider: gootel.GetTracerProvider(),
+		meterProvider:  gootel.GetMeterProvider(),
+		propagators:    gootel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+	inst := &instruments{
+		tracer:      cfg.tracerProvider.Tracer(ScopeName),
+		propagators: cfg.propagators,
+	}
+	var err error
+	inst.duration, err = meter.Float64Histogram(DurationMetric,
+		metric.WithDescription("Duration of the LibreNMS API requests."),
+		metric.WithUnit("s"))
+	if err != nil {
+		gootel.Handle(err)
+	}
+	inst.errors, err = meter.Int64Counter(ErrorsMetric,
+		metric.WithDescription("Number of failed LibreNMS API requests."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		gootel.Handle(err)
+	}
+
+	return func(next librenms.Doer) librenms.Doer {
+		return librenms.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return inst.do(next, req)
+		})
+	}
+}
+
+// do sends a request within a span.
+func (i *instruments) do(next librenms.Doer, req *http.Request) (*http.Response, error) {
+	info, _ := librenms.RequestInfoFromContext(req.Context())
+	operation := info.Operation
+	if operation == "" {
+		operation = "request"
+	}
+	attrs := []attribute.KeyValue{
+		OperationKey.String(operation),
+		semconv.HTTPRequestMethodKey.String(req.Method),
+	}
+	if info.Route != "" {
+		attrs = append(attrs, semconv.URLTemplate(apiPrefix+info.Route))
+	}
+
+	start := time.Now()
+	ctx, span := i.tracer.Start(req.Context(), "librenms."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(
+			semconv.URLFull(req.URL.String()),
+			semconv.ServerAddress(req.URL.Hostname()),
+		))
+	if info.Attempt > 1 {
+		span.SetAttributes(semconv.HTTPRequestResendCount(info.Attempt - 1))
+	}
+
+	req = req.Clone(ctx)
+	i.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := next.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(semconv.ErrorTypeOther)
+		span.End()
+		attrs = append(attrs, semconv.ErrorTypeOther)
+		i.record(req, start, attrs, true)
+		return nil, err
+	}
+
+	attrs = append(attrs, semconv.HTTPResponseStatusCode(resp.StatusCode))
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	failed := resp.StatusCode >= http.StatusBadRequest
+	if failed {
+		errorType := semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode))
+		span.SetStatus(codes.Error, resp.Status)
+		span.SetAttributes(errorType)
+		attrs = append(attrs, errorType)
+	}
+
+	end := func(size int64) {
+		span.SetAttributes(semconv.HTTPResponseBodySize(int(size)))
+		span.End()
+		i.record(req, start, attrs, failed)
+	}
+	if resp.Body == nil || resp.Body == http.NoBody {
+		end(0)
+		return resp, nil
+	}
+	resp.Body = &trackedBody{ReadCloser: resp.Body, end: end}
+	return resp, nil
+}
+
+// record records the duration of a request and counts it when it failed.
+func (i *instruments) record(req *http.Request, start time.Time, attrs []attribute.KeyValue, failed bool) {
+	set := metric.WithAttributeSet(attribute.NewSet(attrs...))
+	i.duration.Record(req.Context(), time.Since(start).Seconds(), set)
+	if failed {
+		i.errors.Add(req.Context(), 1, set)
+	}
+}
+
+// Read implements io.Reader.
+func (b *trackedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	if err == io.EOF {
+		b.once.Do(func() { b.end(b.size) })
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (b *trackedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.end(b.size) })
+	return err
+}
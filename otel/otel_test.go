@@ -0,0 +1,133 @@
+package otel_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/otel"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// attrs returns the attributes of a span by key.
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestMiddleware(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{Hostname: "core1", OS: "iosxe"})
+	srv.AddPort(types.Port{DeviceID: 1, IfName: "Gi0/1"})
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	var traceparents []string
+	client := srv.Client(librenms.WithMiddleware(
+		librenms.RetryMiddleware(2, time.Millisecond),
+		otel.Middleware(
+			otel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+			otel.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+			otel.WithPropagators(propagation.TraceContext{})),
+		func(next librenms.Doer) librenms.Doer {
+			return librenms.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				traceparents = append(traceparents, req.Header.Get("Traceparent"))
+				return next.Do(req)
+			})
+		}))
+
+	_, err := client.Device.GetPorts("core1", "")
+	r.NoError(err, "GetPorts returned an error")
+
+	srv.InjectError("devices/*", http.StatusServiceUnavailable, 1)
+	_, err = client.Device.Get("core1")
+	r.NoError(err, "Get should succeed after a retry")
+
+	srv.InjectError("devices/*", http.StatusNotFound, 1)
+	_, err = client.Device.Get("core1")
+	r.Error(err, "Expected the injected error")
+
+	ended := spans.Ended()
+	r.Len(ended, 4, "Expected a span per attempt")
+	r.Len(traceparents, 4, "Expected a traceparent per attempt")
+	for i, span := range ended {
+		r.Equal(span.SpanContext().TraceID().String(), traceparents[i][3:35], "Expected the span context to be propagated")
+	}
+
+	ports := ended[0]
+	r.Equal("librenms.Device.GetPorts", ports.Name(), "Unexpected span name")
+	r.Equal(codes.Unset, ports.Status().Code, "Unexpected span status")
+	a := attrs(ports)
+	r.Equal("Device.GetPorts", a[otel.OperationKey].AsString(), "Unexpected operation")
+	r.Equal("GET", a["http.request.method"].AsString(), "Unexpected method")
+	r.Equal("/api/v0/devices/:identifier/ports", a["url.template"].AsString(), "Unexpected route")
+	r.Equal(int64(200), a["http.response.status_code"].AsInt64(), "Unexpected status code")
+	r.Positive(a["http.response.body.size"].AsInt64(), "Expected the response size")
+	_, ok := a["http.request.resend_count"]
+	r.False(ok, "Expected no resend count for the first attempt")
+
+	r.Equal("librenms.Device.Get", ended[1].Name(), "Unexpected span name")
+	r.Equal(codes.Error, ended[1].Status().Code, "Expected the 503 span to fail")
+	r.Equal(int64(1), attrs(ended[2])["http.request.resend_count"].AsInt64(), "Expected the retry to be counted")
+	r.Equal(codes.Unset, ended[2].Status().Code, "Expected the retry to succeed")
+	r.Equal(int64(404), attrs(ended[3])["http.response.status_code"].AsInt64(), "Unexpected status code")
+
+	var data metricdata.ResourceMetrics
+	r.NoError(reader.Collect(context.Background(), &data), "Failed to collect metrics")
+	r.Len(data.ScopeMetrics, 1, "Expected the librenms scope")
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range data.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	durations, ok := metrics[otel.DurationMetric].(metricdata.Histogram[float64])
+	r.True(ok, "Expected the duration histogram")
+	var count uint64
+	for _, point := range durations.DataPoints {
+		count += point.Count
+	}
+	r.Equal(uint64(4), count, "Expected a duration per attempt")
+
+	errors, ok := metrics[otel.ErrorsMetric].(metricdata.Sum[int64])
+	r.True(ok, "Expected the error counter")
+	failures := map[string]int64{}
+	for _, point := range errors.DataPoints {
+		errorType, _ := point.Attributes.Value("error.type")
+		failures[errorType.AsString()] += point.Value
+	}
+	r.Equal(map[string]int64{"503": 1, "404": 1}, failures, "Unexpected error counts")
+}
+
+func TestMiddleware_TransportError(t *testing.T) {
+	r := require.New(t)
+
+	spans := tracetest.NewSpanRecorder()
+	client, err := librenms.New("http://127.0.0.1:1/", "token", librenms.WithMiddleware(
+		otel.Middleware(otel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))))))
+	r.NoError(err, "Failed to create client")
+
+	_, err = client.System.Get()
+	r.Error(err, "Expected a connection error")
+
+	ended := spans.Ended()
+	r.Len(ended, 1, "Expected a span for the failed request")
+	r.Equal("librenms.System.Get", ended[0].Name(), "Unexpected span name")
+	r.Equal(codes.Error, ended[0].Status().Code, "Expected the span to fail")
+	r.Equal("_OTHER", attrs(ended[0])["error.type"].AsString(), "Expected the error type")
+}
@@ -0,0 +1,246 @@
+// Package oui looks up the vendors of MAC addresses offline, from the public listings of
+// the IEEE registration authority.
+//
+// Default returns a small built-in database of the vendors most often seen in data centres
+// and campus networks. For complete results, download the IEEE listings, embed them in the
+// program and parse them once:
+//
+//	//go:embed oui.csv
+//	var registry []byte
+//
+//	var vendors = oui.MustParse(bytes.NewReader(registry))
+//
+// The MA-L (oui.csv, oui.txt), MA-M (mam.csv) and MA-S (oui36.csv) listings are supported,
+// as well as the manuf file of Wireshark. Several listings can be combined with Merge.
+package oui
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// prefixLengths are the lengths in bits of the MA-S, MA-M and MA-L assignments, longest
+// first.
+var prefixLengths = []int{36, 28, 24}
+
+//go:embed vendors.csv
+var builtin []byte
+
+var (
+	defaultOnce sync.Once
+	defaultDB   *Database
+)
+
+// Database maps MAC address prefixes to vendors. The zero Database is empty and ready to use.
+// A Database is safe for concurrent lookups once it is no longer modified.
+type Database struct {
+	// vendors maps the prefix lengths to the vendors by prefix.
+	vendors map[int]map[uint64]string
+}
+
+// Default returns the built-in database.
+func Default() *Database {
+	defaultOnce.Do(func() {
+		defaultDB = MustParse(bytes.NewReader(builtin))
+	})
+	return defaultDB
+}
+
+// Parse reads a database in the IEEE CSV or text format or in the Wireshark manuf format.
+// Lines that aren't assignments are skipped.
+func Parse(r io.Reader) (*Database, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len("Registry,"))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	db := &Database{}
+	if strings.EqualFold(string(head), "Registry,") {
+		return db, db.parseCSV(br)
+	}
+	return db, db.parseText(br)
+}
+
+// MustParse is like Parse but panics on errors. It is meant for embedded databases.
+func MustParse(r io.Reader) *Database {
+	db, err := Parse(r)
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+// Add adds a vendor for a prefix: three bytes for an MA-L assignment, e.g. 00:00:0C, or a
+// MAC address followed by the prefix length in bits, e.g. 70:B3:D5:09:F0:00/36.
+func (db *Database) Add(prefix, vendor string) error {
+	bits, value, err := parsePrefix(prefix)
+	if err != nil {
+		return err
+	}
+	db.add(bits, value, strings.TrimSpace(vendor))
+	return nil
+}
+
+// Merge adds the vendors of other, replacing the vendors of the same prefixes.
+func (db *Database) Merge(other *Database) {
+	for bits, vendors := range other.vendors {
+		for value, vendor := range vendors {
+			db.add(bits, value, vendor)
+		}
+	}
+}
+
+// Len returns the number of prefixes in the database.
+func (db *Database) Len() int {
+	n := 0
+	for _, vendors := range db.vendors {
+		n += len(vendors)
+	}
+	return n
+}
+
+// Lookup returns the vendor of a MAC address, using the longest matching prefix. Locally
+// administered and zero addresses have no vendor.
+func (db *Database) Lookup(mac types.MAC) (string, bool) {
+	if mac.IsZero() || mac.IsLocal() {
+		return "", false
+	}
+	var value uint64
+	for _, b := range mac {
+		value = value<<8 | uint64(b)
+	}
+	for _, bits := range prefixLengths {
+		if vendor, ok := db.vendors[bits][value>>(48-bits)]; ok {
+			return vendor, true
+		}
+	}
+	return "", false
+}
+
+// Vendor returns the vendor of a MAC address, or an empty string when it is unknown.
+func (db *Database) Vendor(mac types.MAC) string {
+	vendor, _ := db.Lookup(mac)
+	return vendor
+}
+
+func (db *Database) add(bits int, value uint64, vendor string) {
+	if db.vendors == nil {
+		db.vendors = make(map[int]map[uint64]string)
+	}
+	if db.vendors[bits] == nil {
+		db.vendors[bits] = make(map[uint64]string)
+	}
+	db.vendors[bits][value] = vendor
+}
+
+// parseCSV reads the IEEE CSV format: Registry,Assignment,Organization Name,Organization
+// Address. The length of the hex assignment gives the prefix length.
+func (db *Database) parseCSV(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	line := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse OUI database: %w", err)
+		}
+		line++
+		if line == 1 || len(record) < 3 {
+			continue
+		}
+		assignment := strings.TrimSpace(record[1])
+		bits := 4 * len(assignment)
+		value, err := strconv.ParseUint(assignment, 16, 64)
+		if err != nil || !validLength(bits) {
+			return fmt.Errorf("failed to parse OUI database: line %d: invalid assignment %q", line, assignment)
+		}
+		db.add(bits, value, strings.TrimSpace(record[2]))
+	}
+}
+
+// parseText reads the IEEE text format, where MA-L assignments are on lines like
+// "00-00-0C   (hex)\t\tCisco Systems, Inc", and the Wireshark manuf format, where lines
+// are a prefix, a short name and an optional full name separated by tabs.
+func (db *Database) parseText(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if prefix, vendor, ok := strings.Cut(line, "(hex)"); ok {
+			if err := db.Add(prefix, vendor); err != nil {
+				return fmt.Errorf("failed to parse OUI database: %w", err)
+			}
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			continue
+		}
+		bits, value, err := parsePrefix(fields[0])
+		if err != nil {
+			continue
+		}
+		vendor := fields[len(fields)-1]
+		if i := strings.Index(vendor, "#"); i >= 0 {
+			vendor = vendor[:i]
+		}
+		db.add(bits, value, strings.TrimSpace(vendor))
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to parse OUI database: %w", err)
+	}
+	return nil
+}
+
+// parsePrefix parses three bytes, or a MAC address with a prefix length, and returns the
+// prefix length and the prefix bits.
+func parsePrefix(s string) (int, uint64, error) {
+	s = strings.TrimSpace(s)
+	bits := 24
+	address, length, ok := strings.Cut(s, "/")
+	if ok {
+		n, err := strconv.Atoi(length)
+		if err != nil || !validLength(n) {
+			return 0, 0, fmt.Errorf("invalid OUI prefix %q", s)
+		}
+		bits = n
+	} else {
+		address += ":00:00:00"
+		if strings.Contains(s, "-") {
+			address = strings.ReplaceAll(address, ":", "-")
+		}
+	}
+	mac, err := types.ParseMAC(address)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid OUI prefix %q", s)
+	}
+	var value uint64
+	for _, b := range mac {
+		value = value<<8 | uint64(b)
+	}
+	return bits, value >> (48 - bits), nil
+}
+
+func validLength(bits int) bool {
+	for _, n := range prefixLengths {
+		if bits == n {
+			return true
+		}
+	}
+	return false
+}
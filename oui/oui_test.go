@@ -0,0 +1,64 @@
+package oui_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/oui"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	r := require.New(t)
+
+	db := oui.Default()
+	r.Greater(db.Len(), 0, "Expected built-in vendors")
+	r.Equal("VMware, Inc.", db.Vendor(types.MustParseMAC("00:50:56:aa:bb:cc")), "Unexpected vendor")
+	r.Equal("Cisco Systems, Inc", db.Vendor(types.MustParseMAC("0000.0c07.ac01")), "Unexpected vendor")
+
+	_, ok := db.Lookup(types.MustParseMAC("52:54:00:12:34:56"))
+	r.False(ok, "Expected no vendor for locally administered addresses")
+	_, ok = db.Lookup(types.MAC{})
+	r.False(ok, "Expected no vendor for the zero MAC")
+}
+
+func TestParse_CSV(t *testing.T) {
+	r := require.New(t)
+
+	db, err := oui.Parse(strings.NewReader(`Registry,Assignment,Organization Name,Organization Address
+MA-L,70B3D5,IEEE Registration Authority,"445 Hoes Lane Piscataway NJ US 08554"
+MA-M,70B3D51,"Example Devices, Inc.",Somewhere
+MA-S,70B3D509F,Tiny Sensors Ltd,Elsewhere
+`))
+	r.NoError(err, "Parse returned an error")
+	r.Equal(3, db.Len(), "Expected every assignment")
+	r.Equal("Tiny Sensors Ltd", db.Vendor(types.MustParseMAC("70:b3:d5:09:f0:01")), "Expected the longest prefix")
+	r.Equal("Example Devices, Inc.", db.Vendor(types.MustParseMAC("70:b3:d5:1a:00:01")), "Expected the MA-M assignment")
+	r.Equal("IEEE Registration Authority", db.Vendor(types.MustParseMAC("70:b3:d5:f0:00:01")), "Expected the MA-L assignment")
+
+	_, err = oui.Parse(strings.NewReader("Registry,Assignment,Organization Name\nMA-L,XYZ,Broken\n"))
+	r.ErrorContains(err, "line 2", "Expected an error for invalid assignments")
+}
+
+func TestParse_Text(t *testing.T) {
+	r := require.New(t)
+
+	ieee, err := oui.Parse(strings.NewReader("OUI/MA-L\t\t\tOrganization\ncompany_id\t\t\tOrganization\n\t\t\t\tAddress\n\n" +
+		"00-00-0C   (hex)\t\tCisco Systems, Inc\n00000C     (base 16)\t\tCisco Systems, Inc\n\t\t\t\t170 WEST TASMAN DRIVE\n"))
+	r.NoError(err, "Parse returned an error")
+	r.Equal(1, ieee.Len(), "Expected the assignment only")
+	r.Equal("Cisco Systems, Inc", ieee.Vendor(types.MustParseMAC("00:00:0c:01:02:03")), "Unexpected vendor")
+
+	manuf, err := oui.Parse(strings.NewReader("# Wireshark manuf\n00:00:0C\tCisco\tCisco Systems, Inc\n" +
+		"00:1B:C5:00:00:00/36\tConvergi\tConverging Systems Inc.\n08:00:27\tPcsCompu\n"))
+	r.NoError(err, "Parse returned an error")
+	r.Equal("Converging Systems Inc.", manuf.Vendor(types.MustParseMAC("00:1b:c5:00:00:42")), "Expected the full name")
+	r.Equal("PcsCompu", manuf.Vendor(types.MustParseMAC("08:00:27:00:00:01")), "Expected the short name without full name")
+
+	ieee.Merge(manuf)
+	r.Equal(3, ieee.Len(), "Expected the merged vendors")
+	r.NoError(ieee.Add("00:00:0C", "Cisco"), "Add returned an error")
+	r.Equal("Cisco", ieee.Vendor(types.MustParseMAC("00:00:0c:01:02:03")), "Expected the added vendor")
+	r.Error(ieee.Add("00:00:0C:00:00:00/20", "Cisco"), "Expected an error for unsupported lengths")
+}
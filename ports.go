@@ -18,19 +18,20 @@ const (
 // Documentation: https://docs.librenms.org/API/Ports/#get_all_ports
 // Route: /api/v0/ports
 func (p *PortAPI) GetAllPorts(params *types.PortsQueryParams) (*types.PortsResponse, error) {
-	var queryParams *url.Values
-	if params != nil {
-		query := url.Values{}
-		if params.Columns != "" {
-			query.Set("columns", params.Columns)
-		}
-		if len(query) > 0 {
-			queryParams = &query
-		}
+	var resp types.PortsResponse
+	httpReq, err := p.client.newRequest(opPortGetAllPorts, http.MethodGet, portsEndpoint, nil, portsQuery(params))
+	if err != nil {
+		return nil, err
 	}
+	err = p.client.do(httpReq, &resp)
+	return &resp, err
+}
 
-	var resp types.PortsResponse
-	httpReq, err := p.client.newRequest(http.MethodGet, portsEndpoint, nil, queryParams)
+// GetAllPortsWithFields is like GetAllPorts, but records the fields returned for each port,
+// e.g. to tell the columns that weren't returned apart from zero values.
+func (p *PortAPI) GetAllPortsWithFields(params *types.PortsQueryParams) (*types.PortsWithFieldsResponse, error) {
+	var resp types.PortsWithFieldsResponse
+	httpReq, err := p.client.newRequest(opPortGetAllPortsWithFields, http.MethodGet, portsEndpoint, nil, portsQuery(params))
 	if err != nil {
 		return nil, err
 	}
@@ -38,6 +39,25 @@ func (p *PortAPI) GetAllPorts(params *types.PortsQueryParams) (*types.PortsRespo
 	return &resp, err
 }
 
+// StreamAllPorts is like GetAllPorts but decodes the ports one at a time and calls fn for
+// each of them, so that memory use stays flat on large installations. It stops at the first
+// error returned by fn and returns it.
+func (p *PortAPI) StreamAllPorts(params *types.PortsQueryParams, fn func(types.Port) error) error {
+	httpReq, err := p.client.newRequest(opPortStreamAllPorts, http.MethodGet, portsEndpoint, nil, portsQuery(params))
+	if err != nil {
+		return err
+	}
+	return streamArray(p.client, httpReq, "ports", fn)
+}
+
+// portsQuery returns the query parameters of GetAllPorts.
+func portsQuery(params *types.PortsQueryParams) *url.Values {
+	if params == nil || params.Columns == "" {
+		return nil
+	}
+	return &url.Values{"columns": []string{params.Columns}}
+}
+
 // SearchPorts searches for ports matching the query
 // Search in fields: ifAlias, ifDescr, and ifName
 //
@@ -58,7 +78,7 @@ func (p *PortAPI) SearchPorts(search string, params *types.PortsQueryParams) (*t
 	}
 
 	var resp types.PortsResponse
-	httpReq, err := p.client.newRequest(http.MethodGet, path, nil, queryParams)
+	httpReq, err := p.client.newRequest(opPortSearchPorts, http.MethodGet, path, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +106,7 @@ func (p *PortAPI) SearchPortsInField(field, search string, params *types.PortsQu
 	}
 
 	var resp types.PortsResponse
-	httpReq, err := p.client.newRequest(http.MethodGet, path, nil, queryParams)
+	httpReq, err := p.client.newRequest(opPortSearchPortsInField, http.MethodGet, path, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +134,7 @@ func (p *PortAPI) GetPortsWithMAC(mac string, params *types.PortsQueryParams) (*
 	}
 
 	var resp types.PortResponse
-	httpReq, err := p.client.newRequest(http.MethodGet, path, nil, queryParams)
+	httpReq, err := p.client.newRequest(opPortGetPortsWithMAC, http.MethodGet, path, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -139,7 +159,7 @@ func (p *PortAPI) GetPortInfo(portID int, with ...string) (*types.PortResponse,
 	}
 
 	var resp types.PortResponse
-	httpReq, err := p.client.newRequest(http.MethodGet, path, nil, queryParams)
+	httpReq, err := p.client.newRequest(opPortGetPortInfo, http.MethodGet, path, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +175,7 @@ func (p *PortAPI) GetPortInfo(portID int, with ...string) (*types.PortResponse,
 func (p *PortAPI) GetPortIPInfo(portID int) (*types.PortIPResponse, error) {
 	path := fmt.Sprintf("%s/%d/ip", portsEndpoint, portID)
 	var resp types.PortIPResponse
-	httpReq, err := p.client.newRequest(http.MethodGet, path, nil, nil)
+	httpReq, err := p.client.newRequest(opPortGetPortIPInfo, http.MethodGet, path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +191,7 @@ func (p *PortAPI) GetPortIPInfo(portID int) (*types.PortIPResponse, error) {
 func (p *PortAPI) GetPortTransceiver(portID int) (*types.PortTransceiverResponse, error) {
 	path := fmt.Sprintf("%s/%d/transceiver", portsEndpoint, portID)
 	var resp types.PortTransceiverResponse
-	httpReq, err := p.client.newRequest(http.MethodGet, path, nil, nil)
+	httpReq, err := p.client.newRequest(opPortGetPortTransceiver, http.MethodGet, path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +206,7 @@ func (p *PortAPI) GetPortTransceiver(portID int) (*types.PortTransceiverResponse
 func (p *PortAPI) GetPortDescription(portID int) (*types.PortDescriptionResponse, error) {
 	path := fmt.Sprintf("%s/%d/description", portsEndpoint, portID)
 	var resp types.PortDescriptionResponse
-	httpReq, err := p.client.newRequest(http.MethodGet, path, nil, nil)
+	httpReq, err := p.client.newRequest(opPortGetPortDescription, http.MethodGet, path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -204,7 +224,7 @@ func (p *PortAPI) UpdatePortDescription(portID int, description string) (*types.
 	req := &types.PortDescriptionUpdateRequest{Description: description}
 
 	var resp types.PortDescriptionResponse
-	httpReq, err := p.client.newRequest(http.MethodPatch, path, req, nil)
+	httpReq, err := p.client.newRequest(opPortUpdatePortDescription, http.MethodPatch, path, req, nil)
 	if err != nil {
 		return nil, err
 	}
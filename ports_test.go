@@ -31,27 +31,27 @@ func TestClient_GetPorts(t *testing.T) {
 	r.NotNil(portsResp, "GetPorts response is nil")
 
 	r.Equal("ok", portsResp.Status, "Expected status 'ok'")
-	r.Equal(1, portsResp.Count, "Expected count 1")
+	r.EqualValues(1, portsResp.Count, "Expected count 1")
 	r.Len(portsResp.Ports, 1, "Expected 1 port")
 
 	port := portsResp.Ports[0]
-	r.Equal(1, port.PortID, "Expected Port ID 1")
-	r.Equal(1, port.DeviceID, "Expected DeviceID 1")
-	r.Equal("1", port.IfIndex, "Expected IfIndex 1")
-	r.Equal("GigabitEthernet1/0/1", port.IfName, "Expected IfName 'GigabitEthernet1/0/1'")
-	r.Equal("Uplink to Core Switch", port.IfDescr, "Expected IfDescr 'Uplink to Core Switch'")
-	r.Equal("Core Uplink", port.IfAlias, "Expected IfAlias 'Core Uplink'")
-	r.Equal("1000000000", port.IfSpeed, "Expected IfSpeed 1000000000")
-	r.Equal("up", port.IfOperStatus, "Expected IfOperStatus 'up'")
-	r.Equal("up", port.IfAdminStatus, "Expected IfAdminStatus 'up'")
-	r.Equal("ethernetCsmacd", port.IfType, "Expected IfType 'ethernetCsmacd'")
-	r.Equal("00:11:22:33:44:55", port.IfPhysAddress, "Expected IfPhysAddress '00:11:22:33:44:55'")
-	r.Equal("1000000", port.IfInOctets, "Expected IfInOctets 1000000")
-	r.Equal("2000000", port.IfOutOctets, "Expected IfOutOctets 2000000")
-	r.Equal("0", port.IfInErrors, "Expected IfInErrors 0")
-	r.Equal("0", port.IfOutErrors, "Expected IfOutErrors 0")
-	r.Equal("300", port.PollPeriod, "Expected PollPeriod 300")
-	r.Equal("0", port.Ignore, "Expected Ignore false")
-	r.Equal("0", port.Disabled, "Expected Disabled false")
-	r.Equal("0", port.Deleted, "Expected Deleted false")
+	r.EqualValues(1, port.PortID, "Expected Port ID 1")
+	r.EqualValues(1, port.DeviceID, "Expected DeviceID 1")
+	r.EqualValues(1, port.IfIndex, "Expected IfIndex 1")
+	r.Equal("GigabitEthernet1/0/1", string(port.IfName), "Expected IfName 'GigabitEthernet1/0/1'")
+	r.Equal("Uplink to Core Switch", string(port.IfDescr), "Expected IfDescr 'Uplink to Core Switch'")
+	r.Equal("Core Uplink", string(port.IfAlias), "Expected IfAlias 'Core Uplink'")
+	r.EqualValues(1000000000, port.IfSpeed, "Expected IfSpeed 1000000000")
+	r.Equal("up", string(port.IfOperStatus), "Expected IfOperStatus 'up'")
+	r.Equal("up", string(port.IfAdminStatus), "Expected IfAdminStatus 'up'")
+	r.Equal("ethernetCsmacd", string(port.IfType), "Expected IfType 'ethernetCsmacd'")
+	r.Equal("00:11:22:33:44:55", port.IfPhysAddress.String(), "Expected IfPhysAddress '00:11:22:33:44:55'")
+	r.EqualValues(1000000, port.IfInOctets, "Expected IfInOctets 1000000")
+	r.EqualValues(2000000, port.IfOutOctets, "Expected IfOutOctets 2000000")
+	r.EqualValues(0, port.IfInErrors, "Expected IfInErrors 0")
+	r.EqualValues(0, port.IfOutErrors, "Expected IfOutErrors 0")
+	r.EqualValues(300, port.PollPeriod, "Expected PollPeriod 300")
+	r.EqualValues(0, port.Ignore, "Expected Ignore false")
+	r.EqualValues(0, port.Disabled, "Expected Disabled false")
+	r.EqualValues(0, port.Deleted, "Expected Deleted false")
 }
@@ -0,0 +1,247 @@
+// Package portstats computes the traffic, utilisation and error rates of ports from the
+// counters polled by LibreNMS, and ranks the busiest or most erroring ports.
+//
+// LibreNMS stores, for every interface counter, the raw value, the value of the previous
+// poll, their delta and a per second rate. Octet rates are converted to bits per second and
+// compared to the interface speed, taken from ifHighSpeed when ifSpeed overflows on fast
+// interfaces. When a rate is missing, it is derived from the delta and the poll period.
+package portstats
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// Columns are the port columns used by Analyze. Pass them to GetAllPorts or StreamAllPorts
+// to fetch only what the statistics need.
+var Columns = types.MustColumns[types.Port](
+	"ifName", "ifAlias", "ifOperStatus", "ifSpeed", "ifHighSpeed",
+	"ignore", "disabled", "deleted", "poll_period",
+	"ifInOctets_rate", "ifOutOctets_rate", "ifInOctets_delta", "ifOutOctets_delta",
+	"ifInUcastPkts_rate", "ifOutUcastPkts_rate", "ifInNUcastPkts_rate", "ifOutNUcastPkts_rate",
+	"ifInBroadcastPkts_rate", "ifOutBroadcastPkts_rate", "ifInMulticastPkts_rate", "ifOutMulticastPkts_rate",
+	"ifInErrors_rate", "ifOutErrors_rate", "ifInErrors_delta", "ifOutErrors_delta",
+	"ifInDiscards_rate", "ifOutDiscards_rate", "ifInDiscards_delta", "ifOutDiscards_delta",
+).String()
+
+// Metric is a value ports are ranked by.
+type Metric string
+
+// Metrics of Top.
+const (
+	// MetricTraffic is the sum of the inbound and outbound bits per second.
+	MetricTraffic Metric = "traffic"
+	// MetricUtilisation is the highest of the inbound and outbound utilisation.
+	MetricUtilisation Metric = "utilisation"
+	// MetricErrors is the sum of the inbound and outbound errors per second.
+	MetricErrors Metric = "errors"
+	// MetricErrorRatio is the highest of the inbound and outbound error ratios.
+	MetricErrorRatio Metric = "error-ratio"
+	// MetricDiscards is the sum of the inbound and outbound discards per second.
+	MetricDiscards Metric = "discards"
+	// MetricBroadcast is the highest of the inbound and outbound broadcast ratios.
+	MetricBroadcast Metric = "broadcast"
+)
+
+type (
+	// LoadOptions configures Load.
+	LoadOptions struct {
+		// IncludeIgnored keeps the ports that are ignored, disabled or deleted in LibreNMS.
+		IncludeIgnored bool
+		// SkipDevices skips listing the devices, leaving the hostnames empty.
+		SkipDevices bool
+	}
+
+	// Stats are the rates of a port at its last poll. Rates are per second and ratios and
+	// utilisations are percentages.
+	Stats struct {
+		PortID   int
+		DeviceID int
+		Hostname string
+		// Port is the interface name.
+		Port string
+		// Description is the interface alias.
+		Description string
+		OperStatus  string
+		// Speed is the interface speed in bits per second, 0 when unknown.
+		Speed int64
+
+		InBps  float64
+		OutBps float64
+		// InUtilisation and OutUtilisation are 0 when the speed is unknown.
+		InUtilisation  float64
+		OutUtilisation float64
+
+		// InPackets and OutPackets count the unicast, broadcast and multicast packets.
+		InPackets  float64
+		OutPackets float64
+		InErrors   float64
+		OutErrors  float64
+		InDiscards float64
+		// OutDiscards usually counts the packets dropped by full output queues.
+		OutDiscards float64
+
+		// InErrorRatio and OutErrorRatio are the errors per packet received or sent,
+		// including the erroneous packets.
+		InErrorRatio  float64
+		OutErrorRatio float64
+		// InBroadcastRatio and OutBroadcastRatio are the broadcast packets per packet.
+		// They are 0 on devices that don't report broadcast counters.
+		InBroadcastRatio  float64
+		OutBroadcastRatio float64
+	}
+)
+
+// Load returns the statistics of every port of the LibreNMS instance behind client. The
+// ports are streamed with Columns, so that memory use stays flat on large installations.
+func Load(client *librenms.Client, opts *LoadOptions) ([]Stats, error) {
+	if opts == nil {
+		opts = &LoadOptions{}
+	}
+
+	hostnames := make(map[int]string)
+	if !opts.SkipDevices {
+		devices, err := client.Device.List(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list devices: %w", err)
+		}
+		for _, device := range devices.Devices {
+			hostnames[int(device.DeviceID)] = string(device.Hostname)
+		}
+	}
+
+	var stats []Stats
+	err := client.Port.StreamAllPorts(&types.PortsQueryParams{Columns: Columns}, func(port types.Port) error {
+		if !opts.IncludeIgnored && (port.Ignore != 0 || port.Disabled != 0 || port.Deleted != 0) {
+			return nil
+		}
+		s := Analyze(port)
+		s.Hostname = hostnames[s.DeviceID]
+		stats = append(stats, s)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ports: %w", err)
+	}
+	return stats, nil
+}
+
+// Analyze computes the statistics of a port. The hostname is left empty.
+func Analyze(port types.Port) Stats {
+	period := int64(port.PollPeriod)
+	s := Stats{
+		PortID:      int(port.PortID),
+		DeviceID:    int(port.DeviceID),
+		Port:        string(port.IfName),
+		Description: string(port.IfAlias),
+		OperStatus:  string(port.IfOperStatus),
+		Speed:       Speed(port),
+		InBps:       8 * rate(port.IfInOctetsRate, port.IfInOctetsDelta, period),
+		OutBps:      8 * rate(port.IfOutOctetsRate, port.IfOutOctetsDelta, period),
+		InErrors:    rate(port.IfInErrorsRate, port.IfInErrorsDelta, period),
+		OutErrors:   rate(port.IfOutErrorsRate, port.IfOutErrorsDelta, period),
+		InDiscards:  rate(port.IfInDiscardsRate, port.IfInDiscardsDelta, period),
+		OutDiscards: rate(port.IfOutDiscardsRate, port.IfOutDiscardsDelta, period),
+	}
+	s.InUtilisation = percent(s.InBps, float64(s.Speed))
+	s.OutUtilisation = percent(s.OutBps, float64(s.Speed))
+
+	inBroadcast, outBroadcast := float64(port.IfInBroadcastPktsRate), float64(port.IfOutBroadcastPktsRate)
+	s.InPackets = packets(port.IfInUcastPktsRate, port.IfInNUcastPktsRate, port.IfInBroadcastPktsRate, port.IfInMulticastPktsRate)
+	s.OutPackets = packets(port.IfOutUcastPktsRate, port.IfOutNUcastPktsRate, port.IfOutBroadcastPktsRate, port.IfOutMulticastPktsRate)
+	s.InErrorRatio = percent(s.InErrors, s.InPackets+s.InErrors)
+	s.OutErrorRatio = percent(s.OutErrors, s.OutPackets+s.OutErrors)
+	s.InBroadcastRatio = percent(inBroadcast, s.InPackets)
+	s.OutBroadcastRatio = percent(outBroadcast, s.OutPackets)
+	return s
+}
+
+// Speed returns the speed of a port in bits per second. ifSpeed is a 32-bit gauge that
+// saturates above 4.29 Gbps, so ifHighSpeed, in Mbps, is preferred when it is larger.
+func Speed(port types.Port) int64 {
+	speed := int64(port.IfSpeed)
+	if high := int64(port.IfHighSpeed) * 1_000_000; high > speed {
+		return high
+	}
+	return speed
+}
+
+// Utilisation returns the highest of the inbound and outbound utilisation.
+func (s Stats) Utilisation() float64 {
+	return max(s.InUtilisation, s.OutUtilisation)
+}
+
+// Value returns the value of a metric, or 0 for unknown metrics.
+func (s Stats) Value(metric Metric) float64 {
+	switch metric {
+	case MetricTraffic:
+		return s.InBps + s.OutBps
+	case MetricUtilisation:
+		return s.Utilisation()
+	case MetricErrors:
+		return s.InErrors + s.OutErrors
+	case MetricErrorRatio:
+		return max(s.InErrorRatio, s.OutErrorRatio)
+	case MetricDiscards:
+		return s.InDiscards + s.OutDiscards
+	case MetricBroadcast:
+		return max(s.InBroadcastRatio, s.OutBroadcastRatio)
+	default:
+		return 0
+	}
+}
+
+// Top returns the n ports with the highest value of metric, highest first. Ports where the
+// value is 0 are left out, so fewer than n ports are returned when few ports have errors.
+// Ties are ordered by hostname and port name. n <= 0 returns every port.
+func Top(stats []Stats, metric Metric, n int) []Stats {
+	top := make([]Stats, 0, len(stats))
+	for _, s := range stats {
+		if s.Value(metric) > 0 {
+			top = append(top, s)
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool {
+		a, b := top[i].Value(metric), top[j].Value(metric)
+		if a != b {
+			return a > b
+		}
+		if top[i].Hostname != top[j].Hostname {
+			return top[i].Hostname < top[j].Hostname
+		}
+		return top[i].Port < top[j].Port
+	})
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// rate returns a per second rate, derived from the delta over the poll period when
+// LibreNMS didn't compute it.
+func rate(rate types.Float64, delta types.Int64, period int64) float64 {
+	if rate != 0 || period <= 0 {
+		return float64(rate)
+	}
+	return float64(delta) / float64(period)
+}
+
+// packets returns the packet rate of a direction. Broadcast and multicast counters
+// replace the deprecated non-unicast counter when the device reports them.
+func packets(unicast, nonUnicast, broadcast, multicast types.Float64) float64 {
+	if broadcast != 0 || multicast != 0 {
+		return float64(unicast + broadcast + multicast)
+	}
+	return float64(unicast + nonUnicast)
+}
+
+// percent returns part as a percentage of total, or 0 when total is 0.
+func percent(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return 100 * part / total
+}
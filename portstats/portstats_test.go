@@ -0,0 +1,121 @@
+package portstats_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/portstats"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+var ports = []types.Port{
+	// A 10G uplink at 40% inbound, where ifSpeed saturates.
+	{
+		PortID: 1, DeviceID: 1, IfName: "Te1/1/1", IfOperStatus: "up", IfSpeed: 4294967295, IfHighSpeed: 10000,
+		IfInOctetsRate: 500_000_000, IfOutOctetsRate: 125_000_000,
+		IfInUcastPktsRate: 900, IfInBroadcastPktsRate: 50, IfInMulticastPktsRate: 50, IfOutUcastPktsRate: 1000,
+	},
+	// A 1G access port with errors and a broadcast storm, reporting non-unicast packets only.
+	{
+		PortID: 2, DeviceID: 2, IfName: "Gi1/0/5", IfAlias: "desk 4.12", IfOperStatus: "up", IfSpeed: 1_000_000_000, IfHighSpeed: 1000,
+		IfInOctetsRate: 12_500_000, IfInUcastPktsRate: 60, IfInNUcastPktsRate: 40, IfInErrorsRate: 25,
+	},
+	// A 1G port polled without rates, with output drops.
+	{
+		PortID: 3, DeviceID: 2, IfName: "Gi1/0/6", IfOperStatus: "up", IfSpeed: 1_000_000_000, PollPeriod: 300,
+		IfOutOctetsDelta: 7_500_000_000, IfOutDiscardsDelta: 600,
+	},
+	// An ignored port.
+	{PortID: 4, DeviceID: 2, IfName: "Gi1/0/7", Ignore: 1, IfInErrorsRate: 1000},
+}
+
+func TestAnalyze(t *testing.T) {
+	r := require.New(t)
+
+	uplink := portstats.Analyze(ports[0])
+	r.Equal(int64(10_000_000_000), uplink.Speed, "Expected the speed from ifHighSpeed")
+	r.Equal(4e9, uplink.InBps, "Unexpected inbound bits per second")
+	r.Equal(1e9, uplink.OutBps, "Unexpected outbound bits per second")
+	r.InDelta(40, uplink.InUtilisation, 1e-9, "Unexpected inbound utilisation")
+	r.InDelta(10, uplink.OutUtilisation, 1e-9, "Unexpected outbound utilisation")
+	r.InDelta(40, uplink.Utilisation(), 1e-9, "Expected the highest utilisation")
+	r.Equal(1000.0, uplink.InPackets, "Expected the broadcast and multicast packets")
+	r.InDelta(5, uplink.InBroadcastRatio, 1e-9, "Unexpected broadcast ratio")
+	r.Zero(uplink.InErrorRatio, "Expected no errors")
+
+	access := portstats.Analyze(ports[1])
+	r.Equal(int64(1_000_000_000), access.Speed, "Expected the speed from ifSpeed")
+	r.InDelta(10, access.InUtilisation, 1e-9, "Unexpected inbound utilisation")
+	r.Equal(100.0, access.InPackets, "Expected the non-unicast packets")
+	r.InDelta(20, access.InErrorRatio, 1e-9, "Expected the errors per packet received")
+	r.Zero(access.InBroadcastRatio, "Expected no broadcast ratio without broadcast counters")
+	r.Zero(access.OutErrorRatio, "Expected no ratio without packets")
+
+	polled := portstats.Analyze(ports[2])
+	r.Equal(2e8, polled.OutBps, "Expected the rate from the delta")
+	r.Equal(2.0, polled.OutDiscards, "Expected the discards from the delta")
+
+	unknown := portstats.Analyze(types.Port{IfInOctetsRate: 1000})
+	r.Zero(unknown.InUtilisation, "Expected no utilisation without speed")
+}
+
+func TestTop(t *testing.T) {
+	r := require.New(t)
+
+	stats := make([]portstats.Stats, 0, len(ports))
+	for _, port := range ports {
+		stats = append(stats, portstats.Analyze(port))
+	}
+
+	ids := func(stats []portstats.Stats) []int {
+		ids := make([]int, 0, len(stats))
+		for _, s := range stats {
+			ids = append(ids, s.PortID)
+		}
+		return ids
+	}
+	r.Equal([]int{1, 3, 2}, ids(portstats.Top(stats, portstats.MetricTraffic, 0)), "Unexpected order by traffic")
+	r.Equal([]int{1, 3}, ids(portstats.Top(stats, portstats.MetricUtilisation, 2)), "Expected the two busiest ports")
+	r.Equal([]int{4, 2}, ids(portstats.Top(stats, portstats.MetricErrors, 5)), "Expected only the ports with errors")
+	r.Equal([]int{3}, ids(portstats.Top(stats, portstats.MetricDiscards, 5)), "Expected only the port with discards")
+	r.Equal([]int{1}, ids(portstats.Top(stats, portstats.MetricBroadcast, 5)), "Expected only the port with broadcast counters")
+	r.Empty(portstats.Top(stats, "unknown", 5), "Expected no ports for an unknown metric")
+}
+
+func TestLoad(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{DeviceID: 1, Hostname: "core1"})
+	srv.AddDevice(types.Device{DeviceID: 2, Hostname: "sw1"})
+	for _, port := range ports {
+		srv.AddPort(port)
+	}
+
+	stats, err := portstats.Load(srv.Client(), nil)
+	r.NoError(err, "Load returned an error")
+	r.Len(stats, 3, "Expected the ignored port to be left out")
+	r.Equal("sw1", stats[1].Hostname, "Expected the hostname")
+	r.InDelta(20, stats[1].InErrorRatio, 1e-9, "Expected the rates in the columns")
+
+	var query string
+	for _, req := range srv.Requests() {
+		if req.Path == "ports" {
+			query = req.Query
+		}
+	}
+	values, err := url.ParseQuery(query)
+	r.NoError(err, "Failed to parse the query")
+	r.Equal(portstats.Columns, values.Get("columns"), "Expected only the needed columns")
+
+	stats, err = portstats.Load(srv.Client(), &portstats.LoadOptions{IncludeIgnored: true, SkipDevices: true})
+	r.NoError(err, "Load returned an error")
+	r.Len(stats, 4, "Expected every port")
+	r.Empty(stats[0].Hostname, "Expected no hostname")
+
+	srv.InjectError("ports", 500, 1)
+	_, err = portstats.Load(srv.Client(), nil)
+	r.ErrorContains(err, "failed to list ports", "Expected the API error")
+}
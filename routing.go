@@ -32,6 +32,7 @@ const (
 	ipAddressesEndpoint        = "resources/ip/addresses"
 	ipNetworksEndpoint         = "resources/ip/networks"
 	ipNetworkAddressesEndpoint = "resources/ip/networks"
+	arpEndpoint                = "resources/ip/arp"
 
 	// BGP counters endpoint
 	bgpCountersEndpoint = "routing/bgp/cbgp"
@@ -45,7 +46,7 @@ func (r *RoutingAPI) ListBGP(query *types.BGPQuery) (*types.BGPResponse, error)
 		return nil, err
 	}
 
-	req, err := c.newRequest(http.MethodGet, bgpEndpoint, nil, params)
+	req, err := c.newRequest(opRoutingListBGP, http.MethodGet, bgpEndpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +58,7 @@ func (r *RoutingAPI) ListBGP(query *types.BGPQuery) (*types.BGPResponse, error)
 // GetBGP retrieves a BGP session by ID from the LibreNMS API
 func (r *RoutingAPI) GetBGP(id string) (*types.BGPSessionResponse, error) {
 	c := r.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s", bgpEndpoint, id), nil, nil)
+	req, err := c.newRequest(opRoutingGetBGP, http.MethodGet, fmt.Sprintf("%s/%s", bgpEndpoint, id), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +70,7 @@ func (r *RoutingAPI) GetBGP(id string) (*types.BGPSessionResponse, error) {
 // UpdateBGPDescription updates the description of a BGP session
 func (r *RoutingAPI) UpdateBGPDescription(id string, payload *types.BGPDescriptionUpdate) (*types.BaseResponse, error) {
 	c := r.client
-	req, err := c.newRequest(http.MethodPost, fmt.Sprintf("%s/%s", bgpEndpoint, id), payload, nil)
+	req, err := c.newRequest(opRoutingUpdateBGPDescription, http.MethodPost, fmt.Sprintf("%s/%s", bgpEndpoint, id), payload, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +89,7 @@ func (r *RoutingAPI) ListBGPCounters(hostname string) (*types.BGPCountersRespons
 		params = &p
 	}
 
-	req, err := c.newRequest(http.MethodGet, bgpCountersEndpoint, nil, params)
+	req, err := c.newRequest(opRoutingListBGPCounters, http.MethodGet, bgpCountersEndpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +106,7 @@ func (r *RoutingAPI) ListIPAddresses(addressFamily string) (*types.IPAddressesRe
 		endpoint = fmt.Sprintf("%s/%s", ipAddressesEndpoint, addressFamily)
 	}
 
-	req, err := c.newRequest(http.MethodGet, endpoint, nil, nil)
+	req, err := c.newRequest(opRoutingListIPAddresses, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -114,12 +115,29 @@ func (r *RoutingAPI) ListIPAddresses(addressFamily string) (*types.IPAddressesRe
 	return addressesResp, c.do(req, addressesResp)
 }
 
+// StreamIPAddresses is like ListIPAddresses but decodes the addresses one at a time and calls
+// fn for each of them, so that memory use stays flat on large installations. It stops at
+// the first error returned by fn and returns it.
+func (r *RoutingAPI) StreamIPAddresses(addressFamily string, fn func(types.IPAddress) error) error {
+	c := r.client
+	endpoint := ipAddressesEndpoint
+	if addressFamily != "" {
+		endpoint = fmt.Sprintf("%s/%s", ipAddressesEndpoint, addressFamily)
+	}
+
+	req, err := c.newRequest(opRoutingStreamIPAddresses, http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+	return streamArray(c, req, "ip_addresses", fn)
+}
+
 // GetNetworkIPAddresses retrieves IP addresses for a specific network
 func (r *RoutingAPI) GetNetworkIPAddresses(networkID string) (*types.IPAddressesResponse, error) {
 	c := r.client
 	endpoint := fmt.Sprintf("%s/%s/ip", ipNetworkAddressesEndpoint, networkID)
 
-	req, err := c.newRequest(http.MethodGet, endpoint, nil, nil)
+	req, err := c.newRequest(opRoutingGetNetworkIPAddresses, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -128,6 +146,27 @@ func (r *RoutingAPI) GetNetworkIPAddresses(networkID string) (*types.IPAddresses
 	return addressesResp, c.do(req, addressesResp)
 }
 
+// ListARP retrieves the ARP entries matching query, which is an IP address, a MAC address,
+// a CIDR network, or "all" together with a device hostname or ID.
+//
+// Documentation: https://docs.librenms.org/API/ARP/#list_arp
+// Route: /api/v0/resources/ip/arp/:query
+func (r *RoutingAPI) ListARP(query, device string) (*types.ARPResponse, error) {
+	c := r.client
+	var params *url.Values
+	if device != "" {
+		params = &url.Values{"device": {device}}
+	}
+
+	req, err := c.newRequest(opRoutingListARP, http.MethodGet, fmt.Sprintf("%s/%s", arpEndpoint, query), nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	arpResp := new(types.ARPResponse)
+	return arpResp, c.do(req, arpResp)
+}
+
 // ListIPNetworks retrieves a list of IP networks from the LibreNMS API
 func (r *RoutingAPI) ListIPNetworks(addressFamily string) (*types.IPNetworksResponse, error) {
 	c := r.client
@@ -136,7 +175,7 @@ func (r *RoutingAPI) ListIPNetworks(addressFamily string) (*types.IPNetworksResp
 		endpoint = fmt.Sprintf("%s/%s", ipNetworksEndpoint, addressFamily)
 	}
 
-	req, err := c.newRequest(http.MethodGet, endpoint, nil, nil)
+	req, err := c.newRequest(opRoutingListIPNetworks, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -150,7 +189,7 @@ func (r *RoutingAPI) ListIPSec(hostname string) (*types.IPSecResponse, error) {
 	c := r.client
 	endpoint := fmt.Sprintf("%s/%s", ipsecEndpoint, hostname)
 
-	req, err := c.newRequest(http.MethodGet, endpoint, nil, nil)
+	req, err := c.newRequest(opRoutingListIPSec, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -169,7 +208,7 @@ func (r *RoutingAPI) ListOSPF(hostname string) (*types.OSPFResponse, error) {
 		params = &p
 	}
 
-	req, err := c.newRequest(http.MethodGet, ospfEndpoint, nil, params)
+	req, err := c.newRequest(opRoutingListOSPF, http.MethodGet, ospfEndpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -181,7 +220,7 @@ func (r *RoutingAPI) ListOSPF(hostname string) (*types.OSPFResponse, error) {
 // ListOSPFPorts retrieves a list of OSPF ports from the LibreNMS API
 func (r *RoutingAPI) ListOSPFPorts() (*types.OSPFPortsResponse, error) {
 	c := r.client
-	req, err := c.newRequest(http.MethodGet, ospfPortsEndpoint, nil, nil)
+	req, err := c.newRequest(opRoutingListOSPFPorts, http.MethodGet, ospfPortsEndpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +239,7 @@ func (r *RoutingAPI) ListOSPFv3(hostname string) (*types.OSPFv3Response, error)
 		params = &p
 	}
 
-	req, err := c.newRequest(http.MethodGet, ospfv3Endpoint, nil, params)
+	req, err := c.newRequest(opRoutingListOSPFv3, http.MethodGet, ospfv3Endpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -212,7 +251,7 @@ func (r *RoutingAPI) ListOSPFv3(hostname string) (*types.OSPFv3Response, error)
 // ListOSPFv3Ports retrieves a list of OSPFv3 ports from the LibreNMS API
 func (r *RoutingAPI) ListOSPFv3Ports() (*types.OSPFv3PortsResponse, error) {
 	c := r.client
-	req, err := c.newRequest(http.MethodGet, ospfv3PortsEndpoint, nil, nil)
+	req, err := c.newRequest(opRoutingListOSPFv3Ports, http.MethodGet, ospfv3PortsEndpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -229,7 +268,7 @@ func (r *RoutingAPI) ListVRF(query *types.VRFQuery) (*types.VRFResponse, error)
 		return nil, err
 	}
 
-	req, err := c.newRequest(http.MethodGet, vrfEndpoint, nil, params)
+	req, err := c.newRequest(opRoutingListVRF, http.MethodGet, vrfEndpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -241,7 +280,7 @@ func (r *RoutingAPI) ListVRF(query *types.VRFQuery) (*types.VRFResponse, error)
 // GetVRF retrieves a VRF by ID from the LibreNMS API
 func (r *RoutingAPI) GetVRF(id string) (*types.VRFResponse, error) {
 	c := r.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s", vrfEndpoint, id), nil, nil)
+	req, err := c.newRequest(opRoutingGetVRF, http.MethodGet, fmt.Sprintf("%s/%s", vrfEndpoint, id), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -260,7 +299,7 @@ func (r *RoutingAPI) ListMPLSServices(hostname string) (*types.MPLSServicesRespo
 		params = &p
 	}
 
-	req, err := c.newRequest(http.MethodGet, mplsServicesEndpoint, nil, params)
+	req, err := c.newRequest(opRoutingListMPLSServices, http.MethodGet, mplsServicesEndpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -279,7 +318,7 @@ func (r *RoutingAPI) ListMPLSSAPs(hostname string) (*types.MPLSSAPsResponse, err
 		params = &p
 	}
 
-	req, err := c.newRequest(http.MethodGet, mplsSapsEndpoint, nil, params)
+	req, err := c.newRequest(opRoutingListMPLSSAPs, http.MethodGet, mplsSapsEndpoint, nil, params)
 	if err != nil {
 		return nil, err
 	}
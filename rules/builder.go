@@ -0,0 +1,374 @@
+// Package rules builds, parses and validates the jQuery QueryBuilder rule trees used by
+// LibreNMS alert rules (the "builder" field) and dynamic device groups.
+//
+// Both use the same structure, modelled by types.DeviceGroupRuleContainer. A rule tree can
+// be composed with a small DSL:
+//
+//	expr := rules.Rule("devices.status").Equal(0).
+//		And(rules.Rule("devices.disabled").Equal(0), rules.Rule("devices.ignore").Equal(0))
+//	builder, err := expr.JSON()
+//
+// or parsed from a text expression, see Parse and Format:
+//
+//	container, err := rules.Parse(`devices.os = "ios" AND locations.location LIKE "DC1%"`)
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/javen-yan/librenms-go/types"
+)
+
+type (
+	// Expr is a node of a rule tree, either a *Condition or a *Group.
+	Expr interface {
+		// And combines the expression with others into an AND group.
+		And(exprs ...Expr) Expr
+		// Or combines the expression with others into an OR group.
+		Or(exprs ...Expr) Expr
+		// Build validates the tree and converts it into a rule container.
+		Build() (*types.DeviceGroupRuleContainer, error)
+		// JSON validates the tree and encodes it as builder JSON.
+		JSON() (string, error)
+
+		rule() (types.DeviceGroupRule, error)
+	}
+
+	// Condition is a terminal rule comparing a field with zero or more values.
+	Condition struct {
+		Field    string
+		Operator string
+		Values   []string
+		// Type is the builder field type, derived from the field catalog or the values.
+		Type string
+		// Input is the builder input widget, derived from the field when empty.
+		Input string
+	}
+
+	// Group combines rules with AND or OR.
+	Group struct {
+		Condition string
+		Rules     []Expr
+	}
+
+	// FieldRef starts a condition on a field; see Rule.
+	FieldRef struct {
+		field string
+	}
+)
+
+// Rule starts a condition on the given field, e.g. Rule("devices.os").Equal("ios").
+func Rule(field string) *FieldRef {
+	return &FieldRef{field: field}
+}
+
+// And combines expressions into an AND group.
+func And(exprs ...Expr) Expr {
+	return &Group{Condition: CondAnd, Rules: exprs}
+}
+
+// Or combines expressions into an OR group.
+func Or(exprs ...Expr) Expr {
+	return &Group{Condition: CondOr, Rules: exprs}
+}
+
+// Equal matches fields equal to v.
+func (f *FieldRef) Equal(v any) *Condition { return f.op(OpEqual, v) }
+
+// NotEqual matches fields not equal to v.
+func (f *FieldRef) NotEqual(v any) *Condition { return f.op(OpNotEqual, v) }
+
+// In matches fields equal to any of vs.
+func (f *FieldRef) In(vs ...any) *Condition { return f.op(OpIn, vs...) }
+
+// NotIn matches fields equal to none of vs.
+func (f *FieldRef) NotIn(vs ...any) *Condition { return f.op(OpNotIn, vs...) }
+
+// Less matches fields less than v.
+func (f *FieldRef) Less(v any) *Condition { return f.op(OpLess, v) }
+
+// LessOrEqual matches fields less than or equal to v.
+func (f *FieldRef) LessOrEqual(v any) *Condition { return f.op(OpLessOrEqual, v) }
+
+// Greater matches fields greater than v.
+func (f *FieldRef) Greater(v any) *Condition { return f.op(OpGreater, v) }
+
+// GreaterOrEqual matches fields greater than or equal to v.
+func (f *FieldRef) GreaterOrEqual(v any) *Condition { return f.op(OpGreaterOrEqual, v) }
+
+// Between matches fields in the inclusive range [low, high].
+func (f *FieldRef) Between(low, high any) *Condition { return f.op(OpBetween, low, high) }
+
+// NotBetween matches fields outside the inclusive range [low, high].
+func (f *FieldRef) NotBetween(low, high any) *Condition { return f.op(OpNotBetween, low, high) }
+
+// BeginsWith matches fields starting with s.
+func (f *FieldRef) BeginsWith(s string) *Condition { return f.op(OpBeginsWith, s) }
+
+// NotBeginsWith matches fields not starting with s.
+func (f *FieldRef) NotBeginsWith(s string) *Condition { return f.op(OpNotBeginsWith, s) }
+
+// Contains matches fields containing s.
+func (f *FieldRef) Contains(s string) *Condition { return f.op(OpContains, s) }
+
+// NotContains matches fields not containing s.
+func (f *FieldRef) NotContains(s string) *Condition { return f.op(OpNotContains, s) }
+
+// EndsWith matches fields ending with s.
+func (f *FieldRef) EndsWith(s string) *Condition { return f.op(OpEndsWith, s) }
+
+// NotEndsWith matches fields not ending with s.
+func (f *FieldRef) NotEndsWith(s string) *Condition { return f.op(OpNotEndsWith, s) }
+
+// Regex matches fields against the regular expression re.
+func (f *FieldRef) Regex(re string) *Condition { return f.op(OpRegex, re) }
+
+// NotRegex matches fields not matching the regular expression re.
+func (f *FieldRef) NotRegex(re string) *Condition { return f.op(OpNotRegex, re) }
+
+// IsEmpty matches empty fields.
+func (f *FieldRef) IsEmpty() *Condition { return f.op(OpIsEmpty) }
+
+// IsNotEmpty matches non-empty fields.
+func (f *FieldRef) IsNotEmpty() *Condition { return f.op(OpIsNotEmpty) }
+
+// IsNull matches NULL fields.
+func (f *FieldRef) IsNull() *Condition { return f.op(OpIsNull) }
+
+// IsNotNull matches non-NULL fields.
+func (f *FieldRef) IsNotNull() *Condition { return f.op(OpIsNotNull) }
+
+// op creates a condition, deriving the field type from the catalog or the first value.
+func (f *FieldRef) op(operator string, vs ...any) *Condition {
+	c := &Condition{Field: f.field, Operator: operator}
+	c.Type, _ = FieldType(f.field)
+	for _, v := range vs {
+		c.Values = append(c.Values, formatValue(v))
+		if c.Type == "" {
+			c.Type = valueType(v)
+		}
+	}
+	if c.Type == "" {
+		c.Type = TypeString
+	}
+	return c
+}
+
+// And combines the condition with others into an AND group.
+func (c *Condition) And(exprs ...Expr) Expr { return combine(CondAnd, c, exprs) }
+
+// Or combines the condition with others into an OR group.
+func (c *Condition) Or(exprs ...Expr) Expr { return combine(CondOr, c, exprs) }
+
+// Build validates the condition and wraps it in a rule container.
+func (c *Condition) Build() (*types.DeviceGroupRuleContainer, error) { return build(c) }
+
+// JSON validates the condition and encodes it as builder JSON.
+func (c *Condition) JSON() (string, error) { return encode(c) }
+
+func (c *Condition) rule() (types.DeviceGroupRule, error) {
+	if err := validateField(c.Field); err != nil {
+		return types.DeviceGroupRule{}, err
+	}
+	if err := validateOperator(c.Operator, c.Type, len(c.Values)); err != nil {
+		return types.DeviceGroupRule{}, fmt.Errorf("%s: %w", c.Field, err)
+	}
+
+	r := types.DeviceGroupRule{
+		ID:       c.Field,
+		Field:    c.Field,
+		Type:     c.Type,
+		Input:    c.Input,
+		Operator: c.Operator,
+	}
+	if r.Input == "" {
+		r.Input = defaultInput(c.Field, c.Type)
+	}
+	if n, _ := ValueCount(c.Operator); n == 1 {
+		r.Value = c.Values[0]
+	} else if n != 0 {
+		r.Values = c.Values
+	}
+	return r, nil
+}
+
+// And combines the group with others into an AND group. An AND group is extended
+// with the other expressions instead of being nested.
+func (g *Group) And(exprs ...Expr) Expr { return combine(CondAnd, g, exprs) }
+
+// Or combines the group with others into an OR group. An OR group is extended
+// with the other expressions instead of being nested.
+func (g *Group) Or(exprs ...Expr) Expr { return combine(CondOr, g, exprs) }
+
+// Build validates the group and converts it into a rule container.
+func (g *Group) Build() (*types.DeviceGroupRuleContainer, error) { return build(g) }
+
+// JSON validates the group and encodes it as builder JSON.
+func (g *Group) JSON() (string, error) { return encode(g) }
+
+func (g *Group) rule() (types.DeviceGroupRule, error) {
+	if g.Condition != CondAnd && g.Condition != CondOr {
+		return types.DeviceGroupRule{}, fmt.Errorf("invalid group condition %q", g.Condition)
+	}
+	if len(g.Rules) == 0 {
+		return types.DeviceGroupRule{}, errors.New("empty rule group")
+	}
+
+	r := types.DeviceGroupRule{Condition: g.Condition}
+	var errs []error
+	for _, expr := range g.Rules {
+		child, err := expr.rule()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		r.Rules = append(r.Rules, child)
+	}
+	return r, errors.Join(errs...)
+}
+
+// combine groups expr with others. If expr already is a group of the same kind, a copy
+// of it is extended so the original tree is left untouched.
+func combine(condition string, expr Expr, others []Expr) Expr {
+	if g, ok := expr.(*Group); ok && g.Condition == condition {
+		rules := make([]Expr, 0, len(g.Rules)+len(others))
+		return &Group{Condition: condition, Rules: append(append(rules, g.Rules...), others...)}
+	}
+	return &Group{Condition: condition, Rules: append([]Expr{expr}, others...)}
+}
+
+// build converts an expression into a container. A single condition is wrapped in an AND group.
+func build(expr Expr) (*types.DeviceGroupRuleContainer, error) {
+	r, err := expr.rule()
+	if err != nil {
+		return nil, err
+	}
+	if r.Condition == "" {
+		return &types.DeviceGroupRuleContainer{Condition: CondAnd, Rules: []types.DeviceGroupRule{r}, Valid: true}, nil
+	}
+	return &types.DeviceGroupRuleContainer{Condition: r.Condition, Rules: r.Rules, Valid: true}, nil
+}
+
+// encode builds an expression and serializes it as builder JSON.
+func encode(expr Expr) (string, error) {
+	container, err := build(expr)
+	if err != nil {
+		return "", err
+	}
+	return container.JSON()
+}
+
+// ParseBuilder parses builder JSON, as found in AlertRule.Builder, into a rule tree that can
+// be edited and re-encoded.
+func ParseBuilder(builder string) (Expr, error) {
+	rule := types.AlertRule{Builder: types.String(builder)}
+	container, err := rule.ParseBuilder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse builder: %w", err)
+	}
+	return FromContainer(container)
+}
+
+// FromContainer converts a rule container into a rule tree, validating it on the way.
+func FromContainer(container *types.DeviceGroupRuleContainer) (Expr, error) {
+	condition := container.Condition
+	if condition == "" {
+		condition = CondAnd
+	}
+	return fromRule(types.DeviceGroupRule{Condition: condition, Rules: container.Rules})
+}
+
+// Validate checks the fields and operators of a rule container.
+func Validate(container *types.DeviceGroupRuleContainer) error {
+	expr, err := FromContainer(container)
+	if err != nil {
+		return err
+	}
+	_, err = expr.rule()
+	return err
+}
+
+// fromRule converts a single rule (terminal or group) into an expression.
+func fromRule(r types.DeviceGroupRule) (Expr, error) {
+	if r.Condition == "" && r.Field == "" && len(r.Rules) > 0 {
+		r.Condition = CondAnd
+	}
+	if r.Condition != "" {
+		g := &Group{Condition: strings.ToUpper(r.Condition)}
+		for _, child := range r.Rules {
+			expr, err := fromRule(child)
+			if err != nil {
+				return nil, err
+			}
+			g.Rules = append(g.Rules, expr)
+		}
+		return g, nil
+	}
+
+	field := r.Field
+	if field == "" {
+		field = r.ID
+	}
+	c := &Condition{Field: field, Operator: r.Operator, Type: r.Type, Input: r.Input}
+	n, ok := ValueCount(r.Operator)
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("%s: unknown operator %q", field, r.Operator)
+	case len(r.Values) > 0:
+		c.Values = r.Values
+	case n != 0:
+		c.Values = []string{r.Value}
+	}
+	if _, err := c.rule(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// defaultInput returns the builder input widget for a field.
+func defaultInput(field, fieldType string) string {
+	switch {
+	case strings.HasPrefix(field, "macros."):
+		return "radio"
+	case fieldType == TypeInteger || fieldType == TypeDouble:
+		return "number"
+	default:
+		return "text"
+	}
+}
+
+// formatValue converts a Go value into its builder string form. Booleans become 1/0.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case types.Bool:
+		return formatValue(bool(v))
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// valueType derives the builder field type from a Go value.
+func valueType(v any) string {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, bool, types.Bool:
+		return TypeInteger
+	case float32, float64, types.Float64:
+		return TypeDouble
+	default:
+		return TypeString
+	}
+}
@@ -0,0 +1,122 @@
+package rules_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/rules"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_JSON(t *testing.T) {
+	r := require.New(t)
+
+	builder, err := rules.Rule("macros.port_down").Equal(1).JSON()
+	r.NoError(err, "JSON returned an error")
+	r.Equal(`{"condition":"AND","rules":[{"id":"macros.port_down","field":"macros.port_down","input":"radio","operator":"equal","type":"integer","value":"1"}],"valid":true}`,
+		builder, "Unexpected builder JSON")
+}
+
+func TestBuilder_Nested(t *testing.T) {
+	r := require.New(t)
+
+	expr := rules.Rule("devices.status").Equal(0).
+		And(rules.Rule("devices.disabled").Equal(false)).
+		And(rules.Rule("devices.os").In("ios", "iosxe").Or(rules.Rule("devices.hostname").BeginsWith("core")))
+
+	container, err := expr.Build()
+	r.NoError(err, "Build returned an error")
+	r.Equal(rules.CondAnd, container.Condition, "Expected AND at the root")
+	r.Len(container.Rules, 3, "Chained AND should be flattened")
+
+	status := container.Rules[0]
+	r.Equal("devices.status", status.Field, "Unexpected field")
+	r.Equal(rules.TypeInteger, status.Type, "Type should come from the field catalog")
+	r.Equal("number", status.Input, "Numeric fields should use a number input")
+	r.Equal("0", status.Value, "Unexpected value")
+	r.Equal("0", container.Rules[1].Value, "Booleans should be encoded as 0/1")
+
+	or := container.Rules[2]
+	r.Equal(rules.CondOr, or.Condition, "Expected nested OR group")
+	r.Equal([]string{"ios", "iosxe"}, or.Rules[0].Values, "Expected multiple values for in")
+}
+
+func TestBuilder_ValuesEncodedAsArray(t *testing.T) {
+	r := require.New(t)
+
+	builder, err := rules.Rule("devices.uptime").Between(0, 300).JSON()
+	r.NoError(err, "JSON returned an error")
+	r.Contains(builder, `"value":["0","300"]`, "Between values should be encoded as an array")
+
+	container := new(types.DeviceGroupRuleContainer)
+	r.NoError(json.Unmarshal([]byte(builder), container), "Failed to decode builder")
+	r.Equal([]string{"0", "300"}, container.Rules[0].Values, "Values should decode from an array")
+}
+
+func TestBuilder_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		expr rules.Expr
+		err  string
+	}{
+		{"invalid field", rules.Rule("hostname").Equal("x"), "expected table.column"},
+		{"unknown column", rules.Rule("devices.nope").Equal("x"), `unknown field "devices.nope"`},
+		{"string operator on number", rules.Rule("devices.status").Contains("1"), "requires a string field"},
+		{"missing values", rules.Rule("devices.os").In(), "at least one value"},
+		{"unknown operator", &rules.Condition{Field: "devices.os", Operator: "like", Values: []string{"x"}}, `unknown operator "like"`},
+		{"empty group", rules.And(), "empty rule group"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.expr.JSON()
+			require.ErrorContains(t, err, tt.err, "Expected validation error")
+		})
+	}
+}
+
+func TestBuilder_UnknownTableAllowed(t *testing.T) {
+	r := require.New(t)
+
+	container, err := rules.Rule("access_points.channel").Equal("3").Build()
+	r.NoError(err, "Fields of tables outside the catalog should be accepted")
+	r.Equal(rules.TypeString, container.Rules[0].Type, "Type should be derived from the value")
+}
+
+func TestParseBuilder_Fixtures(t *testing.T) {
+	r := require.New(t)
+
+	data, err := os.ReadFile(filepath.Join("..", "fixtures", "get_alertrules_200.json"))
+	r.NoError(err, "Failed to read fixture")
+	resp := new(types.AlertRuleResponse)
+	r.NoError(json.Unmarshal(data, resp), "Failed to decode fixture")
+
+	for _, rule := range resp.Rules {
+		expr, err := rules.ParseBuilder(string(rule.Builder))
+		r.NoError(err, "Failed to parse builder of %q", rule.Name)
+
+		builder, err := expr.JSON()
+		r.NoError(err, "Failed to re-encode builder of %q", rule.Name)
+
+		want, err := rule.ParseBuilder()
+		r.NoError(err, "Failed to decode builder of %q", rule.Name)
+		got := new(types.DeviceGroupRuleContainer)
+		r.NoError(json.Unmarshal([]byte(builder), got), "Failed to decode re-encoded builder")
+		r.Equal(want.Rules, got.Rules, "Builder of %q should round-trip", rule.Name)
+	}
+}
+
+func TestParseBuilder_Edit(t *testing.T) {
+	r := require.New(t)
+
+	expr, err := rules.ParseBuilder(`{"condition":"AND","rules":[{"id":"macros.device_down","field":"macros.device_down","type":"integer","input":"radio","operator":"equal","value":"1"}],"valid":true}`)
+	r.NoError(err, "ParseBuilder returned an error")
+
+	container, err := expr.And(rules.Rule("devices.status_reason").Equal("icmp")).Build()
+	r.NoError(err, "Build returned an error")
+	r.Len(container.Rules, 2, "Expected the added rule in the same AND group")
+	r.Equal("devices.status_reason", container.Rules[1].Field, "Unexpected added field")
+}
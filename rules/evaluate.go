@@ -0,0 +1,385 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/javen-yan/librenms-go/types"
+)
+
+type (
+	// EvaluateOptions holds the related data used to resolve locations.* and ports.* fields.
+	EvaluateOptions struct {
+		// Locations are matched to devices by location_id. When a device's location is not
+		// found, its location name (Device.Location) is used for locations.location.
+		Locations []types.Location
+		// Ports are matched to devices by device_id. They are required for rules using
+		// ports.* fields.
+		Ports []types.Port
+	}
+
+	// row is a joined device, location and port, like a row of the SQL query LibreNMS builds.
+	// Location and port are nil when the device has none.
+	row struct {
+		device   *types.Device
+		location *types.Location
+		port     *types.Port
+	}
+
+	// predicate reports whether a row matches a rule.
+	predicate func(r row) bool
+
+	// compiler converts rules into predicates and records the tables they use.
+	compiler struct {
+		usesPorts bool
+	}
+)
+
+var (
+	tableTypes = map[string]reflect.Type{
+		"devices":   reflect.TypeOf(types.Device{}),
+		"locations": reflect.TypeOf(types.Location{}),
+		"ports":     reflect.TypeOf(types.Port{}),
+	}
+
+	// columnIndexes caches the field index by JSON name for each table type.
+	columnIndexes sync.Map
+)
+
+// Evaluate returns the devices matched by a rule container, previewing the membership of a
+// dynamic device group before it is created.
+//
+// Rules may use devices.*, locations.* and ports.* fields. A rule using ports.* fields
+// matches a device when one of its ports satisfies the whole rule, the same way the SQL
+// join of LibreNMS does, and a device without ports is evaluated once with NULL port
+// fields. String comparisons are case insensitive like the default MySQL collation, and
+// values are compared numerically when both sides are numbers. Only nil pointers, invalid
+// Null values, zero Times and missing locations or ports are NULL; empty strings are not.
+//
+// Macros and fields of other tables are evaluated by LibreNMS only and return an error.
+func Evaluate(container *types.DeviceGroupRuleContainer, devices []types.Device, opts *EvaluateOptions) ([]types.Device, error) {
+	if opts == nil {
+		opts = new(EvaluateOptions)
+	}
+
+	condition := container.Condition
+	if condition == "" {
+		condition = CondAnd
+	}
+	c := new(compiler)
+	match, err := c.compile(types.DeviceGroupRule{Condition: condition, Rules: container.Rules})
+	if err != nil {
+		return nil, err
+	}
+	if c.usesPorts && opts.Ports == nil {
+		return nil, errors.New("rules use ports fields but no ports were given")
+	}
+
+	locations := make(map[int]*types.Location, len(opts.Locations))
+	for i := range opts.Locations {
+		locations[int(opts.Locations[i].ID)] = &opts.Locations[i]
+	}
+	ports := make(map[int][]*types.Port)
+	for i := range opts.Ports {
+		id := int(opts.Ports[i].DeviceID)
+		ports[id] = append(ports[id], &opts.Ports[i])
+	}
+
+	matched := make([]types.Device, 0)
+	for i := range devices {
+		device := &devices[i]
+		r := row{device: device, location: locations[int(device.LocationID)]}
+		if r.location == nil && device.Location != "" {
+			r.location = &types.Location{ID: device.LocationID, Name: device.Location}
+		}
+
+		if !c.usesPorts {
+			if match(r) {
+				matched = append(matched, *device)
+			}
+			continue
+		}
+		devicePorts := ports[int(device.DeviceID)]
+		if len(devicePorts) == 0 {
+			// Like the LEFT JOIN of LibreNMS, a device without ports is a single row whose
+			// port fields are NULL.
+			devicePorts = []*types.Port{nil}
+		}
+		for _, port := range devicePorts {
+			r.port = port
+			if match(r) {
+				matched = append(matched, *device)
+				break
+			}
+		}
+	}
+	return matched, nil
+}
+
+// DiffMembers compares predicted devices with the actual members of a device group, as
+// returned by DeviceGroupAPI.GetMembers. It returns the sorted IDs of predicted devices that
+// are not members and of members that were not predicted.
+func DiffMembers(predicted []types.Device, members []types.DeviceGroupMember) (added, removed []int) {
+	predictedIDs := make(map[int]bool, len(predicted))
+	for _, device := range predicted {
+		predictedIDs[int(device.DeviceID)] = true
+	}
+	memberIDs := make(map[int]bool, len(members))
+	for _, member := range members {
+		id := int(member.ID)
+		memberIDs[id] = true
+		if !predictedIDs[id] {
+			removed = append(removed, id)
+		}
+	}
+	for id := range predictedIDs {
+		if !memberIDs[id] {
+			added = append(added, id)
+		}
+	}
+	sort.Ints(added)
+	sort.Ints(removed)
+	return added, removed
+}
+
+// compile converts a rule (terminal or group) into a predicate.
+func (c *compiler) compile(r types.DeviceGroupRule) (predicate, error) {
+	if r.Condition == "" && r.Field == "" && r.ID == "" && len(r.Rules) > 0 {
+		r.Condition = CondAnd
+	}
+	if r.Condition == "" {
+		return c.compileRule(r)
+	}
+
+	condition := strings.ToUpper(r.Condition)
+	if condition != CondAnd && condition != CondOr {
+		return nil, fmt.Errorf("invalid group condition %q", r.Condition)
+	}
+	if len(r.Rules) == 0 {
+		return nil, errors.New("empty rule group")
+	}
+	children := make([]predicate, 0, len(r.Rules))
+	for _, child := range r.Rules {
+		p, err := c.compile(child)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, p)
+	}
+
+	if condition == CondOr {
+		return func(r row) bool {
+			for _, p := range children {
+				if p(r) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+	return func(r row) bool {
+		for _, p := range children {
+			if !p(r) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// compileRule converts a terminal rule into a predicate.
+func (c *compiler) compileRule(r types.DeviceGroupRule) (predicate, error) {
+	field := r.Field
+	if field == "" {
+		field = r.ID
+	}
+	if err := validateField(field); err != nil {
+		return nil, err
+	}
+	table, column, _ := strings.Cut(field, ".")
+	t, ok := tableTypes[table]
+	if !ok {
+		return nil, fmt.Errorf("%s: fields of table %q can't be evaluated locally", field, table)
+	}
+	index, ok := columnIndex(t)[column]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+	if table == "ports" {
+		c.usesPorts = true
+	}
+
+	values := r.Values
+	if len(values) == 0 {
+		values = []string{r.Value}
+	}
+	if n, ok := ValueCount(r.Operator); !ok {
+		return nil, fmt.Errorf("%s: unknown operator %q", field, r.Operator)
+	} else if n > 0 && len(values) != n {
+		return nil, fmt.Errorf("%s: operator %q requires %d value(s), got %d", field, r.Operator, n, len(values))
+	}
+
+	test, err := operatorTest(r.Operator, values)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+	return func(r row) bool {
+		var v reflect.Value
+		switch table {
+		case "devices":
+			v = reflect.ValueOf(r.device).Elem()
+		case "locations":
+			if r.location == nil {
+				return test("", true)
+			}
+			v = reflect.ValueOf(r.location).Elem()
+		case "ports":
+			if r.port == nil {
+				return test("", true)
+			}
+			v = reflect.ValueOf(r.port).Elem()
+		}
+		value, null := fieldValue(v.FieldByIndex(index))
+		return test(value, null)
+	}, nil
+}
+
+// operatorTest returns a function testing a field value against the operator. null is set
+// for NULL values, which only match is_null and is_not_null never does.
+func operatorTest(op string, values []string) (func(value string, null bool) bool, error) {
+	switch op {
+	case OpIsNull:
+		return func(_ string, null bool) bool { return null }, nil
+	case OpIsNotNull:
+		return func(_ string, null bool) bool { return !null }, nil
+	}
+
+	var test func(value string) bool
+	switch op {
+	case OpEqual:
+		test = func(v string) bool { return compareValues(v, values[0]) == 0 }
+	case OpNotEqual:
+		test = func(v string) bool { return compareValues(v, values[0]) != 0 }
+	case OpIn, OpNotIn:
+		test = func(v string) bool {
+			for _, want := range values {
+				if compareValues(v, want) == 0 {
+					return op == OpIn
+				}
+			}
+			return op == OpNotIn
+		}
+	case OpLess:
+		test = func(v string) bool { return compareValues(v, values[0]) < 0 }
+	case OpLessOrEqual:
+		test = func(v string) bool { return compareValues(v, values[0]) <= 0 }
+	case OpGreater:
+		test = func(v string) bool { return compareValues(v, values[0]) > 0 }
+	case OpGreaterOrEqual:
+		test = func(v string) bool { return compareValues(v, values[0]) >= 0 }
+	case OpBetween, OpNotBetween:
+		test = func(v string) bool {
+			in := compareValues(v, values[0]) >= 0 && compareValues(v, values[1]) <= 0
+			return in == (op == OpBetween)
+		}
+	case OpBeginsWith, OpNotBeginsWith:
+		want := strings.ToLower(values[0])
+		test = func(v string) bool { return strings.HasPrefix(strings.ToLower(v), want) == (op == OpBeginsWith) }
+	case OpEndsWith, OpNotEndsWith:
+		want := strings.ToLower(values[0])
+		test = func(v string) bool { return strings.HasSuffix(strings.ToLower(v), want) == (op == OpEndsWith) }
+	case OpContains, OpNotContains:
+		want := strings.ToLower(values[0])
+		test = func(v string) bool { return strings.Contains(strings.ToLower(v), want) == (op == OpContains) }
+	case OpRegex, OpNotRegex:
+		re, err := regexp.Compile("(?i)" + values[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		test = func(v string) bool { return re.MatchString(v) == (op == OpRegex) }
+	case OpIsEmpty:
+		test = func(v string) bool { return v == "" }
+	case OpIsNotEmpty:
+		test = func(v string) bool { return v != "" }
+	default:
+		return nil, fmt.Errorf("unsupported operator %q", op)
+	}
+	return func(value string, null bool) bool { return !null && test(value) }, nil
+}
+
+// compareValues compares two values numerically when both are numbers and as case
+// insensitive strings otherwise.
+func compareValues(a, b string) int {
+	if fa, err := strconv.ParseFloat(a, 64); err == nil {
+		if fb, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// fieldValue converts a struct field into its rule value. Booleans become 1/0, and nil
+// pointers and values marshaled as null are NULL.
+func fieldValue(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", true
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), false
+	case reflect.Bool:
+		return formatValue(v.Bool()), false
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), false
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), false
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), false
+	}
+
+	// Other types are converted through their JSON form.
+	data, err := json.Marshal(v.Interface())
+	if err != nil || string(data) == "null" {
+		return "", true
+	}
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		return s, false
+	}
+	return string(data), false
+}
+
+// columnIndex returns the field indexes of a struct type by JSON name.
+func columnIndex(t reflect.Type) map[string][]int {
+	if cached, ok := columnIndexes.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+	index := make(map[string][]int)
+	for _, f := range reflect.VisibleFields(t) {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" || !f.IsExported() {
+			continue
+		}
+		index[name] = f.Index
+	}
+	columnIndexes.Store(t, index)
+	return index
+}
@@ -0,0 +1,125 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/javen-yan/librenms-go/rules"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func testDevices() []types.Device {
+	return []types.Device{
+		{DeviceID: 1, Hostname: "core1.dc1.example.com", OS: "iosxe", Hardware: "C9300-48P", LocationID: 10, Status: true, Uptime: 86400, Latitude: types.NewNull(types.Float64(52.1))},
+		{DeviceID: 2, Hostname: "core2.dc1.example.com", OS: "ios", Hardware: "WS-C3850", LocationID: 10, Status: false, Uptime: 120},
+		{DeviceID: 3, Hostname: "edge1.dc2.example.com", OS: "junos", Hardware: "MX204", LocationID: 20, Status: true, Uptime: 3600, Notes: "uplink"},
+		{DeviceID: 4, Hostname: "lab-sw1", OS: "ios", Hardware: "C9200", Location: "Lab", Status: true},
+	}
+}
+
+func testLocations() []types.Location {
+	return []types.Location{
+		{ID: 10, Name: "DC1 Amsterdam"},
+		{ID: 20, Name: "DC2 Frankfurt"},
+	}
+}
+
+func matchedIDs(devices []types.Device) []int {
+	ids := make([]int, 0, len(devices))
+	for _, device := range devices {
+		ids = append(ids, int(device.DeviceID))
+	}
+	return ids
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []int
+	}{
+		{`devices.os = "IOS"`, []int{2, 4}},
+		{`devices.os != "ios"`, []int{1, 3}},
+		{`devices.os IN ("ios", "iosxe")`, []int{1, 2, 4}},
+		{`devices.os NOT IN ("ios", "iosxe")`, []int{3}},
+		{`devices.hostname LIKE "core%"`, []int{1, 2}},
+		{`devices.hostname LIKE "%.example.com"`, []int{1, 2, 3}},
+		{`devices.hostname LIKE "%dc2%"`, []int{3}},
+		{`devices.hostname NOT LIKE "%example%"`, []int{4}},
+		{`devices.hardware REGEXP "^c9"`, []int{1, 4}},
+		{`devices.hardware NOT REGEXP "^C9"`, []int{2, 3}},
+		{`devices.uptime > 3600`, []int{1}},
+		{`devices.uptime >= 3600`, []int{1, 3}},
+		{`devices.uptime < 3600`, []int{2, 4}},
+		{`devices.uptime BETWEEN 100 AND 3600`, []int{2, 3}},
+		{`devices.uptime NOT BETWEEN 100 AND 3600`, []int{1, 4}},
+		{`devices.status = 1`, []int{1, 3, 4}},
+		{`devices.status = FALSE`, []int{2}},
+		{`devices.notes IS EMPTY`, []int{1, 2, 4}},
+		{`devices.notes IS NOT EMPTY`, []int{3}},
+		{`devices.lat IS NULL`, []int{2, 3, 4}},
+		{`devices.lat IS NOT NULL`, []int{1}},
+		{`locations.location LIKE "DC1%"`, []int{1, 2}},
+		{`locations.location = "lab"`, []int{4}},
+		{`devices.os = "ios" AND (locations.location LIKE "DC1%" OR devices.hardware REGEXP "^C9")`, []int{2, 4}},
+		{`devices.os = "junos" OR devices.uptime > 3600 AND devices.status = 1`, []int{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			r := require.New(t)
+
+			matched, err := rules.Evaluate(rules.MustParse(tt.expr), testDevices(), &rules.EvaluateOptions{Locations: testLocations()})
+			r.NoError(err, "Evaluate returned an error")
+			r.Equal(tt.want, matchedIDs(matched), "Unexpected matched devices")
+		})
+	}
+}
+
+func TestEvaluate_Ports(t *testing.T) {
+	r := require.New(t)
+
+	ports := []types.Port{
+		{PortID: 100, DeviceID: 1, IfName: "Gi1/0/1", IfOperStatus: "up"},
+		{PortID: 101, DeviceID: 1, IfName: "Te1/1/1", IfOperStatus: "down"},
+		{PortID: 200, DeviceID: 2, IfName: "Te1/1/1", IfOperStatus: "up"},
+	}
+	container := rules.MustParse(`ports.ifName LIKE "Te%" AND ports.ifOperStatus = "up"`)
+
+	matched, err := rules.Evaluate(container, testDevices(), &rules.EvaluateOptions{Ports: ports})
+	r.NoError(err, "Evaluate returned an error")
+	r.Equal([]int{2}, matchedIDs(matched), "A single port should satisfy the whole rule")
+
+	matched, err = rules.Evaluate(rules.MustParse(`devices.os = "ios" OR ports.ifName LIKE "Gi%"`), testDevices(), &rules.EvaluateOptions{Ports: ports})
+	r.NoError(err, "Evaluate returned an error")
+	r.Equal([]int{1, 2, 4}, matchedIDs(matched), "Devices without ports should be evaluated with NULL port fields")
+
+	matched, err = rules.Evaluate(rules.MustParse(`ports.ifName IS NULL`), testDevices(), &rules.EvaluateOptions{Ports: ports})
+	r.NoError(err, "Evaluate returned an error")
+	r.Equal([]int{3, 4}, matchedIDs(matched), "Expected the devices without ports")
+
+	_, err = rules.Evaluate(container, testDevices(), nil)
+	r.ErrorContains(err, "no ports were given", "Expected error without ports")
+}
+
+func TestEvaluate_Unsupported(t *testing.T) {
+	r := require.New(t)
+
+	_, err := rules.Evaluate(rules.MustParse(`macros.device_up = 1`), testDevices(), nil)
+	r.ErrorContains(err, "can't be evaluated locally", "Expected error for macros")
+
+	_, err = rules.Evaluate(&types.DeviceGroupRuleContainer{Condition: "AND", Rules: []types.DeviceGroupRule{
+		{Field: "devices.hostname", Operator: rules.OpRegex, Value: "("},
+	}}, testDevices(), nil)
+	r.ErrorContains(err, "invalid regular expression", "Expected error for an invalid regex")
+}
+
+func TestDiffMembers(t *testing.T) {
+	r := require.New(t)
+
+	predicted := []types.Device{{DeviceID: 1}, {DeviceID: 3}, {DeviceID: 2}}
+	members := []types.DeviceGroupMember{{ID: 2}, {ID: 5}, {ID: 4}}
+
+	added, removed := rules.DiffMembers(predicted, members)
+	r.Equal([]int{1, 3}, added, "Unexpected predicted-only devices")
+	r.Equal([]int{4, 5}, removed, "Unexpected member-only devices")
+}
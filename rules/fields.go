@@ -0,0 +1,132 @@
+package rules
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// Field types used in the builder JSON.
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeDouble  = "double"
+)
+
+// fieldPattern matches rule field names in the form table.column.
+var fieldPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*\.[A-Za-z_][A-Za-z0-9_]*$`)
+
+var (
+	fieldsMu sync.RWMutex
+	// fields maps table name to column name to field type for the tables we know about.
+	fields = map[string]map[string]string{
+		"devices":   columnsOf(types.Device{}),
+		"ports":     columnsOf(types.Port{}),
+		"locations": columnsOf(types.Location{}),
+		"macros": {
+			"device":                TypeInteger,
+			"device_up":             TypeInteger,
+			"device_down":           TypeInteger,
+			"port":                  TypeInteger,
+			"port_up":               TypeInteger,
+			"port_down":             TypeInteger,
+			"port_usage_perc":       TypeDouble,
+			"port_in_usage_perc":    TypeDouble,
+			"port_out_usage_perc":   TypeDouble,
+			"sensor":                TypeInteger,
+			"sensor_port_link":      TypeInteger,
+			"state_sensor_ok":       TypeInteger,
+			"state_sensor_warning":  TypeInteger,
+			"state_sensor_critical": TypeInteger,
+			"state_sensor_unknown":  TypeInteger,
+			"component":             TypeInteger,
+			"component_normal":      TypeInteger,
+			"component_alert":       TypeInteger,
+			"bill_quota_over_quota": TypeInteger,
+			"bill_cdr_over_quota":   TypeInteger,
+		},
+	}
+)
+
+// RegisterField adds a field to the catalog used for validation, e.g. a custom macro.
+// The type should be one of TypeString, TypeInteger or TypeDouble.
+func RegisterField(field, fieldType string) error {
+	if !fieldPattern.MatchString(field) {
+		return fmt.Errorf("invalid field name %q, expected table.column", field)
+	}
+	table, column, _ := strings.Cut(field, ".")
+
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	if fields[table] == nil {
+		fields[table] = make(map[string]string)
+	}
+	fields[table][column] = fieldType
+	return nil
+}
+
+// FieldType returns the type of a known field. The second return value is false when
+// the field is not in the catalog.
+func FieldType(field string) (string, bool) {
+	table, column, ok := strings.Cut(field, ".")
+	if !ok {
+		return "", false
+	}
+
+	fieldsMu.RLock()
+	defer fieldsMu.RUnlock()
+	fieldType, ok := fields[table][column]
+	return fieldType, ok
+}
+
+// validateField checks the syntax of a field name. Columns of tables in the catalog must
+// be known; fields of other tables are accepted as LibreNMS exposes its whole schema.
+func validateField(field string) error {
+	if !fieldPattern.MatchString(field) {
+		return fmt.Errorf("invalid field name %q, expected table.column", field)
+	}
+	table, _, _ := strings.Cut(field, ".")
+
+	fieldsMu.RLock()
+	_, knownTable := fields[table]
+	fieldsMu.RUnlock()
+	if _, ok := FieldType(field); knownTable && !ok {
+		return fmt.Errorf("unknown field %q", field)
+	}
+	return nil
+}
+
+// columnsOf builds the column catalog of a table from the JSON tags of its type.
+func columnsOf(v any) map[string]string {
+	columns := make(map[string]string)
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		columns[name] = kindType(f.Type)
+	}
+	return columns
+}
+
+// kindType maps a Go type to the builder field type.
+func kindType(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeInteger
+	case reflect.Float32, reflect.Float64:
+		return TypeDouble
+	default:
+		return TypeString
+	}
+}
@@ -0,0 +1,140 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// numberPattern matches the number literals of the expression language.
+var numberPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// Format prints a rule container as an expression that Parse accepts. Nested groups are
+// always parenthesized.
+func Format(container *types.DeviceGroupRuleContainer) (string, error) {
+	condition := container.Condition
+	if condition == "" {
+		condition = CondAnd
+	}
+	return formatGroup(condition, container.Rules)
+}
+
+// formatGroup prints the rules of a group joined by its condition.
+func formatGroup(condition string, rules []types.DeviceGroupRule) (string, error) {
+	condition = strings.ToUpper(condition)
+	if condition != CondAnd && condition != CondOr {
+		return "", fmt.Errorf("invalid group condition %q", condition)
+	}
+	if len(rules) == 0 {
+		return "", errors.New("empty rule group")
+	}
+
+	parts := make([]string, 0, len(rules))
+	for _, r := range rules {
+		if r.Condition != "" || (r.Field == "" && r.ID == "" && len(r.Rules) > 0) {
+			nested := r.Condition
+			if nested == "" {
+				nested = CondAnd
+			}
+			s, err := formatGroup(nested, r.Rules)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, "("+s+")")
+			continue
+		}
+
+		s, err := formatRule(r)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " "+condition+" "), nil
+}
+
+// formatRule prints a terminal rule.
+func formatRule(r types.DeviceGroupRule) (string, error) {
+	field := r.Field
+	if field == "" {
+		field = r.ID
+	}
+	values := r.Values
+	if len(values) == 0 {
+		values = []string{r.Value}
+	}
+
+	n, ok := ValueCount(r.Operator)
+	switch {
+	case !ok:
+		return "", fmt.Errorf("%s: unknown operator %q", field, r.Operator)
+	case n > 0 && len(values) != n:
+		return "", fmt.Errorf("%s: operator %q requires %d value(s), got %d", field, r.Operator, n, len(values))
+	}
+
+	op, negate := strings.CutPrefix(r.Operator, "not_")
+	not := ""
+	if negate {
+		not = "NOT "
+	}
+
+	switch op {
+	case OpEqual:
+		if negate {
+			return fmt.Sprintf("%s != %s", field, formatLiteral(values[0], r.Type)), nil
+		}
+		return fmt.Sprintf("%s = %s", field, formatLiteral(values[0], r.Type)), nil
+	case OpLess:
+		return fmt.Sprintf("%s < %s", field, formatLiteral(values[0], r.Type)), nil
+	case OpLessOrEqual:
+		return fmt.Sprintf("%s <= %s", field, formatLiteral(values[0], r.Type)), nil
+	case OpGreater:
+		return fmt.Sprintf("%s > %s", field, formatLiteral(values[0], r.Type)), nil
+	case OpGreaterOrEqual:
+		return fmt.Sprintf("%s >= %s", field, formatLiteral(values[0], r.Type)), nil
+	case OpIn:
+		literals := make([]string, len(values))
+		for i, v := range values {
+			literals[i] = formatLiteral(v, r.Type)
+		}
+		return fmt.Sprintf("%s %sIN (%s)", field, not, strings.Join(literals, ", ")), nil
+	case OpBetween:
+		return fmt.Sprintf("%s %sBETWEEN %s AND %s", field, not,
+			formatLiteral(values[0], r.Type), formatLiteral(values[1], r.Type)), nil
+	case OpBeginsWith:
+		return fmt.Sprintf("%s %sLIKE %s", field, not, strconv.Quote(escapeLike(values[0])+"%")), nil
+	case OpEndsWith:
+		return fmt.Sprintf("%s %sLIKE %s", field, not, strconv.Quote("%"+escapeLike(values[0]))), nil
+	case OpContains:
+		return fmt.Sprintf("%s %sLIKE %s", field, not, strconv.Quote("%"+escapeLike(values[0])+"%")), nil
+	case OpRegex:
+		return fmt.Sprintf("%s %sREGEXP %s", field, not, strconv.Quote(values[0])), nil
+	case OpIsEmpty:
+		return field + " IS EMPTY", nil
+	case OpIsNotEmpty:
+		return field + " IS NOT EMPTY", nil
+	case OpIsNull:
+		return field + " IS NULL", nil
+	case OpIsNotNull:
+		return field + " IS NOT NULL", nil
+	default:
+		return "", fmt.Errorf("%s: unsupported operator %q", field, r.Operator)
+	}
+}
+
+// formatLiteral prints a value as a number for numeric fields and as a string otherwise.
+func formatLiteral(value, fieldType string) string {
+	if (fieldType == TypeInteger || fieldType == TypeDouble) && numberPattern.MatchString(value) {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// escapeLike escapes backslashes and percent signs in a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`).Replace(s)
+}
@@ -0,0 +1,95 @@
+package rules
+
+import "fmt"
+
+// Operators supported by the jQuery QueryBuilder used for alert rules and device groups.
+const (
+	OpEqual          = "equal"
+	OpNotEqual       = "not_equal"
+	OpIn             = "in"
+	OpNotIn          = "not_in"
+	OpLess           = "less"
+	OpLessOrEqual    = "less_or_equal"
+	OpGreater        = "greater"
+	OpGreaterOrEqual = "greater_or_equal"
+	OpBetween        = "between"
+	OpNotBetween     = "not_between"
+	OpBeginsWith     = "begins_with"
+	OpNotBeginsWith  = "not_begins_with"
+	OpContains       = "contains"
+	OpNotContains    = "not_contains"
+	OpEndsWith       = "ends_with"
+	OpNotEndsWith    = "not_ends_with"
+	OpIsEmpty        = "is_empty"
+	OpIsNotEmpty     = "is_not_empty"
+	OpIsNull         = "is_null"
+	OpIsNotNull      = "is_not_null"
+	OpRegex          = "regex"
+	OpNotRegex       = "not_regex"
+)
+
+// Group conditions.
+const (
+	CondAnd = "AND"
+	CondOr  = "OR"
+)
+
+// operator describes how many values an operator takes and which field types it applies to.
+type operator struct {
+	// values is the number of values, -1 for one or more.
+	values int
+	// stringOnly is set for text matching operators.
+	stringOnly bool
+}
+
+var operators = map[string]operator{
+	OpEqual:          {values: 1},
+	OpNotEqual:       {values: 1},
+	OpIn:             {values: -1},
+	OpNotIn:          {values: -1},
+	OpLess:           {values: 1},
+	OpLessOrEqual:    {values: 1},
+	OpGreater:        {values: 1},
+	OpGreaterOrEqual: {values: 1},
+	OpBetween:        {values: 2},
+	OpNotBetween:     {values: 2},
+	OpBeginsWith:     {values: 1, stringOnly: true},
+	OpNotBeginsWith:  {values: 1, stringOnly: true},
+	OpContains:       {values: 1, stringOnly: true},
+	OpNotContains:    {values: 1, stringOnly: true},
+	OpEndsWith:       {values: 1, stringOnly: true},
+	OpNotEndsWith:    {values: 1, stringOnly: true},
+	OpIsEmpty:        {values: 0},
+	OpIsNotEmpty:     {values: 0},
+	OpIsNull:         {values: 0},
+	OpIsNotNull:      {values: 0},
+	OpRegex:          {values: 1, stringOnly: true},
+	OpNotRegex:       {values: 1, stringOnly: true},
+}
+
+// ValueCount returns the number of values an operator takes, -1 for one or more.
+// The second return value is false for unknown operators.
+func ValueCount(op string) (int, bool) {
+	o, ok := operators[op]
+	return o.values, ok
+}
+
+// validateOperator checks that op is known, applies to the field type and gets the
+// right number of values.
+func validateOperator(op, fieldType string, values int) error {
+	o, ok := operators[op]
+	if !ok {
+		return fmt.Errorf("unknown operator %q", op)
+	}
+	if o.stringOnly && fieldType != "" && fieldType != TypeString {
+		return fmt.Errorf("operator %q requires a string field, got %s", op, fieldType)
+	}
+
+	switch {
+	case o.values == -1 && values == 0:
+		return fmt.Errorf("operator %q requires at least one value", op)
+	case o.values >= 0 && values != o.values:
+		return fmt.Errorf("operator %q requires %d value(s), got %d", op, o.values, values)
+	}
+	return nil
+}
@@ -0,0 +1,531 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/javen-yan/librenms-go/types"
+)
+
+type (
+	// ParseError is returned for invalid rule expressions. Line and Column are 1-based,
+	// Offset is the byte offset in the input.
+	ParseError struct {
+		Offset int
+		Line   int
+		Column int
+		Msg    string
+	}
+
+	tokenKind int
+
+	token struct {
+		kind tokenKind
+		// text is the token as written, value the decoded literal for strings.
+		text  string
+		value string
+		pos   int
+	}
+
+	parser struct {
+		src    string
+		tokens []token
+		next   int
+	}
+)
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOperator
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+// Error implements the error interface for ParseError.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// Parse parses a rule expression into a rule container, e.g.
+//
+//	devices.os = "ios" AND (locations.location LIKE "DC1%" OR devices.hardware REGEXP "^C9")
+//
+// Conditions compare a table.column field with literals: double or single quoted strings,
+// numbers and TRUE/FALSE (stored as 1/0). Supported operators are =, !=, <>, <, <=, >, >=,
+// [NOT] IN (...), [NOT] BETWEEN x AND y, [NOT] LIKE, [NOT] REGEXP, IS [NOT] NULL and
+// IS [NOT] EMPTY. LIKE patterns need a leading and/or trailing % and map to the begins_with,
+// ends_with and contains operators; use \% for a literal percent sign. Keywords are case
+// insensitive and AND binds tighter than OR.
+//
+// Parentheses always produce a nested group, so Parse(Format(c)) returns c for containers
+// created by Parse or the builder DSL. Joins and input widgets are not part of the language.
+func Parse(text string) (*types.DeviceGroupRuleContainer, error) {
+	expr, err := ParseExpr(text)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Build()
+}
+
+// MustParse is like Parse but panics if the expression is invalid. It is intended for
+// package level rule definitions.
+func MustParse(text string) *types.DeviceGroupRuleContainer {
+	container, err := Parse(text)
+	if err != nil {
+		panic(fmt.Sprintf("rules: MustParse(%q): %v", text, err))
+	}
+	return container
+}
+
+// ParseExpr parses a rule expression into a rule tree that can be combined with the
+// builder DSL. See Parse for the syntax.
+func ParseExpr(text string) (Expr, error) {
+	p := &parser{src: text}
+	if err := p.lex(); err != nil {
+		return nil, err
+	}
+	if p.peek().kind == tokEOF {
+		return nil, p.errorf(p.peek(), "empty expression")
+	}
+
+	expr, chain, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %s", describe(tok))
+	}
+	if !chain {
+		// A single condition or parenthesized group becomes the only rule of the container.
+		expr = &Group{Condition: CondAnd, Rules: []Expr{expr}}
+	}
+	return expr, nil
+}
+
+// parseOr parses operands joined by OR. The returned flag reports whether the result is a
+// group created from an AND or OR chain rather than a single operand.
+func (p *parser) parseOr() (Expr, bool, error) {
+	return p.parseChain(CondOr, p.parseAnd)
+}
+
+// parseAnd parses operands joined by AND.
+func (p *parser) parseAnd() (Expr, bool, error) {
+	return p.parseChain(CondAnd, p.parsePrimary)
+}
+
+// parseChain parses operands joined by the given keyword into a group.
+func (p *parser) parseChain(condition string, operand func() (Expr, bool, error)) (Expr, bool, error) {
+	first, chain, err := operand()
+	if err != nil {
+		return nil, false, err
+	}
+	if !p.isKeyword(p.peek(), condition) {
+		return first, chain, nil
+	}
+
+	g := &Group{Condition: condition, Rules: []Expr{first}}
+	for p.isKeyword(p.peek(), condition) {
+		p.advance()
+		expr, _, err := operand()
+		if err != nil {
+			return nil, false, err
+		}
+		g.Rules = append(g.Rules, expr)
+	}
+	return g, true, nil
+}
+
+// parsePrimary parses a condition or a parenthesized group.
+func (p *parser) parsePrimary() (Expr, bool, error) {
+	if p.peek().kind != tokLParen {
+		c, err := p.parseCondition()
+		return c, false, err
+	}
+
+	open := p.advance()
+	expr, chain, err := p.parseOr()
+	if err != nil {
+		return nil, false, err
+	}
+	if tok := p.peek(); tok.kind != tokRParen {
+		if tok.kind == tokEOF {
+			return nil, false, p.errorf(open, "unclosed parenthesis")
+		}
+		return nil, false, p.errorf(tok, "expected ')', got %s", describe(tok))
+	}
+	p.advance()
+	if !chain {
+		expr = &Group{Condition: CondAnd, Rules: []Expr{expr}}
+	}
+	return expr, false, nil
+}
+
+// parseCondition parses a field followed by an operator and its values.
+func (p *parser) parseCondition() (*Condition, error) {
+	field := p.advance()
+	if field.kind != tokIdent || isReserved(field.text) {
+		return nil, p.errorf(field, "expected field name, got %s", describe(field))
+	}
+	if err := validateField(field.text); err != nil {
+		return nil, p.errorf(field, "%v", err)
+	}
+
+	c := &Condition{Field: field.text}
+	var (
+		kind string
+		err  error
+	)
+
+	tok := p.advance()
+	switch {
+	case tok.kind == tokOperator:
+		c.Operator = comparisonOperators[tok.text]
+		kind, err = p.parseValue(c)
+	case p.isKeyword(tok, "IS"):
+		c.Operator, err = p.parseIs()
+	case p.isKeyword(tok, "NOT"):
+		tok = p.advance()
+		kind, err = p.parseKeywordOperator(c, tok, true)
+	case tok.kind == tokIdent:
+		kind, err = p.parseKeywordOperator(c, tok, false)
+	default:
+		err = p.errorf(tok, "expected operator, got %s", describe(tok))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.Type, _ = FieldType(c.Field)
+	if c.Type == "" {
+		c.Type = kind
+	}
+	if c.Type == "" {
+		c.Type = TypeString
+	}
+	if _, err := c.rule(); err != nil {
+		return nil, p.errorf(field, "%v", err)
+	}
+	return c, nil
+}
+
+// parseKeywordOperator parses IN, BETWEEN, LIKE and REGEXP, optionally negated, and returns
+// the type of the first value.
+func (p *parser) parseKeywordOperator(c *Condition, tok token, negate bool) (string, error) {
+	switch {
+	case p.isKeyword(tok, "IN"):
+		c.Operator = negated(OpIn, negate)
+		return p.parseList(c)
+	case p.isKeyword(tok, "BETWEEN"):
+		c.Operator = negated(OpBetween, negate)
+		kind, err := p.parseValue(c)
+		if err != nil {
+			return "", err
+		}
+		if and := p.advance(); !p.isKeyword(and, "AND") {
+			return "", p.errorf(and, "expected AND in BETWEEN, got %s", describe(and))
+		}
+		_, err = p.parseValue(c)
+		return kind, err
+	case p.isKeyword(tok, "LIKE"):
+		pattern := p.advance()
+		if pattern.kind != tokString {
+			return "", p.errorf(pattern, "expected string pattern after LIKE, got %s", describe(pattern))
+		}
+		op, value, err := likeOperator(pattern.value)
+		if err != nil {
+			return "", p.errorf(pattern, "%v", err)
+		}
+		c.Operator = negated(op, negate)
+		c.Values = []string{value}
+		return TypeString, nil
+	case p.isKeyword(tok, "REGEXP"):
+		re := p.advance()
+		if re.kind != tokString {
+			return "", p.errorf(re, "expected string pattern after REGEXP, got %s", describe(re))
+		}
+		c.Operator = negated(OpRegex, negate)
+		c.Values = []string{re.value}
+		return TypeString, nil
+	case negate:
+		return "", p.errorf(tok, "expected IN, BETWEEN, LIKE or REGEXP after NOT, got %s", describe(tok))
+	default:
+		return "", p.errorf(tok, "expected operator, got %s", describe(tok))
+	}
+}
+
+// parseIs parses the rest of IS [NOT] NULL and IS [NOT] EMPTY.
+func (p *parser) parseIs() (string, error) {
+	tok := p.advance()
+	not := p.isKeyword(tok, "NOT")
+	if not {
+		tok = p.advance()
+	}
+	switch {
+	case p.isKeyword(tok, "NULL") && not:
+		return OpIsNotNull, nil
+	case p.isKeyword(tok, "NULL"):
+		return OpIsNull, nil
+	case p.isKeyword(tok, "EMPTY") && not:
+		return OpIsNotEmpty, nil
+	case p.isKeyword(tok, "EMPTY"):
+		return OpIsEmpty, nil
+	default:
+		return "", p.errorf(tok, "expected NULL or EMPTY, got %s", describe(tok))
+	}
+}
+
+// parseList parses a parenthesized, comma separated list of values and returns the type of
+// the first one.
+func (p *parser) parseList(c *Condition) (string, error) {
+	if open := p.advance(); open.kind != tokLParen {
+		return "", p.errorf(open, "expected '(' after IN, got %s", describe(open))
+	}
+
+	var first string
+	for {
+		kind, err := p.parseValue(c)
+		if err != nil {
+			return "", err
+		}
+		if first == "" {
+			first = kind
+		}
+
+		tok := p.advance()
+		if tok.kind == tokRParen {
+			return first, nil
+		}
+		if tok.kind != tokComma {
+			return "", p.errorf(tok, "expected ',' or ')', got %s", describe(tok))
+		}
+	}
+}
+
+// parseValue parses a literal, appends it to the condition and returns its type.
+func (p *parser) parseValue(c *Condition) (string, error) {
+	tok := p.advance()
+	switch {
+	case tok.kind == tokString:
+		c.Values = append(c.Values, tok.value)
+		return TypeString, nil
+	case tok.kind == tokNumber:
+		c.Values = append(c.Values, tok.text)
+		if strings.Contains(tok.text, ".") {
+			return TypeDouble, nil
+		}
+		return TypeInteger, nil
+	case p.isKeyword(tok, "TRUE"):
+		c.Values = append(c.Values, "1")
+		return TypeInteger, nil
+	case p.isKeyword(tok, "FALSE"):
+		c.Values = append(c.Values, "0")
+		return TypeInteger, nil
+	default:
+		return "", p.errorf(tok, "expected value, got %s", describe(tok))
+	}
+}
+
+// comparisonOperators maps comparison symbols to builder operators.
+var comparisonOperators = map[string]string{
+	"=":  OpEqual,
+	"==": OpEqual,
+	"!=": OpNotEqual,
+	"<>": OpNotEqual,
+	"<":  OpLess,
+	"<=": OpLessOrEqual,
+	">":  OpGreater,
+	">=": OpGreaterOrEqual,
+}
+
+// reserved lists the keywords of the language. They can't be used as field names.
+var reserved = []string{"AND", "OR", "NOT", "IN", "BETWEEN", "LIKE", "REGEXP", "IS", "NULL", "EMPTY", "TRUE", "FALSE"}
+
+func isReserved(word string) bool {
+	for _, kw := range reserved {
+		if strings.EqualFold(word, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// negated returns the negated form of op when negate is set.
+func negated(op string, negate bool) string {
+	if negate {
+		return "not_" + op
+	}
+	return op
+}
+
+// likeOperator converts a LIKE pattern into a begins_with, ends_with or contains operator.
+func likeOperator(pattern string) (string, string, error) {
+	var (
+		value    strings.Builder
+		leading  bool
+		trailing bool
+	)
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		switch {
+		case ch == '\\' && i+1 < len(pattern):
+			i++
+			value.WriteByte(pattern[i])
+		case ch == '%' && i == 0:
+			leading = true
+		case ch == '%' && i == len(pattern)-1:
+			trailing = true
+		case ch == '%':
+			return "", "", fmt.Errorf("unsupported %% wildcard inside LIKE pattern %q, use REGEXP instead", pattern)
+		default:
+			value.WriteByte(ch)
+		}
+	}
+
+	switch {
+	case leading && trailing:
+		return OpContains, value.String(), nil
+	case leading:
+		return OpEndsWith, value.String(), nil
+	case trailing:
+		return OpBeginsWith, value.String(), nil
+	default:
+		return "", "", fmt.Errorf("LIKE pattern %q needs a leading or trailing %%, use = for exact matches", pattern)
+	}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+// advance returns the current token and moves to the next one. It stays on EOF.
+func (p *parser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+	return tok
+}
+
+func (p *parser) isKeyword(tok token, keyword string) bool {
+	return tok.kind == tokIdent && strings.EqualFold(tok.text, keyword)
+}
+
+// errorf creates a ParseError at the position of tok.
+func (p *parser) errorf(tok token, format string, args ...any) *ParseError {
+	return p.errorAt(tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) errorAt(pos int, msg string) *ParseError {
+	before := p.src[:pos]
+	line := strings.Count(before, "\n") + 1
+	column := utf8.RuneCountInString(before[strings.LastIndexByte(before, '\n')+1:]) + 1
+	return &ParseError{Offset: pos, Line: line, Column: column, Msg: msg}
+}
+
+// describe returns a token description for error messages.
+func describe(tok token) string {
+	if tok.kind == tokEOF {
+		return "end of input"
+	}
+	return strconv.Quote(tok.text)
+}
+
+// lex splits the source into tokens, ending with an EOF token.
+func (p *parser) lex() error {
+	src := p.src
+	for i := 0; i < len(src); {
+		ch := src[i]
+		start := i
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+			continue
+		case ch == '(':
+			p.tokens = append(p.tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case ch == ')':
+			p.tokens = append(p.tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case ch == ',':
+			p.tokens = append(p.tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		case strings.ContainsRune("=!<>", rune(ch)):
+			i++
+			if i < len(src) && (src[i] == '=' || (ch == '<' && src[i] == '>')) {
+				i++
+			}
+			text := src[start:i]
+			if _, ok := comparisonOperators[text]; !ok {
+				return p.errorAt(start, fmt.Sprintf("unexpected %q", text))
+			}
+			p.tokens = append(p.tokens, token{kind: tokOperator, text: text, pos: start})
+		case ch == '"' || ch == '\'':
+			value, end, err := scanString(src, i)
+			if err != nil {
+				return p.errorAt(start, err.Error())
+			}
+			i = end
+			p.tokens = append(p.tokens, token{kind: tokString, text: src[start:i], value: value, pos: start})
+		case isDigit(ch) || (ch == '-' && i+1 < len(src) && isDigit(src[i+1])):
+			i++
+			for i < len(src) && isDigit(src[i]) {
+				i++
+			}
+			if i+1 < len(src) && src[i] == '.' && isDigit(src[i+1]) {
+				i++
+				for i < len(src) && isDigit(src[i]) {
+					i++
+				}
+			}
+			p.tokens = append(p.tokens, token{kind: tokNumber, text: src[start:i], pos: start})
+		case isIdentStart(ch):
+			for i < len(src) && (isIdentStart(src[i]) || isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: tokIdent, text: src[start:i], pos: start})
+		default:
+			r, _ := utf8.DecodeRuneInString(src[i:])
+			return p.errorAt(start, fmt.Sprintf("unexpected character %q", r))
+		}
+	}
+	p.tokens = append(p.tokens, token{kind: tokEOF, pos: len(src)})
+	return nil
+}
+
+// scanString scans the quoted string starting at src[start] and returns its value and the
+// offset after the closing quote. Double quoted strings use Go escapes. Single quoted
+// strings only unescape \' and \\ so regular expressions can be written as is.
+func scanString(src string, start int) (string, int, error) {
+	quote := src[start]
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case quote:
+			if quote == '\'' {
+				r := strings.NewReplacer(`\\`, `\`, `\'`, `'`)
+				return r.Replace(src[start+1 : i]), i + 1, nil
+			}
+			value, err := strconv.Unquote(src[start : i+1])
+			if err != nil {
+				return "", 0, fmt.Errorf("invalid string literal %s", src[start:i+1])
+			}
+			return value, i + 1, nil
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isIdentStart(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
@@ -0,0 +1,201 @@
+package rules_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/rules"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	r := require.New(t)
+
+	container, err := rules.Parse(`devices.os = "ios" AND (locations.location LIKE "DC1%" OR devices.hardware REGEXP "^C9")`)
+	r.NoError(err, "Parse returned an error")
+	r.Equal(rules.CondAnd, container.Condition, "Expected AND at the root")
+	r.True(container.Valid, "Container should be valid")
+	r.Len(container.Rules, 2, "Expected 2 rules")
+
+	r.Equal(types.DeviceGroupRule{
+		ID: "devices.os", Field: "devices.os", Type: rules.TypeString, Input: "text", Operator: rules.OpEqual, Value: "ios",
+	}, container.Rules[0], "Unexpected first rule")
+
+	or := container.Rules[1]
+	r.Equal(rules.CondOr, or.Condition, "Expected nested OR group")
+	r.Equal(rules.OpBeginsWith, or.Rules[0].Operator, "LIKE with trailing % should map to begins_with")
+	r.Equal("DC1", or.Rules[0].Value, "Wildcard should be stripped")
+	r.Equal(rules.OpRegex, or.Rules[1].Operator, "Unexpected regex operator")
+	r.Equal("^C9", or.Rules[1].Value, "Unexpected regex value")
+}
+
+func TestParse_Operators(t *testing.T) {
+	tests := []struct {
+		expr     string
+		operator string
+		values   []string
+	}{
+		{`devices.status != 1`, rules.OpNotEqual, []string{"1"}},
+		{`devices.status <> 1`, rules.OpNotEqual, []string{"1"}},
+		{`devices.uptime < 300`, rules.OpLess, []string{"300"}},
+		{`devices.uptime <= 300`, rules.OpLessOrEqual, []string{"300"}},
+		{`devices.uptime > 300`, rules.OpGreater, []string{"300"}},
+		{`devices.uptime >= 300`, rules.OpGreaterOrEqual, []string{"300"}},
+		{`devices.os in ("ios", 'iosxe')`, rules.OpIn, []string{"ios", "iosxe"}},
+		{`devices.os NOT IN ("ios")`, rules.OpNotIn, []string{"ios"}},
+		{`devices.uptime BETWEEN 0 AND 300`, rules.OpBetween, []string{"0", "300"}},
+		{`devices.uptime NOT BETWEEN 0 AND 300`, rules.OpNotBetween, []string{"0", "300"}},
+		{`devices.hostname LIKE "%.example.com"`, rules.OpEndsWith, []string{".example.com"}},
+		{`devices.hostname LIKE "%core%"`, rules.OpContains, []string{"core"}},
+		{`devices.hostname NOT LIKE "lab%"`, rules.OpNotBeginsWith, []string{"lab"}},
+		{`devices.hostname LIKE "50\\%%"`, rules.OpBeginsWith, []string{"50%"}},
+		{`devices.hardware NOT REGEXP '^C9\d+'`, rules.OpNotRegex, []string{`^C9\d+`}},
+		{`devices.notes IS EMPTY`, rules.OpIsEmpty, nil},
+		{`devices.notes IS NOT EMPTY`, rules.OpIsNotEmpty, nil},
+		{`devices.notes is null`, rules.OpIsNull, nil},
+		{`devices.notes IS NOT NULL`, rules.OpIsNotNull, nil},
+		{`devices.disabled = false`, rules.OpEqual, []string{"0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			r := require.New(t)
+
+			expr, err := rules.ParseExpr(tt.expr)
+			r.NoError(err, "ParseExpr returned an error")
+			group, ok := expr.(*rules.Group)
+			r.True(ok, "Expected a group")
+			c, ok := group.Rules[0].(*rules.Condition)
+			r.True(ok, "Expected a condition")
+			r.Equal(tt.operator, c.Operator, "Unexpected operator")
+			r.Equal(tt.values, c.Values, "Unexpected values")
+		})
+	}
+}
+
+func TestParse_Precedence(t *testing.T) {
+	r := require.New(t)
+
+	container, err := rules.Parse(`devices.os = "ios" OR devices.os = "nxos" AND devices.status = 1`)
+	r.NoError(err, "Parse returned an error")
+	r.Equal(rules.CondOr, container.Condition, "OR should be the root")
+	r.Len(container.Rules, 2, "Expected 2 operands")
+	r.Equal(rules.CondAnd, container.Rules[1].Condition, "AND should bind tighter than OR")
+}
+
+func TestParse_Types(t *testing.T) {
+	r := require.New(t)
+
+	container, err := rules.Parse(`devices.status = 1 AND access_points.channel = 3 AND access_points.name = "ap1" AND access_points.load = 1.5`)
+	r.NoError(err, "Parse returned an error")
+	r.Equal(rules.TypeInteger, container.Rules[0].Type, "Catalog type should be used")
+	r.Equal(rules.TypeInteger, container.Rules[1].Type, "Type of unknown tables should come from the literal")
+	r.Equal(rules.TypeString, container.Rules[2].Type, "Type of unknown tables should come from the literal")
+	r.Equal(rules.TypeDouble, container.Rules[3].Type, "Type of unknown tables should come from the literal")
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		expr   string
+		line   int
+		column int
+		msg    string
+	}{
+		{``, 1, 1, "empty expression"},
+		{`devices.os = `, 1, 14, "expected value, got end of input"},
+		{`devices.os "ios"`, 1, 12, `expected operator, got "\"ios\""`},
+		{`devices.os = "ios" AND`, 1, 23, "expected field name, got end of input"},
+		{`(devices.os = "ios"`, 1, 1, "unclosed parenthesis"},
+		{`devices.os = "ios")`, 1, 19, `unexpected ")"`},
+		{"devices.os = \"ios\" AND\n  devices.nope = 1", 2, 3, `unknown field "devices.nope"`},
+		{`hostname = "sw1"`, 1, 1, "expected table.column"},
+		{`devices.os LIKE "i%s"`, 1, 17, "unsupported % wildcard"},
+		{`devices.os LIKE "ios"`, 1, 17, "needs a leading or trailing %"},
+		{`devices.status LIKE "1%"`, 1, 1, "requires a string field"},
+		{`devices.os = "ios`, 1, 14, "unterminated string"},
+		{`devices.os ~ "ios"`, 1, 12, "unexpected character '~'"},
+		{`devices.os NOT = "ios"`, 1, 16, "expected IN, BETWEEN, LIKE or REGEXP after NOT"},
+		{`devices.uptime BETWEEN 1 OR 2`, 1, 26, "expected AND in BETWEEN"},
+		{`devices.os IN ("ios" "nxos")`, 1, 22, "expected ',' or ')'"},
+		{`devices.notes IS MISSING`, 1, 18, "expected NULL or EMPTY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			r := require.New(t)
+
+			_, err := rules.Parse(tt.expr)
+			var perr *rules.ParseError
+			r.True(errors.As(err, &perr), "Expected a ParseError, got %v", err)
+			r.Equal(tt.line, perr.Line, "Unexpected line")
+			r.Equal(tt.column, perr.Column, "Unexpected column")
+			r.Contains(perr.Msg, tt.msg, "Unexpected message")
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	r := require.New(t)
+
+	container := rules.MustParse(`devices.os in ("ios", "iosxe") and (devices.hostname like "%core%" or devices.uptime between 0 and 300) and devices.notes is not empty`)
+	text, err := rules.Format(container)
+	r.NoError(err, "Format returned an error")
+	r.Equal(`devices.os IN ("ios", "iosxe") AND (devices.hostname LIKE "%core%" OR devices.uptime BETWEEN 0 AND 300) AND devices.notes IS NOT EMPTY`,
+		text, "Unexpected formatted expression")
+}
+
+func TestFormat_RoundTrip(t *testing.T) {
+	exprs := []string{
+		`devices.os = "ios"`,
+		`(devices.os = "ios")`,
+		`devices.os = "ios" AND (devices.status = 1)`,
+		`(devices.os = "ios" OR devices.os = "nxos") AND devices.status = 1`,
+		`devices.hostname LIKE "50\\%%" OR devices.hostname NOT LIKE "%\\\\lab"`,
+		`devices.sysDescr REGEXP "^Cisco \"IOS\"\n"`,
+		`access_points.channel = "3" OR access_points.deleted = "true" OR (access_points.mac_addr = "1" AND ((access_points.accesspoint_id = 3)))`,
+		`devices.uptime NOT BETWEEN -1 AND 1.5 AND devices.notes IS NULL`,
+	}
+
+	for _, expr := range exprs {
+		t.Run(expr, func(t *testing.T) {
+			r := require.New(t)
+
+			container, err := rules.Parse(expr)
+			r.NoError(err, "Parse returned an error")
+			text, err := rules.Format(container)
+			r.NoError(err, "Format returned an error")
+			again, err := rules.Parse(text)
+			r.NoError(err, "Failed to parse formatted expression %q", text)
+			r.Equal(container, again, "Parse(Format(c)) should return c")
+		})
+	}
+}
+
+func TestFormat_Fixtures(t *testing.T) {
+	r := require.New(t)
+
+	data, err := os.ReadFile(filepath.Join("..", "fixtures", "get_devicegroups_200.json"))
+	r.NoError(err, "Failed to read fixture")
+	resp := new(types.DeviceGroupResponse)
+	r.NoError(json.Unmarshal(data, resp), "Failed to decode fixture")
+
+	text, err := rules.Format(&resp.Groups[1].Rules)
+	r.NoError(err, "Format returned an error")
+	r.Equal(`access_points.channel = "3" OR access_points.deleted = "true" OR (access_points.mac_addr = "1" AND access_points.accesspoint_id = "3" AND (access_points.accesspoint_id = "3"))`,
+		text, "Unexpected formatted expression")
+
+	container, err := rules.Parse(text)
+	r.NoError(err, "Parse returned an error")
+	r.Equal(resp.Groups[1].Rules.Rules, container.Rules, "Rules should round-trip")
+
+	_, err = rules.Format(&resp.Groups[2].Rules)
+	r.ErrorContains(err, "empty rule group", "Expected error for empty container")
+}
+
+func TestMustParse_Panics(t *testing.T) {
+	require.Panics(t, func() { rules.MustParse(`devices.os =`) }, "MustParse should panic on invalid input")
+}
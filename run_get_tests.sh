@@ -1,14 +1,45 @@
 #!/bin/bash
 
 # LibreNMS GET 请求测试脚本
+#
+# 用法:
+#   ./run_get_tests.sh                       # 离线回放 fixtures/cassettes/get_requests.json
+#   ./run_get_tests.sh live <url> <token>    # 访问真实服务器
+#   ./run_get_tests.sh record <url> <token>  # 访问真实服务器并重新录制 cassette
+
+MODE="${1:-replay}"
+
+case "$MODE" in
+replay)
+	unset LIBRENMS_URL LIBRENMS_TOKEN LIBRENMS_RECORD
+	TARGET="fixtures/cassettes/get_requests.json (回放)"
+	;;
+live | record)
+	if [ -z "$2" ] || [ -z "$3" ]; then
+		echo "用法: $0 $MODE <url> <token>"
+		exit 1
+	fi
+	export LIBRENMS_URL="$2" LIBRENMS_TOKEN="$3"
+	unset LIBRENMS_RECORD
+	if [ "$MODE" = "record" ]; then
+		export LIBRENMS_RECORD=1
+	fi
+	TARGET="$2"
+	;;
+*)
+	echo "未知模式: $MODE (可选: replay, live, record)"
+	exit 1
+	;;
+esac
+
 echo "=========================================="
 echo "LibreNMS GET 请求测试"
-echo "服务器地址: http://192.168.8.2:8000"
+echo "服务器地址: $TARGET"
 echo "=========================================="
 
 # 运行所有 GET 请求测试
 echo "运行测试..."
-go test -v -run "TestRealServer" ./...
+go test -count=1 -v -run "TestRealServer" .
 
 echo ""
 echo "=========================================="
@@ -0,0 +1,162 @@
+package librenms_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLenientScalars(t *testing.T) {
+	r := require.New(t)
+
+	for _, tc := range []struct {
+		json string
+		want any
+	}{
+		{`12`, types.Int(12)},
+		{`"12"`, types.Int(12)},
+		{`" 12 "`, types.Int(12)},
+		{`"12.0"`, types.Int(12)},
+		{`1e3`, types.Int(1000)},
+		{`true`, types.Int(1)},
+		{`null`, types.Int(0)},
+		{`""`, types.Int(0)},
+		{`"9223372036854775807"`, types.Int64(9223372036854775807)},
+		{`"-5"`, types.Int64(-5)},
+		{`"1.5"`, types.Float64(1.5)},
+		{`2`, types.Float64(2)},
+		{`null`, types.Float64(0)},
+		{`"1"`, types.Bool(true)},
+		{`"false"`, types.Bool(false)},
+		{`0`, types.Bool(false)},
+		{`null`, types.Bool(false)},
+		{`"abc"`, types.String("abc")},
+		{`123`, types.String("123")},
+		{`1.50`, types.String("1.50")},
+		{`null`, types.String("")},
+	} {
+		ptr := reflect.New(reflect.TypeOf(tc.want))
+		r.NoError(json.Unmarshal([]byte(tc.json), ptr.Interface()), "Failed to decode %s as %T", tc.json, tc.want)
+		r.Equal(tc.want, ptr.Elem().Interface(), "Unexpected value for %s as %T", tc.json, tc.want)
+	}
+}
+
+func TestLenientScalars_Invalid(t *testing.T) {
+	r := require.New(t)
+
+	var i types.Int
+	r.ErrorContains(json.Unmarshal([]byte(`"abc"`), &i), "failed to unmarshal Int", "Expected an error for text")
+	r.Error(json.Unmarshal([]byte(`"1.5"`), &i), "Expected an error for fractions")
+	r.Error(json.Unmarshal([]byte(`[1]`), &i), "Expected an error for arrays")
+
+	var i64 types.Int64
+	r.Error(json.Unmarshal([]byte(`"1e30"`), &i64), "Expected an error for overflows")
+
+	var b types.Bool
+	r.ErrorContains(json.Unmarshal([]byte(`"yes please"`), &b), "failed to unmarshal Bool", "Expected an error for text")
+
+	var s types.String
+	r.Error(json.Unmarshal([]byte(`{"a":1}`), &s), "Expected an error for objects")
+}
+
+func TestTime(t *testing.T) {
+	r := require.New(t)
+
+	want := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		json string
+		want time.Time
+	}{
+		{`"2024-05-01 10:30:00"`, want},
+		{`"2024-05-01T10:30:00Z"`, want},
+		{`"2024-05-01T12:30:00+02:00"`, want},
+		{`"2024-05-01T10:30:00"`, want},
+		{`1714559400`, want},
+		{`"1714559400"`, want},
+		{`"2024-05-01"`, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{`null`, time.Time{}},
+		{`""`, time.Time{}},
+		{`0`, time.Time{}},
+		{`"0000-00-00 00:00:00"`, time.Time{}},
+	} {
+		var got types.Time
+		r.NoError(json.Unmarshal([]byte(tc.json), &got), "Failed to decode %s", tc.json)
+		r.True(tc.want.Equal(got.Time), "Unexpected time for %s: %s", tc.json, got)
+	}
+
+	var invalid types.Time
+	r.ErrorContains(json.Unmarshal([]byte(`"yesterday"`), &invalid), "failed to unmarshal Time", "Expected an error for text")
+
+	data, err := json.Marshal(struct {
+		UTC   types.Time `json:"utc"`
+		Local types.Time `json:"local"`
+		Zero  types.Time `json:"zero"`
+	}{
+		UTC:   types.NewTime(want),
+		Local: types.NewTime(want.In(time.FixedZone("CEST", 2*3600))),
+	})
+	r.NoError(err, "Failed to encode times")
+	r.JSONEq(`{"utc":"2024-05-01 10:30:00","local":"2024-05-01T12:30:00+02:00","zero":null}`, string(data),
+		"Expected the API layout for UTC times, RFC 3339 for others and null for zero times")
+}
+
+func TestNull(t *testing.T) {
+	r := require.New(t)
+
+	var value struct {
+		Lat     types.NullFloat64 `json:"lat"`
+		Lng     types.NullFloat64 `json:"lng"`
+		Empty   types.NullInt     `json:"empty"`
+		Missing types.NullInt     `json:"missing"`
+		Text    types.NullString  `json:"text"`
+		Blank   types.NullString  `json:"blank"`
+		Flag    types.NullBool    `json:"flag"`
+	}
+	r.NoError(json.Unmarshal([]byte(`{"lat":"52.1","lng":null,"empty":"","text":7,"blank":"","flag":"1"}`), &value),
+		"Failed to decode nullable values")
+	r.Equal(types.NewNull(types.Float64(52.1)), value.Lat, "Expected a valid latitude")
+	r.False(value.Lng.Valid, "Expected a null longitude")
+	r.False(value.Empty.Valid, "Expected an empty string to be null")
+	r.False(value.Missing.Valid, "Expected a missing field to be null")
+	r.Equal(types.NewNull(types.String("7")), value.Text, "Expected the number as text")
+	r.Equal(types.NewNull(types.String("")), value.Blank, "Expected an empty NullString to be valid")
+	r.Equal(types.NewNull(types.Bool(true)), value.Flag, "Expected a valid flag")
+
+	data, err := json.Marshal(value)
+	r.NoError(err, "Failed to encode nullable values")
+	r.JSONEq(`{"lat":52.1,"lng":null,"empty":null,"missing":null,"text":"7","blank":"","flag":1}`, string(data),
+		"Expected null for invalid values")
+}
+
+func TestLenientResponses(t *testing.T) {
+	r := require.New(t)
+
+	client := streamServer(t, "/api/v0/ports", "ports", 2, func(i int) string {
+		if i == 0 {
+			return `{"port_id":1,"device_id":"3","ifIndex":"10","ifSpeed":1000000000,"ifInOctets_rate":"12.5",` +
+				`"ifInOctets":"123","disabled":false,"counter_in":null,"ifVlan":10}`
+		}
+		return `{"port_id":"2","device_id":3,"ifIndex":11,"ifSpeed":"1000000000","ifInOctets_rate":12.5,` +
+			`"ifInOctets":123,"disabled":"0","counter_in":"42","ifVlan":"10"}`
+	})
+	resp, err := client.Port.GetAllPorts(nil)
+	r.NoError(err, "Expected mixed formats to decode")
+	r.EqualValues(2, resp.Count, "Expected the count")
+	r.Len(resp.Ports, 2, "Expected every port")
+
+	first, second := resp.Ports[0], resp.Ports[1]
+	r.EqualValues(2, second.PortID, "Expected the string ID to decode")
+	r.Equal(first.DeviceID, second.DeviceID, "Expected the same device ID")
+	r.EqualValues(10, first.IfIndex, "Expected the string ifIndex to decode")
+	r.Equal(first.IfSpeed, second.IfSpeed, "Expected the same speed")
+	r.Equal(first.IfInOctetsRate, second.IfInOctetsRate, "Expected the same rate")
+	r.Equal(first.IfInOctets, second.IfInOctets, "Expected the same counter")
+	r.Equal(first.Disabled, second.Disabled, "Expected the same disabled flag")
+	r.False(first.CounterIn.Valid, "Expected a null counter")
+	r.Equal(types.NewNull(types.Int64(42)), second.CounterIn, "Expected a valid counter")
+	r.Equal(first.IfVlan, second.IfVlan, "Expected the same VLAN text")
+}
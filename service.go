@@ -16,7 +16,7 @@ const (
 // Documentation: https://docs.librenms.org/API/Services/#add_service_for_host
 func (s *ServiceAPI) Create(deviceIdentifier string, service *types.ServiceCreateRequest) (*types.ServiceResponse, error) {
 	c := s.client
-	req, err := c.newRequest(http.MethodPost, fmt.Sprintf("%s/%s", serviceEndpoint, deviceIdentifier), service, nil)
+	req, err := c.newRequest(opServiceCreate, http.MethodPost, fmt.Sprintf("%s/%s", serviceEndpoint, deviceIdentifier), service, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +30,7 @@ func (s *ServiceAPI) Create(deviceIdentifier string, service *types.ServiceCreat
 // Documentation: https://docs.librenms.org/API/Services/#delete_service_from_host
 func (s *ServiceAPI) Delete(serviceID int) (*types.BaseResponse, error) {
 	c := s.client
-	req, err := c.newRequest(http.MethodDelete, fmt.Sprintf("%s/%d", serviceEndpoint, serviceID), nil, nil)
+	req, err := c.newRequest(opServiceDelete, http.MethodDelete, fmt.Sprintf("%s/%d", serviceEndpoint, serviceID), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +46,7 @@ func (s *ServiceAPI) Delete(serviceID int) (*types.BaseResponse, error) {
 // This is primarily a convenience function for the Terraform provider.
 func (s *ServiceAPI) Get(serviceID int) (*types.ServiceResponse, error) {
 	c := s.client
-	req, err := c.newRequest(http.MethodGet, serviceEndpoint, nil, nil)
+	req, err := c.newRequest(opServiceGet, http.MethodGet, serviceEndpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +73,7 @@ func (s *ServiceAPI) Get(serviceID int) (*types.ServiceResponse, error) {
 	singleServiceResp.Status = resp.Status
 
 	for _, service := range resp.Services {
-		if service.ID == serviceID {
+		if int(service.ID) == serviceID {
 			singleServiceResp.Services = append(singleServiceResp.Services, service)
 			singleServiceResp.Count = 1
 			break
@@ -88,7 +88,7 @@ func (s *ServiceAPI) Get(serviceID int) (*types.ServiceResponse, error) {
 // Documentation: https://docs.librenms.org/API/Services/#list_services
 func (s *ServiceAPI) List() (*types.ServiceResponse, error) {
 	c := s.client
-	req, err := c.newRequest(http.MethodGet, serviceEndpoint, nil, nil)
+	req, err := c.newRequest(opServiceList, http.MethodGet, serviceEndpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +103,7 @@ func (s *ServiceAPI) List() (*types.ServiceResponse, error) {
 		BaseResponse: types.BaseResponse{
 			Status:  internalResp.Status,
 			Message: internalResp.Message,
-			Count:   len(services),
+			Count:   types.Int(len(services)),
 		},
 		Services: services,
 	}, err
@@ -114,7 +114,7 @@ func (s *ServiceAPI) List() (*types.ServiceResponse, error) {
 // Documentation: https://docs.librenms.org/API/Services/#get_service_for_host
 func (s *ServiceAPI) GetForHost(deviceIdentifier string) (*types.ServiceResponse, error) {
 	c := s.client
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("%s/%s", serviceEndpoint, deviceIdentifier), nil, nil)
+	req, err := c.newRequest(opServiceGetForHost, http.MethodGet, fmt.Sprintf("%s/%s", serviceEndpoint, deviceIdentifier), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +129,7 @@ func (s *ServiceAPI) GetForHost(deviceIdentifier string) (*types.ServiceResponse
 		BaseResponse: types.BaseResponse{
 			Status:  internalResp.Status,
 			Message: internalResp.Message,
-			Count:   len(services),
+			Count:   types.Int(len(services)),
 		},
 		Services: services,
 	}, err
@@ -140,7 +140,7 @@ func (s *ServiceAPI) GetForHost(deviceIdentifier string) (*types.ServiceResponse
 // Documentation: https://docs.librenms.org/API/Services/#edit_service_from_host
 func (s *ServiceAPI) Update(serviceID int, service *types.ServiceUpdateRequest) (*types.ServiceResponse, error) {
 	c := s.client
-	req, err := c.newRequest(http.MethodPatch, fmt.Sprintf("%s/%d", serviceEndpoint, serviceID), service.Payload(), nil)
+	req, err := c.newRequest(opServiceUpdate, http.MethodPatch, fmt.Sprintf("%s/%d", serviceEndpoint, serviceID), service.Payload(), nil)
 	if err != nil {
 		return nil, err
 	}
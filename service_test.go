@@ -46,12 +46,12 @@ func TestClient_GetService(t *testing.T) {
 	r.NotNil(serviceResp, "GetService response is nil")
 
 	r.Equal("ok", serviceResp.Status, "Expected status 'ok'")
-	r.Equal(1, serviceResp.Count, "Expected count 1")
+	r.EqualValues(1, serviceResp.Count, "Expected count 1")
 	r.Len(serviceResp.Services, 1, "Expected 1 services")
 
 	service := serviceResp.Services[0]
-	r.Equal(testServiceID, service.ID, "Expected ServiceID "+strconv.Itoa(testServiceID))
-	r.Equal("check other thing", service.Name, "Unxpected service name")
+	r.EqualValues(testServiceID, service.ID, "Expected ServiceID "+strconv.Itoa(testServiceID))
+	r.Equal("check other thing", string(service.Name), "Unxpected service name")
 }
 
 func TestClient_GetServices(t *testing.T) {
@@ -65,12 +65,12 @@ func TestClient_GetServices(t *testing.T) {
 	r.NotNil(serviceResp, "GetServices response is nil")
 
 	r.Equal("ok", serviceResp.Status, "Expected status 'ok'")
-	r.Equal(3, serviceResp.Count, "Expected count 3")
+	r.EqualValues(3, serviceResp.Count, "Expected count 3")
 	r.Len(serviceResp.Services, 3, "Expected 3 services")
 
 	service := serviceResp.Services[0]
-	r.Equal(1, service.ID, "Expected ServiceID 1")
-	r.Equal("check https cert", service.Name, "Expected Service name 'check https cert'")
+	r.EqualValues(1, service.ID, "Expected ServiceID 1")
+	r.Equal("check https cert", string(service.Name), "Expected Service name 'check https cert'")
 }
 
 func TestClient_GetServicesForHost(t *testing.T) {
@@ -84,11 +84,11 @@ func TestClient_GetServicesForHost(t *testing.T) {
 	r.NotNil(serviceResp, "GetServicesForHost response is nil")
 
 	r.Equal("ok", serviceResp.Status, "Expected status 'ok'")
-	r.Equal(2, serviceResp.Count, "Expected count 2")
+	r.EqualValues(2, serviceResp.Count, "Expected count 2")
 	r.Len(serviceResp.Services, 2, "Expected 2 services")
 
 	service := serviceResp.Services[0]
-	r.Equal(1, service.ID, "Expected Service ID 1")
+	r.EqualValues(1, service.ID, "Expected Service ID 1")
 }
 
 func TestClient_CreateService(t *testing.T) {
@@ -0,0 +1,125 @@
+package snapshot
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// portColumns limits the port listing to the fields needed to identify a port.
+var portColumns = types.MustColumns[types.Port]("ifName")
+
+// ExportOptions configures Export.
+type ExportOptions struct {
+	// IncludeSecrets keeps SNMP communities and passwords in the exported devices.
+	// They are stripped by default.
+	IncludeSecrets bool
+	// SkipPortDescriptions skips exporting port descriptions. This requires one
+	// API call per port, which can be slow on large installs.
+	SkipPortDescriptions bool
+}
+
+// Export reads the configuration of the LibreNMS instance behind client into a new archive.
+func Export(client *librenms.Client, opts *ExportOptions) (*Archive, error) {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+
+	archive := &Archive{
+		Version:         Version,
+		CreatedAt:       time.Now().UTC(),
+		Source:          client.GetBaseURL(),
+		SecretsIncluded: opts.IncludeSecrets,
+	}
+
+	locations, err := client.Location.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to export locations: %w", err)
+	}
+	archive.Locations = locations.Locations
+
+	devices, err := client.Device.List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export devices: %w", err)
+	}
+	archive.Devices = devices.Devices
+	if !opts.IncludeSecrets {
+		for i := range archive.Devices {
+			stripSecrets(&archive.Devices[i])
+		}
+	}
+
+	groups, err := client.DeviceGroup.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to export device groups: %w", err)
+	}
+	for _, group := range groups.Groups {
+		g := DeviceGroup{DeviceGroup: group}
+		if group.Type == "static" {
+			members, err := client.DeviceGroup.GetMembers(strconv.Itoa(int(group.ID)))
+			if err != nil {
+				return nil, fmt.Errorf("failed to export members of device group %q: %w", group.Name, err)
+			}
+			for _, member := range members.Devices {
+				g.Members = append(g.Members, int(member.ID))
+			}
+		}
+		archive.DeviceGroups = append(archive.DeviceGroups, g)
+	}
+
+	rules, err := client.AlertRule.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to export alert rules: %w", err)
+	}
+	archive.AlertRules = rules.Rules
+
+	services, err := client.Service.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to export services: %w", err)
+	}
+	archive.Services = services.Services
+
+	if !opts.SkipPortDescriptions {
+		if archive.PortDescriptions, err = exportPortDescriptions(client); err != nil {
+			return nil, fmt.Errorf("failed to export port descriptions: %w", err)
+		}
+	}
+
+	return archive, nil
+}
+
+// exportPortDescriptions collects the non-empty descriptions of all ports.
+func exportPortDescriptions(client *librenms.Client) ([]PortDescription, error) {
+	ports, err := client.Port.GetAllPorts(&types.PortsQueryParams{Columns: portColumns.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions := make([]PortDescription, 0)
+	for _, port := range ports.Ports {
+		resp, err := client.Port.GetPortDescription(int(port.PortID))
+		if err != nil {
+			return nil, fmt.Errorf("port %d: %w", port.PortID, err)
+		}
+		if resp.PortDescription == "" {
+			continue
+		}
+		descriptions = append(descriptions, PortDescription{
+			PortID:      int(port.PortID),
+			DeviceID:    int(port.DeviceID),
+			IfName:      string(port.IfName),
+			Description: string(resp.PortDescription),
+		})
+	}
+	return descriptions, nil
+}
+
+// stripSecrets clears the SNMP credentials of a device.
+func stripSecrets(d *types.Device) {
+	d.Community = ""
+	d.AuthPass = ""
+	d.CryptoPass = ""
+}
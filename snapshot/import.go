@@ -0,0 +1,694 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// Kind identifies the type of object an import result refers to.
+type Kind string
+
+const (
+	KindLocation        Kind = "location"
+	KindDevice          Kind = "device"
+	KindDeviceGroup     Kind = "device_group"
+	KindService         Kind = "service"
+	KindAlertRule       Kind = "alert_rule"
+	KindPortDescription Kind = "port_description"
+)
+
+// Action describes what the importer did with a single object.
+type Action string
+
+const (
+	ActionCreated Action = "created"
+	ActionUpdated Action = "updated"
+	ActionSkipped Action = "skipped"
+	ActionFailed  Action = "failed"
+)
+
+// idPattern matches the "#<id>" suffix of create messages, e.g. "Location added with id #3".
+var idPattern = regexp.MustCompile(`#(\d+)`)
+
+type (
+	// ImportOptions configures Import.
+	ImportOptions struct {
+		// ForceAdd adds devices without checking SNMP reachability first.
+		// This is usually required when the archive was exported without secrets.
+		ForceAdd bool
+		// UpdateExisting updates locations, devices, device groups and alert rules that
+		// already exist in the target (matched by name) instead of skipping them.
+		UpdateExisting bool
+	}
+
+	// IDMap maps IDs from the archive to the IDs of the same objects in the target instance.
+	IDMap struct {
+		Locations    map[int]int
+		Devices      map[int]int
+		DeviceGroups map[int]int
+		AlertRules   map[int]int
+	}
+
+	// ImportResult is the outcome of importing a single object.
+	ImportResult struct {
+		Kind     Kind
+		Name     string
+		SourceID int
+		TargetID int
+		Action   Action
+		Err      error
+	}
+
+	// ImportReport summarizes an import.
+	ImportReport struct {
+		IDs     IDMap
+		Results []ImportResult
+	}
+
+	// importer holds the state of a single import run.
+	importer struct {
+		client *librenms.Client
+		opts   *ImportOptions
+		report *ImportReport
+	}
+)
+
+// Failed returns the results of all objects that could not be imported.
+func (r *ImportReport) Failed() []ImportResult {
+	failed := make([]ImportResult, 0)
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Import replays the archive into the LibreNMS instance behind client.
+//
+// Objects are matched by name (hostname for devices) and created when they do not
+// exist yet. IDs referenced between objects are remapped to the IDs of the target
+// instance. Import continues past failures of individual objects; these are recorded
+// in the report. An error is only returned when the target cannot be read.
+func Import(client *librenms.Client, archive *Archive, opts *ImportOptions) (*ImportReport, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+
+	imp := &importer{
+		client: client,
+		opts:   opts,
+		report: &ImportReport{
+			IDs: IDMap{
+				Locations:    make(map[int]int),
+				Devices:      make(map[int]int),
+				DeviceGroups: make(map[int]int),
+				AlertRules:   make(map[int]int),
+			},
+		},
+	}
+
+	steps := []func(*Archive) error{
+		imp.importLocations,
+		imp.importDevices,
+		imp.importDeviceGroups,
+		imp.importServices,
+		imp.importAlertRules,
+		imp.importPortDescriptions,
+	}
+	for _, step := range steps {
+		if err := step(archive); err != nil {
+			return imp.report, err
+		}
+	}
+	return imp.report, nil
+}
+
+// record appends a result to the report.
+func (imp *importer) record(kind Kind, name string, sourceID, targetID int, action Action, err error) {
+	if err != nil {
+		action = ActionFailed
+	}
+	imp.report.Results = append(imp.report.Results, ImportResult{
+		Kind:     kind,
+		Name:     name,
+		SourceID: sourceID,
+		TargetID: targetID,
+		Action:   action,
+		Err:      err,
+	})
+}
+
+func (imp *importer) importLocations(archive *Archive) error {
+	existing, err := imp.client.Location.List()
+	if err != nil {
+		return fmt.Errorf("failed to list target locations: %w", err)
+	}
+	byName := make(map[string]types.Location, len(existing.Locations))
+	for _, location := range existing.Locations {
+		byName[string(location.Name)] = location
+	}
+
+	for _, location := range archive.Locations {
+		if target, ok := byName[string(location.Name)]; ok {
+			imp.report.IDs.Locations[int(location.ID)] = int(target.ID)
+			var err error
+			action := ActionSkipped
+			if imp.opts.UpdateExisting {
+				if payload := locationChanges(target, location); len(payload.Payload()) > 0 {
+					_, err = imp.client.Location.Update(int(target.ID), payload)
+					action = ActionUpdated
+				}
+			}
+			imp.record(KindLocation, string(location.Name), int(location.ID), int(target.ID), action, err)
+			continue
+		}
+
+		resp, err := imp.client.Location.Create(&types.LocationCreateRequest{
+			Name:             string(location.Name),
+			FixedCoordinates: location.FixedCoordinates,
+			Latitude:         float64(location.Latitude),
+			Longitude:        float64(location.Longitude),
+		})
+		targetID := 0
+		if err == nil {
+			targetID, err = createdID(resp.Message)
+		}
+		if err == nil {
+			imp.report.IDs.Locations[int(location.ID)] = targetID
+		}
+		imp.record(KindLocation, string(location.Name), int(location.ID), targetID, ActionCreated, err)
+	}
+	return nil
+}
+
+// locationChanges returns an update request with the fields of want that differ from have.
+func locationChanges(have, want types.Location) *types.LocationUpdateRequest {
+	update := types.NewLocationUpdateRequest()
+	if have.FixedCoordinates != want.FixedCoordinates {
+		update.SetFixedCoordinates(bool(want.FixedCoordinates))
+	}
+	if have.Latitude != want.Latitude {
+		update.SetLatitude(float64(want.Latitude))
+	}
+	if have.Longitude != want.Longitude {
+		update.SetLongitude(float64(want.Longitude))
+	}
+	return update
+}
+
+func (imp *importer) importDevices(archive *Archive) error {
+	existing, err := imp.client.Device.List(nil)
+	if err != nil {
+		return fmt.Errorf("failed to list target devices: %w", err)
+	}
+	byHostname := make(map[string]types.Device, len(existing.Devices))
+	for _, device := range existing.Devices {
+		byHostname[string(device.Hostname)] = device
+	}
+
+	for _, device := range archive.Devices {
+		if target, ok := byHostname[string(device.Hostname)]; ok {
+			imp.report.IDs.Devices[int(device.DeviceID)] = int(target.DeviceID)
+			var err error
+			action := ActionSkipped
+			if imp.opts.UpdateExisting {
+				var updated bool
+				if updated, err = imp.updateDeviceFields(target, device); updated {
+					action = ActionUpdated
+				}
+			}
+			imp.record(KindDevice, string(device.Hostname), int(device.DeviceID), int(target.DeviceID), action, err)
+			continue
+		}
+
+		request := imp.deviceCreateRequest(device)
+		resp, err := imp.client.Device.Create(request)
+		targetID := 0
+		if err == nil {
+			if len(resp.Devices) == 0 {
+				err = fmt.Errorf("no device returned: %s", resp.Message)
+			} else {
+				created := resp.Devices[0]
+				created.LocationID = types.Int(request.LocationID)
+				targetID = int(created.DeviceID)
+				imp.report.IDs.Devices[int(device.DeviceID)] = targetID
+				_, err = imp.updateDeviceFields(created, device)
+			}
+		}
+		imp.record(KindDevice, string(device.Hostname), int(device.DeviceID), targetID, ActionCreated, err)
+	}
+	return nil
+}
+
+// deviceCreateRequest builds the request for adding a device from the archive to the target.
+func (imp *importer) deviceCreateRequest(device types.Device) *types.DeviceCreateRequest {
+	return &types.DeviceCreateRequest{
+		Hostname:            string(device.Hostname),
+		Display:             string(device.Display),
+		ForceAdd:            imp.opts.ForceAdd,
+		Hardware:            string(device.Hardware),
+		LocationID:          imp.report.IDs.Locations[int(device.LocationID)],
+		OS:                  string(device.OS),
+		OverrideSysLocation: bool(device.OverrideSysLocation),
+		PollerGroup:         int(device.PollerGroup),
+		Port:                int(device.Port),
+		PortAssocMode:       int(device.PortAssociationMode),
+		SNMPAuthAlgo:        string(device.AuthAlgorithm),
+		SNMPAuthLevel:       string(device.AuthLevel),
+		SNMPAuthName:        string(device.AuthName),
+		SNMPAuthPass:        string(device.AuthPass),
+		SNMPCrytoAlgo:       string(device.CryptoAlgorithm),
+		SNMPCryptoPass:      string(device.CryptoPass),
+		SNMPCommunity:       string(device.Community),
+		SNMPDisable:         bool(device.SNMPDisable),
+		SNMPVersion:         string(device.SNMPVersion),
+		SysName:             string(device.SysName),
+		Transport:           string(device.Transport),
+	}
+}
+
+// updateDeviceFields copies the fields that cannot be set on creation and the location of a
+// device from the archive to the target device when they differ. It reports whether the
+// target was updated.
+func (imp *importer) updateDeviceFields(target, device types.Device) (bool, error) {
+	payload := deviceChanges(target, device)
+	if locationID, ok := imp.report.IDs.Locations[int(device.LocationID)]; ok && locationID != int(target.LocationID) {
+		payload.SetLocationID(locationID)
+	}
+	if len(payload.Field) == 0 {
+		return false, nil
+	}
+
+	_, err := imp.client.Device.Update(strconv.Itoa(int(target.DeviceID)), payload)
+	return true, err
+}
+
+// deviceChanges returns an update request with the fields of want that cannot be set on
+// creation and differ from have.
+func deviceChanges(have, want types.Device) *types.DeviceUpdateRequest {
+	update := types.NewDeviceUpdate()
+	if have.Notes != want.Notes {
+		update.SetNotes(string(want.Notes))
+	}
+	if have.Purpose != want.Purpose {
+		update.SetPurpose(string(want.Purpose))
+	}
+	if have.Ignore != want.Ignore {
+		update.SetIgnore(bool(want.Ignore))
+	}
+	if have.Disabled != want.Disabled {
+		update.SetDisabled(bool(want.Disabled))
+	}
+	if have.DisableNotify != want.DisableNotify {
+		update.SetDisableNotify(bool(want.DisableNotify))
+	}
+	return update
+}
+
+func (imp *importer) importDeviceGroups(archive *Archive) error {
+	existing, err := imp.client.DeviceGroup.List()
+	if err != nil {
+		return fmt.Errorf("failed to list target device groups: %w", err)
+	}
+	byName := make(map[string]types.DeviceGroup, len(existing.Groups))
+	for _, group := range existing.Groups {
+		byName[string(group.Name)] = group
+	}
+
+	for _, group := range archive.DeviceGroups {
+		rules := ""
+		if group.Type != "static" {
+			container := group.Rules
+			var err error
+			container.Rules, err = remapRules(container.Rules, map[Kind]map[int]int{KindLocation: imp.report.IDs.Locations})
+			if err == nil {
+				rules, err = container.JSON()
+			}
+			if err != nil {
+				imp.record(KindDeviceGroup, string(group.Name), int(group.ID), 0, ActionFailed, err)
+				continue
+			}
+		}
+		members := remapIDs(group.Members, imp.report.IDs.Devices)
+
+		if target, ok := byName[string(group.Name)]; ok {
+			imp.report.IDs.DeviceGroups[int(group.ID)] = int(target.ID)
+			var err error
+			action := ActionSkipped
+			if imp.opts.UpdateExisting {
+				var changed bool
+				if changed, err = imp.deviceGroupChanged(target, group.DeviceGroup, members, rules); changed {
+					_, err = imp.client.DeviceGroup.Update(strconv.Itoa(int(target.ID)), &types.DeviceGroupUpdateRequest{
+						Description: string(group.Description),
+						Devices:     members,
+						Rules:       rules,
+						Type:        string(group.Type),
+					})
+					action = ActionUpdated
+				}
+			}
+			imp.record(KindDeviceGroup, string(group.Name), int(group.ID), int(target.ID), action, err)
+			continue
+		}
+
+		resp, err := imp.client.DeviceGroup.Create(&types.DeviceGroupCreateRequest{
+			Name:        string(group.Name),
+			Description: string(group.Description),
+			Devices:     members,
+			Rules:       rules,
+			Type:        string(group.Type),
+		})
+		targetID := 0
+		if err == nil {
+			targetID = int(resp.ID)
+			imp.report.IDs.DeviceGroups[int(group.ID)] = targetID
+		}
+		imp.record(KindDeviceGroup, string(group.Name), int(group.ID), targetID, ActionCreated, err)
+	}
+	return nil
+}
+
+// deviceGroupChanged reports whether an existing group differs from the archived group, with
+// its members and rules remapped. The members of static groups are fetched from the target.
+func (imp *importer) deviceGroupChanged(target, group types.DeviceGroup, members []int, rules string) (bool, error) {
+	if target.Description != group.Description || target.Type != group.Type {
+		return true, nil
+	}
+	if group.Type != "static" {
+		current, err := target.Rules.JSON()
+		return current != rules, err
+	}
+
+	resp, err := imp.client.DeviceGroup.GetMembers(strconv.Itoa(int(target.ID)))
+	if err != nil {
+		return false, err
+	}
+	current := make([]int, 0, len(resp.Devices))
+	for _, member := range resp.Devices {
+		current = append(current, int(member.ID))
+	}
+	want := append([]int{}, members...)
+	sort.Ints(current)
+	sort.Ints(want)
+	return !slices.Equal(current, want), nil
+}
+
+func (imp *importer) importServices(archive *Archive) error {
+	existing, err := imp.client.Service.List()
+	if err != nil {
+		return fmt.Errorf("failed to list target services: %w", err)
+	}
+	type serviceKey struct {
+		deviceID int
+		name     string
+		kind     string
+	}
+	known := make(map[serviceKey]types.Service, len(existing.Services))
+	for _, service := range existing.Services {
+		known[serviceKey{int(service.DeviceID), string(service.Name), string(service.Type)}] = service
+	}
+
+	for _, service := range archive.Services {
+		deviceID, ok := imp.report.IDs.Devices[int(service.DeviceID)]
+		if !ok {
+			err := fmt.Errorf("device %d was not imported", service.DeviceID)
+			imp.record(KindService, string(service.Name), int(service.ID), 0, ActionFailed, err)
+			continue
+		}
+		if target, ok := known[serviceKey{deviceID, string(service.Name), string(service.Type)}]; ok {
+			imp.record(KindService, string(service.Name), int(service.ID), int(target.ID), ActionSkipped, nil)
+			continue
+		}
+
+		resp, err := imp.client.Service.Create(strconv.Itoa(deviceID), &types.ServiceCreateRequest{
+			Name:        string(service.Name),
+			Description: string(service.Description),
+			IP:          string(service.IP),
+			Ignore:      service.Ignore,
+			Param:       string(service.Param),
+			Type:        string(service.Type),
+		})
+		targetID := 0
+		if err == nil {
+			// Services are not referenced by other objects, so a missing ID is not an error.
+			targetID, _ = createdID(resp.Message)
+		}
+		imp.record(KindService, string(service.Name), int(service.ID), targetID, ActionCreated, err)
+	}
+	return nil
+}
+
+func (imp *importer) importAlertRules(archive *Archive) error {
+	existing, err := imp.client.AlertRule.List()
+	if err != nil {
+		return fmt.Errorf("failed to list target alert rules: %w", err)
+	}
+	byName := make(map[string]types.AlertRule, len(existing.Rules))
+	for _, rule := range existing.Rules {
+		byName[string(rule.Name)] = rule
+	}
+
+	created := false
+	for _, rule := range archive.AlertRules {
+		payload, err := imp.alertRuleRequest(rule)
+		if err != nil {
+			imp.record(KindAlertRule, string(rule.Name), int(rule.ID), 0, ActionFailed, err)
+			continue
+		}
+
+		if target, ok := byName[string(rule.Name)]; ok {
+			imp.report.IDs.AlertRules[int(rule.ID)] = int(target.ID)
+			var err error
+			action := ActionSkipped
+			if imp.opts.UpdateExisting {
+				_, err = imp.client.AlertRule.Update(&types.AlertRuleUpdateRequest{
+					AlertRuleCreateRequest: *payload,
+					ID:                     int(target.ID),
+				})
+				action = ActionUpdated
+			}
+			imp.record(KindAlertRule, string(rule.Name), int(rule.ID), int(target.ID), action, err)
+			continue
+		}
+
+		_, err = imp.client.AlertRule.Create(payload)
+		if err == nil {
+			created = true
+		}
+		imp.record(KindAlertRule, string(rule.Name), int(rule.ID), 0, ActionCreated, err)
+	}
+	if !created {
+		return nil
+	}
+
+	// The create endpoint does not return the new ID, so look the rules up again by name.
+	existing, err = imp.client.AlertRule.List()
+	if err != nil {
+		return fmt.Errorf("failed to list target alert rules: %w", err)
+	}
+	for _, target := range existing.Rules {
+		byName[string(target.Name)] = target
+	}
+	for i, result := range imp.report.Results {
+		if result.Kind != KindAlertRule || result.Action != ActionCreated {
+			continue
+		}
+		if target, ok := byName[result.Name]; ok {
+			imp.report.Results[i].TargetID = int(target.ID)
+			imp.report.IDs.AlertRules[result.SourceID] = int(target.ID)
+		}
+	}
+	return nil
+}
+
+// alertRuleRequest builds the create request for an alert rule, remapping its device,
+// group and location IDs, including the location and group IDs of its builder, and
+// restoring the settings stored in the rule's extra field.
+func (imp *importer) alertRuleRequest(rule types.AlertRule) (*types.AlertRuleCreateRequest, error) {
+	payload := &types.AlertRuleCreateRequest{
+		Builder:      string(rule.Builder),
+		Devices:      remapIDs(rule.Devices, imp.report.IDs.Devices),
+		Disabled:     rule.Disabled,
+		Groups:       remapIDs(rule.Groups, imp.report.IDs.DeviceGroups),
+		Locations:    remapIDs(rule.Locations, imp.report.IDs.Locations),
+		Name:         string(rule.Name),
+		Notes:        string(rule.Notes),
+		ProcedureURL: string(rule.ProcedureURL),
+		Query:        string(rule.Query),
+		Rule:         string(rule.Rule),
+		Severity:     string(rule.Severity),
+	}
+
+	var extra struct {
+		Mute     bool        `json:"mute"`
+		Count    json.Number `json:"count"`
+		Delay    json.Number `json:"delay"`
+		Interval json.Number `json:"interval"`
+	}
+	if rule.Extra != "" && json.Unmarshal([]byte(rule.Extra), &extra) == nil {
+		payload.Mute = extra.Mute
+		if count, err := extra.Count.Int64(); err == nil {
+			payload.Count = int(count)
+		}
+		payload.Delay = extra.Delay.String()
+		payload.Interval = extra.Interval.String()
+	}
+
+	if rule.Builder == "" {
+		return payload, nil
+	}
+	container, err := rule.ParseBuilder()
+	if err != nil {
+		return nil, fmt.Errorf("invalid builder: %w", err)
+	}
+	container.Rules, err = remapRules(container.Rules, map[Kind]map[int]int{
+		KindLocation:    imp.report.IDs.Locations,
+		KindDeviceGroup: imp.report.IDs.DeviceGroups,
+	})
+	if err == nil {
+		err = payload.SetBuilder(container)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The query is built from the builder by LibreNMS, so the archived one, which may hold
+	// the old IDs, is not sent.
+	payload.Query = ""
+	return payload, nil
+}
+
+func (imp *importer) importPortDescriptions(archive *Archive) error {
+	if len(archive.PortDescriptions) == 0 {
+		return nil
+	}
+
+	ports, err := imp.client.Port.GetAllPorts(&types.PortsQueryParams{Columns: portColumns.String()})
+	if err != nil {
+		return fmt.Errorf("failed to list target ports: %w", err)
+	}
+	type portKey struct {
+		deviceID int
+		ifName   string
+	}
+	portIDs := make(map[portKey]int, len(ports.Ports))
+	for _, port := range ports.Ports {
+		portIDs[portKey{int(port.DeviceID), string(port.IfName)}] = int(port.PortID)
+	}
+
+	for _, desc := range archive.PortDescriptions {
+		deviceID, ok := imp.report.IDs.Devices[desc.DeviceID]
+		if !ok {
+			err := fmt.Errorf("device %d was not imported", desc.DeviceID)
+			imp.record(KindPortDescription, desc.IfName, desc.PortID, 0, ActionFailed, err)
+			continue
+		}
+		portID, ok := portIDs[portKey{deviceID, desc.IfName}]
+		if !ok {
+			err := fmt.Errorf("port %s not found on device %d", desc.IfName, deviceID)
+			imp.record(KindPortDescription, desc.IfName, desc.PortID, 0, ActionFailed, err)
+			continue
+		}
+
+		_, err := imp.client.Port.UpdatePortDescription(portID, desc.Description)
+		imp.record(KindPortDescription, desc.IfName, desc.PortID, portID, ActionUpdated, err)
+	}
+	return nil
+}
+
+// remapIDs translates IDs through m, dropping IDs that have no mapping.
+// Negative IDs (e.g. -1 for "all devices" in alert rules) are kept as-is.
+func remapIDs[T ~int](ids []T, m map[int]int) []int {
+	if ids == nil {
+		return nil
+	}
+	remapped := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id < 0 {
+			remapped = append(remapped, int(id))
+			continue
+		}
+		if target, ok := m[int(id)]; ok {
+			remapped = append(remapped, target)
+		}
+	}
+	return remapped
+}
+
+// ruleIDFields are the fields of rules that hold the IDs of imported objects, with the kind
+// of the objects.
+var ruleIDFields = map[string]Kind{
+	"devices.location_id":                 KindLocation,
+	"locations.id":                        KindLocation,
+	"device_groups.id":                    KindDeviceGroup,
+	"device_group_device.device_group_id": KindDeviceGroup,
+}
+
+// remapRules returns a copy of rules whose IDs of the kinds of ids are translated through
+// the mapping of their kind. It fails when a rule references an object that has no mapping.
+func remapRules(rules []types.DeviceGroupRule, ids map[Kind]map[int]int) ([]types.DeviceGroupRule, error) {
+	if rules == nil {
+		return nil, nil
+	}
+	remapped := make([]types.DeviceGroupRule, len(rules))
+	for i, rule := range rules {
+		var err error
+		if rule.Rules, err = remapRules(rule.Rules, ids); err != nil {
+			return nil, err
+		}
+		field := rule.Field
+		if field == "" {
+			field = rule.ID
+		}
+		kind := ruleIDFields[field]
+		if m, ok := ids[kind]; ok {
+			if rule.Value != "" {
+				if rule.Value, err = remapRuleValue(rule.Value, kind, m); err != nil {
+					return nil, err
+				}
+			}
+			values := make([]string, len(rule.Values))
+			for j, value := range rule.Values {
+				if values[j], err = remapRuleValue(value, kind, m); err != nil {
+					return nil, err
+				}
+			}
+			if rule.Values != nil {
+				rule.Values = values
+			}
+		}
+		remapped[i] = rule
+	}
+	return remapped, nil
+}
+
+// remapRuleValue translates the ID of an object of the given kind in a rule value through m.
+func remapRuleValue(value string, kind Kind, m map[int]int) (string, error) {
+	name := strings.ReplaceAll(string(kind), "_", " ")
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s ID %q in rules", name, value)
+	}
+	target, ok := m[id]
+	if !ok {
+		return "", fmt.Errorf("%s %d in rules was not imported", name, id)
+	}
+	return strconv.Itoa(target), nil
+}
+
+// createdID extracts the ID of a created object from an API message.
+func createdID(message string) (int, error) {
+	match := idPattern.FindStringSubmatch(message)
+	if match == nil {
+		return 0, fmt.Errorf("no ID in response message %q", message)
+	}
+	return strconv.Atoi(match[1])
+}
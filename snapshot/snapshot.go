@@ -0,0 +1,186 @@
+// Package snapshot exports the configuration of a LibreNMS instance into a single
+// versioned archive and replays such an archive into another instance.
+//
+// An archive contains devices, locations, device groups, alert rules, services and
+// port descriptions. It can be serialized as JSON or YAML; both formats use the
+// same field names as the LibreNMS API.
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/javen-yan/librenms-go/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the archive format version written by this package.
+const Version = 1
+
+// Format identifies the serialization format of an archive.
+type Format string
+
+const (
+	// FormatJSON serializes the archive as indented JSON.
+	FormatJSON Format = "json"
+	// FormatYAML serializes the archive as YAML.
+	FormatYAML Format = "yaml"
+)
+
+type (
+	// Archive is a point-in-time snapshot of the configuration of a LibreNMS instance.
+	Archive struct {
+		Version          int               `json:"version"`
+		CreatedAt        time.Time         `json:"created_at"`
+		Source           string            `json:"source,omitempty"`
+		SecretsIncluded  bool              `json:"secrets_included"`
+		Locations        []types.Location  `json:"locations"`
+		Devices          []types.Device    `json:"devices"`
+		DeviceGroups     []DeviceGroup     `json:"device_groups"`
+		AlertRules       []types.AlertRule `json:"alert_rules"`
+		Services         []types.Service   `json:"services"`
+		PortDescriptions []PortDescription `json:"port_descriptions"`
+	}
+
+	// DeviceGroup is a device group together with its static members.
+	//
+	// Members is only populated for static groups, dynamic groups are
+	// recreated from their rules.
+	DeviceGroup struct {
+		types.DeviceGroup
+		Members []int `json:"members,omitempty"`
+	}
+
+	// PortDescription is the description (ifAlias) of a single port.
+	//
+	// Ports are identified by device and ifName on import, as port IDs
+	// differ between instances.
+	PortDescription struct {
+		PortID      int    `json:"port_id"`
+		DeviceID    int    `json:"device_id"`
+		IfName      string `json:"ifName"`
+		Description string `json:"description"`
+	}
+)
+
+// Write serializes the archive to w in the given format.
+func (a *Archive) Write(w io.Writer, format Format) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode archive: %w", err)
+	}
+
+	switch format {
+	case FormatJSON, "":
+		data = append(data, '\n')
+	case FormatYAML:
+		if data, err = jsonToYAML(data); err != nil {
+			return fmt.Errorf("failed to encode archive: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported archive format %q", format)
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// Save writes the archive to the file at path. The format is chosen
+// from the file extension, defaulting to JSON.
+func (a *Archive) Save(path string) error {
+	var buf bytes.Buffer
+	if err := a.Write(&buf, formatFromPath(path)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+// Read decodes an archive from r. Both JSON and YAML input are accepted.
+func Read(r io.Reader) (*Archive, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to decode archive: %w", err)
+		}
+	}
+
+	archive := new(Archive)
+	if err = json.Unmarshal(data, archive); err != nil {
+		return nil, fmt.Errorf("failed to decode archive: %w", err)
+	}
+	if archive.Version < 1 || archive.Version > Version {
+		return nil, fmt.Errorf("unsupported archive version %d", archive.Version)
+	}
+	return archive, nil
+}
+
+// Load reads an archive from the file at path.
+func Load(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// formatFromPath returns the archive format matching the extension of path.
+func formatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatJSON
+	}
+}
+
+// jsonToYAML converts JSON to block-style YAML, keeping the key order and names.
+func jsonToYAML(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	clearStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlToJSON converts YAML to JSON so the archive can be decoded with the
+// JSON field names and custom unmarshalers of the types package.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// clearStyle removes the flow and quoting styles inherited from the JSON input, so the
+// YAML output uses block style. The encoder still quotes strings that would otherwise
+// be read back as a different type.
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
@@ -0,0 +1,359 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/snapshot"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+// loadFixture reads a JSON fixture shared with the client tests.
+func loadFixture(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("..", "fixtures", name))
+	require.NoError(t, err, "Failed to read fixture %s", name)
+	return data
+}
+
+// newTestClient starts a server for the given handler and returns a client for it.
+func newTestClient(t *testing.T, handler http.Handler) *librenms.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := librenms.New(server.URL+"/", "test-token")
+	require.NoError(t, err, "Failed to create client")
+	return client
+}
+
+func TestExport(t *testing.T) {
+	r := require.New(t)
+
+	mux := http.NewServeMux()
+	fixtures := map[string]string{
+		"/api/v0/resources/locations": "get_locations_200.json",
+		"/api/v0/devices":             "get_devices_200.json",
+		"/api/v0/devicegroups":        "get_devicegroups_200.json",
+		"/api/v0/devicegroups/2":      "get_devicegroup_200.json",
+		"/api/v0/rules":               "get_alertrules_200.json",
+		"/api/v0/services":            "get_services_200.json",
+	}
+	for path, fixture := range fixtures {
+		data := loadFixture(t, fixture)
+		mux.HandleFunc(path, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write(data)
+		})
+	}
+	mux.HandleFunc("/api/v0/ports", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"status":"ok","ports":[{"port_id":7,"device_id":5,"ifName":"eth0"},{"port_id":8,"device_id":5,"ifName":"eth1"}]}`)
+	})
+	mux.HandleFunc("/api/v0/ports/7/description", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"status":"ok","port_description":"uplink"}`)
+	})
+	mux.HandleFunc("/api/v0/ports/8/description", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"status":"ok","port_description":""}`)
+	})
+
+	archive, err := snapshot.Export(newTestClient(t, mux), nil)
+	r.NoError(err, "Export returned an error")
+
+	r.Equal(snapshot.Version, archive.Version, "Unexpected archive version")
+	r.False(archive.SecretsIncluded, "Secrets should be excluded by default")
+	r.Len(archive.Locations, 5, "Expected 5 locations")
+	r.Len(archive.DeviceGroups, 3, "Expected 3 device groups")
+	r.Equal([]int{6}, archive.DeviceGroups[2].Members, "Expected members of the static group")
+	r.Len(archive.AlertRules, 12, "Expected 12 alert rules")
+	r.Len(archive.Services, 3, "Expected 3 services")
+
+	r.NotEmpty(archive.Devices, "Expected devices")
+	for _, device := range archive.Devices {
+		r.Empty(device.Community, "Community of %s should be stripped", device.Hostname)
+	}
+
+	r.Equal([]snapshot.PortDescription{
+		{PortID: 7, DeviceID: 5, IfName: "eth0", Description: "uplink"},
+	}, archive.PortDescriptions, "Only non-empty port descriptions should be exported")
+}
+
+func TestArchive_WriteRead(t *testing.T) {
+	archive := &snapshot.Archive{
+		Version:   snapshot.Version,
+		Locations: []types.Location{{ID: 3, Name: "DC1", Latitude: 37.4, FixedCoordinates: true}},
+		Devices:   []types.Device{{DeviceID: 5, Hostname: "sw1", LocationID: 3, Status: true}},
+		DeviceGroups: []snapshot.DeviceGroup{{
+			DeviceGroup: types.DeviceGroup{ID: 2, Name: "core", Type: "static"},
+			Members:     []int{5},
+		}},
+		PortDescriptions: []snapshot.PortDescription{{PortID: 7, DeviceID: 5, IfName: "eth0", Description: "uplink"}},
+	}
+
+	for _, format := range []snapshot.Format{snapshot.FormatJSON, snapshot.FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			r := require.New(t)
+
+			var buf bytes.Buffer
+			r.NoError(archive.Write(&buf, format), "Write returned an error")
+			if format == snapshot.FormatYAML {
+				r.Contains(buf.String(), "port_descriptions:", "YAML should use the JSON field names")
+			}
+
+			decoded, err := snapshot.Read(&buf)
+			r.NoError(err, "Read returned an error")
+			r.Equal(archive.Locations, decoded.Locations, "Locations should round-trip")
+			r.Equal(archive.Devices, decoded.Devices, "Devices should round-trip")
+			r.Equal(archive.DeviceGroups, decoded.DeviceGroups, "Device groups should round-trip")
+			r.Equal(archive.PortDescriptions, decoded.PortDescriptions, "Port descriptions should round-trip")
+		})
+	}
+}
+
+func TestRead_UnsupportedVersion(t *testing.T) {
+	r := require.New(t)
+
+	_, err := snapshot.Read(strings.NewReader(`{"version": 99}`))
+	r.ErrorContains(err, "unsupported archive version", "Expected version error")
+}
+
+// recordedRequest is a request received by the import test server.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   map[string]any
+}
+
+func TestImport(t *testing.T) {
+	r := require.New(t)
+
+	var (
+		mu       sync.Mutex
+		requests []recordedRequest
+	)
+	respond := func(w http.ResponseWriter, body string) {
+		_, _ = io.WriteString(w, body)
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		mu.Lock()
+		rulesCreated := hasRequest(requests, http.MethodPost, "/api/v0/rules")
+		requests = append(requests, recordedRequest{Method: req.Method, Path: req.URL.Path, Body: body})
+		mu.Unlock()
+
+		switch req.Method + " " + req.URL.Path {
+		case "GET /api/v0/resources/locations":
+			respond(w, `{"status":"ok","locations":[{"id":40,"location":"DC2"}]}`)
+		case "POST /api/v0/locations":
+			respond(w, `{"status":"ok","message":"Location added with id #41"}`)
+		case "GET /api/v0/devices":
+			respond(w, `{"status":"ok","devices":[]}`)
+		case "POST /api/v0/devices/":
+			respond(w, `{"status":"ok","devices":[{"device_id":90,"hostname":"sw1"}]}`)
+		case "PATCH /api/v0/devices/90":
+			respond(w, `{"status":"ok"}`)
+		case "GET /api/v0/devicegroups":
+			respond(w, `{"status":"ok","groups":[]}`)
+		case "POST /api/v0/devicegroups":
+			respond(w, `{"status":"ok","id":70}`)
+		case "GET /api/v0/services":
+			respond(w, `{"status":"ok","services":[[]]}`)
+		case "POST /api/v0/services/90":
+			respond(w, `{"status":"ok","message":"Service ping has been added to device 90 (#12)"}`)
+		case "GET /api/v0/rules":
+			if rulesCreated {
+				respond(w, `{"status":"ok","rules":[{"id":80,"name":"Device down"}]}`)
+				return
+			}
+			respond(w, `{"status":"ok","rules":[]}`)
+		case "POST /api/v0/rules":
+			respond(w, `{"status":"ok"}`)
+		case "GET /api/v0/ports":
+			respond(w, `{"status":"ok","ports":[{"port_id":300,"device_id":90,"ifName":"eth0"}]}`)
+		case "PATCH /api/v0/ports/300/description":
+			respond(w, `{"status":"ok"}`)
+		default:
+			http.Error(w, `{"status":"error","message":"unexpected request"}`, http.StatusNotFound)
+		}
+	})
+
+	archive := &snapshot.Archive{
+		Version: snapshot.Version,
+		Locations: []types.Location{
+			{ID: 1, Name: "DC1"},
+			{ID: 2, Name: "DC2"},
+		},
+		Devices: []types.Device{{DeviceID: 5, Hostname: "sw1", OS: "ios", LocationID: 1, Notes: "core switch"}},
+		DeviceGroups: []snapshot.DeviceGroup{{
+			DeviceGroup: types.DeviceGroup{ID: 2, Name: "core", Type: "static"},
+			Members:     []int{5, 6},
+		}},
+		Services: []types.Service{{ID: 9, DeviceID: 5, Name: "ping", Type: "icmp"}},
+		AlertRules: []types.AlertRule{{
+			ID: 1, Name: "Device down", Devices: []types.Int{5}, Groups: []types.Int{2}, Locations: []types.Int{2}, Extra: `{"count":"-1","delay":300}`,
+			Builder: `{"condition":"AND","rules":[{"id":"devices.location_id","field":"devices.location_id","operator":"equal","value":"1"},` +
+				`{"id":"device_groups.id","field":"device_groups.id","operator":"in","value":["2"]}]}`,
+			Query: "SELECT * FROM devices WHERE devices.location_id = 1",
+		}},
+		PortDescriptions: []snapshot.PortDescription{{PortID: 7, DeviceID: 5, IfName: "eth0", Description: "uplink"}},
+	}
+
+	report, err := snapshot.Import(newTestClient(t, handler), archive, &snapshot.ImportOptions{ForceAdd: true})
+	r.NoError(err, "Import returned an error")
+	r.Empty(report.Failed(), "Expected no failed imports")
+
+	r.Equal(map[int]int{1: 41, 2: 40}, report.IDs.Locations, "Unexpected location mapping")
+	r.Equal(map[int]int{5: 90}, report.IDs.Devices, "Unexpected device mapping")
+	r.Equal(map[int]int{2: 70}, report.IDs.DeviceGroups, "Unexpected device group mapping")
+	r.Equal(map[int]int{1: 80}, report.IDs.AlertRules, "Unexpected alert rule mapping")
+
+	device := findRequest(t, requests, http.MethodPost, "/api/v0/devices/")
+	r.EqualValues(41, device.Body["location_id"], "Device location should be remapped")
+	r.Equal(true, device.Body["force_add"], "Expected force_add")
+
+	update := findRequest(t, requests, http.MethodPatch, "/api/v0/devices/90")
+	r.Equal([]any{"notes"}, update.Body["field"], "Expected notes update after create")
+
+	group := findRequest(t, requests, http.MethodPost, "/api/v0/devicegroups")
+	r.Equal([]any{float64(90)}, group.Body["devices"], "Static members should be remapped, unknown devices dropped")
+
+	rule := findRequest(t, requests, http.MethodPost, "/api/v0/rules")
+	r.Equal([]any{float64(90)}, rule.Body["devices"], "Rule devices should be remapped")
+	r.Equal([]any{float64(70)}, rule.Body["groups"], "Rule groups should be remapped")
+	r.Equal([]any{float64(40)}, rule.Body["locations"], "Rule locations should be remapped")
+	r.EqualValues(-1, rule.Body["count"], "Rule count should be restored from extra")
+	r.Empty(rule.Body["query"], "The archived query should not be sent")
+
+	var builder types.DeviceGroupRuleContainer
+	r.NoError(json.Unmarshal([]byte(rule.Body["builder"].(string)), &builder), "Failed to decode the rule builder")
+	r.Equal("41", builder.Rules[0].Value, "Builder location should be remapped")
+	r.Equal([]string{"70"}, builder.Rules[1].Values, "Builder device groups should be remapped")
+
+	desc := findRequest(t, requests, http.MethodPatch, "/api/v0/ports/300/description")
+	r.Equal("uplink", desc.Body["description"], "Unexpected port description")
+}
+
+func hasRequest(requests []recordedRequest, method, path string) bool {
+	for _, req := range requests {
+		if req.Method == method && req.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+func findRequest(t *testing.T, requests []recordedRequest, method, path string) recordedRequest {
+	for _, req := range requests {
+		if req.Method == method && req.Path == path {
+			return req
+		}
+	}
+	t.Fatalf("Expected %s %s request", method, path)
+	return recordedRequest{}
+}
+
+func TestImport_UpdateExisting(t *testing.T) {
+	r := require.New(t)
+
+	var (
+		mu       sync.Mutex
+		requests []recordedRequest
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		mu.Lock()
+		requests = append(requests, recordedRequest{Method: req.Method, Path: req.URL.Path, Body: body})
+		mu.Unlock()
+
+		switch req.Method + " " + req.URL.Path {
+		case "GET /api/v0/resources/locations":
+			_, _ = io.WriteString(w, `{"status":"ok","locations":[{"id":40,"location":"DC1"},{"id":41,"location":"DC2"}]}`)
+		case "GET /api/v0/devices":
+			_, _ = io.WriteString(w, `{"status":"ok","devices":[
+				{"device_id":90,"hostname":"sw1","location_id":40,"notes":"old"},
+				{"device_id":91,"hostname":"sw2","location_id":41,"disabled":1}]}`)
+		case "PATCH /api/v0/devices/90":
+			_, _ = io.WriteString(w, `{"status":"ok"}`)
+		case "GET /api/v0/devicegroups":
+			_, _ = io.WriteString(w, `{"status":"ok","groups":[
+				{"id":71,"name":"core","type":"static"},
+				{"id":72,"name":"edge","type":"dynamic","rules":{"condition":"AND","rules":[
+					{"id":"devices.location_id","field":"devices.location_id","operator":"equal","value":"40"}]}}]}`)
+		case "GET /api/v0/devicegroups/71":
+			_, _ = io.WriteString(w, `{"status":"ok","devices":[{"device_id":91},{"device_id":90}]}`)
+		case "PATCH /api/v0/devicegroups/72":
+			_, _ = io.WriteString(w, `{"status":"ok"}`)
+		case "POST /api/v0/devicegroups":
+			_, _ = io.WriteString(w, `{"status":"ok","id":70}`)
+		case "GET /api/v0/services":
+			_, _ = io.WriteString(w, `{"status":"ok","services":[[]]}`)
+		case "GET /api/v0/rules":
+			_, _ = io.WriteString(w, `{"status":"ok","rules":[]}`)
+		default:
+			http.Error(w, `{"status":"error","message":"unexpected request"}`, http.StatusNotFound)
+		}
+	})
+
+	archive := &snapshot.Archive{
+		Version:   snapshot.Version,
+		Locations: []types.Location{{ID: 1, Name: "DC1"}, {ID: 2, Name: "DC2"}},
+		Devices: []types.Device{
+			{DeviceID: 5, Hostname: "sw1", LocationID: 2, Notes: "core switch"},
+			{DeviceID: 6, Hostname: "sw2", LocationID: 2, Disabled: true},
+		},
+		DeviceGroups: []snapshot.DeviceGroup{
+			{DeviceGroup: types.DeviceGroup{ID: 2, Name: "dc", Type: "dynamic",
+				Rules: types.DeviceGroupRuleContainer{Condition: "OR", Rules: []types.DeviceGroupRule{
+					{ID: "devices.location_id", Field: "devices.location_id", Operator: "equal", Value: "1"},
+					{ID: "locations.id", Field: "locations.id", Operator: "in", Values: []string{"1", "2"}},
+				}},
+			}},
+			{DeviceGroup: types.DeviceGroup{ID: 3, Name: "core", Type: "static"}, Members: []int{5, 6}},
+			{DeviceGroup: types.DeviceGroup{ID: 4, Name: "edge", Type: "dynamic",
+				Rules: types.DeviceGroupRuleContainer{Condition: "AND", Rules: []types.DeviceGroupRule{
+					{ID: "devices.location_id", Field: "devices.location_id", Operator: "equal", Value: "2"},
+				}},
+			}},
+		},
+	}
+
+	report, err := snapshot.Import(newTestClient(t, handler), archive, &snapshot.ImportOptions{UpdateExisting: true})
+	r.NoError(err, "Import returned an error")
+	r.Empty(report.Failed(), "Expected no failed imports")
+	r.Equal(map[int]int{5: 90, 6: 91}, report.IDs.Devices, "Unexpected device mapping")
+
+	actions := make(map[string]snapshot.Action)
+	groupActions := make(map[string]snapshot.Action)
+	for _, result := range report.Results {
+		switch result.Kind {
+		case snapshot.KindDevice:
+			actions[result.Name] = result.Action
+		case snapshot.KindDeviceGroup:
+			groupActions[result.Name] = result.Action
+		}
+	}
+	r.Equal(map[string]snapshot.Action{"sw1": snapshot.ActionUpdated, "sw2": snapshot.ActionSkipped}, actions, "Only changed devices should be updated")
+	r.Equal(map[string]snapshot.Action{"dc": snapshot.ActionCreated, "core": snapshot.ActionSkipped, "edge": snapshot.ActionUpdated},
+		groupActions, "Only changed device groups should be updated")
+	r.False(hasRequest(requests, http.MethodPatch, "/api/v0/devicegroups/71"), "Unchanged groups should not be updated")
+
+	update := findRequest(t, requests, http.MethodPatch, "/api/v0/devices/90")
+	r.Equal([]any{"notes", "location_id"}, update.Body["field"], "Expected the changed fields")
+	r.Equal([]any{"core switch", float64(41)}, update.Body["data"], "Expected the archived values, remapped")
+	r.False(hasRequest(requests, http.MethodPatch, "/api/v0/devices/91"), "Unchanged devices should not be updated")
+
+	var rules types.DeviceGroupRuleContainer
+	group := findRequest(t, requests, http.MethodPost, "/api/v0/devicegroups")
+	r.NoError(json.Unmarshal([]byte(group.Body["rules"].(string)), &rules), "Failed to decode the group rules")
+	r.Equal("40", rules.Rules[0].Value, "Rule location should be remapped")
+	r.Equal([]string{"40", "41"}, rules.Rules[1].Values, "Rule locations should be remapped")
+	r.Equal("1", archive.DeviceGroups[0].Rules.Rules[0].Value, "The archive should not be modified")
+}
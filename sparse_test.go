@@ -0,0 +1,124 @@
+package librenms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparse(t *testing.T) {
+	r := require.New(t)
+
+	var device types.Sparse[types.Device]
+	r.NoError(json.Unmarshal([]byte(`{"device_id":1,"hostname":"core1","ignore":0,"notes":null}`), &device), "Failed to decode the device")
+	r.Equal(types.Device{DeviceID: 1, Hostname: "core1"}, device.Value, "Unexpected device")
+	r.Equal([]string{"device_id", "hostname", "ignore", "notes"}, device.Fields(), "Expected the present fields")
+	r.True(device.Has("ignore"), "Expected ignore to be present")
+	r.False(device.Has("disabled"), "Expected disabled not to be present")
+
+	data, err := json.Marshal(device)
+	r.NoError(err, "Failed to encode the device")
+	r.JSONEq(`{"device_id":1,"hostname":"core1","ignore":false,"notes":""}`, string(data), "Expected the present fields only")
+
+	updated := device.Value
+	updated.Hostname = "core1"
+	updated.Notes = "rack 4"
+	updated.Ignore = false
+	updated.Disabled = false
+	r.Equal(map[string]any{"notes": types.String("rack 4")}, device.Changes(updated), "Expected only the changed fields")
+
+	updated.Ignore = true
+	updated.Disabled = true
+	r.Equal(map[string]any{"notes": types.String("rack 4"), "ignore": types.Bool(true), "disabled": types.Bool(true)},
+		device.Changes(updated), "Expected fields that weren't present to be sent when set")
+
+	full := types.NewSparse(types.Device{DeviceID: 1, Disabled: true})
+	r.True(full.Has("ignore"), "Expected every field without explicit fields")
+	r.Equal(map[string]any{"disabled": types.Bool(false)}, full.Changes(types.Device{DeviceID: 1}), "Expected cleared fields to be sent")
+}
+
+func TestSparse_NotStruct(t *testing.T) {
+	r := require.New(t)
+
+	n := types.NewSparse(1)
+	r.Empty(n.Fields(), "Expected no fields")
+	r.Empty(n.Changes(2), "Expected no changes")
+	_, err := json.Marshal(n)
+	r.ErrorContains(err, "int is not a struct type", "Expected an error encoding a non-struct type")
+
+	var device types.Sparse[*types.Device]
+	err = json.Unmarshal([]byte(`{"device_id":1}`), &device)
+	r.ErrorContains(err, "*types.Device is not a struct type", "Expected an error decoding a pointer type")
+
+	_, err = types.NewColumns[string]("name")
+	r.ErrorContains(err, "string is not a struct type", "Expected an error for columns of a non-struct type")
+}
+
+func TestSparse_Embedded(t *testing.T) {
+	r := require.New(t)
+
+	type (
+		Meta struct {
+			Site  string `json:"site"`
+			Owner string `json:"owner"`
+		}
+		Extra struct {
+			Owner string `json:"owner"`
+		}
+		Item struct {
+			*Meta
+			Extra `json:"extra"`
+			Name  string `json:"name"`
+			Site  string `json:"site"`
+		}
+	)
+
+	var item types.Sparse[Item]
+	r.NoError(json.Unmarshal([]byte(`{"name":"a","owner":"ops","extra":{"owner":"x"}}`), &item), "Failed to decode the item")
+	r.Equal([]string{"extra", "name", "owner"}, item.Fields(), "Unexpected present fields")
+	r.Equal("ops", item.Value.Meta.Owner, "Expected the promoted field to be decoded")
+
+	full := types.NewSparse(Item{Name: "a"})
+	r.Equal([]string{"extra", "name", "owner", "site"}, full.Fields(), "Expected the promoted fields, the shallowest one for site")
+	r.Equal(map[string]any{"owner": "ops"}, full.Changes(Item{Meta: &Meta{Owner: "ops"}, Name: "a"}), "Expected the change of the promoted field")
+
+	data, err := json.Marshal(full)
+	r.NoError(err, "Failed to encode the item")
+	r.JSONEq(`{"extra":{"owner":""},"name":"a","site":""}`, string(data), "Expected the fields of a nil embedded pointer to be omitted")
+}
+
+func TestDiffDevices_Sparse(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	srv.AddDevice(types.Device{DeviceID: 1, Hostname: "core1", Notes: "rack 4", Ignore: true})
+	client := srv.Client()
+
+	fields, err := client.Device.GetWithFields("core1")
+	r.NoError(err, "GetWithFields returned an error")
+	current := fields.Devices[0]
+	r.True(current.Has("ignore"), "Expected ignore to be returned")
+	r.False(current.Has("disabled"), "Expected disabled to be omitted")
+
+	updated := current.Value
+	updated.Notes = "rack 5"
+	payload := types.DiffDevices(current.Value, updated)
+	r.Equal([]string{"notes"}, payload.Field, "Expected only the notes")
+	r.Equal([]any{"rack 5"}, payload.Data, "Expected the new notes")
+
+	updated.Ignore = false
+	payload = types.DiffDevices(current.Value, updated)
+	r.Equal([]string{"ignore", "notes"}, payload.Field, "Expected the cleared field")
+	_, err = client.Device.Update("core1", payload)
+	r.NoError(err, "Update returned an error")
+
+	resp, err := client.Device.Get("core1")
+	r.NoError(err, "Get returned an error")
+	r.Equal(types.String("rack 5"), resp.Devices[0].Notes, "Expected the notes to be updated")
+	r.False(bool(resp.Devices[0].Ignore), "Expected the device not to be ignored")
+
+	r.Empty(types.DiffDevices(resp.Devices[0], resp.Devices[0]).Field, "Expected no changes")
+}
@@ -0,0 +1,81 @@
+package librenms
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// streamArray sends req and decodes the elements of the array in the field of the JSON
+// response one at a time, calling fn for each of them, so that the memory used doesn't
+// grow with the response size. Other fields are skipped. It stops at the first error
+// returned by fn and returns it.
+//
+// The requests aren't coalesced, but WithCache still buffers the responses it stores.
+func streamArray[T any](c *Client, req *http.Request, field string, fn func(T) error) error {
+	resp, err := c.rawDo(req)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp.Body)
+
+	dec := json.NewDecoder(resp.Body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return decodeError(err)
+		}
+		key, _ := token.(string)
+		if key != field {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return decodeError(err)
+			}
+			continue
+		}
+
+		token, err = dec.Token()
+		if err != nil {
+			return decodeError(err)
+		}
+		if token == nil {
+			continue
+		}
+		if token != json.Delim('[') {
+			return decodeError(fmt.Errorf("expected an array for %q, got %v", field, token))
+		}
+		for dec.More() {
+			var item T
+			if err := dec.Decode(&item); err != nil {
+				return decodeError(err)
+			}
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// expectDelim reads the next token of dec, which must be delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return decodeError(err)
+	}
+	if token != delim {
+		return decodeError(fmt.Errorf("expected %v, got %v", delim, token))
+	}
+	return nil
+}
+
+// decodeError wraps an error of the streaming decoder like do does.
+func decodeError(err error) error {
+	return fmt.Errorf("failure decoding response: %w", err)
+}
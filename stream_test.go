@@ -0,0 +1,154 @@
+package librenms_test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+// streamServer serves n generated items in the field of a collection response at path.
+func streamServer(t testing.TB, path, field string, n int, item func(i int) string) *librenms.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.Error(w, `{"status":"error","message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		bw := bufio.NewWriter(w)
+		fmt.Fprintf(bw, `{"status":"ok","meta":{"query":%q},"%s":[`, r.URL.RawQuery, field)
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			bw.WriteString(item(i))
+		}
+		fmt.Fprintf(bw, `],"count":%d}`, n)
+		_ = bw.Flush()
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := librenms.New(srv.URL+"/", "test-token")
+	require.NoError(t, err, "Failed to create client")
+	return client
+}
+
+func TestStreamAllPorts(t *testing.T) {
+	r := require.New(t)
+
+	const n = 20000
+	client := streamServer(t, "/api/v0/ports", "ports", n, func(i int) string {
+		return fmt.Sprintf(`{"port_id":%d,"device_id":%d,"ifName":"Gi0/%d"}`, i+1, i/48+1, i%48)
+	})
+
+	var count int
+	err := client.Port.StreamAllPorts(&types.PortsQueryParams{Columns: "port_id,device_id,ifName"}, func(port types.Port) error {
+		count++
+		r.EqualValues(count, port.PortID, "Expected the ports in order")
+		return nil
+	})
+	r.NoError(err, "StreamAllPorts returned an error")
+	r.Equal(n, count, "Expected every port")
+
+	errStop := errors.New("stop")
+	count = 0
+	err = client.Port.StreamAllPorts(nil, func(types.Port) error {
+		count++
+		if count == 3 {
+			return errStop
+		}
+		return nil
+	})
+	r.ErrorIs(err, errStop, "Expected the callback error")
+	r.Equal(3, count, "Expected the stream to stop at the callback error")
+}
+
+func TestStreamCollections(t *testing.T) {
+	r := require.New(t)
+
+	client := streamServer(t, "/api/v0/resources/vlans", "vlans", 3, func(i int) string {
+		return fmt.Sprintf(`{"vlan_id":"%d","vlan_vlan":"%d","vlan_name":"v%d"}`, i+1, 100+i, i)
+	})
+	var vlans []types.VLAN
+	err := client.Switching.StreamAllVLANs(nil, func(vlan types.VLAN) error {
+		vlans = append(vlans, vlan)
+		return nil
+	})
+	r.NoError(err, "StreamAllVLANs returned an error")
+	r.Len(vlans, 3, "Expected every VLAN")
+	r.Equal("v2", string(vlans[2].VLANName), "Unexpected VLAN")
+
+	client = streamServer(t, "/api/v0/resources/links", "links", 2, func(i int) string {
+		return fmt.Sprintf(`{"id":%d,"local_device_id":1,"remote_hostname":"sw%d"}`, i+1, i)
+	})
+	var links []types.Link
+	err = client.Switching.StreamAllLinks(nil, func(link types.Link) error {
+		links = append(links, link)
+		return nil
+	})
+	r.NoError(err, "StreamAllLinks returned an error")
+	r.Len(links, 2, "Expected every link")
+
+	client = streamServer(t, "/api/v0/resources/ip/addresses/ipv4", "ip_addresses", 4, func(i int) string {
+		return fmt.Sprintf(`{"ipv4_address_id":%d,"ipv4_address":"10.0.0.%d","ipv4_prefixlen":24}`, i+1, i+1)
+	})
+	var count int
+	err = client.Routing.StreamIPAddresses("ipv4", func(types.IPAddress) error {
+		count++
+		return nil
+	})
+	r.NoError(err, "StreamIPAddresses returned an error")
+	r.Equal(4, count, "Expected every address")
+
+	err = client.Routing.StreamIPAddresses("ipv6", func(types.IPAddress) error { return nil })
+	var apiErr *librenms.ErrorResponse
+	r.ErrorAs(err, &apiErr, "Expected an API error")
+	r.Equal(http.StatusNotFound, apiErr.Response.StatusCode, "Unexpected status")
+}
+
+func TestStream_MalformedJSON(t *testing.T) {
+	r := require.New(t)
+
+	client := streamServer(t, "/api/v0/ports", "ports", 3, func(i int) string {
+		if i == 2 {
+			return `{"port_id":`
+		}
+		return fmt.Sprintf(`{"port_id":%d}`, i+1)
+	})
+	var count int
+	err := client.Port.StreamAllPorts(nil, func(types.Port) error {
+		count++
+		return nil
+	})
+	r.ErrorContains(err, "failure decoding response", "Expected a decoding error")
+	r.Equal(2, count, "Expected the ports before the error")
+}
+
+func BenchmarkGetAllPorts(b *testing.B) {
+	client := streamServer(b, "/api/v0/ports", "ports", 50000, func(i int) string {
+		return fmt.Sprintf(`{"port_id":%d,"device_id":1,"ifName":"Gi0/%d","ifAlias":"uplink to core"}`, i+1, i)
+	})
+	b.Run("Decode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := client.Port.GetAllPorts(nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Stream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := client.Port.StreamAllPorts(nil, func(types.Port) error { return nil }); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -20,22 +20,9 @@ const (
 // Documentation: https://docs.librenms.org/API/Switching/#list_vlans
 // Route: /api/v0/resources/vlans
 func (s *SwitchingAPI) GetAllVLANs(params *types.SwitchingQueryParams) (*types.VLANsResponse, error) {
-	var queryParams *url.Values
-	if params != nil {
-		query := url.Values{}
-		if params.Columns != "" {
-			query.Set("columns", params.Columns)
-		}
-		if params.Filter != "" {
-			query.Set("filter", params.Filter)
-		}
-		if len(query) > 0 {
-			queryParams = &query
-		}
-	}
-
+	queryParams := switchingQuery(params)
 	var resp types.VLANsResponse
-	httpReq, err := s.client.newRequest(http.MethodGet, vlansEndpoint, nil, queryParams)
+	httpReq, err := s.client.newRequest(opSwitchingGetAllVLANs, http.MethodGet, vlansEndpoint, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +30,17 @@ func (s *SwitchingAPI) GetAllVLANs(params *types.SwitchingQueryParams) (*types.V
 	return &resp, err
 }
 
+// StreamAllVLANs is like GetAllVLANs but decodes the VLANs one at a time and calls fn for
+// each of them, so that memory use stays flat on large installations. It stops at the first
+// error returned by fn and returns it.
+func (s *SwitchingAPI) StreamAllVLANs(params *types.SwitchingQueryParams, fn func(types.VLAN) error) error {
+	httpReq, err := s.client.newRequest(opSwitchingStreamAllVLANs, http.MethodGet, vlansEndpoint, nil, switchingQuery(params))
+	if err != nil {
+		return err
+	}
+	return streamArray(s.client, httpReq, "vlans", fn)
+}
+
 // GetDeviceVLANs retrieves a list of all VLANs for a given device
 // hostname can be either the device hostname or id
 //
@@ -51,22 +49,9 @@ func (s *SwitchingAPI) GetAllVLANs(params *types.SwitchingQueryParams) (*types.V
 func (s *SwitchingAPI) GetDeviceVLANs(hostname string, params *types.SwitchingQueryParams) (*types.VLANsResponse, error) {
 	path := fmt.Sprintf("devices/%s/vlans", hostname)
 
-	var queryParams *url.Values
-	if params != nil {
-		query := url.Values{}
-		if params.Columns != "" {
-			query.Set("columns", params.Columns)
-		}
-		if params.Filter != "" {
-			query.Set("filter", params.Filter)
-		}
-		if len(query) > 0 {
-			queryParams = &query
-		}
-	}
-
+	queryParams := switchingQuery(params)
 	var resp types.VLANsResponse
-	httpReq, err := s.client.newRequest(http.MethodGet, path, nil, queryParams)
+	httpReq, err := s.client.newRequest(opSwitchingGetDeviceVLANs, http.MethodGet, path, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -79,22 +64,20 @@ func (s *SwitchingAPI) GetDeviceVLANs(hostname string, params *types.SwitchingQu
 // Documentation: https://docs.librenms.org/API/Switching/#list_links
 // Route: /api/v0/resources/links
 func (s *SwitchingAPI) GetAllLinks(params *types.SwitchingQueryParams) (*types.LinksResponse, error) {
-	var queryParams *url.Values
-	if params != nil {
-		query := url.Values{}
-		if params.Columns != "" {
-			query.Set("columns", params.Columns)
-		}
-		if params.Filter != "" {
-			query.Set("filter", params.Filter)
-		}
-		if len(query) > 0 {
-			queryParams = &query
-		}
+	queryParams := switchingQuery(params)
+	var resp types.LinksResponse
+	httpReq, err := s.client.newRequest(opSwitchingGetAllLinks, http.MethodGet, linksEndpoint, nil, queryParams)
+	if err != nil {
+		return nil, err
 	}
+	err = s.client.do(httpReq, &resp)
+	return &resp, err
+}
 
-	var resp types.LinksResponse
-	httpReq, err := s.client.newRequest(http.MethodGet, linksEndpoint, nil, queryParams)
+// GetAllLinksWithFields is like GetAllLinks, but records the fields returned for each link.
+func (s *SwitchingAPI) GetAllLinksWithFields(params *types.SwitchingQueryParams) (*types.LinksWithFieldsResponse, error) {
+	var resp types.LinksWithFieldsResponse
+	httpReq, err := s.client.newRequest(opSwitchingGetAllLinksWithFields, http.MethodGet, linksEndpoint, nil, switchingQuery(params))
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +85,16 @@ func (s *SwitchingAPI) GetAllLinks(params *types.SwitchingQueryParams) (*types.L
 	return &resp, err
 }
 
+// StreamAllLinks is like GetAllLinks but decodes the links one at a time and calls fn for
+// each of them. It stops at the first error returned by fn and returns it.
+func (s *SwitchingAPI) StreamAllLinks(params *types.SwitchingQueryParams, fn func(types.Link) error) error {
+	httpReq, err := s.client.newRequest(opSwitchingStreamAllLinks, http.MethodGet, linksEndpoint, nil, switchingQuery(params))
+	if err != nil {
+		return err
+	}
+	return streamArray(s.client, httpReq, "links", fn)
+}
+
 // GetDeviceLinks retrieves a list of Links per given device
 // hostname can be either the device hostname or id
 //
@@ -110,22 +103,9 @@ func (s *SwitchingAPI) GetAllLinks(params *types.SwitchingQueryParams) (*types.L
 func (s *SwitchingAPI) GetDeviceLinks(hostname string, params *types.SwitchingQueryParams) (*types.LinksResponse, error) {
 	path := fmt.Sprintf("devices/%s/links", hostname)
 
-	var queryParams *url.Values
-	if params != nil {
-		query := url.Values{}
-		if params.Columns != "" {
-			query.Set("columns", params.Columns)
-		}
-		if params.Filter != "" {
-			query.Set("filter", params.Filter)
-		}
-		if len(query) > 0 {
-			queryParams = &query
-		}
-	}
-
+	queryParams := switchingQuery(params)
 	var resp types.LinksResponse
-	httpReq, err := s.client.newRequest(http.MethodGet, path, nil, queryParams)
+	httpReq, err := s.client.newRequest(opSwitchingGetDeviceLinks, http.MethodGet, path, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -140,22 +120,9 @@ func (s *SwitchingAPI) GetDeviceLinks(hostname string, params *types.SwitchingQu
 func (s *SwitchingAPI) GetLink(linkID int, params *types.SwitchingQueryParams) (*types.LinksResponse, error) {
 	path := fmt.Sprintf("%s/%d", linksEndpoint, linkID)
 
-	var queryParams *url.Values
-	if params != nil {
-		query := url.Values{}
-		if params.Columns != "" {
-			query.Set("columns", params.Columns)
-		}
-		if params.Filter != "" {
-			query.Set("filter", params.Filter)
-		}
-		if len(query) > 0 {
-			queryParams = &query
-		}
-	}
-
+	queryParams := switchingQuery(params)
 	var resp types.LinksResponse
-	httpReq, err := s.client.newRequest(http.MethodGet, path, nil, queryParams)
+	httpReq, err := s.client.newRequest(opSwitchingGetLink, http.MethodGet, path, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -174,22 +141,9 @@ func (s *SwitchingAPI) GetPortFDB(mac string, params *types.SwitchingQueryParams
 		path = fmt.Sprintf("%s/%s", fdbEndpoint, mac)
 	}
 
-	var queryParams *url.Values
-	if params != nil {
-		query := url.Values{}
-		if params.Columns != "" {
-			query.Set("columns", params.Columns)
-		}
-		if params.Filter != "" {
-			query.Set("filter", params.Filter)
-		}
-		if len(query) > 0 {
-			queryParams = &query
-		}
-	}
-
+	queryParams := switchingQuery(params)
 	var resp types.PortFDBResponse
-	httpReq, err := s.client.newRequest(http.MethodGet, path, nil, queryParams)
+	httpReq, err := s.client.newRequest(opSwitchingGetPortFDB, http.MethodGet, path, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -205,22 +159,9 @@ func (s *SwitchingAPI) GetPortFDB(mac string, params *types.SwitchingQueryParams
 func (s *SwitchingAPI) GetPortFDBDetail(mac string, params *types.SwitchingQueryParams) (*types.PortFDBDetailResponse, error) {
 	path := fmt.Sprintf("%s/%s/detail", fdbEndpoint, mac)
 
-	var queryParams *url.Values
-	if params != nil {
-		query := url.Values{}
-		if params.Columns != "" {
-			query.Set("columns", params.Columns)
-		}
-		if params.Filter != "" {
-			query.Set("filter", params.Filter)
-		}
-		if len(query) > 0 {
-			queryParams = &query
-		}
-	}
-
+	queryParams := switchingQuery(params)
 	var resp types.PortFDBDetailResponse
-	httpReq, err := s.client.newRequest(http.MethodGet, path, nil, queryParams)
+	httpReq, err := s.client.newRequest(opSwitchingGetPortFDBDetail, http.MethodGet, path, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -239,25 +180,30 @@ func (s *SwitchingAPI) GetPortNAC(mac string, params *types.SwitchingQueryParams
 		path = fmt.Sprintf("%s/%s", nacEndpoint, mac)
 	}
 
-	var queryParams *url.Values
-	if params != nil {
-		query := url.Values{}
-		if params.Columns != "" {
-			query.Set("columns", params.Columns)
-		}
-		if params.Filter != "" {
-			query.Set("filter", params.Filter)
-		}
-		if len(query) > 0 {
-			queryParams = &query
-		}
-	}
-
+	queryParams := switchingQuery(params)
 	var resp types.PortNACResponse
-	httpReq, err := s.client.newRequest(http.MethodGet, path, nil, queryParams)
+	httpReq, err := s.client.newRequest(opSwitchingGetPortNAC, http.MethodGet, path, nil, queryParams)
 	if err != nil {
 		return nil, err
 	}
 	err = s.client.do(httpReq, &resp)
 	return &resp, err
 }
+
+// switchingQuery returns the query parameters of the switching endpoints.
+func switchingQuery(params *types.SwitchingQueryParams) *url.Values {
+	if params == nil {
+		return nil
+	}
+	query := url.Values{}
+	if params.Columns != "" {
+		query.Set("columns", params.Columns)
+	}
+	if params.Filter != "" {
+		query.Set("filter", params.Filter)
+	}
+	if len(query) == 0 {
+		return nil
+	}
+	return &query
+}
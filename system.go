@@ -13,7 +13,7 @@ const (
 // Get retrieves system information from LibreNMS
 func (s *SystemAPI) Get() (*types.SystemResponse, error) {
 	c := s.client
-	req, err := c.newRequest(http.MethodGet, systemEndpoint, nil, nil)
+	req, err := c.newRequest(opSystemGet, http.MethodGet, systemEndpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
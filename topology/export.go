@@ -0,0 +1,206 @@
+package topology
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type (
+	// D3Graph is the graph in the node-link format of d3-force.
+	D3Graph struct {
+		Nodes []D3Node `json:"nodes"`
+		Links []D3Link `json:"links"`
+	}
+
+	// D3Node is a node of a D3Graph.
+	D3Node struct {
+		ID         string `json:"id"`
+		Label      string `json:"label"`
+		DeviceID   int    `json:"device_id,omitempty"`
+		Platform   string `json:"platform,omitempty"`
+		Unresolved bool   `json:"unresolved,omitempty"`
+		// Group is the index of the node's connected component, largest first.
+		Group int `json:"group"`
+	}
+
+	// D3Link is a link of a D3Graph. Source and Target are node IDs.
+	D3Link struct {
+		Source     string   `json:"source"`
+		Target     string   `json:"target"`
+		SourcePort string   `json:"source_port,omitempty"`
+		TargetPort string   `json:"target_port,omitempty"`
+		Protocols  []string `json:"protocols,omitempty"`
+	}
+
+	// graphML is the root element of a GraphML document.
+	graphML struct {
+		XMLName xml.Name     `xml:"graphml"`
+		XMLNS   string       `xml:"xmlns,attr"`
+		Keys    []graphMLKey `xml:"key"`
+		Graph   graphMLGraph `xml:"graph"`
+	}
+
+	graphMLKey struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+
+	graphMLGraph struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	}
+
+	graphMLNode struct {
+		ID   string        `xml:"id,attr"`
+		Data []graphMLData `xml:"data"`
+	}
+
+	graphMLEdge struct {
+		Source string        `xml:"source,attr"`
+		Target string        `xml:"target,attr"`
+		Data   []graphMLData `xml:"data"`
+	}
+
+	graphMLData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// graphMLKeys declares the GraphML attributes written by WriteGraphML.
+var graphMLKeys = []graphMLKey{
+	{ID: "label", For: "node", Name: "label", Type: "string"},
+	{ID: "device_id", For: "node", Name: "device_id", Type: "int"},
+	{ID: "platform", For: "node", Name: "platform", Type: "string"},
+	{ID: "unresolved", For: "node", Name: "unresolved", Type: "boolean"},
+	{ID: "source_port", For: "edge", Name: "source_port", Type: "string"},
+	{ID: "target_port", For: "edge", Name: "target_port", Type: "string"},
+	{ID: "protocols", For: "edge", Name: "protocols", Type: "string"},
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language. Unresolved nodes are dashed and
+// edges are labelled with their ports.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "graph librenms {")
+	for _, node := range g.nodes {
+		attrs := []string{"label=" + dotQuote(node.Label())}
+		if node.Platform != "" {
+			attrs = append(attrs, "tooltip="+dotQuote(node.Platform))
+		}
+		if node.Unresolved {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(bw, "\t%s [%s];\n", dotQuote(node.ID), strings.Join(attrs, ", "))
+	}
+	for _, edge := range g.edges {
+		attrs := []string{
+			"taillabel=" + dotQuote(edge.A.Label()),
+			"headlabel=" + dotQuote(edge.B.Label()),
+		}
+		if len(edge.Protocols) > 0 {
+			attrs = append(attrs, "tooltip="+dotQuote(strings.Join(edge.Protocols, ",")))
+		}
+		fmt.Fprintf(bw, "\t%s -- %s [%s];\n", dotQuote(edge.A.Node.ID), dotQuote(edge.B.Node.ID), strings.Join(attrs, ", "))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// WriteGraphML writes the graph as a GraphML document.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  graphMLKeys,
+		Graph: graphMLGraph{ID: "librenms", EdgeDefault: "undirected"},
+	}
+	for _, node := range g.nodes {
+		n := graphMLNode{ID: node.ID, Data: []graphMLData{{Key: "label", Value: node.Label()}}}
+		if node.DeviceID != 0 {
+			n.Data = append(n.Data, graphMLData{Key: "device_id", Value: strconv.Itoa(node.DeviceID)})
+		}
+		if node.Platform != "" {
+			n.Data = append(n.Data, graphMLData{Key: "platform", Value: node.Platform})
+		}
+		n.Data = append(n.Data, graphMLData{Key: "unresolved", Value: strconv.FormatBool(node.Unresolved)})
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+	}
+	for _, edge := range g.edges {
+		e := graphMLEdge{Source: edge.A.Node.ID, Target: edge.B.Node.ID}
+		if port := edge.A.Label(); port != "" {
+			e.Data = append(e.Data, graphMLData{Key: "source_port", Value: port})
+		}
+		if port := edge.B.Label(); port != "" {
+			e.Data = append(e.Data, graphMLData{Key: "target_port", Value: port})
+		}
+		if len(edge.Protocols) > 0 {
+			e.Data = append(e.Data, graphMLData{Key: "protocols", Value: strings.Join(edge.Protocols, ",")})
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, e)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// D3 returns the graph in the node-link format of d3-force. Nodes are grouped by connected
+// component.
+func (g *Graph) D3() *D3Graph {
+	group := make(map[*Node]int, len(g.nodes))
+	for i, component := range g.Components() {
+		for _, node := range component {
+			group[node] = i
+		}
+	}
+
+	d3 := &D3Graph{Nodes: make([]D3Node, 0, len(g.nodes)), Links: make([]D3Link, 0, len(g.edges))}
+	for _, node := range g.nodes {
+		d3.Nodes = append(d3.Nodes, D3Node{
+			ID:         node.ID,
+			Label:      node.Label(),
+			DeviceID:   node.DeviceID,
+			Platform:   node.Platform,
+			Unresolved: node.Unresolved,
+			Group:      group[node],
+		})
+	}
+	for _, edge := range g.edges {
+		d3.Links = append(d3.Links, D3Link{
+			Source:     edge.A.Node.ID,
+			Target:     edge.B.Node.ID,
+			SourcePort: edge.A.Label(),
+			TargetPort: edge.B.Label(),
+			Protocols:  edge.Protocols,
+		})
+	}
+	return d3
+}
+
+// WriteD3JSON writes the graph as the JSON of D3.
+func (g *Graph) WriteD3JSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g.D3())
+}
+
+// dotQuote quotes a DOT identifier.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,85 @@
+package topology_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/topology"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph_WriteDOT(t *testing.T) {
+	r := require.New(t)
+
+	g := topology.New(links, &topology.Options{Devices: devices, Ports: ports})
+
+	var buf bytes.Buffer
+	r.NoError(g.WriteDOT(&buf), "WriteDOT returned an error")
+	dot := buf.String()
+	r.True(strings.HasPrefix(dot, "graph librenms {\n"), "Expected an undirected graph")
+	r.Contains(dot, "\t\"1\" [label=\"core1\", tooltip=\"C9500\"];\n", "Expected the device node")
+	r.Contains(dot, "\t\"remote:ap-floor1\" [label=\"AP-Floor1\", tooltip=\"AIR-AP2802I\", style=dashed];\n",
+		"Expected a dashed unresolved node")
+	r.Contains(dot, "\t\"1\" -- \"2\" [taillabel=\"Te1/0/1\", headlabel=\"Te0/1\", tooltip=\"cdp,lldp\"];\n",
+		"Expected the edge with its ports")
+	r.Equal(8+7+2, strings.Count(dot, "\n"), "Expected a line per node and edge")
+}
+
+func TestGraph_WriteGraphML(t *testing.T) {
+	r := require.New(t)
+
+	g := topology.New(links, &topology.Options{Devices: devices, Ports: ports})
+
+	var buf bytes.Buffer
+	r.NoError(g.WriteGraphML(&buf), "WriteGraphML returned an error")
+	r.True(strings.HasPrefix(buf.String(), xml.Header), "Expected the XML header")
+
+	var doc struct {
+		Keys []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"key"`
+		Graph struct {
+			Nodes []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	r.NoError(xml.Unmarshal(buf.Bytes(), &doc), "Expected valid XML")
+	r.Len(doc.Keys, 7, "Expected every attribute to be declared")
+	r.Len(doc.Graph.Nodes, 8, "Expected every node")
+	r.Len(doc.Graph.Edges, 7, "Expected every edge")
+	r.Equal("remote:ap-floor1", doc.Graph.Nodes[7].ID, "Unexpected node")
+	r.Contains(buf.String(), `<data key="unresolved">true</data>`, "Expected the unresolved flag")
+	r.Equal("5", doc.Graph.Edges[5].Target, "Unexpected edge")
+}
+
+func TestGraph_D3(t *testing.T) {
+	r := require.New(t)
+
+	g := topology.New(links, &topology.Options{Devices: devices, Ports: ports})
+
+	d3 := g.D3()
+	r.Len(d3.Nodes, 8, "Expected every node")
+	r.Len(d3.Links, 7, "Expected every link")
+	r.Equal(topology.D3Node{ID: "6", Label: "lab1", DeviceID: 6, Group: 1}, d3.Nodes[5],
+		"Expected the node to be grouped by component")
+	r.Equal(topology.D3Link{Source: "1", Target: "3", SourcePort: "Te1/0/2", TargetPort: "Gi0/1", Protocols: []string{"lldp"}},
+		d3.Links[2], "Unexpected link")
+
+	var buf bytes.Buffer
+	r.NoError(g.WriteD3JSON(&buf), "WriteD3JSON returned an error")
+	var decoded topology.D3Graph
+	r.NoError(json.Unmarshal(buf.Bytes(), &decoded), "Expected valid JSON")
+	r.Equal(*d3, decoded, "Expected the JSON to round-trip")
+}
@@ -0,0 +1,138 @@
+package topology
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Neighbors returns the distinct neighbours of a node.
+func (g *Graph) Neighbors(identifier string) ([]*Node, error) {
+	node, err := g.lookup(identifier)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*Node(nil), g.neighbors[node]...), nil
+}
+
+// ShortestPath returns the nodes of a path with the fewest hops between two nodes, both
+// included. It returns ErrNoPath when they are not connected.
+func (g *Graph) ShortestPath(from, to string) ([]*Node, error) {
+	start, err := g.lookup(from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := g.lookup(to)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := map[*Node]*Node{start: nil}
+	queue := []*Node{start}
+	for len(queue) > 0 && end != queue[0] {
+		node := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range g.neighbors[node] {
+			if _, seen := previous[neighbor]; !seen {
+				previous[neighbor] = node
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	if _, reached := previous[end]; !reached {
+		return nil, fmt.Errorf("%w: %s and %s", ErrNoPath, start.Label(), end.Label())
+	}
+
+	var path []*Node
+	for node := end; node != nil; node = previous[node] {
+		path = append(path, node)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, nil
+}
+
+// Components returns the connected components, largest first. The nodes of a component
+// are in the order of Nodes.
+func (g *Graph) Components() [][]*Node {
+	component := make(map[*Node]int, len(g.nodes))
+	var components [][]*Node
+	for _, node := range g.nodes {
+		if _, seen := component[node]; seen {
+			continue
+		}
+		index := len(components)
+		component[node] = index
+		members := []*Node{node}
+		for i := 0; i < len(members); i++ {
+			for _, neighbor := range g.neighbors[members[i]] {
+				if _, seen := component[neighbor]; !seen {
+					component[neighbor] = index
+					members = append(members, neighbor)
+				}
+			}
+		}
+		sort.Slice(members, func(i, j int) bool { return lessNode(members[i], members[j]) })
+		components = append(components, members)
+	}
+	sort.SliceStable(components, func(i, j int) bool { return len(components[i]) > len(components[j]) })
+	return components
+}
+
+// ArticulationPoints returns the nodes whose failure disconnects other nodes from each
+// other: the single points of failure of the network. Parallel links between two nodes
+// count as one.
+func (g *Graph) ArticulationPoints() []*Node {
+	s := &articulationSearch{
+		graph: g,
+		order: make(map[*Node]int, len(g.nodes)),
+		low:   make(map[*Node]int, len(g.nodes)),
+		cut:   make(map[*Node]bool),
+	}
+	for _, node := range g.nodes {
+		if _, seen := s.order[node]; !seen {
+			s.visit(node, nil)
+		}
+	}
+
+	var points []*Node
+	for _, node := range g.nodes {
+		if s.cut[node] {
+			points = append(points, node)
+		}
+	}
+	return points
+}
+
+// articulationSearch is the depth-first search of Tarjan's articulation points algorithm.
+type articulationSearch struct {
+	graph *Graph
+	// order is the discovery order of each node, and low the lowest order reachable
+	// from its subtree through a single back edge.
+	order, low map[*Node]int
+	cut        map[*Node]bool
+}
+
+func (s *articulationSearch) visit(node, parent *Node) {
+	s.order[node] = len(s.order)
+	s.low[node] = s.order[node]
+	children := 0
+	for _, neighbor := range s.graph.neighbors[node] {
+		if neighbor == parent {
+			continue
+		}
+		if order, seen := s.order[neighbor]; seen {
+			s.low[node] = min(s.low[node], order)
+			continue
+		}
+		children++
+		s.visit(neighbor, node)
+		s.low[node] = min(s.low[node], s.low[neighbor])
+		if parent != nil && s.low[neighbor] >= s.order[node] {
+			s.cut[node] = true
+		}
+	}
+	if parent == nil && children > 1 {
+		s.cut[node] = true
+	}
+}
@@ -0,0 +1,400 @@
+// Package topology builds a graph of devices and ports from the links discovered by
+// LibreNMS through CDP, LLDP and similar protocols.
+//
+// Each link returned by the API is a single neighbour entry seen from one device, so a
+// cable between two devices usually appears twice, once per side, and once more per
+// protocol. The graph merges them into a single edge. Neighbours that LibreNMS could not
+// match to a device are kept as unresolved nodes identified by their remote hostname.
+package topology
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+)
+
+// portColumns limits the port listing to the fields needed to name a port.
+var portColumns = types.MustColumns[types.Port]("ifName", "ifDescr")
+
+// unresolvedPrefix prefixes the IDs of unresolved nodes.
+const unresolvedPrefix = "remote:"
+
+var (
+	// ErrUnknownNode is returned when an identifier matches no node of the graph.
+	ErrUnknownNode = errors.New("unknown node")
+	// ErrNoPath is returned by ShortestPath when the nodes are not connected.
+	ErrNoPath = errors.New("no path between nodes")
+)
+
+type (
+	// Options holds the related data used to name the nodes and ports of a graph.
+	Options struct {
+		// Devices provide the hostnames and platforms of the nodes, and resolve the remote
+		// hostnames of links without a remote device ID.
+		Devices []types.Device
+		// Ports provide the port names, and resolve the remote port names of links without
+		// a remote port ID. Without them, both sides of a link can only be merged when
+		// LibreNMS resolved the remote port.
+		Ports []types.Port
+	}
+
+	// LoadOptions configures Load.
+	LoadOptions struct {
+		// SkipPorts skips listing the ports, which can be slow on large installs.
+		SkipPorts bool
+	}
+
+	// Node is a device of the graph.
+	Node struct {
+		// ID is the device ID for LibreNMS devices, and "remote:" followed by the
+		// lowercase hostname for unresolved nodes. Unresolved neighbours without a
+		// hostname are told apart by the port they are seen on, "remote:" followed by
+		// the local device ID, port ID and remote port, or by the link ID when the local
+		// port is unknown.
+		ID string
+		// DeviceID is the LibreNMS device ID, 0 for unresolved nodes.
+		DeviceID int
+		Hostname string
+		SysName  string
+		// Platform is the hardware of LibreNMS devices, or the platform advertised by
+		// unresolved neighbours.
+		Platform string
+		// Unresolved is set for neighbours that LibreNMS could not match to a device.
+		Unresolved bool
+	}
+
+	// Endpoint is one side of an edge.
+	Endpoint struct {
+		Node *Node
+		// PortID is the LibreNMS port ID, 0 when it is unknown.
+		PortID int
+		// Port is the port name, empty when it is unknown.
+		Port string
+	}
+
+	// Edge is a link between two nodes, merged from the links reported by both sides and
+	// by every discovery protocol.
+	Edge struct {
+		A, B Endpoint
+		// Protocols are the sorted discovery protocols that reported the link.
+		Protocols []string
+		// LinkIDs are the IDs of the merged links.
+		LinkIDs []int
+	}
+
+	// Graph is an undirected graph of devices. It is not modified once built and is safe
+	// for concurrent use.
+	Graph struct {
+		nodes []*Node
+		byID  map[string]*Node
+		edges []*Edge
+		// neighbors holds the distinct neighbours of each node, without self loops.
+		neighbors map[*Node][]*Node
+	}
+
+	// builder accumulates the nodes and edges of a graph.
+	builder struct {
+		graph       *Graph
+		devices     map[int]*types.Device
+		byHostname  map[string]*types.Device
+		portNames   map[int]string
+		portsByName map[int]map[string]int
+		edges       map[string]*Edge
+	}
+)
+
+// Label returns the name used to display the node: its hostname, sysName or ID.
+func (n *Node) Label() string {
+	switch {
+	case n.Hostname != "":
+		return n.Hostname
+	case n.SysName != "":
+		return n.SysName
+	}
+	return n.ID
+}
+
+// Label returns the port name, or the port ID when the name is unknown.
+func (e Endpoint) Label() string {
+	if e.Port == "" && e.PortID != 0 {
+		return strconv.Itoa(e.PortID)
+	}
+	return e.Port
+}
+
+// Load builds the graph of the LibreNMS instance behind client from its links, devices
+// and ports.
+func Load(client *librenms.Client, opts *LoadOptions) (*Graph, error) {
+	if opts == nil {
+		opts = &LoadOptions{}
+	}
+
+	links, err := client.Switching.GetAllLinks(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links: %w", err)
+	}
+	devices, err := client.Device.List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	options := &Options{Devices: devices.Devices}
+	if !opts.SkipPorts {
+		ports, err := client.Port.GetAllPorts(&types.PortsQueryParams{Columns: portColumns.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ports: %w", err)
+		}
+		options.Ports = ports.Ports
+	}
+	return New(links.Links, options), nil
+}
+
+// New builds a graph from links. Links between the same ports are merged, whichever side
+// and protocol reported them.
+func New(links []types.Link, opts *Options) *Graph {
+	if opts == nil {
+		opts = new(Options)
+	}
+
+	b := &builder{
+		graph:       &Graph{byID: make(map[string]*Node), neighbors: make(map[*Node][]*Node)},
+		devices:     make(map[int]*types.Device, len(opts.Devices)),
+		byHostname:  make(map[string]*types.Device, len(opts.Devices)),
+		portNames:   make(map[int]string, len(opts.Ports)),
+		portsByName: make(map[int]map[string]int),
+		edges:       make(map[string]*Edge),
+	}
+	for i := range opts.Devices {
+		device := &opts.Devices[i]
+		b.devices[int(device.DeviceID)] = device
+		for _, name := range []string{string(device.Hostname), string(device.SysName)} {
+			if name != "" {
+				b.byHostname[strings.ToLower(name)] = device
+			}
+		}
+	}
+	for _, port := range opts.Ports {
+		id, deviceID := int(port.PortID), int(port.DeviceID)
+		b.portNames[id] = string(port.IfName)
+		if b.portsByName[deviceID] == nil {
+			b.portsByName[deviceID] = make(map[string]int)
+		}
+		for _, name := range []string{string(port.IfDescr), string(port.IfName)} {
+			if name != "" {
+				b.portsByName[deviceID][strings.ToLower(name)] = id
+			}
+		}
+	}
+
+	for _, link := range links {
+		b.addLink(link)
+	}
+	return b.build()
+}
+
+// addLink adds a link, merging it into the edge of the same ports when there is one.
+func (b *builder) addLink(link types.Link) {
+	local := b.endpoint(b.deviceNode(int(link.LocalDeviceID), "", ""), int(link.LocalPortID), "")
+
+	var remoteNode *Node
+	if link.RemoteDeviceID != 0 {
+		remoteNode = b.deviceNode(int(link.RemoteDeviceID), string(link.RemoteHostname), string(link.RemotePlatform))
+	} else if device, ok := b.byHostname[strings.ToLower(string(link.RemoteHostname))]; ok && link.RemoteHostname != "" {
+		remoteNode = b.deviceNode(int(device.DeviceID), "", "")
+	} else {
+		remoteNode = b.unresolvedNode(link)
+	}
+	remote := b.endpoint(remoteNode, int(link.RemotePortID), string(link.RemotePort))
+
+	key := endpointKey(local) + "|" + endpointKey(remote)
+	if endpointKey(remote) < endpointKey(local) {
+		key = endpointKey(remote) + "|" + endpointKey(local)
+	}
+	edge, ok := b.edges[key]
+	if !ok {
+		edge = &Edge{A: local, B: remote}
+		b.edges[key] = edge
+		b.graph.edges = append(b.graph.edges, edge)
+	}
+	if link.ID != 0 {
+		edge.LinkIDs = append(edge.LinkIDs, int(link.ID))
+	}
+	if protocol := strings.ToLower(string(link.Protocol)); protocol != "" && !slices.Contains(edge.Protocols, protocol) {
+		edge.Protocols = append(edge.Protocols, protocol)
+		sort.Strings(edge.Protocols)
+	}
+}
+
+// deviceNode returns the node of a LibreNMS device, creating it when needed. The hostname
+// and platform reported by a neighbour are used when the device is unknown.
+func (b *builder) deviceNode(deviceID int, hostname, platform string) *Node {
+	id := strconv.Itoa(deviceID)
+	node, ok := b.graph.byID[id]
+	if !ok {
+		node = &Node{ID: id, DeviceID: deviceID}
+		b.graph.byID[id] = node
+		b.graph.nodes = append(b.graph.nodes, node)
+		if device, ok := b.devices[deviceID]; ok {
+			node.Hostname, node.SysName, node.Platform = string(device.Hostname), string(device.SysName), string(device.Hardware)
+		}
+	}
+	if node.Hostname == "" && node.SysName == "" {
+		node.Hostname = hostname
+	}
+	if node.Platform == "" {
+		node.Platform = platform
+	}
+	return node
+}
+
+// unresolvedNode returns the node of a neighbour that is not a LibreNMS device, creating it
+// when needed. Neighbours without a hostname get a node per local port and remote port.
+func (b *builder) unresolvedNode(link types.Link) *Node {
+	hostname, platform := string(link.RemoteHostname), string(link.RemotePlatform)
+	id := unresolvedPrefix + strings.ToLower(hostname)
+	switch {
+	case hostname != "":
+	case link.LocalPortID != 0:
+		id = fmt.Sprintf("%s%d:%d:%s", unresolvedPrefix, link.LocalDeviceID, link.LocalPortID, strings.ToLower(string(link.RemotePort)))
+	default:
+		id = fmt.Sprintf("%slink:%d", unresolvedPrefix, link.ID)
+	}
+	node, ok := b.graph.byID[id]
+	if !ok {
+		node = &Node{ID: id, Hostname: hostname, Unresolved: true}
+		b.graph.byID[id] = node
+		b.graph.nodes = append(b.graph.nodes, node)
+	}
+	if node.Platform == "" {
+		node.Platform = platform
+	}
+	return node
+}
+
+// endpoint returns the endpoint of a port, filling in its ID or name from the ports.
+func (b *builder) endpoint(node *Node, portID int, port string) Endpoint {
+	if portID == 0 && port != "" && !node.Unresolved {
+		portID = b.portsByName[node.DeviceID][strings.ToLower(port)]
+	}
+	if name, ok := b.portNames[portID]; ok && portID != 0 && name != "" {
+		port = name
+	}
+	return Endpoint{Node: node, PortID: portID, Port: port}
+}
+
+// build sorts the nodes and edges and indexes the neighbours.
+func (b *builder) build() *Graph {
+	g := b.graph
+	sort.Slice(g.nodes, func(i, j int) bool { return lessNode(g.nodes[i], g.nodes[j]) })
+	for _, edge := range g.edges {
+		if lessNode(edge.B.Node, edge.A.Node) {
+			edge.A, edge.B = edge.B, edge.A
+		}
+		sort.Ints(edge.LinkIDs)
+	}
+	sort.SliceStable(g.edges, func(i, j int) bool {
+		a, b := g.edges[i], g.edges[j]
+		if a.A.Node != b.A.Node {
+			return lessNode(a.A.Node, b.A.Node)
+		}
+		if a.B.Node != b.B.Node {
+			return lessNode(a.B.Node, b.B.Node)
+		}
+		return a.A.Label() < b.A.Label()
+	})
+
+	for _, edge := range g.edges {
+		a, b := edge.A.Node, edge.B.Node
+		if a == b || slices.Contains(g.neighbors[a], b) {
+			continue
+		}
+		g.neighbors[a] = append(g.neighbors[a], b)
+		g.neighbors[b] = append(g.neighbors[b], a)
+	}
+	for _, neighbors := range g.neighbors {
+		sort.Slice(neighbors, func(i, j int) bool { return lessNode(neighbors[i], neighbors[j]) })
+	}
+	return g
+}
+
+// Nodes returns the nodes: LibreNMS devices by device ID, then unresolved nodes by hostname.
+func (g *Graph) Nodes() []*Node {
+	return append([]*Node(nil), g.nodes...)
+}
+
+// Edges returns the edges, sorted by their nodes.
+func (g *Graph) Edges() []*Edge {
+	return append([]*Edge(nil), g.edges...)
+}
+
+// Unresolved returns the neighbours that LibreNMS could not match to a device.
+func (g *Graph) Unresolved() []*Node {
+	var nodes []*Node
+	for _, node := range g.nodes {
+		if node.Unresolved {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// Node returns a node by ID, hostname or sysName, ignoring case, or nil.
+func (g *Graph) Node(identifier string) *Node {
+	if node, ok := g.byID[identifier]; ok {
+		return node
+	}
+	for _, node := range g.nodes {
+		if strings.EqualFold(node.Hostname, identifier) || strings.EqualFold(node.SysName, identifier) {
+			return node
+		}
+	}
+	return nil
+}
+
+// EdgesOf returns the edges of a node.
+func (g *Graph) EdgesOf(identifier string) ([]*Edge, error) {
+	node, err := g.lookup(identifier)
+	if err != nil {
+		return nil, err
+	}
+	var edges []*Edge
+	for _, edge := range g.edges {
+		if edge.A.Node == node || edge.B.Node == node {
+			edges = append(edges, edge)
+		}
+	}
+	return edges, nil
+}
+
+// lookup returns a node by identifier, or ErrUnknownNode.
+func (g *Graph) lookup(identifier string) (*Node, error) {
+	node := g.Node(identifier)
+	if node == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownNode, identifier)
+	}
+	return node, nil
+}
+
+// lessNode orders LibreNMS devices by device ID before unresolved nodes by ID.
+func lessNode(a, b *Node) bool {
+	if a.Unresolved != b.Unresolved {
+		return !a.Unresolved
+	}
+	if !a.Unresolved {
+		return a.DeviceID < b.DeviceID
+	}
+	return a.ID < b.ID
+}
+
+// endpointKey identifies a port of a node, by ID when it is known and by name otherwise.
+func endpointKey(e Endpoint) string {
+	if e.PortID != 0 {
+		return e.Node.ID + "/" + strconv.Itoa(e.PortID)
+	}
+	return e.Node.ID + "/name:" + strings.ToLower(e.Port)
+}
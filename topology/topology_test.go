@@ -0,0 +1,191 @@
+package topology_test
+
+import (
+	"testing"
+
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/topology"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	devices = []types.Device{
+		{DeviceID: 1, Hostname: "core1", Hardware: "C9500"},
+		{DeviceID: 2, Hostname: "dist1"},
+		{DeviceID: 3, Hostname: "dist2"},
+		{DeviceID: 4, Hostname: "access1"},
+		{DeviceID: 5, Hostname: "10.0.0.5", SysName: "access2.example.net"},
+		{DeviceID: 6, Hostname: "lab1"},
+		{DeviceID: 7, Hostname: "lab2"},
+	}
+
+	ports = []types.Port{
+		{PortID: 11, DeviceID: 1, IfName: "Te1/0/1"},
+		{PortID: 12, DeviceID: 1, IfName: "Te1/0/2"},
+		{PortID: 13, DeviceID: 1, IfName: "Te1/0/3"},
+		{PortID: 21, DeviceID: 2, IfName: "Te0/1"},
+		{PortID: 22, DeviceID: 2, IfName: "Te0/2"},
+		{PortID: 31, DeviceID: 3, IfName: "Gi0/1", IfDescr: "GigabitEthernet0/1"},
+	}
+
+	links = []types.Link{
+		// core1 - dist1, reported by both sides and by CDP and LLDP.
+		{ID: 1, LocalDeviceID: 1, LocalPortID: 11, RemoteDeviceID: 2, RemotePortID: 21, Protocol: "cdp"},
+		{ID: 2, LocalDeviceID: 2, LocalPortID: 21, RemoteDeviceID: 1, RemotePortID: 11, Protocol: "cdp"},
+		{ID: 3, LocalDeviceID: 1, LocalPortID: 11, RemoteDeviceID: 2, RemotePortID: 21, Protocol: "LLDP"},
+		// A parallel link between core1 and dist1.
+		{ID: 4, LocalDeviceID: 1, LocalPortID: 13, RemoteDeviceID: 2, RemotePortID: 22, Protocol: "lldp"},
+		// core1 - dist2, with the remote port known by name only.
+		{ID: 5, LocalDeviceID: 1, LocalPortID: 12, RemoteDeviceID: 3, RemotePort: "GigabitEthernet0/1", Protocol: "lldp"},
+		{ID: 6, LocalDeviceID: 3, LocalPortID: 31, RemoteDeviceID: 1, RemotePortID: 12, Protocol: "lldp"},
+		{ID: 7, LocalDeviceID: 2, LocalPortID: 23, RemoteDeviceID: 4, RemotePort: "Gi1/0/48", Protocol: "cdp"},
+		// An access point that isn't a LibreNMS device.
+		{ID: 8, LocalDeviceID: 2, LocalPortID: 24, RemoteHostname: "AP-Floor1", RemotePort: "Gi0", RemotePlatform: "AIR-AP2802I", Protocol: "cdp"},
+		// A device that LibreNMS didn't resolve but is known by its sysName.
+		{ID: 9, LocalDeviceID: 3, LocalPortID: 32, RemoteHostname: "ACCESS2.example.net", RemotePort: "Gi1/0/1", Protocol: "lldp"},
+		{ID: 10, LocalDeviceID: 6, LocalPortID: 61, RemoteDeviceID: 7, RemotePortID: 71, Protocol: "lldp"},
+	}
+)
+
+func labels(nodes []*topology.Node) []string {
+	values := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		values = append(values, node.Label())
+	}
+	return values
+}
+
+func TestNew(t *testing.T) {
+	r := require.New(t)
+
+	g := topology.New(links, &topology.Options{Devices: devices, Ports: ports})
+
+	r.Equal([]string{"core1", "dist1", "dist2", "access1", "10.0.0.5", "lab1", "lab2", "AP-Floor1"}, labels(g.Nodes()),
+		"Expected the devices by ID, then the unresolved nodes")
+	r.Len(g.Edges(), 7, "Expected the links of both sides and protocols to be merged")
+
+	edge := g.Edges()[0]
+	r.Equal("core1", edge.A.Node.Label(), "Unexpected edge")
+	r.Equal("Te1/0/1", edge.A.Port, "Expected the port name")
+	r.Equal("Te0/1", edge.B.Port, "Expected the remote port name")
+	r.Equal([]string{"cdp", "lldp"}, edge.Protocols, "Expected every protocol")
+	r.Equal([]int{1, 2, 3}, edge.LinkIDs, "Expected the merged links")
+
+	edges, err := g.EdgesOf("dist2")
+	r.NoError(err, "EdgesOf returned an error")
+	r.Len(edges, 2, "Expected the links reported by name and by ID to be merged")
+	r.Equal([]int{5, 6}, edges[0].LinkIDs, "Expected the merged links")
+	r.Equal(31, edges[0].B.PortID, "Expected the port ID resolved from its description")
+
+	unresolved := g.Unresolved()
+	r.Len(unresolved, 1, "Expected the access point only")
+	r.Equal("remote:ap-floor1", unresolved[0].ID, "Unexpected unresolved ID")
+	r.Equal("AIR-AP2802I", unresolved[0].Platform, "Expected the advertised platform")
+	r.Equal(5, g.Node("access2.example.net").DeviceID, "Expected the sysName to resolve the remote hostname")
+	r.Equal("C9500", g.Node("CORE1").Platform, "Expected nodes by hostname, ignoring case")
+	r.Nil(g.Node("missing"), "Expected no node")
+}
+
+func TestNew_WithoutOptions(t *testing.T) {
+	r := require.New(t)
+
+	g := topology.New(links, nil)
+
+	r.Len(g.Unresolved(), 2, "Expected hostnames without device IDs to be unresolved")
+	r.Equal("1", g.Nodes()[0].Label(), "Expected the device ID as label")
+	r.Len(g.Edges(), 8, "Expected sides known by port name only not to be merged")
+}
+
+func TestNew_NeighborsWithoutHostname(t *testing.T) {
+	r := require.New(t)
+
+	g := topology.New([]types.Link{
+		{ID: 1, LocalDeviceID: 1, LocalPortID: 10, RemotePort: "eth0", Protocol: "lldp"},
+		{ID: 2, LocalDeviceID: 1, LocalPortID: 10, RemotePort: "ETH0", Protocol: "cdp"},
+		{ID: 3, LocalDeviceID: 1, LocalPortID: 11, RemotePort: "eth0", Protocol: "lldp"},
+		{ID: 4, LocalDeviceID: 2, LocalPortID: 20, RemotePort: "eth0", Protocol: "lldp"},
+		{ID: 5, LocalDeviceID: 2, RemotePort: "eth1", Protocol: "lldp"},
+	}, nil)
+
+	ids := make([]string, 0)
+	for _, node := range g.Unresolved() {
+		ids = append(ids, node.ID)
+	}
+	r.ElementsMatch([]string{"remote:1:10:eth0", "remote:1:11:eth0", "remote:2:20:eth0", "remote:link:5"}, ids,
+		"Expected a node per local port and remote port, not a single node for every neighbour without hostname")
+	r.Len(g.Edges(), 4, "Expected the protocols of the same neighbour to be merged")
+}
+
+func TestGraph_Paths(t *testing.T) {
+	r := require.New(t)
+
+	g := topology.New(links, &topology.Options{Devices: devices, Ports: ports})
+
+	neighbors, err := g.Neighbors("dist1")
+	r.NoError(err, "Neighbors returned an error")
+	r.Equal([]string{"core1", "access1", "AP-Floor1"}, labels(neighbors), "Expected each neighbour once")
+
+	path, err := g.ShortestPath("access1", "access2.example.net")
+	r.NoError(err, "ShortestPath returned an error")
+	r.Equal([]string{"access1", "dist1", "core1", "dist2", "10.0.0.5"}, labels(path), "Unexpected path")
+
+	path, err = g.ShortestPath("core1", "core1")
+	r.NoError(err, "ShortestPath returned an error")
+	r.Len(path, 1, "Expected a path to itself")
+
+	_, err = g.ShortestPath("access1", "lab1")
+	r.ErrorIs(err, topology.ErrNoPath, "Expected no path between components")
+	_, err = g.Neighbors("missing")
+	r.ErrorIs(err, topology.ErrUnknownNode, "Expected an unknown node")
+
+	components := g.Components()
+	r.Len(components, 2, "Expected two components")
+	r.Equal([]string{"core1", "dist1", "dist2", "access1", "10.0.0.5", "AP-Floor1"}, labels(components[0]),
+		"Expected the largest component first")
+	r.Equal([]string{"lab1", "lab2"}, labels(components[1]), "Unexpected component")
+
+	r.Equal([]string{"core1", "dist1", "dist2"}, labels(g.ArticulationPoints()),
+		"Expected the nodes whose failure splits the network")
+}
+
+func TestArticulationPoints_Ring(t *testing.T) {
+	r := require.New(t)
+
+	ring := []types.Link{
+		{LocalDeviceID: 1, LocalPortID: 1, RemoteDeviceID: 2, RemotePortID: 2},
+		{LocalDeviceID: 2, LocalPortID: 3, RemoteDeviceID: 3, RemotePortID: 4},
+		{LocalDeviceID: 3, LocalPortID: 5, RemoteDeviceID: 1, RemotePortID: 6},
+		{LocalDeviceID: 3, LocalPortID: 7, RemoteDeviceID: 4, RemotePortID: 8},
+	}
+	g := topology.New(ring, nil)
+	r.Equal([]string{"3"}, labels(g.ArticulationPoints()), "Expected only the node leading to the spur")
+}
+
+func TestLoad(t *testing.T) {
+	r := require.New(t)
+
+	srv := librenmstest.New(t)
+	for _, device := range devices {
+		srv.AddDevice(device)
+	}
+	for _, port := range ports {
+		srv.AddPort(port)
+	}
+	for _, link := range links {
+		srv.AddLink(link)
+	}
+
+	g, err := topology.Load(srv.Client(), nil)
+	r.NoError(err, "Load returned an error")
+	r.Len(g.Nodes(), 8, "Expected every node")
+	r.Len(g.Edges(), 7, "Expected the links to be merged with the ports")
+
+	g, err = topology.Load(srv.Client(), &topology.LoadOptions{SkipPorts: true})
+	r.NoError(err, "Load returned an error")
+	r.Len(g.Edges(), 8, "Expected the links known by port name only not to be merged")
+
+	srv.InjectError("resources/links", 500, 1)
+	_, err = topology.Load(srv.Client(), nil)
+	r.ErrorContains(err, "failed to list links", "Expected the API error")
+}
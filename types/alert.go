@@ -8,23 +8,23 @@ import (
 type (
 	// Alert represents a LibreNMS alert.
 	//
-	// Pointers are used for fields that may be null.
+	// Null types are used for fields that may be null.
 	// A custom type Bool is used to represent booleans that may be defined as 0/1 by the API.
 	Alert struct {
-		ID           int    `json:"id,omitempty"`
+		ID           Int    `json:"id,omitempty"`
 		Alerted      Bool   `json:"alerted,omitempty"`
-		DeviceID     int    `json:"device_id,omitempty"`
-		Hostname     string `json:"hostname,omitempty"`
-		Info         string `json:"info,omitempty"`
-		Name         string `json:"name,omitempty"`
-		Note         string `json:"note,omitempty"`
-		Notes        string `json:"notes,omitempty"`
+		DeviceID     Int    `json:"device_id,omitempty"`
+		Hostname     String `json:"hostname,omitempty"`
+		Info         String `json:"info,omitempty"`
+		Name         String `json:"name,omitempty"`
+		Note         String `json:"note,omitempty"`
+		Notes        String `json:"notes,omitempty"`
 		Open         Bool   `json:"open,omitempty"`
-		ProcedureURL string `json:"proc,omitempty"`
-		RuleID       int    `json:"rule_id,omitempty"`
-		Severity     string `json:"severity,omitempty"` // "ok", "warning", "critical"
-		State        int    `json:"state,omitempty"`    // 0 = ok, 1 = alert, 2 = ack
-		Timestamp    string `json:"timestamp,omitempty"`
+		ProcedureURL String `json:"proc,omitempty"`
+		RuleID       Int    `json:"rule_id,omitempty"`
+		Severity     String `json:"severity,omitempty"` // "ok", "warning", "critical"
+		State        Int    `json:"state,omitempty"`    // 0 = ok, 1 = alert, 2 = ack
+		Timestamp    Time   `json:"timestamp,omitempty"`
 	}
 
 	// AlertAckRequest represents the request payload for acknowledging an alert.
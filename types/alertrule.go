@@ -1,29 +1,34 @@
 package types
 
+import "encoding/json"
+
 type (
 	// AlertRule represents an alert rule in LibreNMS.
 	//
 	// See https://docs.librenms.org/API/Alerts/#add_rule for field descriptions.
 	AlertRule struct {
-		ID           int    `json:"id,omitempty"`
-		Builder      string `json:"builder,omitempty"`
-		Devices      []int  `json:"devices,omitempty"`
+		ID           Int    `json:"id,omitempty"`
+		Builder      String `json:"builder,omitempty"`
+		Devices      []Int  `json:"devices,omitempty"`
 		Disabled     Bool   `json:"disabled,omitempty"`
-		Extra        string `json:"extra,omitempty"`
-		Groups       []int  `json:"groups,omitempty"`
+		Extra        String `json:"extra,omitempty"`
+		Groups       []Int  `json:"groups,omitempty"`
 		InvertMap    Bool   `json:"invert_map,omitempty"`
-		Locations    []int  `json:"locations,omitempty"`
-		Name         string `json:"name,omitempty"`
-		Notes        string `json:"notes,omitempty"`
-		ProcedureURL string `json:"proc,omitempty"`
-		Query        string `json:"query,omitempty"`
-		Rule         string `json:"rule,omitempty"`
-		Severity     string `json:"severity,omitempty"`
+		Locations    []Int  `json:"locations,omitempty"`
+		Name         String `json:"name,omitempty"`
+		Notes        String `json:"notes,omitempty"`
+		ProcedureURL String `json:"proc,omitempty"`
+		Query        String `json:"query,omitempty"`
+		Rule         String `json:"rule,omitempty"`
+		Severity     String `json:"severity,omitempty"`
 	}
 
 	// AlertRuleCreateRequest is the request structure for creating an alert rule.
 	//
 	// See https://docs.librenms.org/API/Alerts/#add_rule for field descriptions.
+	//
+	// Builder is the encoded rule tree. It uses the same structure as the rules of
+	// dynamic device groups, see DeviceGroupRuleContainer and the rules package.
 	AlertRuleCreateRequest struct {
 		Builder      string `json:"builder"`         // encoded JSON
 		Count        int    `json:"count,omitempty"` // Max Alerts in the UI
@@ -54,3 +59,22 @@ type (
 		Rules []AlertRule `json:"rules"`
 	}
 )
+
+// ParseBuilder decodes the builder JSON of the alert rule into a rule container.
+func (r *AlertRule) ParseBuilder() (*DeviceGroupRuleContainer, error) {
+	container := new(DeviceGroupRuleContainer)
+	if err := json.Unmarshal([]byte(r.Builder), container); err != nil {
+		return nil, err
+	}
+	return container, nil
+}
+
+// SetBuilder encodes the rule container into the builder field of the request.
+func (r *AlertRuleCreateRequest) SetBuilder(container *DeviceGroupRuleContainer) error {
+	builder, err := container.JSON()
+	if err != nil {
+		return err
+	}
+	r.Builder = builder
+	return nil
+}
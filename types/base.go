@@ -1,7 +1,6 @@
 package types
 
 import (
-	"encoding/json"
 	"fmt"
 	"strconv"
 )
@@ -21,7 +20,7 @@ type (
 		Status string `json:"status"`
 		// Message contains additional information about the API call.
 		Message string `json:"message"`
-		Count   int    `json:"count"`
+		Count   Int    `json:"count"`
 	}
 )
 
@@ -33,21 +32,14 @@ func (b *Bool) MarshalJSON() ([]byte, error) {
 	return []byte("0"), nil
 }
 
-// UnmarshalJSON implements the JSON unmarshalling for the Bool type.
+// UnmarshalJSON implements the JSON unmarshalling for the Bool type. Booleans, numbers
+// (non-zero is true) and their string forms are accepted; null is false.
 func (b *Bool) UnmarshalJSON(data []byte) error {
-	// attempt to unmarshal as a boolean first
-	var valueBool bool
-	if err := json.Unmarshal(data, &valueBool); err == nil {
-		*b = Bool(valueBool)
-		return nil
-	}
-
-	// if that fails, try to unmarshal as an integer (0 or 1)
-	var value int
-	if err := json.Unmarshal(data, &value); err != nil {
+	value, err := parseBool(data)
+	if err != nil {
 		return fmt.Errorf("failed to unmarshal Bool: %w", err)
 	}
-	*b = value != 0
+	*b = Bool(value)
 	return nil
 }
 
@@ -56,26 +48,13 @@ func (f *Float64) MarshalJSON() ([]byte, error) {
 	return []byte(strconv.FormatFloat(float64(*f), 'f', -1, 64)), nil
 }
 
-// UnmarshalJSON implements the JSON unmarshalling for the Float64 type.
+// UnmarshalJSON implements the JSON unmarshalling for the Float64 type. Numbers and numeric
+// strings are accepted; null or an empty string is 0.
 func (f *Float64) UnmarshalJSON(data []byte) error {
-	// attempt to unmarshal as a float64 first
-	var valueFloat64 float64
-	if err := json.Unmarshal(data, &valueFloat64); err == nil {
-		*f = Float64(valueFloat64)
-		return nil
-	}
-
-	// if that fails, try to unmarshal and parse as a string
-	var valueString string
-	if err := json.Unmarshal(data, &valueString); err != nil {
-		return fmt.Errorf("failed to unmarshal Float64: %w", err)
-	}
-
-	value, err := strconv.ParseFloat(valueString, 64)
+	value, err := parseFloat(data)
 	if err != nil {
-		return fmt.Errorf("failed to parse Float64 from string: %w", err)
+		return fmt.Errorf("failed to unmarshal Float64: %w", err)
 	}
-
 	*f = Float64(value)
 	return nil
 }
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BGPPeerState is the state of a BGP peer, the bgpPeerState of BGP4-MIB. The values are those
+// of the MIB, and the zero value is an unknown state.
+type BGPPeerState int
+
+// BGP peer states.
+const (
+	BGPStateUnknown BGPPeerState = iota
+	BGPStateIdle
+	BGPStateConnect
+	BGPStateActive
+	BGPStateOpenSent
+	BGPStateOpenConfirm
+	BGPStateEstablished
+)
+
+var bgpStateNames = [...]string{"", "idle", "connect", "active", "opensent", "openconfirm", "established"}
+
+// ParseBGPPeerState parses a peer state by its MIB name or value. Case, dashes and spaces are
+// ignored, so that "OpenSent" and "open-confirm" are accepted.
+func ParseBGPPeerState(s string) (BGPPeerState, error) {
+	name := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(s)))
+	for i, state := range bgpStateNames {
+		if i > 0 && name == state {
+			return BGPPeerState(i), nil
+		}
+	}
+	if n, err := strconv.Atoi(name); err == nil && n > 0 && n < len(bgpStateNames) {
+		return BGPPeerState(n), nil
+	}
+	return BGPStateUnknown, fmt.Errorf("invalid BGP peer state %q", s)
+}
+
+// String returns the MIB name of the state, or an empty string for an unknown state.
+func (s BGPPeerState) String() string {
+	if s < 0 || int(s) >= len(bgpStateNames) {
+		return ""
+	}
+	return bgpStateNames[s]
+}
@@ -0,0 +1,271 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// keyColumns are the columns always requested for a type, so that rows can be identified
+// and related to their device.
+var keyColumns = map[reflect.Type][]string{
+	reflect.TypeOf(Port{}):    {"port_id", "device_id"},
+	reflect.TypeOf(Link{}):    {"id", "local_device_id"},
+	reflect.TypeOf(VLAN{}):    {"vlan_id", "device_id"},
+	reflect.TypeOf(PortFDB{}): {"ports_fdb_id", "device_id"},
+}
+
+// jsonFields caches the JSON field names of the types used with Columns and Sparse.
+var jsonFields sync.Map
+
+type (
+	// Columns is a validated selection of the columns of the API type T, for the columns
+	// parameter of the ports and switching endpoints. The names are the JSON field names of
+	// T, and the key columns of T, like port_id and device_id for ports, are always
+	// included. The zero Columns selects nothing, leaving the API to its default columns.
+	Columns[T any] struct {
+		names []string
+	}
+
+	// jsonField is a struct field encoded by encoding/json.
+	jsonField struct {
+		name string
+		// index is the index sequence of the field, through embedded structs.
+		index []int
+		// tagged is set when the name comes from the json tag.
+		tagged bool
+	}
+
+	// FieldSet is the sorted set of JSON fields returned by the API for the rows of a
+	// response.
+	FieldSet []string
+)
+
+// NewColumns returns a selection of the columns of T. Names may also be comma separated
+// lists, as accepted by the API. Unknown names are an error.
+func NewColumns[T any](names ...string) (Columns[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	fields, err := structFields(t)
+	if err != nil {
+		return Columns[T]{}, err
+	}
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.name] = true
+	}
+	c := Columns[T]{}
+	for _, list := range append(append([]string{}, keyColumns[t]...), names...) {
+		for _, name := range strings.Split(list, ",") {
+			name = strings.TrimSpace(name)
+			switch {
+			case name == "" || c.Has(name):
+				continue
+			case !known[name]:
+				return Columns[T]{}, fmt.Errorf("unknown %s column %q", t.Name(), name)
+			}
+			c.names = append(c.names, name)
+		}
+	}
+	return c, nil
+}
+
+// MustColumns is like NewColumns but panics on unknown names. It is meant for the column
+// lists of packages, checked when the program starts.
+func MustColumns[T any](names ...string) Columns[T] {
+	c, err := NewColumns[T](names...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Names returns the selected columns, key columns first.
+func (c Columns[T]) Names() []string {
+	return append([]string(nil), c.names...)
+}
+
+// Has reports whether a column is selected.
+func (c Columns[T]) Has(name string) bool {
+	for _, n := range c.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the comma separated columns, as expected by the columns parameter.
+func (c Columns[T]) String() string {
+	return strings.Join(c.names, ",")
+}
+
+// Has reports whether a field was returned.
+func (f FieldSet) Has(name string) bool {
+	i := sort.SearchStrings(f, name)
+	return i < len(f) && f[i] == name
+}
+
+// Fields returns the fields returned for any of the ports.
+func (r *PortsWithFieldsResponse) Fields() FieldSet {
+	return unionFields(r.Ports)
+}
+
+// Fields returns the fields returned for any of the links.
+func (r *LinksWithFieldsResponse) Fields() FieldSet {
+	return unionFields(r.Links)
+}
+
+// unionFields returns the sorted union of the fields of rows.
+func unionFields[T any](rows []Sparse[T]) FieldSet {
+	fields := FieldSet{}
+	for _, row := range rows {
+		fields = append(fields, row.fields...)
+	}
+	sort.Strings(fields)
+	return slices.Compact(fields)
+}
+
+// nextObjectFields returns the sorted keys of the next JSON object of dec, skipping its
+// values. null has no keys.
+func nextObjectFields(dec *json.Decoder) (FieldSet, error) {
+	fields := FieldSet{}
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return fields, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object, got %v", tok)
+	}
+	for dec.More() {
+		name, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, name.(string))
+		if err := skipValue(dec); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	sort.Strings(fields)
+	return slices.Compact(fields), nil
+}
+
+// skipValue skips the next JSON value of dec, with its nested values.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// structFields returns the JSON fields of a struct type, as encoding/json encodes them: the
+// tag name, or the Go name of untagged fields, and the fields of untagged embedded structs
+// are promoted. Other types are an error.
+func structFields(t reflect.Type) ([]jsonField, error) {
+	if fields, ok := jsonFields.Load(t); ok {
+		return fields.([]jsonField), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct type", t)
+	}
+	all := collectFields(t, nil, map[reflect.Type]bool{t: true})
+
+	// Like encoding/json, a name used by several fields is the shallowest one, or the tagged
+	// one among them, and is dropped when that is ambiguous.
+	byName := make(map[string][]jsonField)
+	for _, f := range all {
+		byName[f.name] = append(byName[f.name], f)
+	}
+	fields := make([]jsonField, 0, len(all))
+	for _, f := range all {
+		if dominant, ok := dominantField(byName[f.name]); ok && slices.Equal(dominant.index, f.index) {
+			fields = append(fields, f)
+		}
+	}
+	jsonFields.Store(t, fields)
+	return fields, nil
+}
+
+// collectFields returns the encoded fields of t and of its untagged embedded structs, in
+// field order. index is the index of t in the outer struct, and visited holds the embedded
+// types of the path, so that embedding cycles end.
+func collectFields(t reflect.Type, index []int, visited map[reflect.Type]bool) []jsonField {
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if !f.IsExported() && !(f.Anonymous && ft.Kind() == reflect.Struct) {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		fieldIndex := append(append([]int{}, index...), i)
+		if name == "" && f.Anonymous && ft.Kind() == reflect.Struct {
+			if !visited[ft] {
+				visited[ft] = true
+				fields = append(fields, collectFields(ft, fieldIndex, visited)...)
+				delete(visited, ft)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		field := jsonField{name: name, index: fieldIndex, tagged: name != ""}
+		if name == "" {
+			field.name = f.Name
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// dominantField returns the field encoded for a name used by fields, if any.
+func dominantField(fields []jsonField) (jsonField, bool) {
+	depth := len(fields[0].index)
+	for _, f := range fields {
+		depth = min(depth, len(f.index))
+	}
+	var shallowest, tagged []jsonField
+	for _, f := range fields {
+		if len(f.index) != depth {
+			continue
+		}
+		shallowest = append(shallowest, f)
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	switch {
+	case len(shallowest) == 1:
+		return shallowest[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	}
+	return jsonField{}, false
+}
@@ -1,68 +1,75 @@
 package types
 
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
 type (
 	// Device represents a device in LibreNMS.
 	//
-	// Pointers are used for fields that may be null.
+	// Null types are used for fields that may be null.
 	// A custom type Bool is used to represent booleans that may be defined as 0/1 by the API.
 	Device struct {
-		DeviceID int `json:"device_id,omitempty"`
-
-		AgentUptime             int      `json:"agent_uptime,omitempty"`
-		AuthAlgorithm           string   `json:"authalgo,omitempty"`
-		AuthLevel               string   `json:"authlevel,omitempty"`
-		AuthName                string   `json:"authname,omitempty"`
-		AuthPass                string   `json:"authpass,omitempty"`
-		BGPLocalAS              int      `json:"bgpLocalAs,omitempty"`
-		Community               string   `json:"community,omitempty"`
-		CryptoAlgorithm         string   `json:"cryptoalgo,omitempty"`
-		CryptoPass              string   `json:"cryptopass,omitempty"`
-		DisableNotify           Bool     `json:"disable_notify,omitempty"`
-		Disabled                Bool     `json:"disabled,omitempty"`
-		Display                 string   `json:"display,omitempty"`
-		Features                string   `json:"features,omitempty"`
-		Hardware                string   `json:"hardware,omitempty"`
-		Hostname                string   `json:"hostname,omitempty"`
-		Icon                    string   `json:"icon,omitempty"`
-		Ignore                  Bool     `json:"ignore,omitempty"`
-		IgnoreStatus            Bool     `json:"ignore_status,omitempty"`
-		Inserted                string   `json:"inserted,omitempty"`
-		IP                      string   `json:"ip,omitempty"`
-		LastDiscovered          string   `json:"last_discovered"`
-		LastDiscoveredTimeTaken float64  `json:"last_discovered_timetaken,omitempty"`
-		LastPing                string   `json:"last_ping,omitempty"`
-		LastPingTimeTaken       float64  `json:"last_ping_timetaken,omitempty"`
-		LastPollAttempted       string   `json:"last_poll_attempted,omitempty"`
-		LastPolled              string   `json:"last_pulled,omitempty"`
-		LastPolledTimeTaken     float64  `json:"last_polled_timetaken,omitempty"`
-		Latitude                *Float64 `json:"lat,omitempty"`
-		Longitude               *Float64 `json:"lng,omitempty"`
-		Location                string   `json:"location,omitempty"`
-		LocationID              int      `json:"location_id,omitempty"`
-		MaxDepth                int      `json:"max_depth,omitempty"`
-		Notes                   string   `json:"notes,omitempty"`
-		OS                      string   `json:"os,omitempty"`
-		OverrideSysLocation     Bool     `json:"override_sysLocation,omitempty"`
-		OverwriteIP             string   `json:"overwrite_ip,omitempty"`
-		PollerGroup             int      `json:"poller_group,omitempty"`
-		Port                    int      `json:"port,omitempty"`
-		PortAssociationMode     int      `json:"port_association_mode,omitempty"`
-		Purpose                 string   `json:"purpose,omitempty"`
-		Retries                 int      `json:"retries,omitempty"`
-		Serial                  string   `json:"serial,omitempty"`
-		SNMPDisable             Bool     `json:"snmp_disable,omitempty"`
-		SNMPVersion             string   `json:"snmpver,omitempty"`
-		Status                  Bool     `json:"status,omitempty"` // /devices returns 0/1, and /devices/:id returns true/false
-		StatusReason            string   `json:"status_reason,omitempty"`
-		SysContact              string   `json:"sysContact,omitempty"`
-		SysDescr                string   `json:"sysDescr,omitempty"`
-		SysName                 string   `json:"sysName,omitempty"`
-		SysObjectID             string   `json:"sysObjectID,omitempty"`
-		Timeout                 int      `json:"timeout,omitempty"`
-		Transport               string   `json:"transport,omitempty"`
-		Type                    string   `json:"type,omitempty"`
-		Uptime                  int64    `json:"uptime,omitempty"`
-		Version                 string   `json:"version,omitempty"`
+		DeviceID Int `json:"device_id,omitempty"`
+
+		AgentUptime             Int         `json:"agent_uptime,omitempty"`
+		AuthAlgorithm           String      `json:"authalgo,omitempty"`
+		AuthLevel               String      `json:"authlevel,omitempty"`
+		AuthName                String      `json:"authname,omitempty"`
+		AuthPass                String      `json:"authpass,omitempty"`
+		BGPLocalAS              Int         `json:"bgpLocalAs,omitempty"`
+		Community               String      `json:"community,omitempty"`
+		CryptoAlgorithm         String      `json:"cryptoalgo,omitempty"`
+		CryptoPass              String      `json:"cryptopass,omitempty"`
+		DisableNotify           Bool        `json:"disable_notify,omitempty"`
+		Disabled                Bool        `json:"disabled,omitempty"`
+		Display                 String      `json:"display,omitempty"`
+		Features                String      `json:"features,omitempty"`
+		Hardware                String      `json:"hardware,omitempty"`
+		Hostname                String      `json:"hostname,omitempty"`
+		Icon                    String      `json:"icon,omitempty"`
+		Ignore                  Bool        `json:"ignore,omitempty"`
+		IgnoreStatus            Bool        `json:"ignore_status,omitempty"`
+		Inserted                Time        `json:"inserted,omitempty"`
+		IP                      String      `json:"ip,omitempty"`
+		LastDiscovered          Time        `json:"last_discovered"`
+		LastDiscoveredTimeTaken Float64     `json:"last_discovered_timetaken,omitempty"`
+		LastPing                Time        `json:"last_ping,omitempty"`
+		LastPingTimeTaken       Float64     `json:"last_ping_timetaken,omitempty"`
+		LastPollAttempted       Time        `json:"last_poll_attempted,omitempty"`
+		LastPolled              Time        `json:"last_pulled,omitempty"`
+		LastPolledTimeTaken     Float64     `json:"last_polled_timetaken,omitempty"`
+		Latitude                NullFloat64 `json:"lat,omitempty"`
+		Longitude               NullFloat64 `json:"lng,omitempty"`
+		Location                String      `json:"location,omitempty"`
+		LocationID              Int         `json:"location_id,omitempty"`
+		MaxDepth                Int         `json:"max_depth,omitempty"`
+		Notes                   String      `json:"notes,omitempty"`
+		OS                      String      `json:"os,omitempty"`
+		OverrideSysLocation     Bool        `json:"override_sysLocation,omitempty"`
+		OverwriteIP             String      `json:"overwrite_ip,omitempty"`
+		PollerGroup             Int         `json:"poller_group,omitempty"`
+		Port                    Int         `json:"port,omitempty"`
+		PortAssociationMode     Int         `json:"port_association_mode,omitempty"`
+		Purpose                 String      `json:"purpose,omitempty"`
+		Retries                 Int         `json:"retries,omitempty"`
+		Serial                  String      `json:"serial,omitempty"`
+		SNMPDisable             Bool        `json:"snmp_disable,omitempty"`
+		SNMPVersion             String      `json:"snmpver,omitempty"`
+		Status                  Bool        `json:"status,omitempty"` // /devices returns 0/1, and /devices/:id returns true/false
+		StatusReason            String      `json:"status_reason,omitempty"`
+		SysContact              String      `json:"sysContact,omitempty"`
+		SysDescr                String      `json:"sysDescr,omitempty"`
+		SysName                 String      `json:"sysName,omitempty"`
+		SysObjectID             String      `json:"sysObjectID,omitempty"`
+		Timeout                 Int         `json:"timeout,omitempty"`
+		Transport               String      `json:"transport,omitempty"`
+		Type                    String      `json:"type,omitempty"`
+		Uptime                  Int64       `json:"uptime,omitempty"`
+		Version                 String      `json:"version,omitempty"`
 	}
 
 	// DeviceCreateRequest represents the request body for creating a new device in LibreNMS.
@@ -96,9 +103,14 @@ type (
 	//
 	// The `Field` slice contains the names of the field(s) to update,
 	// and `Data` contains the corresponding values. Only specify the fields you want to update.
+	//
+	// Requests created by NewDeviceUpdate or DiffDevices are validated before they are sent,
+	// while requests whose Field and Data are set directly are sent as is.
 	DeviceUpdateRequest struct {
 		Field []string `json:"field,omitempty"`
 		Data  []any    `json:"data,omitempty"`
+
+		built bool
 	}
 
 	// DeviceResponse represents a response containing a list of devices from the LibreNMS API.
@@ -107,9 +119,16 @@ type (
 		Devices []Device `json:"devices"`
 	}
 
+	// DeviceWithFieldsResponse is like DeviceResponse, but records the fields returned for
+	// each device.
+	DeviceWithFieldsResponse struct {
+		BaseResponse
+		Devices []Sparse[Device] `json:"devices"`
+	}
+
 	// DeviceAvailability represents availability information for a device.
 	DeviceAvailability struct {
-		Duration         int     `json:"duration,omitempty"`
+		Duration         Int     `json:"duration,omitempty"`
 		AvailabilityPerc Float64 `json:"availability_perc,omitempty"`
 	}
 
@@ -121,8 +140,8 @@ type (
 
 	// DeviceOutage represents an outage for a device.
 	DeviceOutage struct {
-		GoingDown int64 `json:"going_down,omitempty"`
-		UpAgain   int64 `json:"up_again,omitempty"`
+		GoingDown Int64 `json:"going_down,omitempty"`
+		UpAgain   Int64 `json:"up_again,omitempty"`
 	}
 
 	// DeviceOutagesResponse represents a response containing outages information for a device.
@@ -133,8 +152,8 @@ type (
 
 	// DeviceGraph represents a graph for a device.
 	DeviceGraph struct {
-		Desc string `json:"desc,omitempty"`
-		Name string `json:"name,omitempty"`
+		Desc String `json:"desc,omitempty"`
+		Name String `json:"name,omitempty"`
 	}
 
 	// DeviceGraphsResponse represents a response containing graphs information for a device.
@@ -145,7 +164,7 @@ type (
 
 	// DevicePort represents a port for a device.
 	DevicePort struct {
-		IfName string `json:"ifName,omitempty"`
+		IfName String `json:"ifName,omitempty"`
 	}
 
 	// DevicePortsResponse represents a response containing ports information for a device.
@@ -156,13 +175,13 @@ type (
 
 	// DeviceFDB represents a FDB entry for a device.
 	DeviceFDB struct {
-		PortFDBID  int    `json:"ports_fdb_id,omitempty"`
-		PortID     int    `json:"port_id,omitempty"`
-		MacAddress string `json:"mac_address,omitempty"`
-		VlanID     int    `json:"vlan_id,omitempty"`
-		DeviceID   int    `json:"device_id,omitempty"`
-		CreatedAt  string `json:"created_at,omitempty"`
-		UpdatedAt  string `json:"updated_at,omitempty"`
+		PortFDBID  Int  `json:"ports_fdb_id,omitempty"`
+		PortID     Int  `json:"port_id,omitempty"`
+		MacAddress MAC  `json:"mac_address,omitempty"`
+		VlanID     Int  `json:"vlan_id,omitempty"`
+		DeviceID   Int  `json:"device_id,omitempty"`
+		CreatedAt  Time `json:"created_at,omitempty"`
+		UpdatedAt  Time `json:"updated_at,omitempty"`
 	}
 
 	// DeviceFDBResponse represents a response containing FDB information for a device.
@@ -173,26 +192,26 @@ type (
 
 	// DeviceNAC represents a NAC entry for a device.
 	DeviceNAC struct {
-		PortNACID   int    `json:"ports_nac_id,omitempty"`
-		AuthID      string `json:"auth_id,omitempty"`
-		DeviceID    int    `json:"device_id,omitempty"`
-		PortID      int    `json:"port_id,omitempty"`
-		Domain      string `json:"domain,omitempty"`
-		Username    string `json:"username,omitempty"`
-		MacAddress  string `json:"mac_address,omitempty"`
-		IPAddress   string `json:"ip_address,omitempty"`
-		HostMode    string `json:"host_mode,omitempty"`
-		AuthzStatus string `json:"authz_status,omitempty"`
-		AuthzBy     string `json:"authz_by,omitempty"`
-		AuthcStatus string `json:"authc_status,omitempty"`
-		Method      string `json:"method,omitempty"`
-		Timeout     string `json:"timeout,omitempty"`
-		TimeLeft    string `json:"time_left,omitempty"`
-		Vlan        int    `json:"vlan,omitempty"`
-		TimeElapsed string `json:"time_elapsed,omitempty"`
-		CreatedAt   string `json:"created_at,omitempty"`
-		UpdatedAt   string `json:"updated_at,omitempty"`
-		Historical  int    `json:"historical,omitempty"`
+		PortNACID   Int    `json:"ports_nac_id,omitempty"`
+		AuthID      String `json:"auth_id,omitempty"`
+		DeviceID    Int    `json:"device_id,omitempty"`
+		PortID      Int    `json:"port_id,omitempty"`
+		Domain      String `json:"domain,omitempty"`
+		Username    String `json:"username,omitempty"`
+		MacAddress  MAC    `json:"mac_address,omitempty"`
+		IPAddress   String `json:"ip_address,omitempty"`
+		HostMode    String `json:"host_mode,omitempty"`
+		AuthzStatus String `json:"authz_status,omitempty"`
+		AuthzBy     String `json:"authz_by,omitempty"`
+		AuthcStatus String `json:"authc_status,omitempty"`
+		Method      String `json:"method,omitempty"`
+		Timeout     String `json:"timeout,omitempty"`
+		TimeLeft    String `json:"time_left,omitempty"`
+		Vlan        Int    `json:"vlan,omitempty"`
+		TimeElapsed String `json:"time_elapsed,omitempty"`
+		CreatedAt   Time   `json:"created_at,omitempty"`
+		UpdatedAt   Time   `json:"updated_at,omitempty"`
+		Historical  Int    `json:"historical,omitempty"`
 	}
 	// DeviceNACResponse represents a response containing NAC information for a device.
 	DeviceNACResponse struct {
@@ -208,10 +227,10 @@ type (
 
 	// DevicePortStack represents a port stack for a device.
 	DevicePortStack struct {
-		DeviceID      int    `json:"device_id,omitempty"`
-		PortIDHigh    int    `json:"port_id_high,omitempty"`
-		PortIDLow     int    `json:"port_id_low,omitempty"`
-		IfStackStatus string `json:"ifStackStatus,omitempty"`
+		DeviceID      Int    `json:"device_id,omitempty"`
+		PortIDHigh    Int    `json:"port_id_high,omitempty"`
+		PortIDLow     Int    `json:"port_id_low,omitempty"`
+		IfStackStatus String `json:"ifStackStatus,omitempty"`
 	}
 
 	// DevicePortStackResponse represents a response containing port stack information for a device.
@@ -222,26 +241,26 @@ type (
 
 	// DeviceTransceiver represents a transceiver for a device.
 	DeviceTransceiver struct {
-		ID         int    `json:"id,omitempty"`
-		CreatedAt  string `json:"created_at,omitempty"`
-		UpdatedAt  string `json:"updated_at,omitempty"`
-		DeviceID   int    `json:"device_id,omitempty"`
-		PortID     int    `json:"port_id,omitempty"`
-		Index      string `json:"index,omitempty"`
-		Type       string `json:"type,omitempty"`
-		Vendor     string `json:"vendor,omitempty"`
-		OUI        string `json:"oui,omitempty"`
-		Model      string `json:"model,omitempty"`
-		Revision   string `json:"revision,omitempty"`
-		Serial     string `json:"serial,omitempty"`
-		Date       string `json:"date,omitempty"`
-		DDM        bool   `json:"ddm,omitempty"`
-		Encoding   string `json:"encoding,omitempty"`
-		Cable      string `json:"cable,omitempty"`
-		Distance   int    `json:"distance,omitempty"`
-		Wavelength int    `json:"wavelength,omitempty"`
-		Connector  string `json:"connector,omitempty"`
-		Channels   int    `json:"channels,omitempty"`
+		ID         Int    `json:"id,omitempty"`
+		CreatedAt  Time   `json:"created_at,omitempty"`
+		UpdatedAt  Time   `json:"updated_at,omitempty"`
+		DeviceID   Int    `json:"device_id,omitempty"`
+		PortID     Int    `json:"port_id,omitempty"`
+		Index      String `json:"index,omitempty"`
+		Type       String `json:"type,omitempty"`
+		Vendor     String `json:"vendor,omitempty"`
+		OUI        String `json:"oui,omitempty"`
+		Model      String `json:"model,omitempty"`
+		Revision   String `json:"revision,omitempty"`
+		Serial     String `json:"serial,omitempty"`
+		Date       String `json:"date,omitempty"`
+		DDM        Bool   `json:"ddm,omitempty"`
+		Encoding   String `json:"encoding,omitempty"`
+		Cable      String `json:"cable,omitempty"`
+		Distance   Int    `json:"distance,omitempty"`
+		Wavelength Int    `json:"wavelength,omitempty"`
+		Connector  String `json:"connector,omitempty"`
+		Channels   Int    `json:"channels,omitempty"`
 	}
 
 	// DeviceTransceiversResponse represents a response containing transceivers information for a device.
@@ -262,14 +281,14 @@ type (
 
 	// DeviceComponent represents a component for a device.
 	DeviceComponent struct {
-		TestAttribute1 string `json:"TestAttribute-1,omitempty"`
-		TestAttribute2 string `json:"TestAttribute-2,omitempty"`
-		TestAttribute3 string `json:"TestAttribute-3,omitempty"`
-		Type           string `json:"type,omitempty"`
-		Label          string `json:"label,omitempty"`
-		Status         string `json:"status,omitempty"`
-		Ignore         bool   `json:"ignore,omitempty"`
-		Disabled       bool   `json:"disabled,omitempty"`
+		TestAttribute1 String `json:"TestAttribute-1,omitempty"`
+		TestAttribute2 String `json:"TestAttribute-2,omitempty"`
+		TestAttribute3 String `json:"TestAttribute-3,omitempty"`
+		Type           String `json:"type,omitempty"`
+		Label          String `json:"label,omitempty"`
+		Status         String `json:"status,omitempty"`
+		Ignore         Bool   `json:"ignore,omitempty"`
+		Disabled       Bool   `json:"disabled,omitempty"`
 	}
 
 	// DeviceComponentsResponse represents a response containing components information for a device.
@@ -280,10 +299,10 @@ type (
 
 	// PortStats represents port stats for a device.
 	PortStats struct {
-		PortID     int `json:"port_id,omitempty"`
-		DeviceID   int `json:"device_id,omitempty"`
-		PollPrev   int `json:"poll_prev,omitempty"`
-		PollPeriod int `json:"poll_period,omitempty"`
+		PortID     Int   `json:"port_id,omitempty"`
+		DeviceID   Int   `json:"device_id,omitempty"`
+		PollPrev   Int64 `json:"poll_prev,omitempty"`
+		PollPeriod Int64 `json:"poll_period,omitempty"`
 	}
 
 	// DevicePortStatsResponse represents a response containing port stats information for a device.
@@ -295,10 +314,14 @@ type (
 	// DeviceMaintenanceResponse represents a response containing maintenance information for a device.
 	DeviceMaintenanceResponse struct {
 		BaseResponse
-		IsUnderMaintenance bool `json:"is_under_maintenance,omitempty"`
+		IsUnderMaintenance Bool `json:"is_under_maintenance,omitempty"`
 	}
 
-	// DeviceMaintenanceRequest represents a request to set maintenance for a device.
+	// DeviceMaintenanceRequest represents a request to set maintenance for a device or a
+	// device group.
+	//
+	// Start uses the MaintenanceStartLayout in the timezone of the LibreNMS server and Duration
+	// is formatted as H:i. Use NewDeviceMaintenanceRequest to set them from typed values.
 	DeviceMaintenanceRequest struct {
 		Title    string `json:"title,omitempty"`
 		Notes    string `json:"notes,omitempty"`
@@ -328,3 +351,252 @@ type (
 		Type       string `form:"type,omitempty"`
 	}
 )
+
+// deviceFieldKind is the value type of a device field.
+type deviceFieldKind int
+
+const (
+	deviceFieldString deviceFieldKind = iota
+	deviceFieldInt
+	deviceFieldBool
+)
+
+// deviceUpdateFields are the device fields LibreNMS permits patching, with their value type.
+// LibreNMS refuses the device_id and the hostname (see RenameDevice). The fields maintained
+// by the pollers, like hardware, os or sysName, are accepted but overwritten by the next
+// poll.
+var deviceUpdateFields = map[string]deviceFieldKind{
+	"authalgo":              deviceFieldString,
+	"authlevel":             deviceFieldString,
+	"authname":              deviceFieldString,
+	"authpass":              deviceFieldString,
+	"community":             deviceFieldString,
+	"cryptoalgo":            deviceFieldString,
+	"cryptopass":            deviceFieldString,
+	"disable_notify":        deviceFieldBool,
+	"disabled":              deviceFieldBool,
+	"display":               deviceFieldString,
+	"features":              deviceFieldString,
+	"hardware":              deviceFieldString,
+	"icon":                  deviceFieldString,
+	"ignore":                deviceFieldBool,
+	"ignore_status":         deviceFieldBool,
+	"ip":                    deviceFieldString,
+	"location_id":           deviceFieldInt,
+	"max_depth":             deviceFieldInt,
+	"notes":                 deviceFieldString,
+	"os":                    deviceFieldString,
+	"override_sysLocation":  deviceFieldBool,
+	"overwrite_ip":          deviceFieldString,
+	"poller_group":          deviceFieldInt,
+	"port":                  deviceFieldInt,
+	"port_association_mode": deviceFieldInt,
+	"purpose":               deviceFieldString,
+	"retries":               deviceFieldInt,
+	"serial":                deviceFieldString,
+	"snmp_disable":          deviceFieldBool,
+	"snmpver":               deviceFieldString,
+	"sysContact":            deviceFieldString,
+	"sysName":               deviceFieldString,
+	"timeout":               deviceFieldInt,
+	"transport":             deviceFieldString,
+	"type":                  deviceFieldString,
+	"version":               deviceFieldString,
+}
+
+// NewDeviceUpdate creates a new, empty DeviceUpdateRequest, validated before it is sent.
+func NewDeviceUpdate() *DeviceUpdateRequest {
+	return &DeviceUpdateRequest{built: true}
+}
+
+// Built reports whether the DeviceUpdateRequest was created by NewDeviceUpdate or
+// DiffDevices, and so must pass Validate before it is sent.
+func (r *DeviceUpdateRequest) Built() bool {
+	return r.built
+}
+
+// DiffDevices returns the minimal update turning old into updated: the fields LibreNMS
+// permits patching whose value differ, sorted by name. The update is empty when nothing
+// changed. old may come from a response limited to some columns: the fields that weren't
+// returned are zero, so they are only sent when updated sets them.
+func DiffDevices(old, updated Device) *DeviceUpdateRequest {
+	changes := NewSparse(old).Changes(updated)
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		if _, ok := deviceUpdateFields[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	r := NewDeviceUpdate()
+	for _, field := range fields {
+		r.Set(field, deviceFieldValue(deviceUpdateFields[field], reflect.ValueOf(changes[field])))
+	}
+	return r
+}
+
+// Set sets a field of the DeviceUpdateRequest, replacing its previous value. Prefer the
+// typed setters; the field and value are checked by Validate.
+func (r *DeviceUpdateRequest) Set(field string, value any) *DeviceUpdateRequest {
+	for i, f := range r.Field {
+		if f == field && i < len(r.Data) {
+			r.Data[i] = value
+			return r
+		}
+	}
+	r.Field = append(r.Field, field)
+	r.Data = append(r.Data, value)
+	return r
+}
+
+// SetNotes sets the notes of the device in the DeviceUpdateRequest.
+func (r *DeviceUpdateRequest) SetNotes(notes string) *DeviceUpdateRequest {
+	return r.Set("notes", notes)
+}
+
+// SetPurpose sets the description of the device in the DeviceUpdateRequest.
+func (r *DeviceUpdateRequest) SetPurpose(purpose string) *DeviceUpdateRequest {
+	return r.Set("purpose", purpose)
+}
+
+// SetDisplay sets the display name template of the device in the DeviceUpdateRequest.
+func (r *DeviceUpdateRequest) SetDisplay(display string) *DeviceUpdateRequest {
+	return r.Set("display", display)
+}
+
+// SetDisabled sets whether polling of the device is disabled in the DeviceUpdateRequest.
+func (r *DeviceUpdateRequest) SetDisabled(disabled bool) *DeviceUpdateRequest {
+	return r.Set("disabled", disabled)
+}
+
+// SetIgnore sets whether alerts of the device are ignored in the DeviceUpdateRequest.
+func (r *DeviceUpdateRequest) SetIgnore(ignore bool) *DeviceUpdateRequest {
+	return r.Set("ignore", ignore)
+}
+
+// SetDisableNotify sets whether alert notifications of the device are disabled in the
+// DeviceUpdateRequest.
+func (r *DeviceUpdateRequest) SetDisableNotify(disable bool) *DeviceUpdateRequest {
+	return r.Set("disable_notify", disable)
+}
+
+// SetLocationID sets the location of the device in the DeviceUpdateRequest.
+func (r *DeviceUpdateRequest) SetLocationID(locationID int) *DeviceUpdateRequest {
+	return r.Set("location_id", locationID)
+}
+
+// SetOverrideSysLocation sets whether the location overrides the sysLocation of the device in
+// the DeviceUpdateRequest.
+func (r *DeviceUpdateRequest) SetOverrideSysLocation(override bool) *DeviceUpdateRequest {
+	return r.Set("override_sysLocation", override)
+}
+
+// SetPollerGroup sets the poller group of the device in the DeviceUpdateRequest.
+func (r *DeviceUpdateRequest) SetPollerGroup(group int) *DeviceUpdateRequest {
+	return r.Set("poller_group", group)
+}
+
+// SetOverwriteIP sets the IP address used to poll the device in the DeviceUpdateRequest.
+func (r *DeviceUpdateRequest) SetOverwriteIP(ip string) *DeviceUpdateRequest {
+	return r.Set("overwrite_ip", ip)
+}
+
+// SetPortAssociationMode sets how ports are matched between polls in the DeviceUpdateRequest:
+// ifIndex(1), ifName(2), ifDescr(3) or ifAlias(4).
+func (r *DeviceUpdateRequest) SetPortAssociationMode(mode int) *DeviceUpdateRequest {
+	return r.Set("port_association_mode", mode)
+}
+
+// Validate checks that the fields and values of the DeviceUpdateRequest are aligned, that
+// LibreNMS permits patching the fields and that the values have the right type. nil clears a
+// field, and booleans may also be given as 0 or 1, or "0" or "1".
+func (r *DeviceUpdateRequest) Validate() error {
+	if len(r.Field) == 0 {
+		return fmt.Errorf("no device fields to update")
+	}
+	if len(r.Field) != len(r.Data) {
+		return fmt.Errorf("%d device fields for %d values", len(r.Field), len(r.Data))
+	}
+	for i, field := range r.Field {
+		kind, ok := deviceUpdateFields[field]
+		if !ok {
+			return fmt.Errorf("device field %q cannot be updated", field)
+		}
+		if !validDeviceFieldValue(kind, reflect.ValueOf(r.Data[i])) {
+			return fmt.Errorf("invalid value %v for device field %q", r.Data[i], field)
+		}
+	}
+	return nil
+}
+
+// deviceFieldValue converts a value of a Device field to the plain type of the API.
+func deviceFieldValue(kind deviceFieldKind, v reflect.Value) any {
+	switch {
+	case kind == deviceFieldBool && v.Kind() == reflect.Bool:
+		return v.Bool()
+	case kind == deviceFieldInt && v.CanInt():
+		return int(v.Int())
+	case kind == deviceFieldString && v.Kind() == reflect.String:
+		return v.String()
+	default:
+		return v.Interface()
+	}
+}
+
+// validDeviceFieldValue reports whether v is a valid value for a field of the given kind.
+// The invalid value of nil is valid for every kind.
+func validDeviceFieldValue(kind deviceFieldKind, v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch kind {
+	case deviceFieldBool:
+		return v.Kind() == reflect.Bool || v.CanInt() && (v.Int() == 0 || v.Int() == 1) ||
+			v.Kind() == reflect.String && (v.String() == "0" || v.String() == "1")
+	case deviceFieldInt:
+		return v.CanInt()
+	default:
+		return v.Kind() == reflect.String
+	}
+}
+
+// MaintenanceStartLayout is the time layout of DeviceMaintenanceRequest.Start.
+const MaintenanceStartLayout = "2006-01-02 15:04:00"
+
+// NewDeviceMaintenanceRequest creates a maintenance request for the given duration. A zero
+// start lets LibreNMS start the maintenance immediately.
+func NewDeviceMaintenanceRequest(title string, start time.Time, duration time.Duration) *DeviceMaintenanceRequest {
+	return (&DeviceMaintenanceRequest{Title: title}).SetStart(start).SetDuration(duration)
+}
+
+// SetStart sets the start time of the DeviceMaintenanceRequest. The time is formatted as is,
+// convert it to the timezone of the LibreNMS server first. A zero time clears the start.
+func (r *DeviceMaintenanceRequest) SetStart(start time.Time) *DeviceMaintenanceRequest {
+	r.Start = ""
+	if !start.IsZero() {
+		r.Start = start.Format(MaintenanceStartLayout)
+	}
+	return r
+}
+
+// SetDuration sets the duration of the DeviceMaintenanceRequest, rounded up to whole minutes.
+func (r *DeviceMaintenanceRequest) SetDuration(duration time.Duration) *DeviceMaintenanceRequest {
+	r.Duration = FormatMaintenanceDuration(duration)
+	return r
+}
+
+// SetNotes sets the notes of the DeviceMaintenanceRequest.
+func (r *DeviceMaintenanceRequest) SetNotes(notes string) *DeviceMaintenanceRequest {
+	r.Notes = notes
+	return r
+}
+
+// FormatMaintenanceDuration formats a duration as H:i, rounded up to whole minutes, e.g.
+// 90 minutes as "1:30". Hours are not wrapped at 24.
+func FormatMaintenanceDuration(duration time.Duration) string {
+	if duration < 0 {
+		duration = 0
+	}
+	minutes := int64((duration + time.Minute - 1) / time.Minute)
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
@@ -1,16 +1,20 @@
 package types
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
 
 type (
 	// DeviceGroup represents a device group in LibreNMS.
 	DeviceGroup struct {
-		ID          int                      `json:"id,omitempty"`
-		Name        string                   `json:"name,omitempty"`
-		Description string                   `json:"desc,omitempty"`
-		Pattern     string                   `json:"pattern,omitempty"`
+		ID          Int                      `json:"id,omitempty"`
+		Name        String                   `json:"name,omitempty"`
+		Description String                   `json:"desc,omitempty"`
+		Pattern     String                   `json:"pattern,omitempty"`
 		Rules       DeviceGroupRuleContainer `json:"rules,omitempty"`
-		Type        string                   `json:"type,omitempty"`
+		Type        String                   `json:"type,omitempty"`
 	}
 
 	// DeviceGroupRuleContainer represents the top-level container for device group rules.
@@ -26,6 +30,9 @@ type (
 	//
 	// A terminal section defines id, field, type, input, operator, and value.
 	// A non-terminal section defines condition and a list of rules.
+	//
+	// Operators that take several values (in, between, ...) use Values instead of Value,
+	// which is encoded as a JSON array.
 	DeviceGroupRule struct {
 		ID        string            `json:"id,omitempty"`
 		Condition string            `json:"condition,omitempty"`
@@ -35,6 +42,7 @@ type (
 		Rules     []DeviceGroupRule `json:"rules,omitempty"`
 		Type      string            `json:"type,omitempty"`
 		Value     string            `json:"value,omitempty"`
+		Values    []string          `json:"-"`
 	}
 
 	// DeviceGroupCreateRequest represents the request payload for creating a device group.
@@ -71,7 +79,7 @@ type (
 
 	// DeviceGroupMember represents a member of a device group.
 	DeviceGroupMember struct {
-		ID int `json:"device_id,omitempty"`
+		ID Int `json:"device_id,omitempty"`
 	}
 
 	// DeviceGroupMembersResponse represents a response containing the members of a device group.
@@ -83,7 +91,7 @@ type (
 	// DeviceGroupCreateResponse represents a creation response.
 	DeviceGroupCreateResponse struct {
 		BaseResponse
-		ID int `json:"id,omitempty"`
+		ID Int `json:"id,omitempty"`
 	}
 )
 
@@ -105,3 +113,78 @@ func (g *DeviceGroupRuleContainer) MustJSON() string {
 	}
 	return string(data)
 }
+
+// MarshalJSON implements the JSON marshaling for the DeviceGroupRule type.
+// Values is written as the "value" array when set.
+func (r DeviceGroupRule) MarshalJSON() ([]byte, error) {
+	type rule DeviceGroupRule
+	if len(r.Values) == 0 {
+		return json.Marshal(rule(r))
+	}
+	return json.Marshal(struct {
+		rule
+		Value []string `json:"value"`
+	}{rule(r), r.Values})
+}
+
+// UnmarshalJSON implements the JSON unmarshalling for the DeviceGroupRule type.
+// The API returns values as strings, numbers or arrays depending on the operator.
+func (r *DeviceGroupRule) UnmarshalJSON(data []byte) error {
+	type rule DeviceGroupRule
+	aux := struct {
+		*rule
+		Value json.RawMessage `json:"value"`
+	}{rule: (*rule)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.Value, r.Values = "", nil
+	if len(aux.Value) == 0 || aux.Value[0] != '[' {
+		value, err := ruleValueString(aux.Value)
+		if err != nil {
+			return err
+		}
+		r.Value = value
+		return nil
+	}
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(aux.Value, &values); err != nil {
+		return fmt.Errorf("failed to unmarshal rule value: %w", err)
+	}
+	r.Values = make([]string, 0, len(values))
+	for _, raw := range values {
+		value, err := ruleValueString(raw)
+		if err != nil {
+			return err
+		}
+		r.Values = append(r.Values, value)
+	}
+	return nil
+}
+
+// ruleValueString converts a scalar JSON rule value to its string form.
+func ruleValueString(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", fmt.Errorf("failed to unmarshal rule value: %w", err)
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	default:
+		return "", fmt.Errorf("unsupported rule value %s", raw)
+	}
+}
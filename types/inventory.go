@@ -2,33 +2,33 @@ package types
 
 type (
 	InventoryItem struct {
-		EntPhysicalID           int    `json:"entPhysical_id,omitempty"`
-		DeviceID                int    `json:"device_id,omitempty"`
-		EntPhysicalIndex        int    `json:"entPhysicalIndex,omitempty"`
-		EntPhysicalDescr        string `json:"entPhysicalDescr,omitempty"`
-		EntPhysicalClass        string `json:"entPhysicalClass,omitempty"`
-		EntPhysicalName         string `json:"entPhysicalName,omitempty"`
-		EntPhysicalHardwareRev  string `json:"entPhysicalHardwareRev,omitempty"`
-		EntPhysicalFirmwareRev  string `json:"entPhysicalFirmwareRev,omitempty"`
-		EntPhysicalSoftwareRev  string `json:"entPhysicalSoftwareRev,omitempty"`
-		EntPhysicalSerialNum    string `json:"entPhysicalSerialNum,omitempty"`
-		EntPhysicalModelName    string `json:"entPhysicalModelName,omitempty"`
-		EntPhysicalMfgName      string `json:"entPhysicalMfgName,omitempty"`
+		EntPhysicalID           Int    `json:"entPhysical_id,omitempty"`
+		DeviceID                Int    `json:"device_id,omitempty"`
+		EntPhysicalIndex        Int    `json:"entPhysicalIndex,omitempty"`
+		EntPhysicalDescr        String `json:"entPhysicalDescr,omitempty"`
+		EntPhysicalClass        String `json:"entPhysicalClass,omitempty"`
+		EntPhysicalName         String `json:"entPhysicalName,omitempty"`
+		EntPhysicalHardwareRev  String `json:"entPhysicalHardwareRev,omitempty"`
+		EntPhysicalFirmwareRev  String `json:"entPhysicalFirmwareRev,omitempty"`
+		EntPhysicalSoftwareRev  String `json:"entPhysicalSoftwareRev,omitempty"`
+		EntPhysicalSerialNum    String `json:"entPhysicalSerialNum,omitempty"`
+		EntPhysicalModelName    String `json:"entPhysicalModelName,omitempty"`
+		EntPhysicalMfgName      String `json:"entPhysicalMfgName,omitempty"`
 		EntPhysicalIsFRU        Bool   `json:"entPhysicalIsFRU,omitempty"`
-		EntPhysicalAlias        string `json:"entPhysicalAlias,omitempty"`
-		EntPhysicalAssetID      string `json:"entPhysicalAssetID,omitempty"`
-		EntPhysicalContainedIn  int    `json:"entPhysicalContainedIn,omitempty"`
-		EntPhysicalParentRelPos int    `json:"entPhysicalParentRelPos,omitempty"`
-		EntPhysicalMfgDate      string `json:"entPhysicalMfgDate,omitempty"`
-		EntPhysicalUris         string `json:"entPhysicalUris,omitempty"`
-		EntPhysicalVendorType   string `json:"entPhysicalVendorType,omitempty"`
-		IfIndex                 string `json:"ifIndex,omitempty"`
+		EntPhysicalAlias        String `json:"entPhysicalAlias,omitempty"`
+		EntPhysicalAssetID      String `json:"entPhysicalAssetID,omitempty"`
+		EntPhysicalContainedIn  Int    `json:"entPhysicalContainedIn,omitempty"`
+		EntPhysicalParentRelPos Int    `json:"entPhysicalParentRelPos,omitempty"`
+		EntPhysicalMfgDate      String `json:"entPhysicalMfgDate,omitempty"`
+		EntPhysicalUris         String `json:"entPhysicalUris,omitempty"`
+		EntPhysicalVendorType   String `json:"entPhysicalVendorType,omitempty"`
+		IfIndex                 Int    `json:"ifIndex,omitempty"`
 		Deleted                 Bool   `json:"deleted,omitempty"`
 	}
 
 	InventoryResponse struct {
 		BaseResponse
-		Count     int             `json:"count,omitempty"`
+		Count     Int             `json:"count,omitempty"`
 		Inventory []InventoryItem `json:"inventory"`
 	}
 
@@ -3,12 +3,12 @@ package types
 type (
 	// Location represents a location in LibreNMS.
 	Location struct {
-		ID               int     `json:"id,omitempty"`
+		ID               Int     `json:"id,omitempty"`
 		FixedCoordinates Bool    `json:"fixed_coordinates,omitempty"`
 		Latitude         Float64 `json:"lat,omitempty"`
 		Longitude        Float64 `json:"lng,omitempty"`
-		Name             string  `json:"location,omitempty"`
-		Timestamp        string  `json:"timestamp,omitempty"`
+		Name             String  `json:"location,omitempty"`
+		Timestamp        Time    `json:"timestamp,omitempty"`
 	}
 
 	// LocationCreateRequest represents the request payload for creating a location.
@@ -32,7 +32,7 @@ type (
 
 	// LocationResponse represents a response containing a single location from the LibreNMS API.
 	LocationResponse struct {
-		Status   string   `json:"status,omitempty"`
+		Status   String   `json:"status,omitempty"`
 		Location Location `json:"get_location,omitempty"`
 	}
 
@@ -3,24 +3,24 @@ package types
 type (
 	// Log represents a log entry in LibreNMS.
 	Log struct {
-		Hostname  string `json:"hostname,omitempty"`
-		SysName   string `json:"sysName,omitempty"`
-		EventID   int    `json:"event_id,omitempty"`
-		Host      int    `json:"host,omitempty"`
-		DeviceID  int    `json:"device_id,omitempty"`
-		DateTime  string `json:"datetime,omitempty"`
-		Message   string `json:"message,omitempty"`
-		Type      string `json:"type,omitempty"`
-		Reference string `json:"reference,omitempty"`
-		Username  string `json:"username,omitempty"`
-		Severity  int    `json:"severity,omitempty"`
+		Hostname  String `json:"hostname,omitempty"`
+		SysName   String `json:"sysName,omitempty"`
+		EventID   Int    `json:"event_id,omitempty"`
+		Host      Int    `json:"host,omitempty"`
+		DeviceID  Int    `json:"device_id,omitempty"`
+		DateTime  Time   `json:"datetime,omitempty"`
+		Message   String `json:"message,omitempty"`
+		Type      String `json:"type,omitempty"`
+		Reference String `json:"reference,omitempty"`
+		Username  String `json:"username,omitempty"`
+		Severity  Int    `json:"severity,omitempty"`
 		Details   any    `json:"details,omitempty"`
 	}
 
 	// LogsResponse represents a response containing logs from the LibreNMS API.
 	LogsResponse struct {
 		BaseResponse
-		Total int   `json:"total,omitempty"`
+		Total Int   `json:"total,omitempty"`
 		Logs  []Log `json:"logs"`
 	}
 
@@ -0,0 +1,197 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MACFormat is a notation of MAC addresses.
+type MACFormat int
+
+// MAC address notations.
+const (
+	// MACColon is the IEEE notation with colons, e.g. 00:1a:2b:3c:4d:5e.
+	MACColon MACFormat = iota
+	// MACDash is the IEEE notation with dashes, e.g. 00-1a-2b-3c-4d-5e.
+	MACDash
+	// MACDot is the Cisco dotted notation, e.g. 001a.2b3c.4d5e.
+	MACDot
+	// MACBare is bare hex, e.g. 001a2b3c4d5e. LibreNMS stores and expects this notation.
+	MACBare
+)
+
+// DefaultMACFormat is the notation used by MAC.String and to marshal MAC addresses. Set it
+// once at startup.
+var DefaultMACFormat = MACColon
+
+// MAC represents a 48-bit MAC address. The zero MAC means no address: it is marshaled as an
+// empty string and null or an empty string decode to it.
+type MAC [6]byte
+
+// ParseMAC parses a MAC address in the colon, dash, Cisco dotted, HP (001a2b-3c4d5e) or
+// bare hex notation. Leading zeros of the colon and dash groups may be omitted, e.g.
+// 0:1a:2b:3c:4d:5e, and a 0x prefix or space separated bytes, as printed by SNMP tools, are
+// accepted. Case is ignored.
+func ParseMAC(s string) (MAC, error) {
+	digits, ok := macDigits(strings.ToLower(strings.TrimSpace(s)))
+	var mac MAC
+	if !ok || len(digits) != 2*len(mac) {
+		return MAC{}, fmt.Errorf("invalid MAC address %q", s)
+	}
+	if _, err := hex.Decode(mac[:], []byte(digits)); err != nil {
+		return MAC{}, fmt.Errorf("invalid MAC address %q", s)
+	}
+	return mac, nil
+}
+
+// MustParseMAC is like ParseMAC but panics on invalid addresses. It is meant for constants
+// and tests.
+func MustParseMAC(s string) MAC {
+	mac, err := ParseMAC(s)
+	if err != nil {
+		panic(err)
+	}
+	return mac
+}
+
+// IsZero reports whether m is the zero MAC.
+func (m MAC) IsZero() bool {
+	return m == MAC{}
+}
+
+// IsLocal reports whether m is locally administered, like the random addresses of phones
+// and virtual machines. Such addresses don't belong to the vendor of their OUI.
+func (m MAC) IsLocal() bool {
+	return m[0]&0x02 != 0
+}
+
+// IsMulticast reports whether m is a group address.
+func (m MAC) IsMulticast() bool {
+	return m[0]&0x01 != 0
+}
+
+// OUI returns the organizationally unique identifier, the first three bytes of m.
+func (m MAC) OUI() [3]byte {
+	return [3]byte{m[0], m[1], m[2]}
+}
+
+// String returns m in the DefaultMACFormat, or an empty string for the zero MAC.
+func (m MAC) String() string {
+	return m.Text(DefaultMACFormat)
+}
+
+// Text returns m in the given notation, or an empty string for the zero MAC.
+func (m MAC) Text(format MACFormat) string {
+	if m.IsZero() {
+		return ""
+	}
+	digits := hex.EncodeToString(m[:])
+	switch format {
+	case MACBare:
+		return digits
+	case MACDot:
+		return digits[0:4] + "." + digits[4:8] + "." + digits[8:12]
+	case MACDash:
+		return joinPairs(digits, "-")
+	default:
+		return joinPairs(digits, ":")
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler with the DefaultMACFormat.
+func (m MAC) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. An empty text is the zero MAC.
+func (m *MAC) UnmarshalText(text []byte) error {
+	if len(strings.TrimSpace(string(text))) == 0 {
+		*m = MAC{}
+		return nil
+	}
+	mac, err := ParseMAC(string(text))
+	if err != nil {
+		return err
+	}
+	*m = mac
+	return nil
+}
+
+// MarshalJSON implements the JSON marshaling for the MAC type.
+func (m MAC) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON implements the JSON unmarshalling for the MAC type. Hex addresses that
+// aren't 48 bits long, like the link-layer addresses of tunnels and InfiniBand ports in
+// ifPhysAddress, decode to the zero MAC rather than failing the whole response.
+func (m *MAC) UnmarshalJSON(data []byte) error {
+	value, _, err := parseScalar(data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal MAC: %w", err)
+	}
+	if digits, ok := macDigits(strings.ToLower(strings.TrimSpace(value))); ok && len(digits) != 12 && isHex(digits) {
+		*m = MAC{}
+		return nil
+	}
+	if err := m.UnmarshalText([]byte(value)); err != nil {
+		return fmt.Errorf("failed to unmarshal MAC: %w", err)
+	}
+	return nil
+}
+
+// macDigits returns the hex digits of a lowercase MAC address without separators. It
+// reports false when the separators don't split the address into bytes, or Cisco or HP
+// groups.
+func macDigits(s string) (string, bool) {
+	s = strings.TrimPrefix(s, "0x")
+	for _, sep := range []string{":", "-", " ", "."} {
+		groups := strings.Split(s, sep)
+		if len(groups) == 1 {
+			continue
+		}
+		switch {
+		case len(groups) == 2 && sep == "-":
+			// HP groups of three bytes.
+			if len(groups[0]) != 6 || len(groups[1]) != 6 {
+				return "", false
+			}
+		case len(groups) == 3:
+			// Cisco groups of two bytes.
+			for _, group := range groups {
+				if len(group) != 4 {
+					return "", false
+				}
+			}
+		case sep == ".":
+			return "", false
+		default:
+			// Bytes, with optional leading zeros.
+			for i, group := range groups {
+				if len(group) == 0 || len(group) > 2 {
+					return "", false
+				}
+				if len(group) == 1 {
+					groups[i] = "0" + group
+				}
+			}
+		}
+		return strings.Join(groups, ""), true
+	}
+	return s, true
+}
+
+// joinPairs joins the pairs of hex digits with sep.
+func joinPairs(digits, sep string) string {
+	pairs := make([]string, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		pairs = append(pairs, digits[i:i+2])
+	}
+	return strings.Join(pairs, sep)
+}
+
+func isHex(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789abcdef") == ""
+}
@@ -0,0 +1,139 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OSPFNeighborState is the state of an OSPF neighbour, the ospfNbrState of OSPF-MIB and the
+// ospfv3NbrState of OSPFV3-MIB. The values are those of the MIBs, and the zero value is an
+// unknown state.
+type OSPFNeighborState int
+
+// OSPF neighbour states.
+const (
+	OSPFStateUnknown OSPFNeighborState = iota
+	OSPFStateDown
+	OSPFStateAttempt
+	OSPFStateInit
+	// OSPFStateTwoWay is the final state of neighbours that aren't the designated router
+	// or backup designated router of a broadcast network.
+	OSPFStateTwoWay
+	OSPFStateExchangeStart
+	OSPFStateExchange
+	OSPFStateLoading
+	OSPFStateFull
+)
+
+var ospfStateNames = [...]string{"", "down", "attempt", "init", "twoWay", "exchangeStart", "exchange", "loading", "full"}
+
+// ParseOSPFNeighborState parses a neighbour state by its MIB name or value. Case, dashes and
+// spaces are ignored and the role suffix of the CLI notation is dropped, so that "FULL/DR",
+// "2-Way" and "EXSTART" are accepted.
+func ParseOSPFNeighborState(s string) (OSPFNeighborState, error) {
+	name, _, _ := strings.Cut(strings.TrimSpace(s), "/")
+	name = strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(name))
+	switch name {
+	case "2way":
+		name = "twoway"
+	case "exstart":
+		name = "exchangestart"
+	}
+	for i, state := range ospfStateNames {
+		if i > 0 && name == strings.ToLower(state) {
+			return OSPFNeighborState(i), nil
+		}
+	}
+	if n, err := strconv.Atoi(name); err == nil && n > 0 && n < len(ospfStateNames) {
+		return OSPFNeighborState(n), nil
+	}
+	return OSPFStateUnknown, fmt.Errorf("invalid OSPF neighbour state %q", s)
+}
+
+// String returns the MIB name of the state, or an empty string for an unknown state.
+func (s OSPFNeighborState) String() string {
+	if s < 0 || int(s) >= len(ospfStateNames) {
+		return ""
+	}
+	return ospfStateNames[s]
+}
+
+// Established reports whether the neighbour reached its final state, full or two-way.
+func (s OSPFNeighborState) Established() bool {
+	return s == OSPFStateFull || s == OSPFStateTwoWay
+}
+
+// MarshalJSON implements the JSON marshaling for the OSPFNeighborState type.
+func (s OSPFNeighborState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON implements the JSON unmarshalling for the OSPFNeighborState type. States
+// that aren't known decode to OSPFStateUnknown rather than failing the whole response.
+func (s *OSPFNeighborState) UnmarshalJSON(data []byte) error {
+	value, _, err := parseScalar(data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal OSPF neighbour state: %w", err)
+	}
+	*s, _ = ParseOSPFNeighborState(value)
+	return nil
+}
+
+// OSPFAreaID is the 32-bit identifier of an OSPF area. It is written in dotted decimal, like
+// an IPv4 address, and LibreNMS returns either that notation or the integer.
+type OSPFAreaID uint32
+
+// OSPFBackbone is the backbone area.
+const OSPFBackbone OSPFAreaID = 0
+
+// ParseOSPFAreaID parses an area ID in dotted decimal or as an integer.
+func ParseOSPFAreaID(s string) (OSPFAreaID, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return OSPFAreaID(n), nil
+	}
+	octets := strings.Split(s, ".")
+	if len(octets) != 4 {
+		return 0, fmt.Errorf("invalid OSPF area ID %q", s)
+	}
+	var id uint32
+	for _, octet := range octets {
+		n, err := strconv.ParseUint(octet, 10, 8)
+		if err != nil {
+			return 0, fmt.Errorf("invalid OSPF area ID %q", s)
+		}
+		id = id<<8 | uint32(n)
+	}
+	return OSPFAreaID(id), nil
+}
+
+// String returns the area ID in dotted decimal.
+func (a OSPFAreaID) String() string {
+	return fmt.Sprintf("%d.%d.%d.%d", byte(a>>24), byte(a>>16), byte(a>>8), byte(a))
+}
+
+// MarshalJSON implements the JSON marshaling for the OSPFAreaID type, in dotted decimal.
+func (a OSPFAreaID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON implements the JSON unmarshalling for the OSPFAreaID type. null and an empty
+// string decode to the backbone.
+func (a *OSPFAreaID) UnmarshalJSON(data []byte) error {
+	value, _, err := parseScalar(data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal OSPF area ID: %w", err)
+	}
+	if strings.TrimSpace(value) == "" {
+		*a = OSPFBackbone
+		return nil
+	}
+	id, err := ParseOSPFAreaID(value)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal OSPF area ID: %w", err)
+	}
+	*a = id
+	return nil
+}
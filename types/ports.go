@@ -2,101 +2,133 @@ package types
 
 type (
 	Port struct {
-		PortID                  int     `json:"port_id,omitempty"`
-		DeviceID                int     `json:"device_id,omitempty"`
-		PortDescrType           *string `json:"port_descr_type,omitempty"`
-		PortDescrDescr          *string `json:"port_descr_descr,omitempty"`
-		PortDescrCircuit        *string `json:"port_descr_circuit,omitempty"`
-		PortDescrSpeed          *string `json:"port_descr_speed,omitempty"`
-		PortDescrNotes          *string `json:"port_descr_notes,omitempty"`
-		IfDescr                 string  `json:"ifDescr,omitempty"`
-		IfName                  string  `json:"ifName,omitempty"`
-		PortName                *string `json:"portName,omitempty"`
-		IfIndex                 int     `json:"ifIndex,omitempty"`
-		IfSpeed                 int     `json:"ifSpeed,omitempty"`
-		IfConnectorPresent      *string `json:"ifConnectorPresent,omitempty"`
-		IfPromiscuousMode       string  `json:"ifPromiscuousMode,omitempty"`
-		IfHighSpeed             int     `json:"ifHighSpeed,omitempty"`
-		IfOperStatus            string  `json:"ifOperStatus,omitempty"`
-		IfOperStatusPrev        string  `json:"ifOperStatus_prev,omitempty"`
-		IfAdminStatus           string  `json:"ifAdminStatus,omitempty"`
-		IfAdminStatusPrev       string  `json:"ifAdminStatus_prev,omitempty"`
-		IfDuplex                *string `json:"ifDuplex,omitempty"`
-		IfMtu                   int     `json:"ifMtu,omitempty"`
-		IfType                  string  `json:"ifType,omitempty"`
-		IfAlias                 string  `json:"ifAlias,omitempty"`
-		IfPhysAddress           string  `json:"ifPhysAddress,omitempty"`
-		IfHardType              string  `json:"ifHardType,omitempty"`
-		IfLastChange            int     `json:"ifLastChange,omitempty"`
-		IfVlan                  string  `json:"ifVlan,omitempty"`
-		IfTrunk                 *string `json:"ifTrunk,omitempty"`
-		IfVrf                   int     `json:"ifVrf,omitempty"`
-		CounterIn               string  `json:"counter_in,omitempty"`
-		CounterOut              string  `json:"counter_out,omitempty"`
-		Ignore                  int     `json:"ignore,omitempty"`
-		Disabled                int     `json:"disabled,omitempty"`
-		Detailed                string  `json:"detailed,omitempty"`
-		Deleted                 int     `json:"deleted,omitempty"`
-		PagpOperationMode       string  `json:"pagpOperationMode,omitempty"`
-		PagpPortState           string  `json:"pagpPortState,omitempty"`
-		PagpPartnerDeviceId     string  `json:"pagpPartnerDeviceId,omitempty"`
-		PagpPartnerLearnMethod  string  `json:"pagpPartnerLearnMethod,omitempty"`
-		PagpPartnerIfIndex      int     `json:"pagpPartnerIfIndex,omitempty"`
-		PagpPartnerGroupIfIndex int     `json:"pagpPartnerGroupIfIndex,omitempty"`
-		PagpPartnerDeviceName   string  `json:"pagpPartnerDeviceName,omitempty"`
-		PagpEthcOperationMode   string  `json:"pagpEthcOperationMode,omitempty"`
-		PagpDeviceId            string  `json:"pagpDeviceId,omitempty"`
-		PagpGroupIfIndex        int     `json:"pagpGroupIfIndex,omitempty"`
-		IfInUcastPkts           int     `json:"ifInUcastPkts,omitempty"`
-		IfInUcastPktsPrev       string  `json:"ifInUcastPkts_prev,omitempty"`
-		IfInUcastPktsDelta      string  `json:"ifInUcastPkts_delta,omitempty"`
-		IfInUcastPktsRate       string  `json:"ifInUcastPkts_rate,omitempty"`
-		IfOutUcastPkts          int     `json:"ifOutUcastPkts,omitempty"`
-		IfOutUcastPktsPrev      string  `json:"ifOutUcastPkts_prev,omitempty"`
-		IfOutUcastPktsDelta     string  `json:"ifOutUcastPkts_delta,omitempty"`
-		IfOutUcastPktsRate      string  `json:"ifOutUcastPkts_rate,omitempty"`
-		IfInErrors              int     `json:"ifInErrors,omitempty"`
-		IfInErrorsPrev          string  `json:"ifInErrors_prev,omitempty"`
-		IfInErrorsDelta         string  `json:"ifInErrors_delta,omitempty"`
-		IfInErrorsRate          string  `json:"ifInErrors_rate,omitempty"`
-		IfOutErrors             int     `json:"ifOutErrors,omitempty"`
-		IfOutErrorsPrev         string  `json:"ifOutErrors_prev,omitempty"`
-		IfOutErrorsDelta        string  `json:"ifOutErrors_delta,omitempty"`
-		IfOutErrorsRate         string  `json:"ifOutErrors_rate,omitempty"`
-		IfInOctets              int     `json:"ifInOctets,omitempty"`
-		IfInOctetsPrev          string  `json:"ifInOctets_prev,omitempty"`
-		IfInOctetsDelta         string  `json:"ifInOctets_delta,omitempty"`
-		IfInOctetsRate          string  `json:"ifInOctets_rate,omitempty"`
-		IfOutOctets             int     `json:"ifOutOctets,omitempty"`
-		IfOutOctetsPrev         string  `json:"ifOutOctets_prev,omitempty"`
-		IfOutOctetsDelta        string  `json:"ifOutOctets_delta,omitempty"`
-		IfOutOctetsRate         string  `json:"ifOutOctets_rate,omitempty"`
-		PollTime                int     `json:"poll_time,omitempty"`
-		PollPrev                string  `json:"poll_prev,omitempty"`
-		PollPeriod              string  `json:"poll_period,omitempty"`
+		PortID                  Int        `json:"port_id,omitempty"`
+		DeviceID                Int        `json:"device_id,omitempty"`
+		PortDescrType           NullString `json:"port_descr_type,omitempty"`
+		PortDescrDescr          NullString `json:"port_descr_descr,omitempty"`
+		PortDescrCircuit        NullString `json:"port_descr_circuit,omitempty"`
+		PortDescrSpeed          NullString `json:"port_descr_speed,omitempty"`
+		PortDescrNotes          NullString `json:"port_descr_notes,omitempty"`
+		IfDescr                 String     `json:"ifDescr,omitempty"`
+		IfName                  String     `json:"ifName,omitempty"`
+		PortName                NullString `json:"portName,omitempty"`
+		IfIndex                 Int        `json:"ifIndex,omitempty"`
+		IfSpeed                 Int64      `json:"ifSpeed,omitempty"`
+		IfConnectorPresent      NullString `json:"ifConnectorPresent,omitempty"`
+		IfPromiscuousMode       String     `json:"ifPromiscuousMode,omitempty"`
+		IfHighSpeed             Int64      `json:"ifHighSpeed,omitempty"`
+		IfOperStatus            String     `json:"ifOperStatus,omitempty"`
+		IfOperStatusPrev        String     `json:"ifOperStatus_prev,omitempty"`
+		IfAdminStatus           String     `json:"ifAdminStatus,omitempty"`
+		IfAdminStatusPrev       String     `json:"ifAdminStatus_prev,omitempty"`
+		IfDuplex                NullString `json:"ifDuplex,omitempty"`
+		IfMtu                   Int        `json:"ifMtu,omitempty"`
+		IfType                  String     `json:"ifType,omitempty"`
+		IfAlias                 String     `json:"ifAlias,omitempty"`
+		IfPhysAddress           MAC        `json:"ifPhysAddress,omitempty"`
+		IfHardType              String     `json:"ifHardType,omitempty"`
+		IfLastChange            Int64      `json:"ifLastChange,omitempty"`
+		IfVlan                  String     `json:"ifVlan,omitempty"`
+		IfTrunk                 NullString `json:"ifTrunk,omitempty"`
+		IfVrf                   Int        `json:"ifVrf,omitempty"`
+		CounterIn               NullInt64  `json:"counter_in,omitempty"`
+		CounterOut              NullInt64  `json:"counter_out,omitempty"`
+		Ignore                  Int        `json:"ignore,omitempty"`
+		Disabled                Int        `json:"disabled,omitempty"`
+		Detailed                String     `json:"detailed,omitempty"`
+		Deleted                 Int        `json:"deleted,omitempty"`
+		PagpOperationMode       String     `json:"pagpOperationMode,omitempty"`
+		PagpPortState           String     `json:"pagpPortState,omitempty"`
+		PagpPartnerDeviceId     String     `json:"pagpPartnerDeviceId,omitempty"`
+		PagpPartnerLearnMethod  String     `json:"pagpPartnerLearnMethod,omitempty"`
+		PagpPartnerIfIndex      Int        `json:"pagpPartnerIfIndex,omitempty"`
+		PagpPartnerGroupIfIndex Int        `json:"pagpPartnerGroupIfIndex,omitempty"`
+		PagpPartnerDeviceName   String     `json:"pagpPartnerDeviceName,omitempty"`
+		PagpEthcOperationMode   String     `json:"pagpEthcOperationMode,omitempty"`
+		PagpDeviceId            String     `json:"pagpDeviceId,omitempty"`
+		PagpGroupIfIndex        Int        `json:"pagpGroupIfIndex,omitempty"`
+		IfInUcastPkts           Int64      `json:"ifInUcastPkts,omitempty"`
+		IfInUcastPktsPrev       Int64      `json:"ifInUcastPkts_prev,omitempty"`
+		IfInUcastPktsDelta      Int64      `json:"ifInUcastPkts_delta,omitempty"`
+		IfInUcastPktsRate       Float64    `json:"ifInUcastPkts_rate,omitempty"`
+		IfOutUcastPkts          Int64      `json:"ifOutUcastPkts,omitempty"`
+		IfOutUcastPktsPrev      Int64      `json:"ifOutUcastPkts_prev,omitempty"`
+		IfOutUcastPktsDelta     Int64      `json:"ifOutUcastPkts_delta,omitempty"`
+		IfOutUcastPktsRate      Float64    `json:"ifOutUcastPkts_rate,omitempty"`
+		IfInErrors              Int64      `json:"ifInErrors,omitempty"`
+		IfInErrorsPrev          Int64      `json:"ifInErrors_prev,omitempty"`
+		IfInErrorsDelta         Int64      `json:"ifInErrors_delta,omitempty"`
+		IfInErrorsRate          Float64    `json:"ifInErrors_rate,omitempty"`
+		IfOutErrors             Int64      `json:"ifOutErrors,omitempty"`
+		IfOutErrorsPrev         Int64      `json:"ifOutErrors_prev,omitempty"`
+		IfOutErrorsDelta        Int64      `json:"ifOutErrors_delta,omitempty"`
+		IfOutErrorsRate         Float64    `json:"ifOutErrors_rate,omitempty"`
+		IfInNUcastPkts          Int64      `json:"ifInNUcastPkts,omitempty"`
+		IfInNUcastPktsPrev      Int64      `json:"ifInNUcastPkts_prev,omitempty"`
+		IfInNUcastPktsDelta     Int64      `json:"ifInNUcastPkts_delta,omitempty"`
+		IfInNUcastPktsRate      Float64    `json:"ifInNUcastPkts_rate,omitempty"`
+		IfOutNUcastPkts         Int64      `json:"ifOutNUcastPkts,omitempty"`
+		IfOutNUcastPktsPrev     Int64      `json:"ifOutNUcastPkts_prev,omitempty"`
+		IfOutNUcastPktsDelta    Int64      `json:"ifOutNUcastPkts_delta,omitempty"`
+		IfOutNUcastPktsRate     Float64    `json:"ifOutNUcastPkts_rate,omitempty"`
+		IfInBroadcastPkts       Int64      `json:"ifInBroadcastPkts,omitempty"`
+		IfInBroadcastPktsPrev   Int64      `json:"ifInBroadcastPkts_prev,omitempty"`
+		IfInBroadcastPktsDelta  Int64      `json:"ifInBroadcastPkts_delta,omitempty"`
+		IfInBroadcastPktsRate   Float64    `json:"ifInBroadcastPkts_rate,omitempty"`
+		IfOutBroadcastPkts      Int64      `json:"ifOutBroadcastPkts,omitempty"`
+		IfOutBroadcastPktsPrev  Int64      `json:"ifOutBroadcastPkts_prev,omitempty"`
+		IfOutBroadcastPktsDelta Int64      `json:"ifOutBroadcastPkts_delta,omitempty"`
+		IfOutBroadcastPktsRate  Float64    `json:"ifOutBroadcastPkts_rate,omitempty"`
+		IfInMulticastPkts       Int64      `json:"ifInMulticastPkts,omitempty"`
+		IfInMulticastPktsPrev   Int64      `json:"ifInMulticastPkts_prev,omitempty"`
+		IfInMulticastPktsDelta  Int64      `json:"ifInMulticastPkts_delta,omitempty"`
+		IfInMulticastPktsRate   Float64    `json:"ifInMulticastPkts_rate,omitempty"`
+		IfOutMulticastPkts      Int64      `json:"ifOutMulticastPkts,omitempty"`
+		IfOutMulticastPktsPrev  Int64      `json:"ifOutMulticastPkts_prev,omitempty"`
+		IfOutMulticastPktsDelta Int64      `json:"ifOutMulticastPkts_delta,omitempty"`
+		IfOutMulticastPktsRate  Float64    `json:"ifOutMulticastPkts_rate,omitempty"`
+		IfInDiscards            Int64      `json:"ifInDiscards,omitempty"`
+		IfInDiscardsPrev        Int64      `json:"ifInDiscards_prev,omitempty"`
+		IfInDiscardsDelta       Int64      `json:"ifInDiscards_delta,omitempty"`
+		IfInDiscardsRate        Float64    `json:"ifInDiscards_rate,omitempty"`
+		IfOutDiscards           Int64      `json:"ifOutDiscards,omitempty"`
+		IfOutDiscardsPrev       Int64      `json:"ifOutDiscards_prev,omitempty"`
+		IfOutDiscardsDelta      Int64      `json:"ifOutDiscards_delta,omitempty"`
+		IfOutDiscardsRate       Float64    `json:"ifOutDiscards_rate,omitempty"`
+		IfInOctets              Int64      `json:"ifInOctets,omitempty"`
+		IfInOctetsPrev          Int64      `json:"ifInOctets_prev,omitempty"`
+		IfInOctetsDelta         Int64      `json:"ifInOctets_delta,omitempty"`
+		IfInOctetsRate          Float64    `json:"ifInOctets_rate,omitempty"`
+		IfOutOctets             Int64      `json:"ifOutOctets,omitempty"`
+		IfOutOctetsPrev         Int64      `json:"ifOutOctets_prev,omitempty"`
+		IfOutOctetsDelta        Int64      `json:"ifOutOctets_delta,omitempty"`
+		IfOutOctetsRate         Float64    `json:"ifOutOctets_rate,omitempty"`
+		PollTime                Int64      `json:"poll_time,omitempty"`
+		PollPrev                Int64      `json:"poll_prev,omitempty"`
+		PollPeriod              Int64      `json:"poll_period,omitempty"`
 	}
 
 	PortTransceiver struct {
-		ID         int    `json:"id,omitempty"`
-		CreatedAt  string `json:"created_at,omitempty"`
-		UpdatedAt  string `json:"updated_at,omitempty"`
-		DeviceID   int    `json:"device_id,omitempty"`
-		PortID     int    `json:"port_id,omitempty"`
-		Index      string `json:"index,omitempty"`
-		Type       string `json:"type,omitempty"`
-		Vendor     string `json:"vendor,omitempty"`
-		OUI        string `json:"oui,omitempty"`
-		Model      string `json:"model,omitempty"`
-		Revision   string `json:"revision,omitempty"`
-		Serial     string `json:"serial,omitempty"`
-		Date       string `json:"date,omitempty"`
-		DDM        bool   `json:"ddm,omitempty"`
-		Encoding   string `json:"encoding,omitempty"`
-		Cable      string `json:"cable,omitempty"`
-		Distance   int    `json:"distance,omitempty"`
-		Wavelength int    `json:"wavelength,omitempty"`
-		Connector  string `json:"connector,omitempty"`
-		Channels   int    `json:"channels,omitempty"`
+		ID         Int    `json:"id,omitempty"`
+		CreatedAt  Time   `json:"created_at,omitempty"`
+		UpdatedAt  Time   `json:"updated_at,omitempty"`
+		DeviceID   Int    `json:"device_id,omitempty"`
+		PortID     Int    `json:"port_id,omitempty"`
+		Index      String `json:"index,omitempty"`
+		Type       String `json:"type,omitempty"`
+		Vendor     String `json:"vendor,omitempty"`
+		OUI        String `json:"oui,omitempty"`
+		Model      String `json:"model,omitempty"`
+		Revision   String `json:"revision,omitempty"`
+		Serial     String `json:"serial,omitempty"`
+		Date       String `json:"date,omitempty"`
+		DDM        Bool   `json:"ddm,omitempty"`
+		Encoding   String `json:"encoding,omitempty"`
+		Cable      String `json:"cable,omitempty"`
+		Distance   Int    `json:"distance,omitempty"`
+		Wavelength Int    `json:"wavelength,omitempty"`
+		Connector  String `json:"connector,omitempty"`
+		Channels   Int    `json:"channels,omitempty"`
 	}
 
 	PortDescriptionUpdateRequest struct {
@@ -108,6 +140,13 @@ type (
 		Ports []Port `json:"ports"`
 	}
 
+	// PortsWithFieldsResponse is like PortsResponse, but records the fields returned for
+	// each port, which depend on the columns requested.
+	PortsWithFieldsResponse struct {
+		BaseResponse
+		Ports []Sparse[Port] `json:"ports"`
+	}
+
 	PortResponse struct {
 		BaseResponse
 		Port []Port `json:"port"`
@@ -125,10 +164,11 @@ type (
 
 	PortDescriptionResponse struct {
 		BaseResponse
-		PortDescription string `json:"port_description,omitempty"`
+		PortDescription String `json:"port_description,omitempty"`
 	}
 
 	PortsQueryParams struct {
+		// Columns limits the returned fields, preferably built with NewColumns[Port].
 		Columns string `form:"columns,omitempty"`
 		Filter  string `form:"filter,omitempty"`
 	}
@@ -0,0 +1,274 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeLayout is the layout of the timestamps returned by LibreNMS, in the timezone of the
+// server.
+const TimeLayout = "2006-01-02 15:04:05"
+
+type (
+	// Int represents an int value, used for JSON unmarshalling. The API returns some fields
+	// as numbers, strings, booleans or null depending on the endpoint and version; strings
+	// are parsed, booleans are 0/1 and null or an empty string is 0.
+	Int int
+
+	// Int64 represents an int64 value, decoded like Int. It is used for counters and Unix
+	// timestamps.
+	Int64 int64
+
+	// String represents a string value, used for JSON unmarshalling. The API returns some
+	// fields as strings or numbers; numbers and booleans keep their JSON text and null is
+	// an empty string.
+	String string
+
+	// Time represents a timestamp, used for JSON marshaling. The API returns timestamps
+	// in the TimeLayout or RFC 3339 formats, or as Unix timestamps. LibreNMS doesn't send
+	// the timezone of TimeLayout timestamps, they are parsed as UTC. Null, empty and zero
+	// dates ("0000-00-00 00:00:00") are the zero Time, which is marshaled as null.
+	Time struct {
+		time.Time
+	}
+
+	// Null represents a value that may be null, like sql.Null. V is decoded with the
+	// unmarshaler of T. An empty string is null too, except for NullString.
+	Null[T any] struct {
+		V     T
+		Valid bool
+	}
+
+	// NullInt is an Int that may be null.
+	NullInt = Null[Int]
+	// NullInt64 is an Int64 that may be null.
+	NullInt64 = Null[Int64]
+	// NullFloat64 is a Float64 that may be null.
+	NullFloat64 = Null[Float64]
+	// NullBool is a Bool that may be null.
+	NullBool = Null[Bool]
+	// NullString is a String that may be null.
+	NullString = Null[String]
+	// NullTime is a Time that may be null.
+	NullTime = Null[Time]
+)
+
+// timeLayouts are the layouts accepted by Time, tried in order.
+var timeLayouts = []string{
+	TimeLayout,
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// UnmarshalJSON implements the JSON unmarshalling for the Int type.
+func (i *Int) UnmarshalJSON(data []byte) error {
+	value, err := parseInt(data, strconv.IntSize)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal Int: %w", err)
+	}
+	*i = Int(value)
+	return nil
+}
+
+// UnmarshalJSON implements the JSON unmarshalling for the Int64 type.
+func (i *Int64) UnmarshalJSON(data []byte) error {
+	value, err := parseInt(data, 64)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal Int64: %w", err)
+	}
+	*i = Int64(value)
+	return nil
+}
+
+// UnmarshalJSON implements the JSON unmarshalling for the String type.
+func (s *String) UnmarshalJSON(data []byte) error {
+	value, _, err := parseScalar(data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal String: %w", err)
+	}
+	*s = String(value)
+	return nil
+}
+
+// String returns the string value.
+func (s String) String() string {
+	return string(s)
+}
+
+// NewTime returns t as a Time.
+func NewTime(t time.Time) Time {
+	return Time{Time: t}
+}
+
+// MarshalJSON implements the JSON marshaling for the Time type. UTC times use the TimeLayout
+// of the API, other times RFC 3339.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	layout := time.RFC3339Nano
+	if t.Location() == time.UTC {
+		layout = TimeLayout
+		if t.Nanosecond() != 0 {
+			layout = "2006-01-02 15:04:05.999999999"
+		}
+	}
+	return json.Marshal(t.Format(layout))
+}
+
+// UnmarshalJSON implements the JSON unmarshalling for the Time type.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	value, quoted, err := parseScalar(data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal Time: %w", err)
+	}
+	parsed, err := parseTime(value, quoted)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal Time: %w", err)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// NewNull returns a valid Null holding v.
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// MarshalJSON implements the JSON marshaling for the Null type.
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(&n.V)
+}
+
+// UnmarshalJSON implements the JSON unmarshalling for the Null type.
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	*n = Null[T]{}
+	trimmed := bytes.TrimSpace(data)
+	if string(trimmed) == "null" {
+		return nil
+	}
+	if _, isString := any(&n.V).(*String); string(trimmed) == `""` && !isString {
+		return nil
+	}
+	if err := json.Unmarshal(trimmed, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// parseScalar returns the value of a JSON scalar: the unquoted string, or the JSON text of
+// numbers and booleans. Null is an empty string.
+func parseScalar(data []byte) (value string, quoted bool, err error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+		return "", false, fmt.Errorf("empty value")
+	case string(trimmed) == "null":
+		return "", false, nil
+	case trimmed[0] == '"':
+		if err := json.Unmarshal(trimmed, &value); err != nil {
+			return "", false, err
+		}
+		return value, true, nil
+	case trimmed[0] == '{' || trimmed[0] == '[':
+		return "", false, fmt.Errorf("unexpected JSON value %.20s", trimmed)
+	}
+	if !json.Valid(trimmed) {
+		return "", false, fmt.Errorf("invalid JSON value %.20s", trimmed)
+	}
+	return string(trimmed), false, nil
+}
+
+// parseInt parses a lenient JSON integer of the given bit size. Floats with an integral
+// value, e.g. 1e3 or "12.0", are accepted.
+func parseInt(data []byte, bitSize int) (int64, error) {
+	value, _, err := parseScalar(data)
+	if err != nil {
+		return 0, err
+	}
+	value = strings.TrimSpace(value)
+	switch value {
+	case "", "false":
+		return 0, nil
+	case "true":
+		return 1, nil
+	}
+	if i, err := strconv.ParseInt(value, 10, bitSize); err == nil {
+		return i, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	limit := math.Ldexp(1, bitSize-1)
+	if err != nil || f != math.Trunc(f) || f < -limit || f >= limit {
+		return 0, fmt.Errorf("invalid integer %q", value)
+	}
+	return int64(f), nil
+}
+
+// parseFloat parses a lenient JSON float.
+func parseFloat(data []byte) (float64, error) {
+	value, _, err := parseScalar(data)
+	if err != nil {
+		return 0, err
+	}
+	value = strings.TrimSpace(value)
+	switch value {
+	case "", "false":
+		return 0, nil
+	case "true":
+		return 1, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// parseBool parses a lenient JSON boolean: true/false, numbers (non-zero is true) or their
+// string forms.
+func parseBool(data []byte) (bool, error) {
+	value, _, err := parseScalar(data)
+	if err != nil {
+		return false, err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false, nil
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %q", value)
+	}
+	return f != 0, nil
+}
+
+// parseTime parses a timestamp. Unquoted numbers and numeric strings are Unix timestamps.
+func parseTime(value string, quoted bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" || strings.HasPrefix(value, "0000-00-00") {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		whole, fraction := math.Modf(seconds)
+		return time.Unix(int64(whole), int64(fraction*1e9)).UTC(), nil
+	}
+	if !quoted {
+		return time.Time{}, fmt.Errorf("invalid time %s", value)
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
@@ -3,19 +3,19 @@ package types
 type (
 	// Service represents a service in LibreNMS.
 	Service struct {
-		ID          int    `json:"service_id,omitempty"`
-		Changed     int64  `json:"service_changed,omitempty"`
-		Description string `json:"service_desc,omitempty"`
-		DeviceID    int    `json:"device_id,omitempty"`
-		DS          string `json:"service_ds,omitempty"`
+		ID          Int    `json:"service_id,omitempty"`
+		Changed     Int64  `json:"service_changed,omitempty"`
+		Description String `json:"service_desc,omitempty"`
+		DeviceID    Int    `json:"device_id,omitempty"`
+		DS          String `json:"service_ds,omitempty"`
 		Ignore      Bool   `json:"service_ignore,omitempty"`
-		IP          string `json:"service_ip,omitempty"`
-		Message     string `json:"service_message,omitempty"`
-		Name        string `json:"service_name,omitempty"`
-		Param       string `json:"service_param,omitempty"`
-		Status      int    `json:"service_status,omitempty"` // assuming this follows Nagios conventions, 0=ok, 1=warning, 2=critical, 3=unknown
-		TemplateID  int    `json:"service_template_id,omitempty"`
-		Type        string `json:"service_type,omitempty"`
+		IP          String `json:"service_ip,omitempty"`
+		Message     String `json:"service_message,omitempty"`
+		Name        String `json:"service_name,omitempty"`
+		Param       String `json:"service_param,omitempty"`
+		Status      Int    `json:"service_status,omitempty"` // assuming this follows Nagios conventions, 0=ok, 1=warning, 2=critical, 3=unknown
+		TemplateID  Int    `json:"service_template_id,omitempty"`
+		Type        String `json:"service_type,omitempty"`
 	}
 
 	// ServiceCreateRequest represents the request payload for creating a service.
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Sparse is a value of the API type T decoded together with the JSON fields that were
+// present. The zero values of T are ambiguous: a device decoded with Ignore false may be
+// ignored in LibreNMS when the ignore column wasn't requested. Sparse tells both cases
+// apart, and the updates built from it only send the fields that really changed.
+//
+// T must be a struct type. Encoding and decoding other types is an error, and they have no
+// fields or changes.
+type Sparse[T any] struct {
+	Value T
+	// fields are the JSON fields that were present, sorted.
+	fields FieldSet
+}
+
+// NewSparse returns value with the given fields present. Without fields, every field of T
+// is present, for values that were fully loaded.
+func NewSparse[T any](value T, fields ...string) Sparse[T] {
+	if len(fields) == 0 {
+		fields = fieldNames(reflect.TypeOf((*T)(nil)).Elem())
+	}
+	set := append(FieldSet{}, fields...)
+	sort.Strings(set)
+	return Sparse[T]{Value: value, fields: set}
+}
+
+// Has reports whether a field was present.
+func (s Sparse[T]) Has(name string) bool {
+	return s.fields.Has(name)
+}
+
+// Fields returns the fields that were present, sorted.
+func (s Sparse[T]) Fields() []string {
+	return append([]string(nil), s.fields...)
+}
+
+// Changes returns the fields of updated that differ from the value, keyed by JSON field
+// name. Fields that weren't present are only included when they are set in updated, since
+// their current value is unknown.
+func (s Sparse[T]) Changes(updated T) map[string]any {
+	current := reflect.ValueOf(s.Value)
+	next := reflect.ValueOf(updated)
+	changes := make(map[string]any)
+	fields, _ := structFields(reflect.TypeOf((*T)(nil)).Elem())
+	for _, f := range fields {
+		value := f.value(next)
+		if s.Has(f.name) {
+			if sameJSON(f.value(current).Interface(), value.Interface()) {
+				continue
+			}
+		} else if value.IsZero() {
+			continue
+		}
+		changes[f.name] = value.Interface()
+	}
+	return changes
+}
+
+// UnmarshalJSON implements the JSON unmarshalling for the Sparse type, recording the fields
+// of the JSON object.
+func (s *Sparse[T]) UnmarshalJSON(data []byte) error {
+	var value T
+	if _, err := structFields(reflect.TypeOf((*T)(nil)).Elem()); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	fields, err := objectFields(data)
+	if err != nil {
+		return err
+	}
+	s.Value, s.fields = value, fields
+	return nil
+}
+
+// MarshalJSON implements the JSON marshaling for the Sparse type. Only the present fields
+// are encoded, including their zero values.
+func (s Sparse[T]) MarshalJSON() ([]byte, error) {
+	v := reflect.ValueOf(s.Value)
+	fields, err := structFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, f := range fields {
+		field, err := v.FieldByIndexErr(f.index)
+		if err != nil || !s.Has(f.name) {
+			// Like encoding/json, fields of nil embedded pointers are omitted.
+			continue
+		}
+		value, err := json.Marshal(field.Interface())
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// objectFields returns the sorted keys of a JSON object. null has no keys.
+func objectFields(data []byte) (FieldSet, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return nextObjectFields(dec)
+}
+
+// fieldNames returns the JSON field names of a struct type, none for other types.
+func fieldNames(t reflect.Type) []string {
+	fields, _ := structFields(t)
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+// value returns the field of the struct v, or its zero value when an embedded pointer on
+// the way is nil.
+func (f jsonField) value(v reflect.Value) reflect.Value {
+	field, err := v.FieldByIndexErr(f.index)
+	if err != nil {
+		return reflect.Zero(v.Type().FieldByIndex(f.index).Type)
+	}
+	return field
+}
+
+// sameJSON reports whether a and b have the same JSON encoding.
+func sameJSON(a, b any) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
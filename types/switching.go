@@ -2,70 +2,70 @@ package types
 
 type (
 	VLAN struct {
-		VLANID     string `json:"vlan_id,omitempty"`
-		DeviceID   string `json:"device_id,omitempty"`
-		VLANVLAN   string `json:"vlan_vlan,omitempty"`
-		VLANDomain string `json:"vlan_domain,omitempty"`
-		VLANName   string `json:"vlan_name,omitempty"`
-		VLANType   string `json:"vlan_type,omitempty"`
-		VLANState  int    `json:"vlan_state,omitempty"`
+		VLANID     Int    `json:"vlan_id,omitempty"`
+		DeviceID   Int    `json:"device_id,omitempty"`
+		VLANVLAN   Int    `json:"vlan_vlan,omitempty"`
+		VLANDomain Int    `json:"vlan_domain,omitempty"`
+		VLANName   String `json:"vlan_name,omitempty"`
+		VLANType   String `json:"vlan_type,omitempty"`
+		VLANState  Int    `json:"vlan_state,omitempty"`
 	}
 
 	Link struct {
-		ID             int    `json:"id,omitempty"`
-		LocalPortID    int    `json:"local_port_id,omitempty"`
-		LocalDeviceID  int    `json:"local_device_id,omitempty"`
-		RemotePortID   int    `json:"remote_port_id,omitempty"`
-		Active         int    `json:"active,omitempty"`
-		Protocol       string `json:"protocol,omitempty"`
-		RemoteHostname string `json:"remote_hostname,omitempty"`
-		RemoteDeviceID int    `json:"remote_device_id,omitempty"`
-		RemotePort     string `json:"remote_port,omitempty"`
-		RemotePlatform string `json:"remote_platform,omitempty"`
-		RemoteVersion  string `json:"remote_version,omitempty"`
+		ID             Int    `json:"id,omitempty"`
+		LocalPortID    Int    `json:"local_port_id,omitempty"`
+		LocalDeviceID  Int    `json:"local_device_id,omitempty"`
+		RemotePortID   Int    `json:"remote_port_id,omitempty"`
+		Active         Int    `json:"active,omitempty"`
+		Protocol       String `json:"protocol,omitempty"`
+		RemoteHostname String `json:"remote_hostname,omitempty"`
+		RemoteDeviceID Int    `json:"remote_device_id,omitempty"`
+		RemotePort     String `json:"remote_port,omitempty"`
+		RemotePlatform String `json:"remote_platform,omitempty"`
+		RemoteVersion  String `json:"remote_version,omitempty"`
 	}
 
 	PortFDB struct {
-		PortsFDBID int    `json:"ports_fdb_id,omitempty"`
-		PortID     int    `json:"port_id,omitempty"`
-		MACAddress string `json:"mac_address,omitempty"`
-		VLANID     int    `json:"vlan_id,omitempty"`
-		DeviceID   int    `json:"device_id,omitempty"`
-		CreatedAt  string `json:"created_at,omitempty"`
-		UpdatedAt  string `json:"updated_at,omitempty"`
+		PortsFDBID Int  `json:"ports_fdb_id,omitempty"`
+		PortID     Int  `json:"port_id,omitempty"`
+		MACAddress MAC  `json:"mac_address,omitempty"`
+		VLANID     Int  `json:"vlan_id,omitempty"`
+		DeviceID   Int  `json:"device_id,omitempty"`
+		CreatedAt  Time `json:"created_at,omitempty"`
+		UpdatedAt  Time `json:"updated_at,omitempty"`
 	}
 
 	PortFDBDetail struct {
-		Hostname  string `json:"hostname,omitempty"`
-		SysName   string `json:"sysName,omitempty"`
-		IfName    string `json:"ifName,omitempty"`
-		IfAlias   string `json:"ifAlias,omitempty"`
-		IfDescr   string `json:"ifDescr,omitempty"`
-		LastSeen  string `json:"last_seen,omitempty"`
-		UpdatedAt string `json:"updated_at,omitempty"`
+		Hostname  String `json:"hostname,omitempty"`
+		SysName   String `json:"sysName,omitempty"`
+		IfName    String `json:"ifName,omitempty"`
+		IfAlias   String `json:"ifAlias,omitempty"`
+		IfDescr   String `json:"ifDescr,omitempty"`
+		LastSeen  Time   `json:"last_seen,omitempty"`
+		UpdatedAt Time   `json:"updated_at,omitempty"`
 	}
 
 	PortNAC struct {
-		PortsNACID  string `json:"ports_nac_id,omitempty"`
-		AuthID      string `json:"auth_id,omitempty"`
-		DeviceID    int    `json:"device_id,omitempty"`
-		PortID      int    `json:"port_id,omitempty"`
-		Domain      string `json:"domain,omitempty"`
-		Username    string `json:"username,omitempty"`
-		MACAddress  string `json:"mac_address,omitempty"`
-		IPAddress   string `json:"ip_address,omitempty"`
-		HostMode    string `json:"host_mode,omitempty"`
-		AuthzStatus string `json:"authz_status,omitempty"`
-		AuthzBy     string `json:"authz_by,omitempty"`
-		AuthcStatus string `json:"authc_status,omitempty"`
-		Method      string `json:"method,omitempty"`
-		Timeout     string `json:"timeout,omitempty"`
-		TimeLeft    string `json:"time_left,omitempty"`
-		VLAN        int    `json:"vlan,omitempty"`
-		TimeElapsed string `json:"time_elapsed,omitempty"`
-		CreatedAt   string `json:"created_at,omitempty"`
-		UpdatedAt   string `json:"updated_at,omitempty"`
-		Historical  int    `json:"historical,omitempty"`
+		PortsNACID  Int    `json:"ports_nac_id,omitempty"`
+		AuthID      String `json:"auth_id,omitempty"`
+		DeviceID    Int    `json:"device_id,omitempty"`
+		PortID      Int    `json:"port_id,omitempty"`
+		Domain      String `json:"domain,omitempty"`
+		Username    String `json:"username,omitempty"`
+		MACAddress  MAC    `json:"mac_address,omitempty"`
+		IPAddress   String `json:"ip_address,omitempty"`
+		HostMode    String `json:"host_mode,omitempty"`
+		AuthzStatus String `json:"authz_status,omitempty"`
+		AuthzBy     String `json:"authz_by,omitempty"`
+		AuthcStatus String `json:"authc_status,omitempty"`
+		Method      String `json:"method,omitempty"`
+		Timeout     String `json:"timeout,omitempty"`
+		TimeLeft    String `json:"time_left,omitempty"`
+		VLAN        Int    `json:"vlan,omitempty"`
+		TimeElapsed String `json:"time_elapsed,omitempty"`
+		CreatedAt   Time   `json:"created_at,omitempty"`
+		UpdatedAt   Time   `json:"updated_at,omitempty"`
+		Historical  Int    `json:"historical,omitempty"`
 	}
 
 	VLANsResponse struct {
@@ -78,6 +78,13 @@ type (
 		Links []Link `json:"links"`
 	}
 
+	// LinksWithFieldsResponse is like LinksResponse, but records the fields returned for
+	// each link, which depend on the columns requested.
+	LinksWithFieldsResponse struct {
+		BaseResponse
+		Links []Sparse[Link] `json:"links"`
+	}
+
 	PortFDBResponse struct {
 		BaseResponse
 		PortsFDB []PortFDB `json:"ports_fdb"`
@@ -85,8 +92,8 @@ type (
 
 	PortFDBDetailResponse struct {
 		BaseResponse
-		MAC      string          `json:"mac,omitempty"`
-		MACOUI   string          `json:"mac_oui,omitempty"`
+		MAC      MAC             `json:"mac,omitempty"`
+		MACOUI   String          `json:"mac_oui,omitempty"`
 		PortsFDB []PortFDBDetail `json:"ports_fdb"`
 	}
 
@@ -96,6 +103,8 @@ type (
 	}
 
 	SwitchingQueryParams struct {
+		// Columns limits the returned fields, preferably built with NewColumns of the
+		// listed type.
 		Columns string `form:"columns,omitempty"`
 		Filter  string `form:"filter,omitempty"`
 	}
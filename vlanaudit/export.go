@@ -0,0 +1,47 @@
+package vlanaudit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes the VLAN matrix as CSV, one row per VLAN and device. The access ports are
+// separated by spaces.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"vlan", "device_id", "hostname", "name", "configured", "access_ports"})
+	for _, vlan := range r.VLANs {
+		for _, m := range vlan.Devices {
+			_ = cw.Write([]string{
+				strconv.Itoa(vlan.ID), strconv.Itoa(m.DeviceID), m.Hostname, m.Name,
+				strconv.FormatBool(m.Configured), strings.Join(m.AccessPorts, " "),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteIssuesCSV writes the issues as CSV.
+func (r *Report) WriteIssuesCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"kind", "vlan", "hostname", "port", "remote_hostname", "remote_port", "message"})
+	for _, issue := range r.Issues {
+		_ = cw.Write([]string{
+			string(issue.Kind), strconv.Itoa(issue.VLAN), issue.Hostname, issue.Port,
+			issue.RemoteHostname, issue.RemotePort, issue.Message,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,59 @@
+package vlanaudit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/vlanaudit"
+	"github.com/stretchr/testify/require"
+)
+
+var report = &vlanaudit.Report{
+	VLANs: []vlanaudit.VLAN{
+		{ID: 10, Names: []string{"users"}, Devices: []vlanaudit.Member{
+			{DeviceID: 1, Hostname: "core", Name: "users", Configured: true, AccessPorts: []string{}},
+			{DeviceID: 2, Hostname: "sw1", Name: "users", Configured: true, AccessPorts: []string{"Gi1", "Gi3"}},
+		}},
+		{ID: 40, Names: []string{}, Devices: []vlanaudit.Member{
+			{DeviceID: 3, Hostname: "sw2", AccessPorts: []string{"Gi2"}},
+		}},
+	},
+	Issues: []vlanaudit.Issue{
+		{Kind: vlanaudit.IssueMissingOnTrunk, VLAN: 40, Hostname: "sw2", Port: "Te1", RemoteHostname: "core", RemotePort: "Te1/2",
+			Message: "VLAN 40 is used on access ports of sw2 but core, at the other end of trunk Te1, doesn't have it"},
+	},
+}
+
+func TestReport_WriteCSV(t *testing.T) {
+	r := require.New(t)
+
+	var buf bytes.Buffer
+	r.NoError(report.WriteCSV(&buf), "WriteCSV returned an error")
+	r.Equal(`vlan,device_id,hostname,name,configured,access_ports
+10,1,core,users,true,
+10,2,sw1,users,true,Gi1 Gi3
+40,3,sw2,,false,Gi2
+`, buf.String(), "Unexpected matrix")
+
+	buf.Reset()
+	r.NoError(report.WriteIssuesCSV(&buf), "WriteIssuesCSV returned an error")
+	r.Equal(`kind,vlan,hostname,port,remote_hostname,remote_port,message
+missing-on-trunk,40,sw2,Te1,core,Te1/2,"VLAN 40 is used on access ports of sw2 but core, at the other end of trunk Te1, doesn't have it"
+`, buf.String(), "Unexpected issues")
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	r := require.New(t)
+
+	var buf bytes.Buffer
+	r.NoError(report.WriteJSON(&buf), "WriteJSON returned an error")
+	var decoded vlanaudit.Report
+	r.NoError(json.Unmarshal(buf.Bytes(), &decoded), "Failed to decode the report")
+	r.Equal(report, &decoded, "Expected the report to round-trip")
+
+	var fields map[string][]map[string]any
+	r.NoError(json.Unmarshal(buf.Bytes(), &fields), "Failed to decode the report")
+	r.Equal("missing-on-trunk", fields["issues"][0]["kind"], "Expected the issue kind")
+	r.Equal([]any{"Gi1", "Gi3"}, fields["vlans"][0]["devices"].([]any)[1].(map[string]any)["access_ports"], "Expected the access ports")
+}
@@ -0,0 +1,336 @@
+// Package vlanaudit builds the matrix of the VLANs configured across the devices known to
+// LibreNMS and audits their consistency.
+//
+// The matrix lists, for each VLAN ID, the devices that have it in their VLAN table or on
+// access ports, and the names they give it. The audit flags VLANs named differently by
+// different devices, VLANs used on access ports of a switch but missing on the device at
+// the other end of one of its trunks, and VLANs no port uses at all. LibreNMS doesn't
+// expose the allowed VLANs of trunks through the API, so a trunk is considered to carry a
+// VLAN when the device at its other end has the VLAN. Devices without a VLAN table, like
+// routers and servers, are never expected to have one.
+package vlanaudit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/javen-yan/librenms-go"
+	"github.com/javen-yan/librenms-go/types"
+)
+
+var (
+	// portColumns are the port columns used to find the VLANs of access ports and trunks.
+	portColumns = types.MustColumns[types.Port]("ifName", "ifVlan", "ifTrunk", "deleted").String()
+	// linkColumns are the link columns used to find the other end of trunks.
+	linkColumns = types.MustColumns[types.Link]("local_port_id", "remote_device_id", "remote_port_id", "remote_port").String()
+)
+
+// DefaultIgnored are the VLANs that aren't flagged by default: the default VLAN and the
+// FDDI and Token Ring VLANs that Cisco switches reserve.
+var DefaultIgnored = []int{1, 1002, 1003, 1004, 1005}
+
+// IssueKind is a kind of inconsistency.
+type IssueKind string
+
+// Kinds of issues.
+const (
+	// IssueNameMismatch is a VLAN whose name differs between devices.
+	IssueNameMismatch IssueKind = "name-mismatch"
+	// IssueMissingOnTrunk is a VLAN used on access ports of a device but missing on the
+	// device at the other end of one of its trunks.
+	IssueMissingOnTrunk IssueKind = "missing-on-trunk"
+	// IssueOrphan is a VLAN configured on devices but used by no port.
+	IssueOrphan IssueKind = "orphan"
+)
+
+type (
+	// Options configures Audit.
+	Options struct {
+		// Ignore are the VLANs that are never flagged. nil ignores DefaultIgnored, an
+		// empty slice none.
+		Ignore []int
+	}
+
+	// Input is the data audited by Analyze.
+	Input struct {
+		Devices []types.Device
+		VLANs   []types.VLAN
+		// Ports provide the VLANs of access ports and the trunks. Only the IDs, ifName,
+		// ifVlan, ifTrunk and deleted are used.
+		Ports []types.Port
+		// Links find the other end of trunks.
+		Links []types.Link
+		// Ignore are the VLANs that are never flagged, see Options.Ignore.
+		Ignore []int
+	}
+
+	// Report is the result of an audit.
+	Report struct {
+		// VLANs is the matrix of the VLANs, ordered by ID.
+		VLANs  []VLAN  `json:"vlans"`
+		Issues []Issue `json:"issues"`
+	}
+
+	// VLAN is a VLAN ID and the devices that have it.
+	VLAN struct {
+		ID int `json:"id"`
+		// Names are the distinct names of the VLAN, sorted.
+		Names []string `json:"names"`
+		// Devices are ordered by hostname.
+		Devices []Member `json:"devices"`
+	}
+
+	// Member is a device that has a VLAN.
+	Member struct {
+		DeviceID int    `json:"device_id"`
+		Hostname string `json:"hostname"`
+		Name     string `json:"name,omitempty"`
+		// Configured reports whether the VLAN is in the VLAN table of the device, rather
+		// than only on its ports.
+		Configured bool `json:"configured"`
+		// AccessPorts are the names of the access ports in the VLAN.
+		AccessPorts []string `json:"access_ports"`
+	}
+
+	// Issue is an inconsistency found by an audit.
+	Issue struct {
+		Kind IssueKind `json:"kind"`
+		VLAN int       `json:"vlan"`
+		// Hostname and Port are the device and the trunk of IssueMissingOnTrunk, whose
+		// other end is RemoteHostname and RemotePort.
+		Hostname       string `json:"hostname,omitempty"`
+		Port           string `json:"port,omitempty"`
+		RemoteHostname string `json:"remote_hostname,omitempty"`
+		RemotePort     string `json:"remote_port,omitempty"`
+		Message        string `json:"message"`
+	}
+)
+
+// trunk is the other end of a trunk port.
+type trunk struct {
+	port       types.Port
+	remoteID   int
+	remotePort string
+}
+
+// Audit loads the VLANs, ports and links of the LibreNMS instance behind client and returns
+// the report of Analyze. The ports and links are streamed with only the columns the audit
+// needs.
+func Audit(client *librenms.Client, opts *Options) (*Report, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	in := &Input{Ignore: opts.Ignore}
+
+	devices, err := client.Device.List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	in.Devices = devices.Devices
+	err = client.Switching.StreamAllVLANs(nil, func(vlan types.VLAN) error {
+		in.VLANs = append(in.VLANs, vlan)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VLANs: %w", err)
+	}
+	err = client.Port.StreamAllPorts(&types.PortsQueryParams{Columns: portColumns}, func(port types.Port) error {
+		in.Ports = append(in.Ports, port)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ports: %w", err)
+	}
+	err = client.Switching.StreamAllLinks(&types.SwitchingQueryParams{Columns: linkColumns}, func(link types.Link) error {
+		in.Links = append(in.Links, link)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links: %w", err)
+	}
+	return Analyze(in), nil
+}
+
+// Analyze builds the VLAN matrix of in and audits it.
+func Analyze(in *Input) *Report {
+	ignore := in.Ignore
+	if ignore == nil {
+		ignore = DefaultIgnored
+	}
+	ignored := make(map[int]bool, len(ignore))
+	for _, id := range ignore {
+		ignored[id] = true
+	}
+
+	hostnames := make(map[int]string, len(in.Devices))
+	for _, device := range in.Devices {
+		hostnames[int(device.DeviceID)] = string(device.Hostname)
+	}
+	hostname := func(deviceID int) string {
+		if name := hostnames[deviceID]; name != "" {
+			return name
+		}
+		return fmt.Sprintf("device %d", deviceID)
+	}
+
+	// members maps VLAN IDs to their devices, and configured the devices to the VLANs of
+	// their VLAN table.
+	members := make(map[int]map[int]*Member)
+	configured := make(map[int]map[int]bool)
+	member := func(id, deviceID int) *Member {
+		if members[id] == nil {
+			members[id] = make(map[int]*Member)
+		}
+		m := members[id][deviceID]
+		if m == nil {
+			m = &Member{DeviceID: deviceID, Hostname: hostname(deviceID), AccessPorts: []string{}}
+			members[id][deviceID] = m
+		}
+		return m
+	}
+	for _, vlan := range in.VLANs {
+		id, deviceID := int(vlan.VLANVLAN), int(vlan.DeviceID)
+		m := member(id, deviceID)
+		m.Configured = true
+		if name := strings.TrimSpace(string(vlan.VLANName)); name != "" {
+			m.Name = name
+		}
+		if configured[deviceID] == nil {
+			configured[deviceID] = make(map[int]bool)
+		}
+		configured[deviceID][id] = true
+	}
+
+	// used are the VLANs of ports and trunks are the trunk ports by ID.
+	used := make(map[int]bool)
+	trunks := make(map[int]types.Port)
+	for _, port := range in.Ports {
+		if port.Deleted != 0 {
+			continue
+		}
+		isTrunk := port.IfTrunk.Valid && port.IfTrunk.V != ""
+		if isTrunk {
+			trunks[int(port.PortID)] = port
+		}
+		id, err := strconv.Atoi(strings.TrimSpace(string(port.IfVlan)))
+		if err != nil || id <= 0 {
+			continue
+		}
+		used[id] = true
+		if isTrunk {
+			continue
+		}
+		m := member(id, int(port.DeviceID))
+		m.AccessPorts = append(m.AccessPorts, string(port.IfName))
+	}
+
+	// uplinks are the trunks of each device to other devices, one per trunk and remote
+	// device even when several protocols report the link.
+	uplinks := make(map[int][]trunk)
+	seen := make(map[[2]int]bool)
+	for _, link := range in.Links {
+		port, ok := trunks[int(link.LocalPortID)]
+		remoteID := int(link.RemoteDeviceID)
+		key := [2]int{int(port.PortID), remoteID}
+		if !ok || remoteID == 0 || remoteID == int(port.DeviceID) || seen[key] {
+			continue
+		}
+		seen[key] = true
+		remotePort := string(link.RemotePort)
+		if remote, ok := trunks[int(link.RemotePortID)]; ok && remote.IfName != "" {
+			remotePort = string(remote.IfName)
+		}
+		uplinks[int(port.DeviceID)] = append(uplinks[int(port.DeviceID)], trunk{port: port, remoteID: remoteID, remotePort: remotePort})
+	}
+
+	report := &Report{VLANs: make([]VLAN, 0, len(members)), Issues: []Issue{}}
+	ids := make([]int, 0, len(members))
+	for id := range members {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		vlan := VLAN{ID: id, Names: []string{}, Devices: make([]Member, 0, len(members[id]))}
+		names := make(map[string][]string)
+		for _, m := range members[id] {
+			sort.Strings(m.AccessPorts)
+			vlan.Devices = append(vlan.Devices, *m)
+			if m.Name != "" {
+				names[m.Name] = append(names[m.Name], m.Hostname)
+			}
+		}
+		sort.Slice(vlan.Devices, func(i, j int) bool {
+			a, b := vlan.Devices[i], vlan.Devices[j]
+			if a.Hostname != b.Hostname {
+				return a.Hostname < b.Hostname
+			}
+			return a.DeviceID < b.DeviceID
+		})
+		for name := range names {
+			vlan.Names = append(vlan.Names, name)
+		}
+		sort.Strings(vlan.Names)
+		report.VLANs = append(report.VLANs, vlan)
+		if ignored[id] {
+			continue
+		}
+
+		if len(vlan.Names) > 1 {
+			parts := make([]string, 0, len(vlan.Names))
+			for _, name := range vlan.Names {
+				sort.Strings(names[name])
+				parts = append(parts, fmt.Sprintf("%q on %s", name, strings.Join(names[name], ", ")))
+			}
+			report.Issues = append(report.Issues, Issue{
+				Kind: IssueNameMismatch, VLAN: id,
+				Message: fmt.Sprintf("VLAN %d is named %s", id, strings.Join(parts, " and ")),
+			})
+		}
+
+		for _, m := range vlan.Devices {
+			if len(m.AccessPorts) == 0 {
+				continue
+			}
+			for _, uplink := range uplinks[m.DeviceID] {
+				if configured[uplink.remoteID] == nil || configured[uplink.remoteID][id] {
+					continue
+				}
+				report.Issues = append(report.Issues, Issue{
+					Kind: IssueMissingOnTrunk, VLAN: id,
+					Hostname: m.Hostname, Port: string(uplink.port.IfName),
+					RemoteHostname: hostname(uplink.remoteID), RemotePort: uplink.remotePort,
+					Message: fmt.Sprintf("VLAN %d is used on access ports of %s but %s, at the other end of trunk %s, doesn't have it",
+						id, m.Hostname, hostname(uplink.remoteID), uplink.port.IfName),
+				})
+			}
+		}
+
+		if !used[id] {
+			hosts := make([]string, 0, len(vlan.Devices))
+			for _, m := range vlan.Devices {
+				hosts = append(hosts, m.Hostname)
+			}
+			report.Issues = append(report.Issues, Issue{
+				Kind: IssueOrphan, VLAN: id,
+				Message: fmt.Sprintf("VLAN %d is configured on %s but no port uses it", id, strings.Join(hosts, ", ")),
+			})
+		}
+	}
+	return report
+}
+
+// OK reports whether no issues were found.
+func (r *Report) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// VLAN returns the VLAN of the matrix with the given ID, or nil.
+func (r *Report) VLAN(id int) *VLAN {
+	i := sort.Search(len(r.VLANs), func(i int) bool { return r.VLANs[i].ID >= id })
+	if i < len(r.VLANs) && r.VLANs[i].ID == id {
+		return &r.VLANs[i]
+	}
+	return nil
+}
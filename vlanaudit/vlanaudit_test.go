@@ -0,0 +1,129 @@
+package vlanaudit_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/javen-yan/librenms-go/librenmstest"
+	"github.com/javen-yan/librenms-go/types"
+	"github.com/javen-yan/librenms-go/vlanaudit"
+	"github.com/stretchr/testify/require"
+)
+
+func trunk(id, deviceID int, name string, native string) types.Port {
+	return types.Port{PortID: types.Int(id), DeviceID: types.Int(deviceID), IfName: types.String(name), IfVlan: types.String(native),
+		IfTrunk: types.NullString{V: "dot1Q", Valid: true}}
+}
+
+func access(id, deviceID int, name string, vlan string) types.Port {
+	return types.Port{PortID: types.Int(id), DeviceID: types.Int(deviceID), IfName: types.String(name), IfVlan: types.String(vlan)}
+}
+
+// newServer returns a server with a core switch, two access switches whose trunks go to the
+// core and a router without VLANs.
+func newServer(t *testing.T) *librenmstest.Server {
+	srv := librenmstest.New(t)
+	for _, device := range []types.Device{
+		{DeviceID: 1, Hostname: "core"},
+		{DeviceID: 2, Hostname: "sw1"},
+		{DeviceID: 3, Hostname: "sw2"},
+		{DeviceID: 4, Hostname: "rtr"},
+	} {
+		srv.AddDevice(device)
+	}
+	for _, vlan := range []types.VLAN{
+		{DeviceID: 1, VLANVLAN: 1, VLANName: "default"},
+		{DeviceID: 1, VLANVLAN: 10, VLANName: "users"},
+		{DeviceID: 1, VLANVLAN: 20, VLANName: "voice"},
+		{DeviceID: 1, VLANVLAN: 30, VLANName: "mgmt"},
+		{DeviceID: 2, VLANVLAN: 1, VLANName: "default"},
+		{DeviceID: 2, VLANVLAN: 10, VLANName: "users"},
+		{DeviceID: 2, VLANVLAN: 20, VLANName: "Voice"},
+		{DeviceID: 3, VLANVLAN: 10, VLANName: "staff"},
+		{DeviceID: 3, VLANVLAN: 40, VLANName: "printers"},
+		{DeviceID: 3, VLANVLAN: 99, VLANName: "old"},
+	} {
+		srv.AddVLAN(vlan)
+	}
+	deleted := access(304, 3, "Gi3", "99")
+	deleted.Deleted = 1
+	for _, port := range []types.Port{
+		trunk(101, 1, "Te1/1", "30"),
+		trunk(102, 1, "Te1/2", "30"),
+		trunk(103, 1, "Gi0/1", "30"),
+		access(201, 2, "Gi1", "10"),
+		access(202, 2, "Gi2", "20"),
+		access(203, 2, "Gi3", "10"),
+		trunk(204, 2, "Te1", "1"),
+		access(205, 2, "Gi4", "1"),
+		access(301, 3, "Gi1", "10"),
+		access(302, 3, "Gi2", "40"),
+		trunk(303, 3, "Te1", "1"),
+		deleted,
+		access(401, 4, "Gi0", ""),
+	} {
+		srv.AddPort(port)
+	}
+	for _, link := range []types.Link{
+		{LocalDeviceID: 2, LocalPortID: 204, RemoteDeviceID: 1, RemotePortID: 101, Protocol: "lldp"},
+		{LocalDeviceID: 2, LocalPortID: 204, RemoteDeviceID: 1, RemotePortID: 101, Protocol: "cdp"},
+		{LocalDeviceID: 1, LocalPortID: 101, RemoteDeviceID: 2, RemotePortID: 204, Protocol: "lldp"},
+		{LocalDeviceID: 3, LocalPortID: 303, RemoteDeviceID: 1, RemotePort: "TenGigabitEthernet1/2", Protocol: "lldp"},
+		{LocalDeviceID: 1, LocalPortID: 102, RemoteDeviceID: 3, RemotePortID: 303, Protocol: "lldp"},
+		{LocalDeviceID: 1, LocalPortID: 103, RemoteDeviceID: 4, RemotePortID: 401, Protocol: "lldp"},
+		{LocalDeviceID: 2, LocalPortID: 201, RemoteHostname: "phone1", Protocol: "lldp"},
+	} {
+		srv.AddLink(link)
+	}
+	return srv
+}
+
+func TestAudit(t *testing.T) {
+	r := require.New(t)
+
+	srv := newServer(t)
+	report, err := vlanaudit.Audit(srv.Client(), nil)
+	r.NoError(err, "Audit returned an error")
+
+	ids := make([]int, 0, len(report.VLANs))
+	for _, vlan := range report.VLANs {
+		ids = append(ids, vlan.ID)
+	}
+	r.Equal([]int{1, 10, 20, 30, 40, 99}, ids, "Expected every VLAN of the estate")
+	r.Equal(&vlanaudit.VLAN{ID: 10, Names: []string{"staff", "users"}, Devices: []vlanaudit.Member{
+		{DeviceID: 1, Hostname: "core", Name: "users", Configured: true, AccessPorts: []string{}},
+		{DeviceID: 2, Hostname: "sw1", Name: "users", Configured: true, AccessPorts: []string{"Gi1", "Gi3"}},
+		{DeviceID: 3, Hostname: "sw2", Name: "staff", Configured: true, AccessPorts: []string{"Gi1"}},
+	}}, report.VLAN(10), "Unexpected VLAN 10")
+	r.Nil(report.VLAN(50), "Expected no VLAN 50")
+
+	r.Equal([]vlanaudit.Issue{
+		{Kind: vlanaudit.IssueNameMismatch, VLAN: 10, Message: `VLAN 10 is named "staff" on sw2 and "users" on core, sw1`},
+		{Kind: vlanaudit.IssueNameMismatch, VLAN: 20, Message: `VLAN 20 is named "Voice" on sw1 and "voice" on core`},
+		{Kind: vlanaudit.IssueMissingOnTrunk, VLAN: 40, Hostname: "sw2", Port: "Te1", RemoteHostname: "core", RemotePort: "TenGigabitEthernet1/2",
+			Message: "VLAN 40 is used on access ports of sw2 but core, at the other end of trunk Te1, doesn't have it"},
+		{Kind: vlanaudit.IssueOrphan, VLAN: 99, Message: "VLAN 99 is configured on sw2 but no port uses it"},
+	}, report.Issues, "Unexpected issues")
+	r.False(report.OK(), "Expected issues")
+
+	report, err = vlanaudit.Audit(srv.Client(), &vlanaudit.Options{Ignore: []int{10, 20, 40, 99}})
+	r.NoError(err, "Audit returned an error")
+	r.True(report.OK(), "Expected the default VLAN to be checked and consistent: %v", report.Issues)
+	r.Equal([]string{"Gi4"}, report.VLAN(1).Devices[1].AccessPorts, "Expected the access ports of the default VLAN")
+}
+
+func TestAudit_Errors(t *testing.T) {
+	r := require.New(t)
+
+	srv := newServer(t)
+	for endpoint, message := range map[string]string{
+		"devices":         "failed to list devices",
+		"resources/vlans": "failed to list VLANs",
+		"ports":           "failed to list ports",
+		"resources/links": "failed to list links",
+	} {
+		srv.InjectError(endpoint, http.StatusInternalServerError, 1)
+		_, err := vlanaudit.Audit(srv.Client(), nil)
+		r.ErrorContains(err, message, "Expected the error of %s", endpoint)
+	}
+}